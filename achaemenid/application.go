@@ -16,6 +16,8 @@ import (
 	"../node"
 	"../protocol"
 	"../service"
+	"../srpc"
+	"../time/earth"
 )
 
 // App is the base object that use by other part of app and platforms!
@@ -62,7 +64,9 @@ func (app *appStructure) Init() {
 
 	app.Services.Init()
 	app.Errors.Init()
-	app.Connections.Init()
+	app.Connections.Init(protocol.Duration(app.Manifest.NetworkInfo.ConnectionIdleTimeout) * earth.Second)
+	app.Connections.SetPinger(newPinger, app.Manifest.NetworkInfo.ConnectionMaxMissedPongs, app.Manifest.NetworkInfo.ConnectionCloseNotResponse)
+	app.Connections.SetEventParent(&app.Logger.EventTarget)
 
 	// Get UserGivenPermission from OS
 
@@ -150,3 +154,6 @@ func (app *appStructure) changeState(state protocol.ApplicationState) {
 	}
 	app.stateChangeLocker.Unlock()
 }
+
+// newPinger return new sRPC pinger to check liveness of each registered connection.
+func newPinger() connection.Pinger { return new(srpc.Pinger) }
@@ -6,6 +6,7 @@ import (
 	"crypto/sha512"
 	"time"
 
+	"../connection"
 	etime "../earth-time"
 	"../protocol"
 )
@@ -30,6 +31,7 @@ type Manifest struct {
 
 func (ma *Manifest) init() {
 	ma.AppID = sha512.Sum512_256(convert.UnsafeStringToByteSlice(ma.DomainName))
+	ma.NetworkInfo.init()
 }
 
 type AppDetail struct {
@@ -60,6 +62,9 @@ type NetworkInfo struct {
 	// Application Overal rate limit
 	MaxOpenConnection     uint64         // The maximum number of concurrent connections the app may serve.
 	ConnectionIdleTimeout etime.Duration // In seconds
+	// Liveness detection by ping frames. Peer will ping (ConnectionMaxMissedPongs+1) times in each ConnectionIdleTimeout.
+	ConnectionMaxMissedPongs   uint8 // 0 means use default srpc.PingerMaxMissedPongs.
+	ConnectionCloseNotResponse bool  // Close connection after ConnectionMaxMissedPongs reached, otherwise just mark it as NotResponse.
 	// MaxStreamHeaderSize   uint64 // For stream protocols with variable header size like HTTP
 	// MaxStreamPayloadSize  uint64 // For stream protocols with variable payload size like sRPC, HTTP, ...
 
@@ -69,6 +74,7 @@ type NetworkInfo struct {
 	GuestMaxConcurrentStreams      uint32
 	GuestMaxStreamConnectionDaily  uint32 // Max open stream per day for a guest connection. overflow will drop on creation!
 	GuestMaxServiceCallDaily       uint64 // 0 means no limit and good for PayAsGo strategy!
+	GuestMaxServiceCallPerSecond   uint32 // 0 means no limit. Allow burst of this number of calls too.
 	GuestMaxBytesSendDaily         uint64
 	GuestMaxBytesReceiveDaily      uint64
 	GuestMaxPacketsSendDaily       uint64
@@ -80,6 +86,7 @@ type NetworkInfo struct {
 	RegisteredMaxConcurrentStreams      uint32
 	RegisteredMaxStreamConnectionDaily  uint32 // Max open stream per day for a Registered user connection. overflow will drop on creation!
 	RegisteredMaxServiceCallDaily       uint64 // 0 means no limit and good for PayAsGo strategy!
+	RegisteredMaxServiceCallPerSecond   uint32 // 0 means no limit. Allow burst of this number of calls too.
 	RegisteredMaxBytesSendDaily         uint64
 	RegisteredMaxBytesReceiveDaily      uint64
 	RegisteredMaxPacketsSendDaily       uint64
@@ -88,6 +95,30 @@ type NetworkInfo struct {
 	// If you want to know Connection.OwnerType>1 rate limit strategy, You must read server codes!!
 }
 
+// init set connections quotas by the network information to enforce them on each connection.
+func (ni *NetworkInfo) init() {
+	connection.GuestQuota = connection.Quota{
+		MaxConcurrentStreams:    ni.GuestMaxConcurrentStreams,
+		MaxStreamDaily:          ni.GuestMaxStreamConnectionDaily,
+		MaxServiceCallDaily:     ni.GuestMaxServiceCallDaily,
+		MaxServiceCallPerSecond: ni.GuestMaxServiceCallPerSecond,
+		MaxBytesSendDaily:       ni.GuestMaxBytesSendDaily,
+		MaxBytesReceiveDaily:    ni.GuestMaxBytesReceiveDaily,
+		MaxPacketsSendDaily:     ni.GuestMaxPacketsSendDaily,
+		MaxPacketsReceiveDaily:  ni.GuestMaxPacketsReceiveDaily,
+	}
+	connection.RegisteredQuota = connection.Quota{
+		MaxConcurrentStreams:    ni.RegisteredMaxConcurrentStreams,
+		MaxStreamDaily:          ni.RegisteredMaxStreamConnectionDaily,
+		MaxServiceCallDaily:     ni.RegisteredMaxServiceCallDaily,
+		MaxServiceCallPerSecond: ni.RegisteredMaxServiceCallPerSecond,
+		MaxBytesSendDaily:       ni.RegisteredMaxBytesSendDaily,
+		MaxBytesReceiveDaily:    ni.RegisteredMaxBytesReceiveDaily,
+		MaxPacketsSendDaily:     ni.RegisteredMaxPacketsSendDaily,
+		MaxPacketsReceiveDaily:  ni.RegisteredMaxPacketsReceiveDaily,
+	}
+}
+
 // DeployInfo store some application deployment information.
 type DeployInfo struct {
 	// Distribution
@@ -0,0 +1,47 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package assets
+
+// FileState indicate file data changed and must write to its storage or not.
+type FileState uint8
+
+// File states
+const (
+	StateUnChanged FileState = iota
+	StateChanged
+)
+
+// File is a file data that generators and minifiers can change in memory before write it back.
+type File struct {
+	Data  []byte
+	State FileState
+}
+
+// ReplaceReq is a request to replace Data[Start:End] of a file with given Data.
+type ReplaceReq struct {
+	Data  string
+	Start int
+	End   int
+}
+
+// Replace replace all requests in one pass, so offsets of all requests are in the original data.
+// Requests must be in ascending order and not overlap.
+func (f *File) Replace(reqs []ReplaceReq) {
+	if len(reqs) == 0 {
+		return
+	}
+
+	var ln = len(f.Data)
+	for _, req := range reqs {
+		ln += len(req.Data) - (req.End - req.Start)
+	}
+	var data = make([]byte, 0, ln)
+	var last int
+	for _, req := range reqs {
+		data = append(data, f.Data[last:req.Start]...)
+		data = append(data, req.Data...)
+		last = req.End
+	}
+	data = append(data, f.Data[last:]...)
+	f.Data = data
+}
@@ -6,6 +6,7 @@ import (
 	etime "../earth-time"
 	er "../error"
 	"../json"
+	"../protocol"
 	"../syllab"
 )
 
@@ -31,9 +32,8 @@ type AccessControl struct {
 	AllowCRUD     CRUD     // CRUD == Create, Read, Update, Delete
 	DenyCRUD      CRUD     // CRUD == Create, Read, Update, Delete
 
-	// Authorize What:
-	// Authorize How:
-	// Authorize If:
+	// Authorize What, How and If by rules. It is runtime only data and not encode||decode by syllab or json.
+	Policies PolicySet
 }
 
 // GiveFullAccess set some data to given AccessControl to be full access
@@ -170,29 +170,20 @@ DR:
 	return
 }
 
-// AuthorizeWhat --
-func (ac *AccessControl) AuthorizeWhat() (err *er.Error) {
-	// TODO::: can be implement?
-
-	// AllowRecords   [][16]byte // ["RecordUUID", "RecordUUID"]
-	// DenyRecords    [][16]byte // ["RecordUUID", "RecordUUID"]
-	return
+// AuthorizeWhat authorize requested resource(media-type), CRUD and owner by policies rules that have any What condition.
+// Rules in ac.Policies must compile before call it.
+func (ac *AccessControl) AuthorizeWhat(attr *Attributes) (err protocol.Error) {
+	return ac.Policies.authorize(attr, rulePart_What)
 }
 
-// AuthorizeHow --
-func (ac *AccessControl) AuthorizeHow() (err *er.Error) {
-	// TODO::: can be implement?
-
-	// How []string
-	return
+// AuthorizeHow authorize transport and encryption of the connection by policies rules that have any How condition.
+func (ac *AccessControl) AuthorizeHow(attr *Attributes) (err protocol.Error) {
+	return ac.Policies.authorize(attr, rulePart_How)
 }
 
-// AuthorizeIf --
-func (ac *AccessControl) AuthorizeIf() (err *er.Error) {
-	// TODO::: can be implement?
-
-	// If  []string
-	return
+// AuthorizeIf authorize predicate expressions of policies rules on given attributes.
+func (ac *AccessControl) AuthorizeIf(attr *Attributes) (err protocol.Error) {
+	return ac.Policies.authorize(attr, rulePart_If)
 }
 
 // SyllabDecoder decode syllab to given AccessControl
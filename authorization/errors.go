@@ -5,6 +5,7 @@ package authorization
 import (
 	er "../error"
 	lang "../language"
+	"../protocol"
 )
 
 const errorEnglishDomain = "Authorization"
@@ -116,3 +117,38 @@ var (
 		SetDetail(lang.LanguagePersian, errorPersianDomain, "عدم اجازه به وکالت ندادن",
 			"قوانین پلتفرم به نوع کاربر فعلی (معمولا سازمان) اجازه نمی دهد اتصال غیر وکالتی ایجاد نماید").Save()
 )
+
+// Policy errors
+var (
+	ErrPolicyDenied      er.Error
+	ErrPolicyNotCompiled er.Error
+	ErrPolicyExpression  er.Error
+)
+
+func init() {
+	ErrPolicyDenied.Init("domain/authorization.protocol; type=error; name=policy-denied")
+	ErrPolicyDenied.SetDetail(protocol.LanguageEnglish, errorEnglishDomain,
+		"Policy Denied",
+		"Request denied by the application or the connection policies",
+		"",
+		"",
+		nil)
+
+	ErrPolicyNotCompiled.Init("domain/authorization.protocol; type=error; name=policy-not-compiled")
+	ErrPolicyNotCompiled.SetDetail(protocol.LanguageEnglish, errorEnglishDomain,
+		"Policy Not Compiled",
+		"Policy set must compile after add any rule and before authorize any request",
+		"",
+		"",
+		nil)
+	ErrPolicyNotCompiled.SetInternal()
+
+	ErrPolicyExpression.Init("domain/authorization.protocol; type=error; name=policy-expression")
+	ErrPolicyExpression.SetDetail(protocol.LanguageEnglish, errorEnglishDomain,
+		"Policy Expression",
+		"Given If expression of a policy rule is not valid",
+		"",
+		"",
+		nil)
+	ErrPolicyExpression.SetInternal()
+}
@@ -0,0 +1,166 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package authorization
+
+import (
+	"math/bits"
+	"reflect"
+
+	"../protocol"
+	"../time/utc"
+)
+
+// Owner indicate relation of the connection user with the requested record.
+type Owner uint8
+
+// Owners
+const (
+	Owner_Any   Owner = iota // Not checked or not important
+	Owner_Self               // Record own by connection user
+	Owner_Other              // Record own by other users
+)
+
+// Attributes store any data that policies can evaluate on them.
+// Use NewAttributes to fill it by a stream data and change any field after that e.g. Owner when service read the record.
+type Attributes struct {
+	UserType  protocol.UserType
+	ServiceID protocol.MediaTypeID
+	CRUD      protocol.CRUD
+	Resource  protocol.MediaTypeID // Requested resource media-type. It is the service until service set the record it read.
+	Owner     Owner
+	Transport protocol.NetworkLink_NextHeaderID
+	Encrypted bool
+	Weekday   uint8 // 0 is Monday as utc.Weekdays_Monday
+	Hour      uint8 // 0-23 in UTC
+	Domain    string
+}
+
+// NewAttributes return attributes of given stream and its service to authorize by policies.
+func NewAttributes(st protocol.Stream, service protocol.Service) (attr Attributes) {
+	var conn = st.Connection()
+	if conn != nil {
+		var userID = conn.UserID()
+		if userID != nil {
+			attr.UserType = userID.Type()
+		}
+		attr.Transport = conn.HeaderID()
+		attr.Encrypted = hasCipher(conn)
+		attr.Domain = conn.DomainName()
+	}
+	if service != nil {
+		attr.ServiceID = service.ID()
+		attr.CRUD = service.CRUDType()
+		// Service is the requested resource until the service read a record and set the record media-type.
+		attr.Resource = service.ID()
+	}
+
+	var now = utc.Now()
+	attr.Weekday = uint8(bits.TrailingZeros8(uint8(now.Weekdays())))
+	attr.Hour = uint8(bits.TrailingZeros32(uint32(now.DayHours())))
+	return
+}
+
+// hasCipher report the connection has any cipher. Connections can return a nil pointer of their cipher type
+// as protocol.Cipher that is not equal to nil interface, so check the pointer too.
+func hasCipher(conn protocol.Connection) bool {
+	var cipher = conn.Cipher()
+	if cipher == nil {
+		return false
+	}
+	var value = reflect.ValueOf(cipher)
+	return value.Kind() != reflect.Ptr || !value.IsNil()
+}
+
+// attributeName is name of an attribute that can use in policy expressions.
+type attributeName string
+
+const (
+	attributeName_UserType  attributeName = "user.type"
+	attributeName_ServiceID attributeName = "service.id"
+	attributeName_CRUD      attributeName = "crud"
+	attributeName_Resource  attributeName = "resource.id"
+	attributeName_Owner     attributeName = "owner"
+	attributeName_Transport attributeName = "transport"
+	attributeName_Encrypted attributeName = "encrypted"
+	attributeName_Weekday   attributeName = "time.weekday"
+	attributeName_Hour      attributeName = "time.hour"
+	attributeName_Domain    attributeName = "conn.domain"
+)
+
+func (an attributeName) valid() bool {
+	switch an {
+	case attributeName_UserType, attributeName_ServiceID, attributeName_CRUD, attributeName_Resource, attributeName_Owner,
+		attributeName_Transport, attributeName_Encrypted, attributeName_Weekday, attributeName_Hour, attributeName_Domain:
+		return true
+	}
+	return false
+}
+
+type attributeValue struct {
+	isString bool
+	num      int64
+	str      string
+}
+
+func (attr *Attributes) value(an attributeName) (value attributeValue) {
+	switch an {
+	case attributeName_UserType:
+		value.num = int64(attr.UserType)
+	case attributeName_ServiceID:
+		value.num = int64(attr.ServiceID)
+	case attributeName_CRUD:
+		value.num = int64(attr.CRUD)
+	case attributeName_Resource:
+		value.num = int64(attr.Resource)
+	case attributeName_Owner:
+		value.num = int64(attr.Owner)
+	case attributeName_Transport:
+		value.num = int64(attr.Transport)
+	case attributeName_Encrypted:
+		if attr.Encrypted {
+			value.num = 1
+		}
+	case attributeName_Weekday:
+		value.num = int64(attr.Weekday)
+	case attributeName_Hour:
+		value.num = int64(attr.Hour)
+	case attributeName_Domain:
+		value.isString = true
+		value.str = attr.Domain
+	}
+	return
+}
+
+// attributeValueNames use to let policy writers use names instead of numbers in expressions.
+var attributeValueNames = map[string]int64{
+	"false": 0,
+	"true":  1,
+
+	"guest":   int64(protocol.UserType_Guest),
+	"person":  int64(protocol.UserType_Person),
+	"thing":   int64(protocol.UserType_Thing),
+	"org":     int64(protocol.UserType_Org),
+	"app":     int64(protocol.UserType_App),
+	"society": int64(protocol.UserType_Society),
+
+	"create": int64(protocol.CRUDCreate),
+	"read":   int64(protocol.CRUDRead),
+	"update": int64(protocol.CRUDUpdate),
+	"delete": int64(protocol.CRUDDelete),
+
+	"any":   int64(Owner_Any),
+	"self":  int64(Owner_Self),
+	"other": int64(Owner_Other),
+
+	"srpc": int64(protocol.NetworkLink_SRPC),
+	"gp":   int64(protocol.NetworkLink_GP),
+	"ipv6": int64(protocol.NetworkLink_IPv6),
+
+	"monday":    0,
+	"tuesday":   1,
+	"wednesday": 2,
+	"thursday":  3,
+	"friday":    4,
+	"saturday":  5,
+	"sunday":    6,
+}
@@ -0,0 +1,224 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package authorization
+
+import (
+	"strconv"
+
+	"../protocol"
+)
+
+// expression is a compiled "If" predicate of a policy rule.
+// Grammar:
+//
+//	expr    = and *("||" and)
+//	and     = unary *("&&" unary)
+//	unary   = "!" unary | "(" expr ")" | compare
+//	compare = attribute [op value]	; attribute alone means attribute != 0 e.g. `encrypted`
+//	op      = "==" | "!=" | "<" | "<=" | ">" | ">="
+//	value   = number | name | '"' string '"'	; name is a known attribute value name e.g. person, read, gp, true
+//
+// e.g. `user.type == person && (time.hour >= 8 && time.hour < 17) || !encrypted`
+type expression interface {
+	eval(attr *Attributes) bool
+}
+
+type exprOr struct{ left, right expression }
+type exprAnd struct{ left, right expression }
+type exprNot struct{ expr expression }
+type exprCompare struct {
+	attribute attributeName
+	op        string
+	value     attributeValue
+}
+
+func (e *exprOr) eval(attr *Attributes) bool  { return e.left.eval(attr) || e.right.eval(attr) }
+func (e *exprAnd) eval(attr *Attributes) bool { return e.left.eval(attr) && e.right.eval(attr) }
+func (e *exprNot) eval(attr *Attributes) bool { return !e.expr.eval(attr) }
+func (e *exprCompare) eval(attr *Attributes) bool {
+	var value = attr.value(e.attribute)
+	if value.isString != e.value.isString {
+		return false
+	}
+	if value.isString {
+		switch e.op {
+		case "==":
+			return value.str == e.value.str
+		case "!=":
+			return value.str != e.value.str
+		}
+		return false
+	}
+	switch e.op {
+	case "==":
+		return value.num == e.value.num
+	case "!=":
+		return value.num != e.value.num
+	case "<":
+		return value.num < e.value.num
+	case "<=":
+		return value.num <= e.value.num
+	case ">":
+		return value.num > e.value.num
+	case ">=":
+		return value.num >= e.value.num
+	}
+	return false
+}
+
+// parseExpression compile given predicate expression.
+func parseExpression(expr string) (e expression, err protocol.Error) {
+	var p = expressionParser{tokens: tokenizeExpression(expr)}
+	e, err = p.parseOr()
+	if err == nil && p.index != len(p.tokens) {
+		err = &ErrPolicyExpression
+	}
+	return
+}
+
+type expressionParser struct {
+	tokens []string
+	index  int
+}
+
+func (p *expressionParser) peek() string {
+	if p.index < len(p.tokens) {
+		return p.tokens[p.index]
+	}
+	return ""
+}
+func (p *expressionParser) next() (token string) { token = p.peek(); p.index++; return }
+
+func (p *expressionParser) parseOr() (e expression, err protocol.Error) {
+	e, err = p.parseAnd()
+	for err == nil && p.peek() == "||" {
+		p.next()
+		var right expression
+		right, err = p.parseAnd()
+		e = &exprOr{left: e, right: right}
+	}
+	return
+}
+
+func (p *expressionParser) parseAnd() (e expression, err protocol.Error) {
+	e, err = p.parseUnary()
+	for err == nil && p.peek() == "&&" {
+		p.next()
+		var right expression
+		right, err = p.parseUnary()
+		e = &exprAnd{left: e, right: right}
+	}
+	return
+}
+
+func (p *expressionParser) parseUnary() (e expression, err protocol.Error) {
+	switch p.peek() {
+	case "!":
+		p.next()
+		e, err = p.parseUnary()
+		e = &exprNot{expr: e}
+	case "(":
+		p.next()
+		e, err = p.parseOr()
+		if err == nil && p.next() != ")" {
+			err = &ErrPolicyExpression
+		}
+	default:
+		e, err = p.parseCompare()
+	}
+	return
+}
+
+func (p *expressionParser) parseCompare() (e expression, err protocol.Error) {
+	var attribute = attributeName(p.next())
+	if !attribute.valid() {
+		return nil, &ErrPolicyExpression
+	}
+
+	var compare = exprCompare{attribute: attribute, op: "!=", value: attributeValue{num: 0}}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		compare.op = op
+		compare.value, err = parseAttributeValue(p.next())
+	}
+	e = &compare
+	return
+}
+
+func parseAttributeValue(token string) (value attributeValue, err protocol.Error) {
+	var ln = len(token)
+	if ln == 0 {
+		return value, &ErrPolicyExpression
+	}
+	if token[0] == '"' {
+		if ln < 2 || token[ln-1] != '"' {
+			return value, &ErrPolicyExpression
+		}
+		value.isString = true
+		value.str = token[1 : ln-1]
+		return
+	}
+
+	var num, ok = attributeValueNames[token]
+	if ok {
+		value.num = num
+		return
+	}
+
+	var goErr error
+	value.num, goErr = strconv.ParseInt(token, 10, 64)
+	if goErr != nil {
+		err = &ErrPolicyExpression
+	}
+	return
+}
+
+// tokenizeExpression split given expression to its tokens. It doesn't validate anything.
+func tokenizeExpression(expr string) (tokens []string) {
+	var ln = len(expr)
+	for i := 0; i < ln; {
+		var c = expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, expr[i:i+1])
+			i++
+		case c == '"':
+			var end = i + 1
+			for end < ln && expr[end] != '"' {
+				end++
+			}
+			if end < ln {
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+		case c == '&' || c == '|' || c == '=' || c == '!' || c == '<' || c == '>':
+			if i+1 < ln && (expr[i+1] == '=' || (c != '!' && c != '<' && c != '>' && expr[i+1] == c)) {
+				tokens = append(tokens, expr[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, expr[i:i+1])
+				i++
+			}
+		default:
+			var end = i
+			for end < ln && isExpressionNameChar(expr[end]) {
+				end++
+			}
+			if end == i {
+				// Unknown character, add it as a token to fail in parse phase.
+				end++
+			}
+			tokens = append(tokens, expr[i:end])
+			i = end
+		}
+	}
+	return
+}
+
+func isExpressionNameChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '.' || c == '_' || c == '-'
+}
@@ -0,0 +1,307 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package authorization
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"../protocol"
+)
+
+// Effect indicate what a rule do if it match a request.
+type Effect uint8
+
+// Effects
+const (
+	Effect_Unset Effect = iota
+	Effect_Allow
+	Effect_Deny
+)
+
+// Precedence indicate how a PolicySet combine effects of matched rules.
+type Precedence uint8
+
+// Precedences
+const (
+	Precedence_DenyOverrides   Precedence = iota // Any matched deny rule wins
+	Precedence_AllowOverrides                    // Any matched allow rule wins
+	Precedence_FirstApplicable                   // First matched rule in add order wins
+)
+
+// Encrypted indicate a rule need encrypted connections or not.
+type Encrypted uint8
+
+// Encrypted states
+const (
+	Encrypted_Any Encrypted = iota
+	Encrypted_Yes
+	Encrypted_No
+)
+
+// Rule is a policy rule that authorize What, How and If of a request.
+// Empty fields match any request.
+type Rule struct {
+	Effect Effect
+
+	// Authorize What:
+	Resources []protocol.MediaTypeID // Requested resources media-types
+	CRUD      protocol.CRUD          // Match if request CRUD is in it
+	Owner     Owner
+
+	// Authorize How:
+	Transports []protocol.NetworkLink_NextHeaderID
+	Encrypted  Encrypted
+
+	// Authorize If:
+	If string // e.g. `user.type == person && time.hour >= 8`
+
+	// Compiled data, fill by PolicySet.Compile
+	parts      rulePart  // Parts that rule has any condition on them
+	transports [4]uint64 // bitmap of Transports
+	ifExpr     expression
+}
+
+// rulePart indicate parts of a rule that authorize separately by AccessControl AuthorizeWhat, AuthorizeHow and AuthorizeIf.
+type rulePart uint8
+
+const (
+	rulePart_What rulePart = 1 << iota
+	rulePart_How
+	rulePart_If
+	rulePart_All = rulePart_What | rulePart_How | rulePart_If
+)
+
+func (r *Rule) compile() (err protocol.Error) {
+	sort.Slice(r.Resources, func(i, j int) bool { return r.Resources[i] < r.Resources[j] })
+
+	r.transports = [4]uint64{}
+	for _, t := range r.Transports {
+		r.transports[t/64] |= 1 << (t % 64)
+	}
+
+	r.ifExpr = nil
+	if r.If != "" {
+		r.ifExpr, err = parseExpression(r.If)
+	}
+
+	r.parts = 0
+	if len(r.Resources) > 0 || r.CRUD != protocol.CRUDNone || r.Owner != Owner_Any {
+		r.parts |= rulePart_What
+	}
+	if len(r.Transports) > 0 || r.Encrypted != Encrypted_Any {
+		r.parts |= rulePart_How
+	}
+	if r.ifExpr != nil {
+		r.parts |= rulePart_If
+	}
+	return
+}
+
+// match report whether given attributes match the rule conditions in given parts.
+func (r *Rule) match(attr *Attributes, parts rulePart) bool {
+	return (parts&rulePart_What == 0 || r.matchWhat(attr)) &&
+		(parts&rulePart_How == 0 || r.matchHow(attr)) &&
+		(parts&rulePart_If == 0 || r.matchIf(attr))
+}
+
+// matchWhat match requested resource(media-type), CRUD and owner.
+func (r *Rule) matchWhat(attr *Attributes) bool {
+	if len(r.Resources) > 0 {
+		var i = sort.Search(len(r.Resources), func(i int) bool { return r.Resources[i] >= attr.Resource })
+		if i == len(r.Resources) || r.Resources[i] != attr.Resource {
+			return false
+		}
+	}
+	if r.CRUD != protocol.CRUDNone && attr.CRUD&r.CRUD != attr.CRUD {
+		return false
+	}
+	if r.Owner != Owner_Any && r.Owner != attr.Owner {
+		return false
+	}
+	return true
+}
+
+// matchHow match transport and encryption of the connection.
+func (r *Rule) matchHow(attr *Attributes) bool {
+	if len(r.Transports) > 0 && r.transports[attr.Transport/64]&(1<<(attr.Transport%64)) == 0 {
+		return false
+	}
+	switch r.Encrypted {
+	case Encrypted_Yes:
+		return attr.Encrypted
+	case Encrypted_No:
+		return !attr.Encrypted
+	}
+	return true
+}
+
+// matchIf evaluate the rule predicate expression.
+func (r *Rule) matchIf(attr *Attributes) bool {
+	return r.ifExpr == nil || r.ifExpr.eval(attr)
+}
+
+// PolicySet is a list of rules that combine by its Precedence.
+// Add all rules and call Compile before any Authorize call. Not concurrent safe to Add||Compile while authorizing.
+type PolicySet struct {
+	Precedence Precedence
+	Default    Effect // Effect when no rule match. Unset means allow.
+	Rules      []Rule
+
+	compiled int32
+	// Rules indexes in add order. Authorize just evaluate rules of the requested resource and rules without any resource,
+	// so it don't scan all rules of other resources on each call.
+	byResource  map[protocol.MediaTypeID][]int
+	anyResource []int
+	all         []int
+}
+
+// Add append given rule to the set. Compile must call after any add.
+func (ps *PolicySet) Add(rule Rule) {
+	ps.Rules = append(ps.Rules, rule)
+	atomic.StoreInt32(&ps.compiled, 0)
+}
+
+// Compile parse expressions and make sorted and bitmap lookups of rules and index rules by their resources.
+func (ps *PolicySet) Compile() (err protocol.Error) {
+	ps.byResource = make(map[protocol.MediaTypeID][]int)
+	ps.anyResource = ps.anyResource[:0]
+	ps.all = ps.all[:0]
+	for i := 0; i < len(ps.Rules); i++ {
+		var rule = &ps.Rules[i]
+		err = rule.compile()
+		if err != nil {
+			return
+		}
+
+		ps.all = append(ps.all, i)
+		if len(rule.Resources) == 0 {
+			ps.anyResource = append(ps.anyResource, i)
+			continue
+		}
+		for j, resource := range rule.Resources {
+			// Resources are sorted, so a duplicated resource is next to its first one.
+			if j > 0 && rule.Resources[j-1] == resource {
+				continue
+			}
+			ps.byResource[resource] = append(ps.byResource[resource], i)
+		}
+	}
+	atomic.StoreInt32(&ps.compiled, 1)
+	return
+}
+
+// Authorize evaluate rules on given attributes and return ErrPolicyDenied if request not allowed.
+// A rule match if all its What, How and If conditions match.
+func (ps *PolicySet) Authorize(attr *Attributes) (err protocol.Error) {
+	return ps.authorize(attr, rulePart_All)
+}
+
+// authorize evaluate given parts of rules that have any condition on them.
+// Rules without any condition in the parts are not applicable and skip, except when authorize all parts.
+func (ps *PolicySet) authorize(attr *Attributes, parts rulePart) (err protocol.Error) {
+	if len(ps.Rules) == 0 {
+		return ps.defaultResult()
+	}
+	if atomic.LoadInt32(&ps.compiled) == 0 {
+		return &ErrPolicyNotCompiled
+	}
+
+	var allowed, denied bool
+	var byResource, anyResource = ps.applicableRules(attr, parts)
+	for len(byResource) > 0 || len(anyResource) > 0 {
+		// Take lower index of both lists to evaluate rules in add order as first-applicable precedence need.
+		var i int
+		if len(anyResource) == 0 || (len(byResource) > 0 && byResource[0] < anyResource[0]) {
+			i, byResource = byResource[0], byResource[1:]
+		} else {
+			i, anyResource = anyResource[0], anyResource[1:]
+		}
+
+		var rule = &ps.Rules[i]
+		if parts != rulePart_All && rule.parts&parts == 0 {
+			continue
+		}
+		if !rule.match(attr, parts) {
+			continue
+		}
+		switch rule.Effect {
+		case Effect_Allow:
+			if ps.Precedence != Precedence_DenyOverrides {
+				return
+			}
+			allowed = true
+		case Effect_Deny:
+			if ps.Precedence != Precedence_AllowOverrides {
+				return &ErrPolicyDenied
+			}
+			denied = true
+		}
+	}
+
+	// Deny-overrides return on first deny and allow-overrides on first allow, so just one of them can be set here.
+	if denied {
+		return &ErrPolicyDenied
+	}
+	if allowed {
+		return
+	}
+	return ps.defaultResult()
+}
+
+// applicableRules return indexes of rules that can match given attributes.
+// Rules of other resources can't match if What part authorize, otherwise all rules are applicable.
+func (ps *PolicySet) applicableRules(attr *Attributes, parts rulePart) (byResource, anyResource []int) {
+	if parts&rulePart_What == 0 {
+		return nil, ps.all
+	}
+	return ps.byResource[attr.Resource], ps.anyResource
+}
+
+func (ps *PolicySet) defaultResult() (err protocol.Error) {
+	if ps.Default == Effect_Deny {
+		err = &ErrPolicyDenied
+	}
+	return
+}
+
+// AppPolicies is application wide policies that check on any service call before connection policies.
+var AppPolicies PolicySet
+
+// PolicyHolder is the interface that connections can implement to have their own policies.
+type PolicyHolder interface {
+	Policies() *PolicySet
+}
+
+// ServiceCalled check the stream connection quotas for a new service call. Protocols handlers must call it
+// before do any heavy logic, and let the peer know why the request dropped by returned error.
+func ServiceCalled(st protocol.Stream) (err protocol.Error) {
+	return st.Connection().ServiceCalled()
+}
+
+// AuthorizeServiceCall check the stream connection quotas by ServiceCalled and then authorize the stream by AuthorizeService.
+func AuthorizeServiceCall(st protocol.Stream, service protocol.Service) (err protocol.Error) {
+	err = ServiceCalled(st)
+	if err != nil {
+		return
+	}
+	return AuthorizeService(st, service)
+}
+
+// AuthorizeService authorize given stream to call given service by AppPolicies and the connection policies if any.
+// Protocols handlers e.g. sRPC, HTTP, ... must call it before call the service handler.
+func AuthorizeService(st protocol.Stream, service protocol.Service) (err protocol.Error) {
+	var attr = NewAttributes(st, service)
+	err = AppPolicies.Authorize(&attr)
+	if err != nil {
+		return
+	}
+	var ph, ok = st.Connection().(PolicyHolder)
+	if ok {
+		var policies = ph.Policies()
+		if policies != nil {
+			err = policies.Authorize(&attr)
+		}
+	}
+	return
+}
@@ -0,0 +1,254 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package authorization
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+var (
+	personReadOwn = Attributes{
+		UserType:  protocol.UserType_Person,
+		CRUD:      protocol.CRUDRead,
+		Resource:  20,
+		Owner:     Owner_Self,
+		Transport: protocol.NetworkLink_GP,
+		Encrypted: true,
+		Hour:      10,
+		Domain:    "sabz.city",
+	}
+	guestDeletePlain = Attributes{
+		UserType:  protocol.UserType_Guest,
+		CRUD:      protocol.CRUDDelete,
+		Resource:  30,
+		Owner:     Owner_Other,
+		Transport: protocol.NetworkLink_IPv6,
+		Hour:      22,
+	}
+)
+
+type policySetTest struct {
+	name  string
+	set   PolicySet
+	attr  Attributes
+	allow bool
+}
+
+var policySetTests = []policySetTest{
+	{
+		name:  "empty-allow",
+		attr:  guestDeletePlain,
+		allow: true,
+	}, {
+		name:  "empty-default-deny",
+		set:   PolicySet{Default: Effect_Deny},
+		attr:  personReadOwn,
+		allow: false,
+	}, {
+		name: "no-match-default",
+		set: PolicySet{Default: Effect_Deny, Rules: []Rule{
+			{Effect: Effect_Allow, Resources: []protocol.MediaTypeID{40, 10}},
+		}},
+		attr:  personReadOwn,
+		allow: false,
+	}, {
+		name: "resource-sorted-lookup",
+		set: PolicySet{Default: Effect_Deny, Rules: []Rule{
+			{Effect: Effect_Allow, Resources: []protocol.MediaTypeID{40, 20, 10}},
+		}},
+		attr:  personReadOwn,
+		allow: true,
+	}, {
+		name: "crud-subset",
+		set: PolicySet{Default: Effect_Deny, Rules: []Rule{
+			{Effect: Effect_Allow, CRUD: protocol.CRUDRead | protocol.CRUDUpdate},
+		}},
+		attr:  guestDeletePlain,
+		allow: false,
+	}, {
+		name: "transport-bitmap",
+		set: PolicySet{Rules: []Rule{
+			{Effect: Effect_Deny, Transports: []protocol.NetworkLink_NextHeaderID{protocol.NetworkLink_IPv6}},
+		}},
+		attr:  guestDeletePlain,
+		allow: false,
+	}, {
+		name: "deny-overrides",
+		set: PolicySet{Precedence: Precedence_DenyOverrides, Rules: []Rule{
+			{Effect: Effect_Allow, Owner: Owner_Self},
+			{Effect: Effect_Deny, Encrypted: Encrypted_Yes},
+		}},
+		attr:  personReadOwn,
+		allow: false,
+	}, {
+		name: "allow-overrides",
+		set: PolicySet{Precedence: Precedence_AllowOverrides, Rules: []Rule{
+			{Effect: Effect_Deny, Encrypted: Encrypted_Yes},
+			{Effect: Effect_Allow, Owner: Owner_Self},
+		}},
+		attr:  personReadOwn,
+		allow: true,
+	}, {
+		name: "first-applicable",
+		set: PolicySet{Precedence: Precedence_FirstApplicable, Rules: []Rule{
+			{Effect: Effect_Allow, If: `user.type == person`},
+			{Effect: Effect_Deny},
+		}},
+		attr:  personReadOwn,
+		allow: true,
+	}, {
+		name: "first-applicable-resource-order",
+		set: PolicySet{Precedence: Precedence_FirstApplicable, Rules: []Rule{
+			{Effect: Effect_Allow, Resources: []protocol.MediaTypeID{30}},
+			{Effect: Effect_Deny, Owner: Owner_Self},
+			{Effect: Effect_Allow, Resources: []protocol.MediaTypeID{20, 20}},
+		}},
+		attr:  personReadOwn,
+		allow: false,
+	}, {
+		name: "first-applicable-resource-first",
+		set: PolicySet{Precedence: Precedence_FirstApplicable, Rules: []Rule{
+			{Effect: Effect_Allow, Resources: []protocol.MediaTypeID{20}},
+			{Effect: Effect_Deny, Owner: Owner_Self},
+		}},
+		attr:  personReadOwn,
+		allow: true,
+	}, {
+		name: "if-expression",
+		set: PolicySet{Default: Effect_Deny, Rules: []Rule{
+			{Effect: Effect_Allow, If: `user.type == person && (time.hour >= 8 && time.hour < 17) && conn.domain == "sabz.city"`},
+		}},
+		attr:  personReadOwn,
+		allow: true,
+	}, {
+		name: "if-expression-not",
+		set: PolicySet{Rules: []Rule{
+			{Effect: Effect_Deny, If: `!encrypted || crud == delete`},
+		}},
+		attr:  guestDeletePlain,
+		allow: false,
+	},
+}
+
+func TestPolicySetAuthorize(t *testing.T) {
+	for _, tt := range policySetTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err = tt.set.Compile()
+			if err != nil {
+				t.Fatalf("PolicySet.Compile() error = %v", err)
+			}
+			err = tt.set.Authorize(&tt.attr)
+			if tt.allow && err != nil {
+				t.Errorf("PolicySet.Authorize() error = %v, want nil", err)
+			}
+			if !tt.allow && err != &ErrPolicyDenied {
+				t.Errorf("PolicySet.Authorize() error = %v, want %v", err, &ErrPolicyDenied)
+			}
+		})
+	}
+}
+
+func TestPolicySetNotCompiled(t *testing.T) {
+	var ps PolicySet
+	ps.Add(Rule{Effect: Effect_Allow})
+	var attr = personReadOwn
+	if err := ps.Authorize(&attr); err != &ErrPolicyNotCompiled {
+		t.Errorf("PolicySet.Authorize() before Compile error = %v, want %v", err, &ErrPolicyNotCompiled)
+	}
+}
+
+func TestPolicyExpression(t *testing.T) {
+	var tests = []struct {
+		expr  string
+		valid bool
+	}{
+		{`encrypted`, true},
+		{`user.type == person || owner != self`, true},
+		{`!(time.weekday == sunday) && service.id >= 10`, true},
+		{`conn.domain == "sabz.city"`, true},
+		{`unknown.attribute == 1`, false},
+		{`user.type == unknownName`, false},
+		{`(encrypted`, false},
+		{`encrypted )`, false},
+		{`crud == `, false},
+		{`conn.domain == "open`, false},
+	}
+	for _, tt := range tests {
+		var _, err = parseExpression(tt.expr)
+		if tt.valid && err != nil {
+			t.Errorf("parseExpression(%q) error = %v, want nil", tt.expr, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("parseExpression(%q) error = nil, want %v", tt.expr, &ErrPolicyExpression)
+		}
+	}
+}
+
+// TestAccessControlParts check each of What, How and If authorize just by its own rule conditions.
+func TestAccessControlParts(t *testing.T) {
+	var ac AccessControl
+	ac.Policies.Default = Effect_Deny
+	ac.Policies.Add(Rule{Effect: Effect_Allow, CRUD: protocol.CRUDRead, Owner: Owner_Self})
+	ac.Policies.Add(Rule{Effect: Effect_Allow, Encrypted: Encrypted_Yes})
+	ac.Policies.Add(Rule{Effect: Effect_Allow, If: `time.hour < 12`})
+	var err = ac.Policies.Compile()
+	if err != nil {
+		t.Fatalf("PolicySet.Compile() error = %v", err)
+	}
+
+	var tests = []struct {
+		name      string
+		attr      Attributes
+		what      bool
+		how       bool
+		predicate bool
+	}{
+		{"all-allow", personReadOwn, true, true, true},
+		{"all-deny", guestDeletePlain, false, false, false},
+		{"just-what", Attributes{CRUD: protocol.CRUDRead, Owner: Owner_Self, Hour: 20}, true, false, false},
+		{"just-how", Attributes{CRUD: protocol.CRUDDelete, Encrypted: true, Hour: 20}, false, true, false},
+		{"just-if", Attributes{CRUD: protocol.CRUDDelete, Hour: 9}, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ac.AuthorizeWhat(&tt.attr); (err == nil) != tt.what {
+				t.Errorf("AccessControl.AuthorizeWhat() error = %v, want allow %v", err, tt.what)
+			}
+			if err := ac.AuthorizeHow(&tt.attr); (err == nil) != tt.how {
+				t.Errorf("AccessControl.AuthorizeHow() error = %v, want allow %v", err, tt.how)
+			}
+			if err := ac.AuthorizeIf(&tt.attr); (err == nil) != tt.predicate {
+				t.Errorf("AccessControl.AuthorizeIf() error = %v, want allow %v", err, tt.predicate)
+			}
+		})
+	}
+}
+
+// nilCipher is a cipher type that a connection can return as a nil pointer.
+type nilCipher struct{ protocol.Cipher }
+
+type cipherConnection struct {
+	protocol.Connection
+	cipher protocol.Cipher
+}
+
+func (conn *cipherConnection) Cipher() protocol.Cipher { return conn.cipher }
+
+func TestHasCipher(t *testing.T) {
+	var tests = []struct {
+		name   string
+		cipher protocol.Cipher
+		want   bool
+	}{
+		{"nil", nil, false},
+		{"typed-nil", (*nilCipher)(nil), false},
+		{"cipher", &nilCipher{}, true},
+	}
+	for _, tt := range tests {
+		if got := hasCipher(&cipherConnection{cipher: tt.cipher}); got != tt.want {
+			t.Errorf("hasCipher() %s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
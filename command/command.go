@@ -28,8 +28,10 @@ func (c *Command) UsageLine() string               { return "" }
 func (c *Command) Runnable() bool                  { return false }
 func (c *Command) Parent() protocol.Command        { return c.parent }
 func (c *Command) SubCommands() []protocol.Command { return c.subCommands }
+
+// SubCommand return the sub command by exact name or alias. Use Suggestions() for mistyped names,
+// due to run a command by a guess is not safe.
 func (c *Command) SubCommand(name string) protocol.Command {
-	// TODO::: intelligent suggestion or correction
 	for _, cmd := range c.subCommands {
 		if cmd.Name() == name {
 			return cmd
@@ -0,0 +1,267 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// CompletionCommand is the "completion" command that print the shell completion script of its root command tree.
+// Add it as sub command of the root command e.g. "<app> completion bash > /etc/bash_completion.d/<app>"
+type CompletionCommand struct {
+	Command
+	detail.DetailsContainer
+	mediatype.MT
+}
+
+func (c *CompletionCommand) Init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=command; name=completion")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Completion",
+		"Print the shell completion script of the commands for bash, zsh or fish",
+		"",
+		"Load it in the current shell by 'source <(app completion bash)' or save it in the shell completions directory",
+		nil)
+	c.Command.Init(parent)
+}
+
+//libgo:impl protocol.Command
+func (c *CompletionCommand) Name() string               { return "completion" }
+func (c *CompletionCommand) Aliases() []string          { return nil }
+func (c *CompletionCommand) UsageLine() string          { return "completion {bash | zsh | fish}" }
+func (c *CompletionCommand) Runnable() bool             { return true }
+func (c *CompletionCommand) Request() []protocol.Field  { return nil }
+func (c *CompletionCommand) Response() []protocol.Field { return nil }
+func (c *CompletionCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	if len(arguments) != 1 {
+		Help(os.Stderr, c)
+		return &ErrShellNotSupported
+	}
+	return Completion(os.Stdout, Root(c), arguments[0])
+}
+
+//libgo:impl Completer
+func (c *CompletionCommand) Completions() []string { return []string{"bash", "zsh", "fish"} }
+
+// Completion write the completion script of the command tree for the shell that can be "bash", "zsh" or "fish".
+// Scripts complete sub commands, "help" and flags by the path of the typed commands.
+func Completion(w io.Writer, root protocol.Command, shell string) (err protocol.Error) {
+	var nodes = completionNodes(root)
+	var funcName = "_" + shellIdentifier(root.Name()) + "_completion"
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		bashCompletion(&b, root.Name(), funcName, nodes)
+	case "zsh":
+		zshCompletion(&b, root.Name(), funcName, nodes)
+	case "fish":
+		fishCompletion(&b, root.Name(), funcName, nodes)
+	default:
+		return &ErrShellNotSupported
+	}
+	io.WriteString(w, b.String())
+	return
+}
+
+// Completer is implemented by commands that accept arguments other than flags to complete them
+// e.g. shell names of the completion command.
+type Completer interface {
+	Completions() []string
+}
+
+// completionNode is a command in the tree by all paths that can type to reach it by names and aliases.
+type completionNode struct {
+	paths     []string
+	commands  []protocol.Command
+	flags     []protocol.Field
+	arguments []string
+}
+
+func completionNodes(root protocol.Command) (nodes []completionNode) {
+	var walk func(c protocol.Command, paths []string)
+	walk = func(c protocol.Command, paths []string) {
+		var node = completionNode{
+			paths:    paths,
+			commands: c.SubCommands(),
+			flags:    c.Request(),
+		}
+		if completer, ok := c.(Completer); ok {
+			node.arguments = completer.Completions()
+		}
+		nodes = append(nodes, node)
+		for _, sub := range c.SubCommands() {
+			var subPaths = make([]string, 0, len(paths)*(1+len(sub.Aliases())))
+			for _, path := range paths {
+				subPaths = append(subPaths, path+" "+sub.Name())
+				for _, alias := range sub.Aliases() {
+					subPaths = append(subPaths, path+" "+alias)
+				}
+			}
+			walk(sub, subPaths)
+		}
+	}
+	walk(root, []string{root.Name()})
+	return
+}
+
+// words return the words to complete after the node path.
+func (n *completionNode) words() (words []string) {
+	for _, c := range n.commands {
+		words = append(words, c.Name())
+	}
+	if len(n.commands) > 0 {
+		words = append(words, "help")
+	}
+	words = append(words, n.arguments...)
+	for _, flag := range n.flags {
+		words = append(words, "--"+flag.Name())
+		if flag.Abbreviation() != "" {
+			words = append(words, "-"+flag.Abbreviation())
+		}
+	}
+	return
+}
+
+func bashCompletion(b *strings.Builder, rootName, funcName string, nodes []completionNode) {
+	fmt.Fprintf(b, "# bash completion for %s\n\n", rootName)
+	fmt.Fprintf(b, "%s() {\n", funcName)
+	fmt.Fprintf(b, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" cmdpath=%s word words i\n", shellQuote(rootName))
+	b.WriteString("\tfor ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("\t\tword=\"${COMP_WORDS[i]}\"\n")
+	b.WriteString("\t\t[[ \"$word\" == -* ]] && continue\n")
+	b.WriteString("\t\tcmdpath=\"$cmdpath $word\"\n")
+	b.WriteString("\tdone\n")
+	b.WriteString("\tcase \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(b, "\t%s) words=%s ;;\n", shellPatterns(node.paths, "|"), shellQuote(strings.Join(node.words(), " ")))
+	}
+	b.WriteString("\tesac\n")
+	b.WriteString("\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "complete -F %s %s\n", funcName, rootName)
+}
+
+func zshCompletion(b *strings.Builder, rootName, funcName string, nodes []completionNode) {
+	fmt.Fprintf(b, "#compdef %s\n\n", rootName)
+	fmt.Fprintf(b, "%s() {\n", funcName)
+	fmt.Fprintf(b, "\tlocal cmdpath=%s word\n", shellQuote(rootName))
+	b.WriteString("\tlocal -a items\n")
+	b.WriteString("\tfor word in ${words[2,CURRENT-1]}; do\n")
+	b.WriteString("\t\t[[ $word == -* ]] && continue\n")
+	b.WriteString("\t\tcmdpath=\"$cmdpath $word\"\n")
+	b.WriteString("\tdone\n")
+	b.WriteString("\tcase \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		var items []string
+		for _, c := range node.commands {
+			items = append(items, zshItem(c.Name(), summary(c)))
+		}
+		if len(node.commands) > 0 {
+			items = append(items, zshItem("help", localeHelpText().HelpCommand))
+		}
+		for _, argument := range node.arguments {
+			items = append(items, zshItem(argument, ""))
+		}
+		for _, flag := range node.flags {
+			items = append(items, zshItem("--"+flag.Name(), summary(flag)))
+			if flag.Abbreviation() != "" {
+				items = append(items, zshItem("-"+flag.Abbreviation(), summary(flag)))
+			}
+		}
+		fmt.Fprintf(b, "\t(%s) items=(%s) ;;\n", shellPatterns(node.paths, "|"), strings.Join(items, " "))
+	}
+	b.WriteString("\tesac\n")
+	b.WriteString("\t_describe 'command' items\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(b, "compdef %s %s\n", funcName, rootName)
+}
+
+func fishCompletion(b *strings.Builder, rootName, funcName string, nodes []completionNode) {
+	fmt.Fprintf(b, "# fish completion for %s\n\n", rootName)
+	fmt.Fprintf(b, "function %s_path\n", funcName)
+	b.WriteString("\tset -l tokens (commandline -opc)\n")
+	b.WriteString("\tset -e tokens[1]\n")
+	fmt.Fprintf(b, "\tset -l cmdpath %s\n", fishQuote(rootName))
+	b.WriteString("\tfor token in $tokens\n")
+	b.WriteString("\t\tstring match -q -- '-*' $token; and continue\n")
+	b.WriteString("\t\tset cmdpath \"$cmdpath $token\"\n")
+	b.WriteString("\tend\n")
+	b.WriteString("\tcontains -- $cmdpath $argv\n")
+	b.WriteString("end\n\n")
+	fmt.Fprintf(b, "complete -c %s -f\n", rootName)
+	for _, node := range nodes {
+		var condition = fishQuote(funcName + "_path " + shellPatterns(node.paths, " "))
+		for _, c := range node.commands {
+			fmt.Fprintf(b, "complete -c %s -n %s -a %s%s\n", rootName, condition, fishQuote(c.Name()), fishDescription(summary(c)))
+		}
+		if len(node.commands) > 0 {
+			fmt.Fprintf(b, "complete -c %s -n %s -a help%s\n", rootName, condition, fishDescription(localeHelpText().HelpCommand))
+		}
+		if len(node.arguments) > 0 {
+			fmt.Fprintf(b, "complete -c %s -n %s -a %s\n", rootName, condition, fishQuote(strings.Join(node.arguments, " ")))
+		}
+		for _, flag := range node.flags {
+			fmt.Fprintf(b, "complete -c %s -n %s -l %s", rootName, condition, fishQuote(flag.Name()))
+			if flag.Abbreviation() != "" {
+				fmt.Fprintf(b, " -s %s", fishQuote(flag.Abbreviation()))
+			}
+			if flag.Type() != protocol.FieldType_Boolean {
+				b.WriteString(" -r")
+			}
+			b.WriteString(fishDescription(summary(flag)))
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// shellPatterns return the paths as double quoted words separated by sep e.g. "|" for case patterns.
+func shellPatterns(paths []string, sep string) string {
+	var patterns = make([]string, len(paths))
+	for i, path := range paths {
+		patterns[i] = `"` + path + `"`
+	}
+	return strings.Join(patterns, sep)
+}
+
+// shellQuote quote s in single quotes for bash and zsh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshItem return the quoted item of _describe as "name:description" or just name if no description.
+func zshItem(name, description string) string {
+	if description == "" {
+		return shellQuote(name)
+	}
+	return shellQuote(name + ":" + description)
+}
+
+// fishDescription return the description option of fish complete command if description isn't empty.
+func fishDescription(description string) string {
+	if description == "" {
+		return ""
+	}
+	return " -d " + fishQuote(description)
+}
+
+// fishQuote quote s in single quotes for fish that escape backslash and single quote in them.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// shellIdentifier replace any character that can't be in a shell function name with underscore.
+func shellIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,145 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func TestCompletion(t *testing.T) {
+	var root, _, _, _ = testTree()
+
+	// Help summary is in the locale files, so it is empty if test run without lang_* tags.
+	var helpItem = "'help'"
+	if text := localeHelpText().HelpCommand; text != "" {
+		helpItem = shellQuote("help:" + text)
+	}
+
+	var tests = []struct {
+		shell string
+		want  string
+	}{
+		{"bash", `# bash completion for app
+
+_app_completion() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" cmdpath='app' word words i
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		word="${COMP_WORDS[i]}"
+		[[ "$word" == -* ]] && continue
+		cmdpath="$cmdpath $word"
+	done
+	case "$cmdpath" in
+	"app") words='log completion help' ;;
+	"app log"|"app logs") words='tail help' ;;
+	"app log tail"|"app logs tail") words='--lines -n --follow -f' ;;
+	"app completion") words='bash zsh fish' ;;
+	esac
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+
+complete -F _app_completion app
+`},
+		{"zsh", `#compdef app
+
+_app_completion() {
+	local cmdpath='app' word
+	local -a items
+	for word in ${words[2,CURRENT-1]}; do
+		[[ $word == -* ]] && continue
+		cmdpath="$cmdpath $word"
+	done
+	case "$cmdpath" in
+	("app") items=('log:Show logs of the app' 'completion:Completion' ` + helpItem + `) ;;
+	("app log"|"app logs") items=('tail:Print the last logs of the app'\''s nodes' ` + helpItem + `) ;;
+	("app log tail"|"app logs tail") items=('--lines:Number of the last lines' '-n:Number of the last lines' '--follow:Wait for new logs' '-f:Wait for new logs') ;;
+	("app completion") items=('bash' 'zsh' 'fish') ;;
+	esac
+	_describe 'command' items
+}
+
+compdef _app_completion app
+`},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Completion(&b, root, tt.shell); err != nil {
+			t.Errorf("Completion(%s) error = %v", tt.shell, err)
+			continue
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("Completion(%s) =\n%s\nwant\n%s", tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionFish(t *testing.T) {
+	var root, _, _, _ = testTree()
+	var b strings.Builder
+	if err := Completion(&b, root, "fish"); err != nil {
+		t.Fatalf("Completion(fish) error = %v", err)
+	}
+	var got = b.String()
+	for _, want := range []string{
+		"complete -c app -f\n",
+		`complete -c app -n '_app_completion_path "app"' -a 'log' -d 'Show logs of the app'` + "\n",
+		`complete -c app -n '_app_completion_path "app log" "app logs"' -a 'tail' -d 'Print the last logs of the app\'s nodes'` + "\n",
+		`complete -c app -n '_app_completion_path "app log tail" "app logs tail"' -l 'lines' -s 'n' -r -d 'Number of the last lines'` + "\n",
+		`complete -c app -n '_app_completion_path "app log tail" "app logs tail"' -l 'follow' -s 'f' -d 'Wait for new logs'` + "\n",
+		`complete -c app -n '_app_completion_path "app completion"' -a 'bash zsh fish'` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Completion(fish) =\n%s\nwant line %s", got, want)
+		}
+	}
+}
+
+func TestCompletionShell(t *testing.T) {
+	var root, _, _, _ = testTree()
+	var tests = []struct {
+		shell string
+		err   protocol.Error
+	}{
+		{"bash", nil},
+		{"zsh", nil},
+		{"fish", nil},
+		{"powershell", &ErrShellNotSupported},
+		{"", &ErrShellNotSupported},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Completion(&b, root, tt.shell); err != tt.err {
+			t.Errorf("Completion(%q) error = %v, want %v", tt.shell, err, tt.err)
+		}
+		if tt.err != nil && b.Len() != 0 {
+			t.Errorf("Completion(%q) wrote %q for not supported shell", tt.shell, b.String())
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	var tests = []struct {
+		s         string
+		want      string
+		wantFish  string
+		wantIdent string
+	}{
+		{"app", `'app'`, `'app'`, "app"},
+		{"app's", `'app'\''s'`, `'app\'s'`, "app_s"},
+		{`a\b`, `'a\b'`, `'a\\b'`, "a_b"},
+		{"my-app.v2", `'my-app.v2'`, `'my-app.v2'`, "my_app_v2"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.s); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.s, got, tt.want)
+		}
+		if got := fishQuote(tt.s); got != tt.wantFish {
+			t.Errorf("fishQuote(%q) = %s, want %s", tt.s, got, tt.wantFish)
+		}
+		if got := shellIdentifier(tt.s); got != tt.wantIdent {
+			t.Errorf("shellIdentifier(%q) = %s, want %s", tt.s, got, tt.wantIdent)
+		}
+	}
+}
@@ -6,20 +6,27 @@ import (
 	er "github.com/GeniusesGroup/libgo/error"
 )
 
+const domainEnglish = "Command"
+
 // Errors
 var (
 	ErrServiceNotFound     er.Error
 	ErrServiceNotAcceptCLI er.Error
+	ErrShellNotSupported   er.Error
 
 	ErrFlagNotFound        er.Error
 	ErrFlagBadSyntax       er.Error
 	ErrFlagNeedsAnArgument er.Error
+	ErrFlagBadValue        er.Error
 )
 
 func init() {
+	ErrServiceNotFound.Init("domain/geniuses.group; type=error; package=command; name=service-not_found")
 	ErrServiceNotAcceptCLI.Init("domain/geniuses.group; type=error; package=command; name=service-not-accept-cli")
+	ErrShellNotSupported.Init("domain/geniuses.group; type=error; package=command; name=shell-not_supported")
 
 	ErrFlagNotFound.Init("domain/geniuses.group; type=error; package=command; name=flag-not_found")
 	ErrFlagBadSyntax.Init("domain/geniuses.group; type=error; package=command; name=flag-bad_syntax")
 	ErrFlagNeedsAnArgument.Init("domain/geniuses.group; type=error; package=command; name=flag-needs_an_arguments")
+	ErrFlagBadValue.Init("domain/geniuses.group; type=error; package=command; name=flag-bad_value")
 }
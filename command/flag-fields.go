@@ -0,0 +1,116 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Flag implement protocol.Field methods that are same for all command-line flags.
+// Embed it to the typed flags that implement the value related methods.
+type Flag struct {
+	detail.DetailsContainer
+
+	name         string
+	abbreviation string
+}
+
+// Init set the flag name e.g. "lines" and its abbreviation e.g. "n" that can be empty.
+func (f *Flag) Init(name, abbreviation string) {
+	f.name = name
+	f.abbreviation = abbreviation
+}
+
+//libgo:impl protocol.Field
+func (f *Flag) Name() string             { return f.name }
+func (f *Flag) Abbreviation() string     { return f.abbreviation }
+func (f *Flag) Optional() bool           { return true }
+func (f *Flag) Immutable() bool          { return false }
+func (f *Flag) Atomic() bool             { return false }
+func (f *Flag) Validate() protocol.Error { return nil }
+
+// StringFlag is a command-line flag with string value.
+type StringFlag struct {
+	Flag
+	Value   string
+	Default string
+}
+
+func (f *StringFlag) Init(name, abbreviation, defaultValue string) {
+	f.Flag.Init(name, abbreviation)
+	f.Default = defaultValue
+	f.Value = defaultValue
+}
+
+//libgo:impl protocol.Field
+func (f *StringFlag) Type() protocol.FieldType { return protocol.FieldType_Array }
+func (f *StringFlag) Size() int                { return len(f.Value) }
+func (f *StringFlag) SetDefault()              { f.Value = f.Default }
+func (f *StringFlag) String() string           { return f.Value }
+
+//libgo:impl Value
+func (f *StringFlag) FromString(value string) (err protocol.Error) {
+	f.Value = value
+	return
+}
+
+// BooleanFlag is a command-line flag with boolean value that can set without value e.g. "-f" same as "-f=true".
+type BooleanFlag struct {
+	Flag
+	Value   bool
+	Default bool
+}
+
+func (f *BooleanFlag) Init(name, abbreviation string, defaultValue bool) {
+	f.Flag.Init(name, abbreviation)
+	f.Default = defaultValue
+	f.Value = defaultValue
+}
+
+//libgo:impl protocol.Field
+func (f *BooleanFlag) Type() protocol.FieldType { return protocol.FieldType_Boolean }
+func (f *BooleanFlag) Size() int                { return 1 }
+func (f *BooleanFlag) SetDefault()              { f.Value = f.Default }
+func (f *BooleanFlag) String() string           { return strconv.FormatBool(f.Value) }
+
+//libgo:impl Value
+func (f *BooleanFlag) FromString(value string) (err protocol.Error) {
+	var goErr error
+	f.Value, goErr = strconv.ParseBool(value)
+	if goErr != nil {
+		err = &ErrFlagBadValue
+	}
+	return
+}
+
+// IntegerFlag is a command-line flag with signed integer value.
+type IntegerFlag struct {
+	Flag
+	Value   int64
+	Default int64
+}
+
+func (f *IntegerFlag) Init(name, abbreviation string, defaultValue int64) {
+	f.Flag.Init(name, abbreviation)
+	f.Default = defaultValue
+	f.Value = defaultValue
+}
+
+//libgo:impl protocol.Field
+func (f *IntegerFlag) Type() protocol.FieldType { return protocol.FieldType_Integer }
+func (f *IntegerFlag) Size() int                { return 8 }
+func (f *IntegerFlag) SetDefault()              { f.Value = f.Default }
+func (f *IntegerFlag) String() string           { return strconv.FormatInt(f.Value, 10) }
+
+//libgo:impl Value
+func (f *IntegerFlag) FromString(value string) (err protocol.Error) {
+	var goErr error
+	f.Value, goErr = strconv.ParseInt(value, 10, 64)
+	if goErr != nil {
+		err = &ErrFlagBadValue
+	}
+	return
+}
@@ -8,6 +8,13 @@ import (
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
+// Value is a field that can decode its value from the command-line text format.
+// Fields that don't implement it can't set by a flag. StringFlag, BooleanFlag and IntegerFlag implement it.
+type Value interface {
+	protocol.Field
+	FromString(value string) protocol.Error
+}
+
 // A FlagSet represents a set of defined fields
 //
 // field names must be unique within a FlagSet. An attempt to define a flag whose
@@ -18,6 +25,18 @@ type FlagSet struct {
 	args   []string // arguments after flags
 }
 
+// ParseFlags set fields to their default values and then parse arguments to them.
+// It is the common way a command parse its Request() fields in ServeCLA().
+func ParseFlags(fields []protocol.Field, arguments []string) (err protocol.Error) {
+	for _, field := range fields {
+		field.SetDefault()
+	}
+	var flags FlagSet
+	flags.Init(fields, arguments)
+	err = flags.Parse()
+	return
+}
+
 // argument list should not include the commands name.
 func (f *FlagSet) Init(fields []protocol.Field, arguments []string) {
 	f.fields = fields
@@ -114,7 +133,11 @@ func (f *FlagSet) Set(name, value string) (err protocol.Error) {
 		}
 	}
 
-	err = flag.FromString(value)
+	var flagValue, ok = flag.(Value)
+	if !ok {
+		return &ErrFlagBadValue
+	}
+	err = flagValue.FromString(value)
 	if err != nil {
 		return
 	}
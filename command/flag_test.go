@@ -0,0 +1,55 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// plainField is a field that don't implement Value, so it can't set by a flag.
+type plainField struct{ Flag }
+
+func (f *plainField) Type() protocol.FieldType { return protocol.FieldType_Array }
+func (f *plainField) Size() int                { return 0 }
+func (f *plainField) SetDefault()              {}
+func (f *plainField) String() string           { return "" }
+
+func TestParseFlags(t *testing.T) {
+	var text StringFlag
+	text.Init("text", "t", "default")
+	var follow BooleanFlag
+	follow.Init("follow", "f", false)
+	var lines IntegerFlag
+	lines.Init("lines", "n", 10)
+	var fields = []protocol.Field{&text, &follow, &lines}
+
+	var err = ParseFlags(fields, []string{"-t=hello", "-f", "--lines", "25"})
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if text.Value != "hello" || !follow.Value || lines.Value != 25 {
+		t.Errorf("ParseFlags() = %q, %v, %d, want %q, %v, %d", text.Value, follow.Value, lines.Value, "hello", true, 25)
+	}
+
+	err = ParseFlags(fields, nil)
+	if err != nil {
+		t.Fatalf("ParseFlags() error = %v", err)
+	}
+	if text.Value != "default" || follow.Value || lines.Value != 10 {
+		t.Errorf("ParseFlags() defaults = %q, %v, %d, want %q, %v, %d", text.Value, follow.Value, lines.Value, "default", false, 10)
+	}
+
+	err = ParseFlags(fields, []string{"-n=ten"})
+	if err != &ErrFlagBadValue {
+		t.Errorf("ParseFlags() bad integer error = %v, want %v", err, &ErrFlagBadValue)
+	}
+
+	var plain plainField
+	plain.Init("plain", "")
+	err = ParseFlags([]protocol.Field{&plain}, []string{"-plain=value"})
+	if err != &ErrFlagBadValue {
+		t.Errorf("ParseFlags() not Value field error = %v, want %v", err, &ErrFlagBadValue)
+	}
+}
@@ -0,0 +1,161 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// helpText is the locale texts of the generated help that locale files register by their language.
+type helpText struct {
+	Usage          string
+	Aliases        string
+	Commands       string
+	Flags          string
+	Default        string
+	MoreInfo       string // format with the command path e.g. "Run '%s help <command>' for more information about a command."
+	UnknownCommand string // format with the unknown command and the command path
+	DidYouMean     string
+	HelpCommand    string // summary of the help pseudo command
+}
+
+var helpTexts = map[protocol.LanguageID]*helpText{}
+
+// localeHelpText return help texts in the app language, or in English if not exist.
+func localeHelpText() (text *helpText) {
+	text = helpTexts[protocol.AppLanguage]
+	if text == nil {
+		text = helpTexts[protocol.LanguageEnglish]
+	}
+	if text == nil {
+		for _, t := range helpTexts {
+			return t
+		}
+		// App build without any lang_* tag, so just keep format strings valid.
+		text = &helpText{MoreInfo: "%s help <command>", UnknownCommand: "%q: %s"}
+	}
+	return
+}
+
+// localeDetail return the detail in the app language, or in English or first one if not exist.
+func localeDetail(details protocol.Details) protocol.Detail {
+	var detail = details.Detail(protocol.AppLanguage)
+	if detail == nil {
+		detail = details.Detail(protocol.LanguageEnglish)
+	}
+	if detail == nil {
+		var all = details.Details()
+		if len(all) > 0 {
+			detail = all[0]
+		}
+	}
+	return detail
+}
+
+func summary(details protocol.Details) string {
+	var detail = localeDetail(details)
+	if detail == nil {
+		return ""
+	}
+	return detail.Summary()
+}
+
+// Help write the generated help of the command from its details, usage line, aliases, sub commands and flags.
+func Help(w io.Writer, c protocol.Command) {
+	var text = localeHelpText()
+	var b strings.Builder
+
+	if detail := localeDetail(c); detail != nil {
+		b.WriteString(detail.Summary())
+		b.WriteString("\n\n")
+		if overview := detail.Overview(); overview != "" && overview != detail.Summary() {
+			b.WriteString(overview)
+			b.WriteString("\n\n")
+		}
+	}
+
+	// UsageLine is the command path without the root name e.g. "log tail [-n=lines]" as protocol suggest by "<app> ...",
+	// so add the root name to it.
+	var usageLine = c.UsageLine()
+	if usageLine == "" {
+		usageLine = CommandPath(c)
+	} else if rootName := Root(c).Name(); !strings.HasPrefix(usageLine, rootName+" ") && c.Parent() != nil {
+		usageLine = rootName + " " + usageLine
+	}
+	fmt.Fprintf(&b, "%s:\n\t%s\n", text.Usage, usageLine)
+
+	if aliases := c.Aliases(); len(aliases) > 0 {
+		fmt.Fprintf(&b, "\n%s:\n\t%s\n", text.Aliases, strings.Join(aliases, ", "))
+	}
+
+	var subCommands = c.SubCommands()
+	if len(subCommands) > 0 {
+		fmt.Fprintf(&b, "\n%s:\n", text.Commands)
+		var width = 0
+		for _, sub := range subCommands {
+			if len(sub.Name()) > width {
+				width = len(sub.Name())
+			}
+		}
+		for _, sub := range subCommands {
+			var line = fmt.Sprintf("\t%-*s  %s", width, sub.Name(), summary(sub))
+			b.WriteString(strings.TrimRight(line, " "))
+			b.WriteByte('\n')
+		}
+	}
+
+	var flags = c.Request()
+	if len(flags) > 0 {
+		fmt.Fprintf(&b, "\n%s:\n", text.Flags)
+		var names = make([]string, len(flags))
+		var width = 0
+		for i, flag := range flags {
+			names[i] = flagUsage(flag)
+			if len(names[i]) > width {
+				width = len(names[i])
+			}
+		}
+		for i, flag := range flags {
+			var line = fmt.Sprintf("\t%-*s  %s", width, names[i], summary(flag))
+			flag.SetDefault()
+			if def := flag.String(); (def != "" && flag.Type() != protocol.FieldType_Boolean) || def == "true" {
+				line += fmt.Sprintf(" (%s %q)", text.Default, def)
+			}
+			b.WriteString(strings.TrimRight(line, " "))
+			b.WriteByte('\n')
+		}
+	}
+
+	if len(subCommands) > 0 {
+		b.WriteByte('\n')
+		fmt.Fprintf(&b, text.MoreInfo, CommandPath(c))
+		b.WriteByte('\n')
+	}
+	io.WriteString(w, b.String())
+}
+
+// flagUsage return the flag names and its value type e.g. "-n, --lines int" or "    --follow" for a boolean flag.
+func flagUsage(flag protocol.Field) string {
+	var usage = "    --" + flag.Name()
+	if abbreviation := flag.Abbreviation(); abbreviation != "" {
+		usage = "-" + abbreviation + ", --" + flag.Name()
+	}
+	switch flag.Type() {
+	case protocol.FieldType_Boolean:
+	case protocol.FieldType_Integer:
+		usage += " int"
+	case protocol.FieldType_Natural, protocol.FieldType_Whole:
+		usage += " uint"
+	case protocol.FieldType_Rational, protocol.FieldType_Real:
+		usage += " float"
+	case protocol.FieldType_Array:
+		usage += " string"
+	default:
+		usage += " value"
+	}
+	return usage
+}
@@ -5,16 +5,37 @@
 package cmd
 
 import (
-	er "github.com/GeniusesGroup/libgo/error"
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
-const domainEnglish = "Command"
-
 func init() {
+	helpTexts[protocol.LanguageEnglish] = &helpText{
+		Usage:          "Usage",
+		Aliases:        "Aliases",
+		Commands:       "Commands",
+		Flags:          "Flags",
+		Default:        "default",
+		MoreInfo:       "Run '%s help <command>' for more information about a command.",
+		UnknownCommand: "Unknown command %q for '%s'.",
+		DidYouMean:     "Did you mean this?",
+		HelpCommand:    "Print help of the command",
+	}
+
+	ErrServiceNotFound.SetDetail(protocol.LanguageEnglish, domainEnglish, "Service Not Found",
+		"Requested command not found in the sub commands",
+		"Run the help command to see the list of the commands",
+		"",
+		nil)
+
 	ErrServiceNotAcceptCLI.SetDetail(protocol.LanguageEnglish, domainEnglish, "Service Not Accept CLI",
 		"Requested service not accept CLI protocol in this server",
 		"Try other server or contact support of the software",
 		"It is so easy to implement CLI handler for a service! Take a time and do it!",
 		nil)
+
+	ErrShellNotSupported.SetDetail(protocol.LanguageEnglish, domainEnglish, "Shell Not Supported",
+		"Completion script can't generate for the requested shell",
+		"Use one of bash, zsh or fish shells",
+		"",
+		nil)
 }
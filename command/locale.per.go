@@ -5,16 +5,39 @@
 package cmd
 
 import (
-	er "github.com/GeniusesGroup/libgo/error"
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
 const domainPersian = "فرمان"
 
 func init() {
+	helpTexts[protocol.LanguagePersian] = &helpText{
+		Usage:          "نحوه استفاده",
+		Aliases:        "نام های دیگر",
+		Commands:       "فرمان ها",
+		Flags:          "پرچم ها",
+		Default:        "پیش فرض",
+		MoreInfo:       "برای اطلاعات بیشتر درباره هر فرمان '%s help <command>' را اجرا کنید.",
+		UnknownCommand: "فرمان %q در '%s' یافت نشد.",
+		DidYouMean:     "منظور شما این بود؟",
+		HelpCommand:    "نمایش راهنمای فرمان",
+	}
+
+	ErrServiceNotFound.SetDetail(protocol.LanguagePersian, domainPersian, "فرمان یافت نشد",
+		"فرمان درخواست شده در فرمان های زیرمجموعه یافت نشد",
+		"برای دیدن فهرست فرمان ها فرمان راهنما را اجرا کنید",
+		"",
+		nil)
+
 	ErrServiceNotAcceptCLI.SetDetail(protocol.LanguagePersian, domainPersian, "پروتکل CLI پشتیبانی نمی شود",
 		"درخواست برای سرویس مدنظر بدلیل عدم پشتیبانی پروتکل مورد نیاز قابلیت انجام روی سرور فعلی را ندارد",
 		"سرور دیگر را امتحان کنید یا با پشتیبانی پلتفرم تماس بگیرید",
 		"پیاده سازی این پروتکل برای پاسخ گویی به سرویس ها به شدت ساده است، وقتی برای پیاده سازی اختصاص دهید",
 		nil)
+
+	ErrShellNotSupported.SetDetail(protocol.LanguagePersian, domainPersian, "پوسته پشتیبانی نمی شود",
+		"اسکریپت تکمیل خودکار برای پوسته درخواست شده قابل تولید نیست",
+		"از یکی از پوسته های bash، zsh یا fish استفاده کنید",
+		"",
+		nil)
 }
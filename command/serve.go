@@ -5,10 +5,15 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
+// ServeCLA serve the arguments by the sub command that name or alias of it is the first argument.
+// "help", "-h" and "--help" print the generated help of the command or of its sub commands in next arguments
+// e.g. "<app> help log tail", unless the command has a custom help sub command.
+// Unknown sub commands print the suggested sub commands that the user may mean.
 func ServeCLA(c protocol.Command, arguments []string) (err protocol.Error) {
 	var serviceName string
 	if len(arguments) > 0 {
@@ -21,47 +26,89 @@ func ServeCLA(c protocol.Command, arguments []string) (err protocol.Error) {
 	var command protocol.Command = c.SubCommand(serviceName)
 	if command == nil {
 		// We don't find any related command even custom help, so print auto generated help.
-		if serviceName == "help" || serviceName == "-h" || serviceName == "--help" {
-			// helpMessage()
-			// Accept 'go mod help' and 'go mod help foo' for 'go help mod' and 'go help mod foo'.
-			// help.Help(os.Stdout, append(strings.Split(cfg.CmdName, " "), args[1:]...))
-		} else {
-			fmt.Fprintf(os.Stderr, "unknown command\nRun '%s help' for usage.\n", CommandPath(c))
-			err = &ErrServiceNotFound
+		if isHelp(serviceName) {
+			// Accept '<app> log help tail' same as '<app> help log tail'.
+			var helpArguments []string
+			if len(arguments) > 1 {
+				helpArguments = arguments[1:]
+			}
+			return serveHelp(c, helpArguments)
 		}
-		return
-	} else if command.Name() != serviceName {
-		fmt.Fprintf(os.Stderr, "Do you mean '%s %s'?\n", CommandPath(c), command.Name())
-		// TODO:::
-		return
+		unknownCommand(c, serviceName)
+		return &ErrServiceNotFound
 	}
 
-	err = command.ServeCLA(arguments[1:])
+	var commandArguments = arguments[1:]
+	// Let '<app> log tail -h' print the help of the tail command if it doesn't have its own help flag.
+	if len(commandArguments) == 1 && isHelp(commandArguments[0]) && commandArguments[0] != "help" && !hasHelpFlag(command) {
+		Help(os.Stdout, command)
+		return
+	}
+	err = command.ServeCLA(commandArguments)
 	return
 }
 
-// Root finds root command. or return nil if it is the root
-func Root(c protocol.Command) (root protocol.Command) {
-	for {
-		if root.Parent() != nil {
-			root = root.Parent()
-		} else {
-			break
+func isHelp(argument string) bool {
+	return argument == "help" || argument == "-h" || argument == "--help"
+}
+
+func hasHelpFlag(c protocol.Command) bool {
+	for _, flag := range c.Request() {
+		if flag.Name() == "help" || flag.Abbreviation() == "h" {
+			return true
+		}
+	}
+	return false
+}
+
+// serveHelp print the help of the sub command in the arguments path from the command.
+func serveHelp(c protocol.Command, arguments []string) (err protocol.Error) {
+	for _, name := range arguments {
+		var sub = c.SubCommand(name)
+		if sub == nil {
+			unknownCommand(c, name)
+			return &ErrServiceNotFound
 		}
+		c = sub
 	}
+	Help(os.Stdout, c)
 	return
 }
 
-// CommandPath returns the full path to this command exclude itself.
-func CommandPath(command protocol.Command) (fullName string) {
-	for {
-		fullName = command.Name() + " " + fullName
-		command = command.Parent()
-		if command == nil {
-			break
+func unknownCommand(c protocol.Command, name string) {
+	var text = localeHelpText()
+	var commandPath = CommandPath(c)
+	fmt.Fprintf(os.Stderr, text.UnknownCommand, name, commandPath)
+	os.Stderr.WriteString("\n")
+	var suggestions = Suggestions(c, name)
+	if len(suggestions) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%s\n", text.DidYouMean)
+		for _, suggestion := range suggestions {
+			fmt.Fprintf(os.Stderr, "\t%s %s\n", commandPath, suggestion)
 		}
 	}
-	// remove trailing space
-	fullName = fullName[:len(fullName)-1]
+	os.Stderr.WriteString("\n")
+	fmt.Fprintf(os.Stderr, text.MoreInfo, commandPath)
+	os.Stderr.WriteString("\n")
+}
+
+// Root finds root command of the given command or return the command itself if it is the root.
+func Root(c protocol.Command) (root protocol.Command) {
+	root = c
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
 	return
 }
+
+// CommandPath returns the full path to this command include itself e.g. "<app> log tail".
+func CommandPath(command protocol.Command) (fullName string) {
+	var names []string
+	for ; command != nil; command = command.Parent() {
+		names = append(names, command.Name())
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,86 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// testCommand is a command with given name, aliases, summary and flags to make command trees in tests.
+type testCommand struct {
+	Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	name    string
+	aliases []string
+	flags   []protocol.Field
+}
+
+func newTestCommand(parent protocol.Command, name, summary string, aliases ...string) (c *testCommand) {
+	c = &testCommand{name: name, aliases: aliases}
+	c.MT.Init("domain/geniuses.group; type=command; package=command; name=test-" + name)
+	if summary != "" {
+		c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish, summary, "", "", "", nil)
+	}
+	c.Command.Init(parent)
+	return
+}
+
+func (c *testCommand) addSubCommands(subs ...protocol.Command) { c.Command.Init(c.Parent(), subs...) }
+
+//libgo:impl protocol.Command
+func (c *testCommand) Name() string                                     { return c.name }
+func (c *testCommand) Aliases() []string                                { return c.aliases }
+func (c *testCommand) UsageLine() string                                { return "" }
+func (c *testCommand) Runnable() bool                                   { return true }
+func (c *testCommand) Request() []protocol.Field                        { return c.flags }
+func (c *testCommand) Response() []protocol.Field                       { return nil }
+func (c *testCommand) ServeCLA(arguments []string) (err protocol.Error) { return }
+
+// testTree return "app" root command with "log" (alias "logs") that has "tail" with flags, and "completion" sub commands.
+func testTree() (root, log, tail *testCommand, completion *CompletionCommand) {
+	root = newTestCommand(nil, "app", "")
+	log = newTestCommand(root, "log", "Show logs of the app", "logs")
+	tail = newTestCommand(log, "tail", "Print the last logs of the app's nodes")
+
+	var lines StringFlag
+	lines.Init("lines", "n", "10")
+	lines.SetDetail(protocol.LanguageEnglish, domainEnglish, "Number of the last lines", "", "", "", nil)
+	var follow BooleanFlag
+	follow.Init("follow", "f", false)
+	follow.SetDetail(protocol.LanguageEnglish, domainEnglish, "Wait for new logs", "", "", "", nil)
+	tail.flags = []protocol.Field{&lines, &follow}
+
+	completion = new(CompletionCommand)
+	completion.Init(root)
+
+	log.addSubCommands(tail)
+	root.addSubCommands(log, completion)
+	return
+}
+
+func TestCommandPath(t *testing.T) {
+	var root, log, tail, completion = testTree()
+	var tests = []struct {
+		command protocol.Command
+		want    string
+	}{
+		{root, "app"},
+		{log, "app log"},
+		{tail, "app log tail"},
+		{completion, "app completion"},
+	}
+	for _, tt := range tests {
+		if got := CommandPath(tt.command); got != tt.want {
+			t.Errorf("CommandPath(%s) = %q, want %q", tt.command.Name(), got, tt.want)
+		}
+		if got := Root(tt.command); got != root {
+			t.Errorf("Root(%s) = %s, want %s", tt.command.Name(), got.Name(), root.Name())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// SuggestionsMaxDistance is the max Damerau-Levenshtein distance of a mistyped command to its suggested sub commands.
+const SuggestionsMaxDistance = 2
+
+// Suggestions return name of the sub commands that the given mistyped name can be, in order of similarity.
+// A sub command suggest if its name or one of its aliases is near to the name or start with it.
+func Suggestions(c protocol.Command, name string) (suggestions []string) {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var found []suggestion
+	var lowerName = strings.ToLower(name)
+	for _, sub := range c.SubCommands() {
+		var best = -1
+		for _, candidate := range append([]string{sub.Name()}, sub.Aliases()...) {
+			candidate = strings.ToLower(candidate)
+			var distance = damerauLevenshtein(lowerName, candidate)
+			if distance > SuggestionsMaxDistance && !strings.HasPrefix(candidate, lowerName) {
+				continue
+			}
+			if best == -1 || distance < best {
+				best = distance
+			}
+		}
+		if best != -1 {
+			found = append(found, suggestion{sub.Name(), best})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].distance < found[j].distance })
+	for _, s := range found {
+		suggestions = append(suggestions, s.name)
+	}
+	return
+}
+
+// damerauLevenshtein return the optimal string alignment distance of a and b that is number of insertions,
+// deletions, substitutions and transpositions of two adjacent characters to change a to b.
+// https://en.wikipedia.org/wiki/Damerau%E2%80%93Levenshtein_distance#Optimal_string_alignment_distance
+func damerauLevenshtein(a, b string) int {
+	var ar, br = []rune(a), []rune(b)
+	// Keep just last three rows of the distance matrix.
+	var prevPrev, prev, current = make([]int, len(br)+1), make([]int, len(br)+1), make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			var cost = 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(prev[j]+1, current[j-1]+1), prev[j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				current[j] = minInt(current[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, current = prev, current, prevPrev
+	}
+	return prev[len(br)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
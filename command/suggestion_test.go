@@ -0,0 +1,75 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDamerauLevenshtein(t *testing.T) {
+	var tests = []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"log", "", 3},
+		{"", "log", 3},
+		{"log", "log", 0},
+		{"log", "lgo", 1},
+		{"ab", "ba", 1},
+		{"build", "biuld", 1},
+		{"completion", "completoin", 1},
+		{"log", "logs", 1},
+		{"log", "lg", 1},
+		{"log", "lug", 1},
+		{"kitten", "sitting", 3},
+		{"abcdef", "badcfe", 3},
+		// Optimal string alignment don't edit a substring twice, so it is 3 not 2 of unrestricted Damerau-Levenshtein.
+		{"ca", "abc", 3},
+		// Distance is by characters not bytes.
+		{"héllo", "hlélo", 1},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := damerauLevenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("damerauLevenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := damerauLevenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("damerauLevenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	var root = newTestCommand(nil, "app", "")
+	root.addSubCommands(
+		newTestCommand(root, "build", ""),
+		newTestCommand(root, "bundle", ""),
+		newTestCommand(root, "log", "", "logs"),
+		newTestCommand(root, "completion", ""),
+	)
+
+	var tests = []struct {
+		name string
+		want []string
+	}{
+		{"biuld", []string{"build"}},
+		{"bxxld", []string{"build"}},        // at max distance
+		{"bxxxd", nil},                      // over max distance
+		{"bu", []string{"build", "bundle"}}, // prefix of both in distance order
+		{"bundel", []string{"bundle"}},      // transposition
+		{"lgo", []string{"log"}},            // transposition
+		{"logz", []string{"log"}},           // near to both name and alias but suggest the name once
+		{"LGO", []string{"log"}},            // case insensitive
+		{"complet", []string{"completion"}}, // prefix over max distance
+		{"xyz", nil},
+		{"", []string{"log", "build", "bundle", "completion"}},
+	}
+	for _, tt := range tests {
+		var got = Suggestions(root, tt.name)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Suggestions(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
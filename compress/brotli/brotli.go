@@ -0,0 +1,51 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package brotli
+
+import (
+	"io"
+
+	compress ".."
+	"../../protocol"
+	// Go standard library has no brotli implementation, so use the pure Go port of the reference C implementation.
+	ebrotli "github.com/andybalholm/brotli"
+)
+
+const (
+	BrotliContentEncoding = "br"
+	BrotliExtension       = "br"
+)
+
+// Brotli is the Brotli (RFC 7932) coding. It use its built-in static dictionary of common words and phrases
+// of text and web content, so it compress small text assets better than gzip with any level.
+// Brotli stream don't have any checksum and truncated stream can't detect always,
+// so transport layer must guarantee the compressed data integrity e.g. by Content-Length.
+var Brotli = brotli{
+	Coding: compress.NewCoding("domain/brotli.protocol.data-structure", BrotliContentEncoding, BrotliExtension, engine{}),
+}
+
+type brotli struct {
+	*compress.Coding
+}
+
+type engine struct{}
+
+// Level map given compress level to brotli quality from 0 to 11.
+// Brotli don't have any store or huffman only mode, so both map to its fastest quality.
+func (engine) Level(level protocol.CompressLevel) int {
+	switch level {
+	case protocol.CompressLevel_Default:
+		return ebrotli.DefaultCompression
+	case protocol.CompressLevel_BestCompression:
+		return ebrotli.BestCompression
+	case protocol.CompressLevel_NoCompression, protocol.CompressLevel_HuffmanOnly:
+		return ebrotli.BestSpeed
+	default:
+		// Other levels from BestSpeed(1) to 8 has same meaning in brotli qualities.
+		return int(level)
+	}
+}
+func (engine) NewWriter(w io.Writer, quality int) compress.WriteResetter {
+	return ebrotli.NewWriterLevel(w, quality)
+}
+func (engine) NewReader(r io.Reader) (compress.ReadResetter, error) { return ebrotli.NewReader(r), nil }
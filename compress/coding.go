@@ -0,0 +1,149 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package compress
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"../protocol"
+)
+
+// Engine is a compression algorithm that a Coding use to make its writers and readers.
+// Each algorithm package implement it for its library and Coding pool and reuse the made writers and readers.
+type Engine interface {
+	// Level map given compress level to the engine level. Given level is in the valid range.
+	Level(level protocol.CompressLevel) int
+	NewWriter(w io.Writer, level int) WriteResetter
+	// NewReader can read the stream header, so error means the compressed data is corrupted or empty.
+	NewReader(r io.Reader) (ReadResetter, error)
+}
+
+// WriteResetter is a compress writer that can reuse for other destination after Reset.
+type WriteResetter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// ReadResetter is a decompress reader that can reuse for other source after Reset.
+type ReadResetter interface {
+	io.Reader
+	Reset(r io.Reader) error
+}
+
+// Coding implement protocol.CompressType methods by an Engine that any compression algorithm can embed it.
+type Coding struct {
+	*CompressType
+	engine Engine
+
+	writerPools sync.Map // engine level as key and *sync.Pool of its writers as value
+	readerPool  sync.Pool
+}
+
+func NewCoding(mediatype, contentEncoding, extension string, engine Engine) (c *Coding) {
+	if engine == nil {
+		panic("Coding doesn't has a valid Engine. Can't make it.")
+	}
+	c = &Coding{
+		CompressType: New(mediatype, contentEncoding, extension),
+		engine:       engine,
+	}
+	return
+}
+
+//libgo:impl protocol.CompressType
+func (c *Coding) Compress(raw protocol.Codec, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
+	var com *Compressor
+	com, err = c.newCompressor(options)
+	if err != nil {
+		return
+	}
+	com.source.InitByCodec(raw)
+	return com, nil
+}
+func (c *Coding) CompressBySlice(raw []byte, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
+	var com *Compressor
+	com, err = c.newCompressor(options)
+	if err != nil {
+		return
+	}
+	com.source.InitBySlice(raw)
+	return com, nil
+}
+func (c *Coding) CompressByReader(raw protocol.Reader, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
+	var com *Compressor
+	com, err = c.newCompressor(options)
+	if err != nil {
+		return
+	}
+	com.source.InitByReader(raw, -1)
+	return com, nil
+}
+
+func (c *Coding) Decompress(compressed protocol.Codec) (raw protocol.Codec, err protocol.Error) {
+	var dc = Decompressor{coding: c}
+	dc.source.InitByCodec(compressed)
+	return &dc, nil
+}
+func (c *Coding) DecompressFromSlice(compressed []byte) (raw protocol.Codec, err protocol.Error) {
+	var dc = Decompressor{coding: c}
+	dc.source.InitBySlice(compressed)
+	return &dc, nil
+}
+func (c *Coding) DecompressFromReader(compressed protocol.Reader, compressedLen int) (raw protocol.Codec, err protocol.Error) {
+	var dc = Decompressor{coding: c}
+	dc.source.InitByReader(compressed, compressedLen)
+	return &dc, nil
+}
+
+func (c *Coding) newCompressor(options protocol.CompressOptions) (com *Compressor, err protocol.Error) {
+	if options.CompressLevel < protocol.CompressLevel_HuffmanOnly || options.CompressLevel > protocol.CompressLevel_BestCompression {
+		return nil, &ErrCompressLevel
+	}
+	com = &Compressor{
+		coding: c,
+		level:  c.engine.Level(options.CompressLevel),
+	}
+	return
+}
+
+// getWriter return a pooled writer of given engine level, so each compress don't allocate new window and hash tables.
+func (c *Coding) getWriter(level int, w io.Writer) (cw WriteResetter) {
+	var pool, _ = c.writerPools.LoadOrStore(level, &sync.Pool{})
+	var pooled = pool.(*sync.Pool).Get()
+	if pooled == nil {
+		return c.engine.NewWriter(w, level)
+	}
+	cw = pooled.(WriteResetter)
+	cw.Reset(w)
+	return
+}
+
+func (c *Coding) putWriter(level int, cw WriteResetter) {
+	// Don't hold the writer destination in the pool.
+	cw.Reset(io.Discard)
+	var pool, _ = c.writerPools.Load(level)
+	pool.(*sync.Pool).Put(cw)
+}
+
+// getReader return a pooled reader, so each decompress don't allocate new window and huffman tables.
+func (c *Coding) getReader(r io.Reader) (cr ReadResetter, goErr error) {
+	var pooled = c.readerPool.Get()
+	if pooled == nil {
+		return c.engine.NewReader(r)
+	}
+	cr = pooled.(ReadResetter)
+	goErr = cr.Reset(r)
+	if goErr != nil {
+		c.putReader(cr)
+		cr = nil
+	}
+	return
+}
+
+func (c *Coding) putReader(cr ReadResetter) {
+	// Don't hold the reader source in the pool. Reset error is expected for empty source.
+	cr.Reset(bytes.NewReader(nil))
+	c.readerPool.Put(cr)
+}
@@ -3,31 +3,44 @@
 package compress
 
 import (
+	"../detail"
 	"../mediatype"
 	"../protocol"
 )
 
+// CompressType implement common methods of protocol.CompressType that any compression algorithm can embed it.
 type CompressType struct {
-	mediatype       *mediatype.MediaType
+	mediatype       mediaType
 	contentEncoding string
 	extension       string
 }
 
-func (ct *CompressType) MediaType() protocol.MediaType { return ct.mediatype }
+func (ct *CompressType) MediaType() protocol.MediaType { return &ct.mediatype }
 func (ct *CompressType) ContentEncoding() string       { return ct.contentEncoding }
 func (ct *CompressType) FileExtension() string         { return ct.extension }
 
-func New(contentEncoding string, mediatype *mediatype.MediaType) (ct *CompressType) {
-	if mediatype == nil {
+func New(mediatype, contentEncoding, extension string) (ct *CompressType) {
+	if mediatype == "" {
 		panic("CompressType doesn't has a valid MediaType. Can't make it.")
 	}
 	if contentEncoding == "" {
 		panic("CompressType doesn't has a valid ContentEncoding. Can't make it.")
 	}
 	ct = &CompressType{
-		mediatype:       mediatype,
 		contentEncoding: contentEncoding,
-		extension:       mediatype.FileExtension(),
+		extension:       extension,
 	}
+	ct.mediatype.Init(mediatype)
+	ct.mediatype.extension = extension
 	return
 }
+
+type mediaType struct {
+	detail.DS
+	mediatype.MT
+	extension string
+}
+
+//libgo:impl protocol.MediaType
+func (m *mediaType) FileExtension() string           { return m.extension }
+func (m *mediaType) Status() protocol.SoftwareStatus { return protocol.Software_PreAlpha }
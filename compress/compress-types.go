@@ -23,28 +23,28 @@ func (cts *CompressTypes) RegisterCompressType(ct protocol.CompressType) {
 func (cts *CompressTypes) GetCompressTypeByID(id uint64) (ct protocol.CompressType, err protocol.Error) {
 	ct = ByID(id)
 	if ct == nil {
-		err = ErrNotFound
+		err = &ErrNotFound
 	}
 	return
 }
 func (cts *CompressTypes) GetCompressTypeByMediaType(mt string) (ct protocol.CompressType, err protocol.Error) {
 	ct = ByMediaType(mt)
 	if ct == nil {
-		err = ErrNotFound
+		err = &ErrNotFound
 	}
 	return
 }
 func (cts *CompressTypes) GetCompressTypeByFileExtension(ex string) (ct protocol.CompressType, err protocol.Error) {
 	ct = ByFileExtension(ex)
 	if ct == nil {
-		err = ErrNotFound
+		err = &ErrNotFound
 	}
 	return
 }
 func (cts *CompressTypes) GetCompressTypeByContentEncoding(ce string) (ct protocol.CompressType, err protocol.Error) {
 	ct = ByContentEncoding(ce)
 	if ct == nil {
-		err = ErrNotFound
+		err = &ErrNotFound
 	}
 	return
 }
@@ -0,0 +1,156 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package compress_test
+
+import (
+	"bytes"
+	eflate "compress/flate"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	compress "."
+	"../protocol"
+	"./brotli"
+	"./flate"
+	"./gzip"
+	"./zstd"
+)
+
+var compressTypes = []protocol.CompressType{&gzip.GZIP, &flate.Deflate, &brotli.Brotli, &zstd.Zstd, &flate.Raw}
+
+// testData return data that is partly compressible like real assets.
+func testData(size int) (data []byte) {
+	data = make([]byte, size)
+	var x uint32 = 2463534242
+	for i := range data {
+		if i%64 < 32 {
+			data[i] = byte('a' + i%26)
+			continue
+		}
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		data[i] = byte(x)
+	}
+	return
+}
+
+func compressSlice(t *testing.T, ct protocol.CompressType, data []byte, level protocol.CompressLevel) (compressed []byte) {
+	var codec, err = ct.CompressBySlice(data, protocol.CompressOptions{CompressLevel: level})
+	if err != nil {
+		t.Fatalf("%s CompressBySlice() error = %v", ct.ContentEncoding(), err)
+	}
+	compressed, err = codec.Marshal()
+	if err != nil {
+		t.Fatalf("%s Compressor.Marshal() error = %v", ct.ContentEncoding(), err)
+	}
+	return
+}
+
+func TestCompressTypesRoundTrip(t *testing.T) {
+	var inputs = [][]byte{
+		{},
+		[]byte("Hello World"),
+		testData(200 * 1024),
+	}
+	var levels = []protocol.CompressLevel{protocol.CompressLevel_Default, protocol.CompressLevel_BestSpeed, protocol.CompressLevel_BestCompression}
+	for _, ct := range compressTypes {
+		for _, level := range levels {
+			for _, input := range inputs {
+				var compressed = compressSlice(t, ct, input, level)
+
+				var dc, err = ct.DecompressFromSlice(compressed)
+				if err != nil {
+					t.Fatalf("%s DecompressFromSlice() error = %v", ct.ContentEncoding(), err)
+				}
+				var decompressed []byte
+				decompressed, err = dc.Marshal()
+				if err != nil {
+					t.Fatalf("%s Decompressor.Marshal() level %d len %d error = %v", ct.ContentEncoding(), level, len(input), err)
+				}
+				if !bytes.Equal(decompressed, input) {
+					t.Errorf("%s round trip level %d len %d not equal, got len %d", ct.ContentEncoding(), level, len(input), len(decompressed))
+				}
+
+				// Decompress part by part to a writer must produce the same data.
+				dc, _ = ct.DecompressFromReader(bytes.NewReader(compressed), len(compressed))
+				var buf bytes.Buffer
+				var _, goErr = dc.(io.WriterTo).WriteTo(&buf)
+				if goErr != nil || !bytes.Equal(buf.Bytes(), input) {
+					t.Errorf("%s Decompressor.WriteTo() len %d error = %v, equal %v", ct.ContentEncoding(), len(input), goErr, bytes.Equal(buf.Bytes(), input))
+				}
+			}
+		}
+	}
+}
+
+func TestCompressTypesTruncated(t *testing.T) {
+	for _, ct := range compressTypes {
+		var compressed = compressSlice(t, ct, testData(100*1024), protocol.CompressLevel_Default)
+		var lengths = []int{1, len(compressed) / 2, len(compressed) - 1}
+		if ct == &brotli.Brotli {
+			// Brotli stream don't have any checksum and its reader report truncate on a meta-block boundary as end of stream.
+			lengths = lengths[:1]
+		}
+		for _, ln := range lengths {
+			var dc, err = ct.DecompressFromSlice(compressed[:ln])
+			if err != nil {
+				t.Fatalf("%s DecompressFromSlice() error = %v", ct.ContentEncoding(), err)
+			}
+			_, err = dc.Marshal()
+			if err == nil {
+				t.Errorf("%s Decompressor.Marshal() truncated to %d of %d error = nil, want error", ct.ContentEncoding(), ln, len(compressed))
+			}
+		}
+	}
+}
+
+func TestDecompressTooLarge(t *testing.T) {
+	var defaultMax = compress.MaxDecompressSize
+	compress.MaxDecompressSize = 64 * 1024
+	defer func() { compress.MaxDecompressSize = defaultMax }()
+
+	for _, ct := range compressTypes {
+		// 1MB of zeros compress to a few bytes.
+		var compressed = compressSlice(t, ct, make([]byte, 1024*1024), protocol.CompressLevel_BestCompression)
+		var dc, _ = ct.DecompressFromSlice(compressed)
+		var _, err = dc.Marshal()
+		if err != &compress.ErrDecompressTooLarge {
+			t.Errorf("%s Decompressor.Marshal() error = %v, want %v", ct.ContentEncoding(), err, &compress.ErrDecompressTooLarge)
+		}
+
+		var small = compressSlice(t, ct, make([]byte, compress.MaxDecompressSize), protocol.CompressLevel_Default)
+		dc, _ = ct.DecompressFromSlice(small)
+		_, err = dc.Marshal()
+		if err != nil {
+			t.Errorf("%s Decompressor.Marshal() max size data error = %v, want nil", ct.ContentEncoding(), err)
+		}
+	}
+}
+
+// TestDeflateIsZlib check HTTP deflate content coding is zlib format due to RFC 9110 not raw DEFLATE.
+func TestDeflateIsZlib(t *testing.T) {
+	var input = testData(10 * 1024)
+	var compressed = compressSlice(t, &flate.Deflate, input, protocol.CompressLevel_Default)
+	var zr, goErr = zlib.NewReader(bytes.NewReader(compressed))
+	if goErr != nil {
+		t.Fatalf("zlib.NewReader() error = %v", goErr)
+	}
+	var decompressed []byte
+	decompressed, goErr = io.ReadAll(zr)
+	if goErr != nil || !bytes.Equal(decompressed, input) {
+		t.Errorf("zlib decompress of deflate content coding error = %v, equal %v", goErr, bytes.Equal(decompressed, input))
+	}
+}
+
+// TestRawIsDeflate check raw coding is raw DEFLATE without zlib header and checksum.
+func TestRawIsDeflate(t *testing.T) {
+	var input = testData(10 * 1024)
+	var compressed = compressSlice(t, &flate.Raw, input, protocol.CompressLevel_Default)
+	var fr = eflate.NewReader(bytes.NewReader(compressed))
+	var decompressed, goErr = io.ReadAll(fr)
+	if goErr != nil || !bytes.Equal(decompressed, input) {
+		t.Errorf("flate decompress of raw coding error = %v, equal %v", goErr, bytes.Equal(decompressed, input))
+	}
+}
@@ -0,0 +1,113 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package compress
+
+import (
+	"bytes"
+	"io"
+
+	"../protocol"
+)
+
+// Compressor compress its source by its coding engine part by part as Encode() pull it,
+// so its Len() is -1 until whole data compress by Marshal().
+type Compressor struct {
+	coding     *Coding
+	source     Source
+	level      int    // engine level
+	compressed []byte // cache whole compressed data to serve next calls
+}
+
+// compress write whole compressed data to given writer.
+func (c *Compressor) compress(w io.Writer) (err protocol.Error) {
+	var cw = c.coding.getWriter(c.level, w)
+	var _, goErr = c.source.WriteTo(cw)
+	if goErr == nil {
+		goErr = cw.Close()
+	}
+	c.coding.putWriter(c.level, cw)
+	if goErr != nil {
+		err = ToError(goErr)
+	}
+	return
+}
+
+//libgo:impl protocol.Codec
+func (c *Compressor) MediaType() protocol.MediaType       { return c.source.MediaType() }
+func (c *Compressor) CompressType() protocol.CompressType { return c.coding }
+
+// Len return length of compressed data or -1 if it is not compressed yet.
+func (c *Compressor) Len() (ln int) {
+	if c.compressed == nil {
+		return -1
+	}
+	return len(c.compressed)
+}
+func (c *Compressor) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+
+// Encode compress the source part by part and write each compressed part to destination as soon as it is ready.
+func (c *Compressor) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	if c.compressed != nil {
+		return destination.Unmarshal(c.compressed)
+	}
+	var cw = CodecWriter{Destination: destination}
+	err = c.compress(&cw)
+	if cw.Err != nil {
+		err = cw.Err
+	}
+	n = cw.Wrote
+	return
+}
+func (c *Compressor) Marshal() (data []byte, err protocol.Error) {
+	if c.compressed == nil {
+		// Allocate the buffer even for unknown length, so empty result cache as non nil slice too.
+		var size = 512
+		if c.source.Len() > 0 {
+			size = c.source.Len() / 2
+		}
+		var buf = bytes.NewBuffer(make([]byte, 0, size))
+		err = c.compress(buf)
+		if err != nil {
+			return
+		}
+		c.compressed = buf.Bytes()
+	}
+	return c.compressed, nil
+}
+func (c *Compressor) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	var compressed []byte
+	compressed, err = c.Marshal()
+	return append(data, compressed...), err
+}
+func (c *Compressor) Unmarshal(data []byte) (n int, err protocol.Error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+func (c *Compressor) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+
+/*
+********** io package interfaces **********
+ */
+
+func (c *Compressor) ReadFrom(reader io.Reader) (n int64, err error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+func (c *Compressor) WriteTo(w io.Writer) (totalWrite int64, goErr error) {
+	if c.compressed != nil {
+		var n, goErr = w.Write(c.compressed)
+		return int64(n), goErr
+	}
+	var counter = WriteCounter{Writer: w}
+	var err = c.compress(&counter)
+	if err != nil {
+		goErr = err
+	}
+	return counter.Wrote, goErr
+}
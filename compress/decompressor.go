@@ -0,0 +1,126 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package compress
+
+import (
+	"bytes"
+	"io"
+
+	"../protocol"
+)
+
+// Decompressor decompress its source by its coding engine part by part as Encode() pull it,
+// so its Len() is -1 until whole data decompress by Marshal().
+type Decompressor struct {
+	coding       *Coding
+	source       Source
+	decompressed []byte // cache whole decompressed data to serve next calls
+}
+
+// decompress write whole decompressed data to given writer.
+func (d *Decompressor) decompress(w io.Writer) (err protocol.Error) {
+	var reader io.Reader
+	reader, err = d.source.Reader()
+	if err != nil {
+		return
+	}
+	var cr, goErr = d.coding.getReader(reader)
+	if goErr != nil {
+		return ToError(goErr)
+	}
+	var buf = GetBuffer()
+	_, goErr = io.CopyBuffer(&LimitWriter{Writer: w}, cr, *buf)
+	PutBuffer(buf)
+	d.coding.putReader(cr)
+	return ToError(goErr)
+}
+
+//libgo:impl protocol.Codec
+func (d *Decompressor) MediaType() protocol.MediaType       { return d.source.MediaType() }
+func (d *Decompressor) CompressType() protocol.CompressType { return nil }
+
+// Len return length of decompressed data or -1 if it is not decompressed yet.
+func (d *Decompressor) Len() (ln int) {
+	if d.decompressed == nil {
+		return -1
+	}
+	return len(d.decompressed)
+}
+
+// Decode set given codec as compressed source. It will decompress lazily on first Encode() or Marshal() call.
+func (d *Decompressor) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	d.source.InitByCodec(source)
+	d.decompressed = nil
+	n = source.Len()
+	return
+}
+
+// Encode decompress the source part by part and write each part to destination as soon as it is ready.
+func (d *Decompressor) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	if d.decompressed != nil {
+		return destination.Unmarshal(d.decompressed)
+	}
+	var cw = CodecWriter{Destination: destination}
+	err = d.decompress(&cw)
+	if cw.Err != nil {
+		err = cw.Err
+	}
+	n = cw.Wrote
+	return
+}
+func (d *Decompressor) Marshal() (data []byte, err protocol.Error) {
+	if d.decompressed == nil {
+		// Allocate the buffer even for unknown length, so empty result cache as non nil slice too.
+		var size = 512
+		if d.source.Len() > 0 {
+			size = d.source.Len() * 2
+		}
+		var buf = bytes.NewBuffer(make([]byte, 0, size))
+		err = d.decompress(buf)
+		if err != nil {
+			return
+		}
+		d.decompressed = buf.Bytes()
+	}
+	return d.decompressed, nil
+}
+func (d *Decompressor) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	var decompressed []byte
+	decompressed, err = d.Marshal()
+	return append(data, decompressed...), err
+}
+
+// Unmarshal set given data as compressed source. Don't change data until decompress done.
+func (d *Decompressor) Unmarshal(data []byte) (n int, err protocol.Error) {
+	d.source.InitBySlice(data)
+	d.decompressed = nil
+	n = len(data)
+	return
+}
+func (d *Decompressor) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = d.Unmarshal(data)
+	return
+}
+
+/*
+********** io package interfaces **********
+ */
+
+func (d *Decompressor) ReadFrom(reader io.Reader) (n int64, goErr error) {
+	var data []byte
+	data, goErr = io.ReadAll(reader)
+	d.Unmarshal(data)
+	return int64(len(data)), goErr
+}
+func (d *Decompressor) WriteTo(w io.Writer) (totalWrite int64, goErr error) {
+	if d.decompressed != nil {
+		var n, goErr = w.Write(d.decompressed)
+		return int64(n), goErr
+	}
+	var counter = WriteCounter{Writer: w}
+	var err = d.decompress(&counter)
+	if err != nil {
+		goErr = err
+	}
+	return counter.Wrote, goErr
+}
@@ -4,7 +4,6 @@ package compress
 
 import (
 	er "../error"
-	"../mediatype"
 	"../protocol"
 )
 
@@ -13,17 +12,51 @@ const domainPersian = "فشرده سازی"
 
 // Errors
 var (
-	ErrNotFound = er.New(mediatype.New("domain/compress.protocol.error; name=not-found").SetDetail(protocol.LanguageEnglish, domainEnglish,
+	ErrNotFound            er.Error
+	ErrSourceNotChangeable er.Error
+	ErrCompressLevel       er.Error
+	ErrCorruptedData       er.Error
+	ErrDecompressTooLarge  er.Error
+)
+
+func init() {
+	ErrNotFound.Init("domain/compress.protocol; type=error; name=not-found")
+	ErrNotFound.SetDetail(protocol.LanguageEnglish, domainEnglish,
 		"Not Found",
 		"Can't find requested compression||decompression algorithm",
 		"",
 		"",
-		nil))
+		nil)
 
-	ErrSourceNotChangeable = er.New(mediatype.New("domain/compress.protocol.error; name=source-not-changeable").SetDetail(protocol.LanguageEnglish, domainEnglish,
+	ErrSourceNotChangeable.Init("domain/compress.protocol; type=error; name=source-not-changeable")
+	ErrSourceNotChangeable.SetDetail(protocol.LanguageEnglish, domainEnglish,
 		"Source not Changeable",
 		"Can't read from other source than source given in compression||decompression creation",
 		"",
 		"",
-		nil))
-)
+		nil)
+
+	ErrCompressLevel.Init("domain/compress.protocol; type=error; name=compress-level")
+	ErrCompressLevel.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Compress Level",
+		"Requested compress level is not supported by the compression algorithm",
+		"",
+		"",
+		nil)
+
+	ErrCorruptedData.Init("domain/compress.protocol; type=error; name=corrupted-data")
+	ErrCorruptedData.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Corrupted Data",
+		"Compressed data is corrupted or truncated and can't decompress",
+		"",
+		"",
+		nil)
+
+	ErrDecompressTooLarge.Init("domain/compress.protocol; type=error; name=decompress-too-large")
+	ErrDecompressTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Decompress Too Large",
+		"Decompressed data is larger than MaxDecompressSize and decompress stopped to protect the memory",
+		"",
+		"",
+		nil)
+}
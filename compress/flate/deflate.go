@@ -3,51 +3,47 @@
 package flate
 
 import (
+	"compress/zlib"
+	"io"
+
 	compress ".."
-	"../../mediatype"
 	"../../protocol"
 )
 
+// HTTP "deflate" content coding is the zlib (RFC 1950) format that wrap DEFLATE (RFC 1951) compressed data,
+// not the raw DEFLATE. https://datatracker.ietf.org/doc/html/rfc9110#section-8.4.1.2
 const (
 	ContentEncoding = "deflate"
 	Extension       = "zz"
 )
 
 var Deflate = deflate{
-	CompressType: compress.New(ContentEncoding, mediatype.New("domain/deflate.protocol.data-structure").SetFileExtension(Extension)),
+	Coding: compress.NewCoding("domain/deflate.protocol.data-structure", ContentEncoding, Extension, engine{}),
 }
 
 type deflate struct {
-	*compress.CompressType
+	*compress.Coding
 }
 
-func (d *deflate) Compress(raw protocol.Codec, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	compressed = &Compressor{
-		source:  raw,
-		options: options,
-	}
-	return
-}
-func (d *deflate) CompressBySlice(raw []byte, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
-}
-func (d *deflate) CompressByReader(raw protocol.Reader, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
-}
+type engine struct{}
 
-func (d *deflate) Decompress(compressed protocol.Codec) (raw protocol.Codec, err protocol.Error) {
-	raw = &Decompressor{
-		source: compressed,
+// Level use compress levels as is, due to they are same as zlib levels from HuffmanOnly(-2) to BestCompression(9).
+func (engine) Level(level protocol.CompressLevel) int { return int(level) }
+func (engine) NewWriter(w io.Writer, level int) compress.WriteResetter {
+	// level checked before, so no error can occur.
+	var zw, _ = zlib.NewWriterLevel(w, level)
+	return zw
+}
+func (engine) NewReader(r io.Reader) (compress.ReadResetter, error) {
+	// Reader read the zlib header, so an error here means the header is corrupted or the source is empty.
+	var zr, goErr = zlib.NewReader(r)
+	if goErr != nil {
+		return nil, goErr
 	}
-	return
-}
-func (d *deflate) DecompressFromSlice(compressed []byte) (raw protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
-}
-func (d *deflate) DecompressFromReader(compressed protocol.Reader, compressedLen int) (raw protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
+	return zlibReader{zr}, nil
 }
+
+// zlibReader reset the zlib reader without any preset dictionary.
+type zlibReader struct{ io.ReadCloser }
+
+func (zr zlibReader) Reset(r io.Reader) error { return zr.ReadCloser.(zlib.Resetter).Reset(r, nil) }
@@ -0,0 +1,45 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package flate
+
+import (
+	"compress/flate"
+	"io"
+
+	compress ".."
+	"../../protocol"
+)
+
+// "deflate-raw" is the name of raw DEFLATE in web compression streams, but it is not an HTTP content coding.
+const (
+	RawContentEncoding = "deflate-raw"
+	RawExtension       = "deflate"
+)
+
+// Raw is the raw DEFLATE (RFC 1951) coding without zlib header and checksum e.g. for WebSocket permessage-deflate.
+// It is not registered in protocol.OS, so peers can't use it as HTTP content coding.
+var Raw = raw{
+	Coding: compress.NewCoding("domain/deflate-raw.protocol.data-structure", RawContentEncoding, RawExtension, rawEngine{}),
+}
+
+type raw struct {
+	*compress.Coding
+}
+
+type rawEngine struct{}
+
+// Level use compress levels as is, due to they are same as flate levels from HuffmanOnly(-2) to BestCompression(9).
+func (rawEngine) Level(level protocol.CompressLevel) int { return int(level) }
+func (rawEngine) NewWriter(w io.Writer, level int) compress.WriteResetter {
+	// level checked before, so no error can occur.
+	var fw, _ = flate.NewWriter(w, level)
+	return fw
+}
+func (rawEngine) NewReader(r io.Reader) (compress.ReadResetter, error) {
+	return flateReader{flate.NewReader(r)}, nil
+}
+
+// flateReader reset the flate reader without any preset dictionary.
+type flateReader struct{ io.ReadCloser }
+
+func (fr flateReader) Reset(r io.Reader) error { return fr.ReadCloser.(flate.Resetter).Reset(r, nil) }
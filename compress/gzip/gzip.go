@@ -3,56 +3,41 @@
 package gzip
 
 import (
+	egzip "compress/gzip"
+	"io"
+
 	compress ".."
-	"../../mediatype"
 	"../../protocol"
 )
 
 const (
 	GZIPContentEncoding = "gzip"
-	GZIPExtension       = "gzip"
+	GZIPExtension       = "gz"
 )
 
+// GZIP is the GZIP (RFC 1952) coding.
 var GZIP = gzip{
-	CompressType: compress.New(GZIPContentEncoding, mediatype.New("domain/gzip.protocol.data-structure").SetFileExtension(GZIPExtension)),
+	Coding: compress.NewCoding("domain/gzip.protocol.data-structure", GZIPContentEncoding, GZIPExtension, engine{}),
 }
 
 type gzip struct {
-	*compress.CompressType
+	*compress.Coding
 }
 
-func (g *gzip) Compress(raw protocol.Codec, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	compressed = &Compressor{
-		source:  raw,
-		options: options,
-	}
-	return
-}
-func (g *gzip) CompressBySlice(raw []byte, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
-}
-func (g *gzip) CompressByReader(raw protocol.Reader, options protocol.CompressOptions) (compressed protocol.Codec, err protocol.Error) {
-	// TODO:::
-	return
-}
+type engine struct{}
 
-func (g *gzip) Decompress(compressed protocol.Codec) (raw protocol.Codec, err protocol.Error) {
-	var gzipDecoder Decompressor
-	gzipDecoder.initByCodec(compressed)
-	raw = &gzipDecoder
-	return
-}
-func (g *gzip) DecompressFromSlice(compressed []byte) (raw protocol.Codec, err protocol.Error) {
-	var gzipDecoder Decompressor
-	err = gzipDecoder.Unmarshal(compressed)
-	raw = &gzipDecoder
-	return
-}
-func (g *gzip) DecompressFromReader(compressed protocol.Reader, compressedLen int) (raw protocol.Codec, err protocol.Error) {
-	var gzipDecoder Decompressor
-	gzipDecoder.comLen = compressedLen
-	err = gzipDecoder.Decode(compressed)
-	raw = &gzipDecoder
-	return
+// Level use compress levels as is, due to they are same as gzip levels from HuffmanOnly(-2) to BestCompression(9).
+func (engine) Level(level protocol.CompressLevel) int { return int(level) }
+func (engine) NewWriter(w io.Writer, level int) compress.WriteResetter {
+	// level checked before, so no error can occur.
+	var gw, _ = egzip.NewWriterLevel(w, level)
+	return gw
+}
+func (engine) NewReader(r io.Reader) (compress.ReadResetter, error) {
+	// Reader read the gzip header, so an error here means the header is corrupted or the source is empty.
+	var gr, goErr = egzip.NewReader(r)
+	if goErr != nil {
+		return nil, goErr
+	}
+	return gr, nil
 }
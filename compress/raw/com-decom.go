@@ -40,11 +40,11 @@ func (r *comDecom) Encode(writer protocol.Writer) (err protocol.Error) {
 func (r *comDecom) Marshal() (data []byte)       { return r.data }
 func (r *comDecom) MarshalTo(data []byte) []byte { return append(data, r.data...) }
 func (r *comDecom) Unmarshal(data []byte) (err protocol.Error) {
-	err = compress.ErrSourceNotChangeable
+	err = &compress.ErrSourceNotChangeable
 	return
 }
 func (r *comDecom) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
-	err = compress.ErrSourceNotChangeable
+	err = &compress.ErrSourceNotChangeable
 	return
 }
 
@@ -53,7 +53,7 @@ func (r *comDecom) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Er
  */
 
 func (r *comDecom) ReadFrom(reader io.Reader) (n int64, err error) {
-	err = compress.ErrSourceNotChangeable
+	err = &compress.ErrSourceNotChangeable
 	return
 }
 func (r *comDecom) WriteTo(w io.Writer) (totalWrite int64, err error) {
@@ -4,7 +4,6 @@ package raw
 
 import (
 	compress ".."
-	"../../mediatype"
 	"../../protocol"
 )
 
@@ -14,7 +13,7 @@ const (
 )
 
 var RAW = raw{
-	CompressType: compress.New(RawContentEncoding, mediatype.New("domain/raw.protocol.data-structure").SetFileExtension(RawExtension)),
+	CompressType: compress.New("domain/raw.protocol.data-structure", RawContentEncoding, RawExtension),
 }
 
 type raw struct {
@@ -0,0 +1,226 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package compress
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"../protocol"
+)
+
+// BufferSize is size of each part of data that read from a source or write to a compressor or destination.
+const BufferSize = 32 * 1024
+
+// MaxDecompressSize is max size of data that any decompressor produce, so a small compressed data (compression bomb)
+// can't exhaust the memory. 0 means no limit.
+var MaxDecompressSize = 256 << 20
+
+// Source is the data of a compressor or decompressor that can give as a codec, a slice or a reader,
+// so compressors can read it part by part without need to know its kind.
+type Source struct {
+	codec  protocol.Codec
+	slice  []byte
+	reader protocol.Reader
+	len    int // -1 means unknown
+}
+
+func (s *Source) InitByCodec(codec protocol.Codec) { *s = Source{codec: codec, len: codec.Len()} }
+func (s *Source) InitBySlice(slice []byte)         { *s = Source{slice: slice, len: len(slice)} }
+
+// InitByReader set the reader as source. readLen < 0 means read until io.EOF.
+func (s *Source) InitByReader(reader protocol.Reader, readLen int) {
+	if readLen < 0 {
+		readLen = -1
+	}
+	*s = Source{reader: reader, len: readLen}
+}
+
+// MediaType return media type of codec source or nil for other sources.
+func (s *Source) MediaType() protocol.MediaType {
+	if s.codec != nil {
+		return s.codec.MediaType()
+	}
+	return nil
+}
+
+// Len return source data length or -1 if it is unknown until read whole data.
+func (s *Source) Len() int { return s.len }
+
+// Reader return a reader of whole source data.
+func (s *Source) Reader() (reader io.Reader, err protocol.Error) {
+	switch {
+	case s.codec != nil:
+		var data []byte
+		data, err = s.codec.Marshal()
+		reader = bytes.NewReader(data)
+	case s.reader != nil:
+		reader = s.reader
+		if s.len >= 0 {
+			reader = io.LimitReader(reader, int64(s.len))
+		}
+	default:
+		reader = bytes.NewReader(s.slice)
+	}
+	return
+}
+
+// WriteTo write source data part by part to given writer e.g. a compressor.
+// Codec source with unknown length e.g. output of other compressor encode itself to the writer as it produce data.
+func (s *Source) WriteTo(w io.Writer) (n int64, goErr error) {
+	switch {
+	case s.codec != nil && s.len < 0:
+		var wc = writerCodec{writer: w}
+		var _, err = s.codec.Encode(&wc)
+		n = int64(wc.wrote)
+		if err != nil {
+			goErr = err
+		}
+		return
+	case s.codec != nil:
+		var data, err = s.codec.Marshal()
+		if err != nil {
+			return 0, err
+		}
+		return writeParts(w, data)
+	case s.reader != nil:
+		var reader, _ = s.Reader()
+		var buf = GetBuffer()
+		n, goErr = io.CopyBuffer(w, reader, *buf)
+		PutBuffer(buf)
+		return
+	default:
+		return writeParts(w, s.slice)
+	}
+}
+
+// writeParts write data part by part to let compressors output their data in the middle.
+func writeParts(w io.Writer, data []byte) (n int64, goErr error) {
+	for len(data) > 0 {
+		var part = data
+		if len(part) > BufferSize {
+			part = part[:BufferSize]
+		}
+		var wrote int
+		wrote, goErr = w.Write(part)
+		n += int64(wrote)
+		if goErr != nil {
+			return
+		}
+		data = data[len(part):]
+	}
+	return
+}
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		var buf = make([]byte, BufferSize)
+		return &buf
+	},
+}
+
+// GetBuffer return a BufferSize buffer from the pool. Call PutBuffer() after use.
+func GetBuffer() *[]byte  { return bufferPool.Get().(*[]byte) }
+func PutBuffer(b *[]byte) { bufferPool.Put(b) }
+
+// CodecWriter is an io.Writer that write any given data to the destination codec by its Unmarshal method,
+// so compressors can write their output directly to e.g. a network stream.
+type CodecWriter struct {
+	Destination protocol.Codec
+	Wrote       int
+	Err         protocol.Error // first error that destination return
+}
+
+func (cw *CodecWriter) Write(p []byte) (n int, goErr error) {
+	if cw.Err != nil {
+		return 0, cw.Err
+	}
+	n, cw.Err = cw.Destination.Unmarshal(p)
+	cw.Wrote += n
+	if cw.Err != nil {
+		goErr = cw.Err
+	}
+	return
+}
+
+// WriteCounter is an io.Writer that count wrote data to the underlying writer.
+type WriteCounter struct {
+	Writer io.Writer
+	Wrote  int64
+}
+
+func (wc *WriteCounter) Write(p []byte) (n int, goErr error) {
+	n, goErr = wc.Writer.Write(p)
+	wc.Wrote += int64(n)
+	return
+}
+
+// LimitWriter is an io.Writer that return ErrDecompressTooLarge if more than MaxDecompressSize data write to the underlying writer.
+type LimitWriter struct {
+	Writer io.Writer
+	wrote  int
+}
+
+func (lw *LimitWriter) Write(p []byte) (n int, goErr error) {
+	if MaxDecompressSize > 0 && lw.wrote+len(p) > MaxDecompressSize {
+		return 0, &ErrDecompressTooLarge
+	}
+	n, goErr = lw.Writer.Write(p)
+	lw.wrote += n
+	return
+}
+
+// ToError return given go error as protocol.Error. Errors that are not a protocol.Error means compressed data is corrupted.
+func ToError(goErr error) (err protocol.Error) {
+	if goErr == nil {
+		return nil
+	}
+	if protocolErr, ok := goErr.(protocol.Error); ok {
+		return protocolErr
+	}
+	return &ErrCorruptedData
+}
+
+// writerCodec is a protocol.Codec that just accept write by Unmarshal method and write given data to the writer.
+type writerCodec struct {
+	writer io.Writer
+	wrote  int
+}
+
+//libgo:impl protocol.Codec
+func (wc *writerCodec) MediaType() protocol.MediaType       { return nil }
+func (wc *writerCodec) CompressType() protocol.CompressType { return nil }
+func (wc *writerCodec) Len() (ln int)                       { return wc.wrote }
+func (wc *writerCodec) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return wc.Unmarshal(data)
+}
+func (wc *writerCodec) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+func (wc *writerCodec) Marshal() (data []byte, err protocol.Error) {
+	err = &ErrSourceNotChangeable
+	return
+}
+func (wc *writerCodec) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return data, &ErrSourceNotChangeable
+}
+func (wc *writerCodec) Unmarshal(data []byte) (n int, err protocol.Error) {
+	var goErr error
+	n, goErr = wc.writer.Write(data)
+	wc.wrote += n
+	if goErr != nil {
+		err = ToError(goErr)
+	}
+	return
+}
+func (wc *writerCodec) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = wc.Unmarshal(data)
+	return
+}
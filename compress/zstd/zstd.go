@@ -0,0 +1,62 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package zstd
+
+import (
+	"io"
+
+	compress ".."
+	"../../protocol"
+	// Go standard library has no zstd implementation, so use the klauspost one that is pure Go and widely used.
+	ezstd "github.com/klauspost/compress/zstd"
+)
+
+const (
+	ZstdContentEncoding = "zstd"
+	ZstdExtension       = "zst"
+)
+
+// Zstd is the Zstandard (RFC 8878) coding.
+var Zstd = zstd{
+	Coding: compress.NewCoding("domain/zstd.protocol.data-structure", ZstdContentEncoding, ZstdExtension, engine{}),
+}
+
+type zstd struct {
+	*compress.Coding
+}
+
+// maxWindowSize is the largest window that encoders use and decoders accept.
+// Window limited to 8MB to let HTTP peers decode it. https://www.rfc-editor.org/rfc/rfc9659#section-3
+const maxWindowSize = 8 << 20
+
+type engine struct{}
+
+// Level map given compress level to one of zstd encoder levels.
+// Zstd don't have any store or huffman only mode, so both map to its fastest level.
+func (engine) Level(level protocol.CompressLevel) int {
+	switch {
+	case level == protocol.CompressLevel_Default:
+		return int(ezstd.SpeedDefault)
+	case level <= protocol.CompressLevel_BestSpeed+2:
+		return int(ezstd.SpeedFastest)
+	case level <= 6:
+		return int(ezstd.SpeedDefault)
+	case level < protocol.CompressLevel_BestCompression:
+		return int(ezstd.SpeedBetterCompression)
+	default:
+		return int(ezstd.SpeedBestCompression)
+	}
+}
+func (engine) NewWriter(w io.Writer, level int) compress.WriteResetter {
+	// Options are valid, so no error can occur.
+	var zw, _ = ezstd.NewWriter(w, ezstd.WithEncoderLevel(ezstd.EncoderLevel(level)), ezstd.WithEncoderConcurrency(1),
+		ezstd.WithWindowSize(maxWindowSize))
+	return zw
+}
+func (engine) NewReader(r io.Reader) (compress.ReadResetter, error) {
+	var zr, goErr = ezstd.NewReader(r, ezstd.WithDecoderConcurrency(1), ezstd.WithDecoderMaxWindow(maxWindowSize))
+	if goErr != nil {
+		return nil, goErr
+	}
+	return zr, nil
+}
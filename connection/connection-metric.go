@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"../protocol"
+	"../time/monotonic"
+	"../time/unix"
 )
 
 // Metric store the connection metric data and impelement protocol.ConnectionMetrics
 type Metric struct {
-	lastUsage                   int64  // Last use of this connection
+	lastUsage                   int64  // Last use of this connection in unix seconds
 	maxBandwidth                uint64 // Byte/Second and Connection can limit to a fixed number
 	bytesSent                   uint64 // Counts the bytes of packets sent.
 	packetsSent                 uint64 // Counts sent packets.
@@ -21,9 +23,19 @@ type Metric struct {
 	notRequestedPacketsReceived uint64 // Counts not requested packets received for firewalling server from some attack types!
 	succeedStreamCount          uint64 // Count successful request.
 	failedStreamCount           uint64 // Count failed services call e.g. data validation failed, ...
+
+	// Liveness data that fill by ping & pong frames
+	lastPingID   int64  // Monotonic nano time of last ping that peer not response yet. 0 means no ping waiting for pong.
+	missedPongs  uint32 // Counts sequential pings that peer not response to them.
+	smoothedRTT  int64  // Nanosecond. Calculate as RFC6298 suggest.
+	rttVariation int64  // Nanosecond. Calculate as RFC6298 suggest.
 }
 
-func (m *Metric) LastUsage() protocol.TimeUnixMilli   { return protocol.TimeUnixMilli(m.lastUsage) }
+func (m *Metric) LastUsage() protocol.Time {
+	var lastUsage unix.Time
+	lastUsage.ChangeTo(unix.SecElapsed(atomic.LoadInt64(&m.lastUsage)), 0)
+	return &lastUsage
+}
 func (m *Metric) MaxBandwidth() uint64                { return m.maxBandwidth }
 func (m *Metric) BytesSent() uint64                   { return m.bytesSent }
 func (m *Metric) PacketsSent() uint64                 { return m.packetsSent }
@@ -33,6 +45,13 @@ func (m *Metric) FailedPacketsReceived() uint64       { return m.failedPacketsRe
 func (m *Metric) NotRequestedPacketsReceived() uint64 { return m.notRequestedPacketsReceived }
 func (m *Metric) SucceedStreamCount() uint64          { return m.succeedStreamCount }
 func (m *Metric) FailedStreamCount() uint64           { return m.failedStreamCount }
+func (m *Metric) MissedPongs() uint32                 { return atomic.LoadUint32(&m.missedPongs) }
+func (m *Metric) SmoothedRTT() protocol.Duration {
+	return protocol.Duration(atomic.LoadInt64(&m.smoothedRTT))
+}
+func (m *Metric) RTTVariation() protocol.Duration {
+	return protocol.Duration(atomic.LoadInt64(&m.rttVariation))
+}
 
 // StreamSucceed store successfull service call occur on this connection
 func (m *Metric) StreamSucceed() {
@@ -55,8 +74,55 @@ func (m *Metric) PacketReceived(packetLength uint64) {
 	atomic.AddUint64(&m.bytesReceived, packetLength)
 }
 
+// PacketReceiveFailed store malformed or not decryptable packet received on this connection.
+// Base on the connection it can other action to prevent any attack! e.g. tel router to block
+func (m *Metric) PacketReceiveFailed() {
+	atomic.StoreInt64(&m.lastUsage, time.Now().Unix())
+	atomic.AddUint64(&m.failedPacketsReceived, 1)
+}
+
 func (m *Metric) PacketSent(packetLength uint64) {
 	atomic.StoreInt64(&m.lastUsage, time.Now().Unix())
 	atomic.AddUint64(&m.packetsSent, 1)
 	atomic.AddUint64(&m.bytesSent, packetLength)
 }
+
+// PingSent store the ID of the ping that just sent to the peer.
+// If peer not response to the last ping yet, count it as a missed pong.
+func (m *Metric) PingSent(id int64) {
+	var lastPingID = atomic.SwapInt64(&m.lastPingID, id)
+	if lastPingID != 0 {
+		atomic.AddUint32(&m.missedPongs, 1)
+	}
+}
+
+// PongReceived update smoothed RTT by the pong that peer send as response of our ping.
+// Any pong that not belong to last sent ping will ignore, due to it is late or a replay.
+func (m *Metric) PongReceived(id int64) (rtt protocol.Duration) {
+	atomic.StoreInt64(&m.lastUsage, time.Now().Unix())
+	if id == 0 || !atomic.CompareAndSwapInt64(&m.lastPingID, id, 0) {
+		return
+	}
+	atomic.StoreUint32(&m.missedPongs, 0)
+
+	rtt = monotonic.Time(id).SinceNow()
+	var sample = int64(rtt)
+	var srtt = atomic.LoadInt64(&m.smoothedRTT)
+	if srtt == 0 {
+		// First measurement: SRTT <- R, RTTVAR <- R/2
+		atomic.StoreInt64(&m.smoothedRTT, sample)
+		atomic.StoreInt64(&m.rttVariation, sample/2)
+		return
+	}
+
+	// RTTVAR <- (1 - 1/4) * RTTVAR + 1/4 * |SRTT - R'|
+	// SRTT <- (1 - 1/8) * SRTT + 1/8 * R'
+	var delta = srtt - sample
+	if delta < 0 {
+		delta = -delta
+	}
+	var rttVar = atomic.LoadInt64(&m.rttVariation)
+	atomic.StoreInt64(&m.rttVariation, rttVar-rttVar/4+delta/4)
+	atomic.StoreInt64(&m.smoothedRTT, srtt-srtt/8+sample/8)
+	return
+}
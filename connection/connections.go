@@ -5,38 +5,89 @@ package connection
 import (
 	"strconv"
 	"sync"
-	"time"
+	"sync/atomic"
 
+	"../event"
 	"../log"
 	"../protocol"
 )
 
-// Connections store pools of connection to retrieve in many ways
+// SaveHandler is the interface that can implement to persist idle connection state before free it.
+type SaveHandler interface {
+	// SaveConnection persist the connection state to given local storages e.g. LocalKeyValues() by UserID+DelegateUserID as key.
+	// It can be a blocking operation.
+	SaveConnection(conn protocol.Connection, storages protocol.StoragesLocal) (err protocol.Error)
+}
+
+// Pinger is the interface that can implement to check liveness of each registered connection e.g. by sRPC ping frames.
+type Pinger interface {
+	Init(conn protocol.Connection, maxMissedPongs uint8, closeNotResponse bool)
+	Start(idleTimeout protocol.Duration) (err protocol.Error)
+	Stop() (alreadyStopped bool)
+}
+
+// eventTarget is the interface that connections implement by embed event.EventTarget.
+type eventTarget interface {
+	SetParent(parent *event.EventTarget)
+}
+
+// Connections store pools of connection to retrieve in many ways.
+// Each index is lock-striped to shards, so concurrent lookups and registrations on different keys don't contend.
 type Connections struct {
-	mutex                      sync.Mutex
-	poolByPeerAddr             map[[16]byte]protocol.Connection
-	poolByUserIDDelegateUserID map[[32]byte]protocol.Connection
-	poolByUserID               map[[16]byte][]protocol.Connection
-	poolByDomain               map[string]protocol.Connection
-	poolByBlackList            map[[16]byte]protocol.Connection // key is PeerAddr
-	guestConnectionCount       uint64
-	shutdownSignal             chan struct{}
+	userShards   [shardsNumber]userShard
+	addrShards   [shardsNumber]addrShard
+	domainShards [shardsNumber]domainShard
+
+	idleTimeout          protocol.Duration
+	saveHandler          SaveHandler
+	newPinger            func() Pinger
+	maxMissedPongs       uint8
+	closeNotResponse     bool
+	eventParent          *event.EventTarget
+	activeConnections    int64
+	guestConnectionCount int64
 }
 
-func (c *Connections) Init() {
-	c.poolByPeerAddr = make(map[[16]byte]protocol.Connection, 16384)
-	c.poolByUserIDDelegateUserID = make(map[[32]byte]protocol.Connection, 16384)
-	c.poolByUserID = make(map[[16]byte][]protocol.Connection, 16384)
-	c.poolByDomain = make(map[string]protocol.Connection, 16384)
-	c.poolByBlackList = make(map[[16]byte]protocol.Connection, 256)
-	c.shutdownSignal = make(chan struct{})
+// Init initialize the connections pools. idleTimeout 0 means use default ConnectionIdleTimeout.
+func (c *Connections) Init(idleTimeout protocol.Duration) {
+	if idleTimeout == 0 {
+		idleTimeout = ConnectionIdleTimeout
+	}
+	c.idleTimeout = idleTimeout
 
-	go c.connectionIdleTimeoutSaveAndFree()
+	for i := 0; i < shardsNumber; i++ {
+		c.userShards[i].init()
+		c.addrShards[i].init()
+		c.domainShards[i].init()
+	}
 }
 
+// SetSaveHandler set optional handler that persist idle connection state before free it.
+// Must call before any connection registered.
+func (c *Connections) SetSaveHandler(sh SaveHandler) { c.saveHandler = sh }
+
+// SetPinger set optional pinger that start for each registered connection and stop on deregister.
+// Must call before any connection registered.
+func (c *Connections) SetPinger(newPinger func() Pinger, maxMissedPongs uint8, closeNotResponse bool) {
+	c.newPinger = newPinger
+	c.maxMissedPongs = maxMissedPongs
+	c.closeNotResponse = closeNotResponse
+}
+
+// SetEventParent set the parent of registered connections event targets e.g. the application target,
+// so connections events propagate to the application listeners.
+// Must call before any connection registered.
+func (c *Connections) SetEventParent(parent *event.EventTarget) { c.eventParent = parent }
+
+func (c *Connections) GuestConnectionCount() int64  { return atomic.LoadInt64(&c.guestConnectionCount) }
+func (c *Connections) ActiveConnectionCount() int64 { return atomic.LoadInt64(&c.activeConnections) }
+
 // GetConnectionByPeerAddr get a connection by peer GP from connections pool.
 func (c *Connections) GetConnectionByPeerAddr(addr [16]byte) (conn protocol.Connection, err protocol.Error) {
-	conn = c.poolByPeerAddr[addr]
+	var shard = &c.addrShards[shardIndex(addr)]
+	shard.mutex.RLock()
+	conn = shard.poolByPeerAddr[addr]
+	shard.mutex.RUnlock()
 	if conn == nil {
 		err = ErrNoConnection
 	}
@@ -46,17 +97,30 @@ func (c *Connections) GetConnectionByPeerAddr(addr [16]byte) (conn protocol.Conn
 // GetConnectionByUserIDDelegateUserID return the connection from pool or app storage.
 // A connection can use just by single app node, so user can't use same connection to connect other node before close connection on usage node.
 func (c *Connections) GetConnectionByUserIDDelegateUserID(userID, delegateUserID [16]byte) (conn protocol.Connection, err protocol.Error) {
-	conn = c.poolByUserIDDelegateUserID[c.userIDDelegateUserID(userID, delegateUserID)]
-	if conn == nil {
+	var shard = &c.userShards[shardIndex(userID)]
+	shard.mutex.RLock()
+	var it = shard.poolByUserIDDelegateUserID[c.userIDDelegateUserID(userID, delegateUserID)]
+	shard.mutex.RUnlock()
+	if it == nil {
 		err = ErrNoConnection
+		return
 	}
+	conn = it.conn
 	return
 }
 
 // GetConnectionsByUserID get the connections by peer userID||domainID from connections pool.
-func (c *Connections) GetConnectionsByUserID(userID [16]byte) (conn []protocol.Connection, err protocol.Error) {
-	conn = c.poolByUserID[userID]
-	if conn == nil {
+// Returned slice is a copy and caller can use it without any lock.
+func (c *Connections) GetConnectionsByUserID(userID [16]byte) (conns []protocol.Connection, err protocol.Error) {
+	var shard = &c.userShards[shardIndex(userID)]
+	shard.mutex.RLock()
+	var userConnections = shard.poolByUserID[userID]
+	if len(userConnections) > 0 {
+		conns = make([]protocol.Connection, len(userConnections))
+		copy(conns, userConnections)
+	}
+	shard.mutex.RUnlock()
+	if conns == nil {
 		err = ErrNoConnection
 	}
 	return
@@ -64,81 +128,185 @@ func (c *Connections) GetConnectionsByUserID(userID [16]byte) (conn []protocol.C
 
 // GetConnectionByDomain return the connection from pool or app storage if any exist.
 func (c *Connections) GetConnectionByDomain(domain string) (conn protocol.Connection, err protocol.Error) {
-	conn = c.poolByDomain[domain]
+	var shard = &c.domainShards[shardIndexString(domain)]
+	shard.mutex.RLock()
+	conn = shard.poolByDomain[domain]
+	shard.mutex.RUnlock()
 	if conn == nil {
 		err = ErrNoConnection
 	}
 	return
 }
 
-// RegisterConnection register new connection in server connection pool
+// RegisterConnection register new connection in server connection pool and schedule its idle timeout check.
+// All indexes update under their shards locks together, so no lookup can see a partially registered connection.
+// Old connection of the same user and delegate user will deregister and close.
 func (c *Connections) RegisterConnection(conn protocol.Connection) (err protocol.Error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	var userID = conn.UserID().UUID()
-	c.poolByUserIDDelegateUserID[c.userIDDelegateUserID(userID, conn.DelegateUserID().UUID())] = conn
-	if conn.Addr() != [16]byte{} {
-		c.poolByPeerAddr[conn.Addr()] = conn
+	var it idleTimer
+	it.init(conn, c)
+	it.userID = conn.UserID().UUID()
+	it.userIDDelegateUserID = c.userIDDelegateUserID(it.userID, conn.DelegateUserID().UUID())
+	it.addr = c.peerAddr(conn)
+	it.domain = conn.DomainName()
+	it.guest = conn.UserID().Type() == protocol.UserType_Unset
+	if target, ok := conn.(eventTarget); ok && c.eventParent != nil {
+		target.SetParent(c.eventParent)
 	}
 
-	var connDomain = conn.DomainName()
-	if connDomain != "" {
-		c.poolByDomain[connDomain] = conn
+	var us, as, ds = c.lockShards(it.userID, it.addr, it.domain)
+	// Old connection of the user can have other addr or domain shards, so deregister it without hold any lock.
+	for oldIdleTimer := us.poolByUserIDDelegateUserID[it.userIDDelegateUserID]; oldIdleTimer != nil; oldIdleTimer = us.poolByUserIDDelegateUserID[it.userIDDelegateUserID] {
+		c.unlockShards(us, as, ds)
+		if c.deregister(oldIdleTimer) && oldIdleTimer.conn != conn {
+			// Replaced connection is not reachable anymore, so close it to not leak its streams and resources.
+			oldIdleTimer.conn.Close()
+		}
+		us, as, ds = c.lockShards(it.userID, it.addr, it.domain)
 	}
 
-	if userID != [16]byte{} {
-		c.poolByUserID[userID] = append(c.poolByUserID[userID], conn)
+	us.poolByUserIDDelegateUserID[it.userIDDelegateUserID] = &it
+	if it.userID != [16]byte{} {
+		us.poolByUserID[it.userID] = append(us.poolByUserID[it.userID], conn)
 	}
-
-	if conn.UserID().Type() == protocol.UserType_Unset {
-		c.guestConnectionCount++
+	if it.addr != [16]byte{} {
+		as.poolByPeerAddr[it.addr] = conn
 	}
+	if it.domain != "" {
+		ds.poolByDomain[it.domain] = conn
+	}
+	atomic.AddInt64(&c.activeConnections, 1)
+	if it.guest {
+		atomic.AddInt64(&c.guestConnectionCount, 1)
+	}
+	c.unlockShards(us, as, ds)
+
+	err = it.start()
 	return
 }
 
 // DeregisterConnection delete the given connection in connections pool
 func (c *Connections) DeregisterConnection(conn protocol.Connection) (err protocol.Error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	err = c.deregisterConnection(conn)
+	var userID = conn.UserID().UUID()
+	var us = &c.userShards[shardIndex(userID)]
+	us.mutex.RLock()
+	var it = us.poolByUserIDDelegateUserID[c.userIDDelegateUserID(userID, conn.DelegateUserID().UUID())]
+	us.mutex.RUnlock()
+
+	if it == nil || it.conn != conn || !c.deregister(it) {
+		err = ErrNoConnection
+	}
 	return
 }
 
-func (c *Connections) deregisterConnection(conn protocol.Connection) (err protocol.Error) {
-	if conn.UserID().Type() == protocol.UserType_Unset {
-		c.guestConnectionCount--
+// deregister delete the connection of the idle timer from all indexes by its registered keys and stop the timer.
+// Registered keys use, so connection state that change after register e.g. guest promotion can't leak any index or counter.
+// It return false if the connection deregistered before.
+func (c *Connections) deregister(it *idleTimer) (deregistered bool) {
+	var us, as, ds = c.lockShards(it.userID, it.addr, it.domain)
+	if us.poolByUserIDDelegateUserID[it.userIDDelegateUserID] == it {
+		c.deregisterConnection(us, as, ds, it)
+		deregistered = true
 	}
+	c.unlockShards(us, as, ds)
 
-	var userID = conn.UserID().UUID()
-	delete(c.poolByUserIDDelegateUserID, c.userIDDelegateUserID(userID, conn.DelegateUserID().UUID()))
-	delete(c.poolByPeerAddr, conn.Addr())
-	delete(c.poolByDomain, conn.DomainName())
+	if deregistered {
+		it.stop()
+	}
+	return
+}
+
+// deregisterConnection delete the connection from all indexes. Caller must hold all shards locks.
+func (c *Connections) deregisterConnection(us *userShard, as *addrShard, ds *domainShard, it *idleTimer) {
+	if it.guest {
+		atomic.AddInt64(&c.guestConnectionCount, -1)
+	}
+	atomic.AddInt64(&c.activeConnections, -1)
+
+	var conn = it.conn
+	delete(us.poolByUserIDDelegateUserID, it.userIDDelegateUserID)
 
-	var userConnections = c.poolByUserID[userID]
-	var userConnectionsLen =len(userConnections)
+	if as != nil && as.poolByPeerAddr[it.addr] == conn {
+		delete(as.poolByPeerAddr, it.addr)
+	}
+	if ds != nil && ds.poolByDomain[it.domain] == conn {
+		delete(ds.poolByDomain, it.domain)
+	}
+
+	var userConnections = us.poolByUserID[it.userID]
+	var userConnectionsLen = len(userConnections)
 	for i := 0; i < userConnectionsLen; i++ {
-		if userConnections[i].UserID().UUID() == userID {
+		if userConnections[i] == conn {
 			var newUserConnectionsLen = userConnectionsLen - 1
-			// copy(userConnections[i:], userConnections[i+1:])
-			// userConnections = userConnections[:newUserConnectionsLen]
 			userConnections[i] = userConnections[newUserConnectionsLen]
+			userConnections[newUserConnectionsLen] = nil
 			userConnections = userConnections[:newUserConnectionsLen]
 			break
 		}
 	}
-	c.poolByUserID[userID] = userConnections
+	if len(userConnections) == 0 {
+		delete(us.poolByUserID, it.userID)
+	} else {
+		us.poolByUserID[it.userID] = userConnections
+	}
+}
+
+// lockShards lock related shards of given keys in fixed order (user, addr, domain) to prevent any deadlock.
+// domain shard is nil if domain is empty.
+func (c *Connections) lockShards(userID, addr [16]byte, domain string) (us *userShard, as *addrShard, ds *domainShard) {
+	us = &c.userShards[shardIndex(userID)]
+	as = &c.addrShards[shardIndex(addr)]
+	us.mutex.Lock()
+	as.mutex.Lock()
+	if domain != "" {
+		ds = &c.domainShards[shardIndexString(domain)]
+		ds.mutex.Lock()
+	}
 	return
 }
 
-func (c *Connections) Shutdown() {
-	c.shutdownSignal <- struct{}{}
+func (c *Connections) unlockShards(us *userShard, as *addrShard, ds *domainShard) {
+	if ds != nil {
+		ds.mutex.Unlock()
+	}
+	as.mutex.Unlock()
+	us.mutex.Unlock()
+}
+
+// freeIdleConnection call by connection idle timer to save and free the connection.
+func (c *Connections) freeIdleConnection(it *idleTimer) {
+	if c.saveHandler != nil {
+		var err = c.saveHandler.SaveConnection(it.conn, protocol.App)
+		if err != nil {
+			protocol.App.Log(log.WarnEvent(domainEnglish, "Idle connection can't save before free: "+err.ToString()))
+		}
+	}
+
+	if !c.deregister(it) {
+		// Connection deregistered or replaced by other logic before idle timer fired.
+		return
+	}
+	it.conn.Close()
+}
 
+// Shutdown save and free all connections and return when all of them done.
+func (c *Connections) Shutdown() {
 	protocol.App.Log(log.InfoEvent(domainEnglish, "ShutDown - Saving proccess begin ...\n"+
 		"Number of active connections:"+c.activeConnectionsNumber()))
-	for _, conn := range c.poolByUserIDDelegateUserID {
-		go conn.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < shardsNumber; i++ {
+		var shard = &c.userShards[i]
+		shard.mutex.RLock()
+		for _, it := range shard.poolByUserIDDelegateUserID {
+			it.stop()
+			wg.Add(1)
+			go func(it *idleTimer) {
+				c.freeIdleConnection(it)
+				wg.Done()
+			}(it)
+		}
+		shard.mutex.RUnlock()
 	}
+	wg.Wait()
 	protocol.App.Log(log.InfoEvent(domainEnglish, "ShutDown - Saving proccess end now"))
 }
 
@@ -148,38 +316,11 @@ func (c *Connections) userIDDelegateUserID(userID, delegateUserID [16]byte) (use
 	return
 }
 
-func (c *Connections) connectionIdleTimeoutSaveAndFree() {
-	var timer = time.NewTimer(ConnectionIdleTimeout)
-	var timerTime time.Time
-	for {
-		select {
-		case timerTime = <-timer.C:
-			protocol.App.Log(log.InfoEvent(domainEnglish, "Cron - Idle connections timeout save and free proccess begin ...\n"+
-				"Number of active connections: "+c.activeConnectionsNumber()))
-
-			var timeNowMilli = timerTime.UnixMilli()
-
-			c.mutex.Lock()
-			defer c.mutex.Unlock()
-			for _, conn := range c.poolByUserIDDelegateUserID {
-				var lastUsage = int64(conn.LastUsage())
-				if lastUsage+ConnectionIdleTimeout < timeNowMilli {
-					continue
-				}
-
-				c.deregisterConnection(conn)
-				go conn.Close()
-			}
-
-			timer.Reset(ConnectionIdleTimeout)
-			protocol.App.Log(log.InfoEvent(domainEnglish, "Cron - Number of active connections after:"+c.activeConnectionsNumber()+
-				"\nIdle connections timeout save and free proccess end now"))
-		case <-c.shutdownSignal:
-			return
-		}
-	}
+func (c *Connections) peerAddr(conn protocol.Connection) (addr [16]byte) {
+	copy(addr[:], conn.RemoteAddr())
+	return
 }
 
 func (c *Connections) activeConnectionsNumber() string {
-	return strconv.FormatInt(int64(len(c.poolByUserIDDelegateUserID)), 10)
+	return strconv.FormatInt(c.ActiveConnectionCount(), 10)
 }
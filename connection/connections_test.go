@@ -0,0 +1,105 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"../event"
+	"../protocol"
+)
+
+type testApplication struct {
+	protocol.Application
+}
+
+func (app testApplication) Log(event protocol.LogEvent) protocol.Error { return nil }
+
+type testUserID struct {
+	protocol.UserID
+	uuid     [16]byte
+	userType protocol.UserType
+}
+
+func (id *testUserID) UUID() [16]byte          { return id.uuid }
+func (id *testUserID) Type() protocol.UserType { return id.userType }
+
+// testConnection count its Close calls.
+type testConnection struct {
+	protocol.Connection
+	event.EventTarget
+	userID         testUserID
+	delegateUserID testUserID
+	addr           []byte
+	closed         int32 // atomic access
+}
+
+func (conn *testConnection) UserID() protocol.UserID         { return &conn.userID }
+func (conn *testConnection) DelegateUserID() protocol.UserID { return &conn.delegateUserID }
+func (conn *testConnection) RemoteAddr() []byte              { return conn.addr }
+func (conn *testConnection) DomainName() string              { return "" }
+func (conn *testConnection) Close() (err protocol.Error) {
+	atomic.AddInt32(&conn.closed, 1)
+	return
+}
+
+func TestConnections(t *testing.T) {
+	protocol.App = testApplication{}
+	var c Connections
+	c.Init(0)
+	var app event.EventTarget
+	c.SetEventParent(&app)
+
+	var guest = testConnection{addr: []byte{1}}
+	var user = testConnection{userID: testUserID{uuid: [16]byte{1}, userType: protocol.UserType_Person}, addr: []byte{2}}
+	var other = testConnection{userID: testUserID{uuid: [16]byte{2}, userType: protocol.UserType_Person}, addr: []byte{3}}
+	for _, conn := range []*testConnection{&guest, &user, &other} {
+		if err := c.RegisterConnection(conn); err != nil {
+			t.Fatalf("Connections.RegisterConnection() error = %v", err)
+		}
+	}
+	if c.ActiveConnectionCount() != 3 || c.GuestConnectionCount() != 1 {
+		t.Fatalf("Connections active = %v, guest = %v, want 3, 1", c.ActiveConnectionCount(), c.GuestConnectionCount())
+	}
+	if user.Parent() != &app {
+		t.Errorf("Registered connection event parent = %p, want %p", user.Parent(), &app)
+	}
+
+	// Guest connection promoted after register must not leak the guest count.
+	guest.userID.userType = protocol.UserType_Person
+	if err := c.DeregisterConnection(&guest); err != nil {
+		t.Fatalf("Connections.DeregisterConnection() error = %v", err)
+	}
+	if c.ActiveConnectionCount() != 2 || c.GuestConnectionCount() != 0 {
+		t.Errorf("Connections after promoted guest deregister active = %v, guest = %v, want 2, 0", c.ActiveConnectionCount(), c.GuestConnectionCount())
+	}
+
+	// New connection of the same user replace and close the old one.
+	var userNew = testConnection{userID: user.userID, addr: []byte{4}}
+	if err := c.RegisterConnection(&userNew); err != nil {
+		t.Fatalf("Connections.RegisterConnection() error = %v", err)
+	}
+	if c.ActiveConnectionCount() != 2 {
+		t.Errorf("Connections after replace active = %v, want 2", c.ActiveConnectionCount())
+	}
+	if atomic.LoadInt32(&user.closed) != 1 {
+		t.Errorf("Replaced connection closed = %v, want 1", user.closed)
+	}
+	if conn, _ := c.GetConnectionByPeerAddr([16]byte{2}); conn != nil {
+		t.Errorf("Connections.GetConnectionByPeerAddr() of replaced connection = %v, want nil", conn)
+	}
+
+	c.Shutdown()
+	if c.ActiveConnectionCount() != 0 {
+		t.Errorf("Connections after Shutdown() active = %v, want 0", c.ActiveConnectionCount())
+	}
+	for _, conn := range []*testConnection{&user, &userNew, &other} {
+		if closed := atomic.LoadInt32(&conn.closed); closed != 1 {
+			t.Errorf("Connection %v closed = %v, want 1", conn.addr, closed)
+		}
+	}
+	if atomic.LoadInt32(&guest.closed) != 0 {
+		t.Errorf("Deregistered connection closed = %v, want 0", guest.closed)
+	}
+}
@@ -2,7 +2,14 @@
 
 package connection
 
+import (
+	"../time/earth"
+)
+
 const (
-	// 24 hour in millisecond
-	ConnectionIdleTimeout = 24 * 60 * 60 * 1000
+	// ConnectionIdleTimeout use if application not set any idle timeout in Connections.Init()
+	ConnectionIdleTimeout = 24 * earth.Hour
+
+	// shardsNumber is number of lock-striped shards of each connections index. It must be power of 2.
+	shardsNumber = 64
 )
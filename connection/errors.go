@@ -41,3 +41,36 @@ var (
 		"",
 		"").Save()
 )
+
+// Quota errors
+var (
+	ErrMaxConcurrentStreams = er.New("urn:giti:connection.protocol:error:max-concurrent-streams").SetDetail(protocol.LanguageEnglish, domainEnglish, "Max Concurrent Streams",
+		"Connection reached its maximum concurrent streams, close some streams or wait for them to finish",
+		"",
+		"").Save()
+
+	ErrDailyStreamQuota = er.New("urn:giti:connection.protocol:error:daily-stream-quota").SetDetail(protocol.LanguageEnglish, domainEnglish, "Daily Stream Quota",
+		"Connection reached its daily open stream quota, try again tomorrow(UTC) or upgrade your user type",
+		"",
+		"").Save()
+
+	ErrDailyServiceCallQuota = er.New("urn:giti:connection.protocol:error:daily-service-call-quota").SetDetail(protocol.LanguageEnglish, domainEnglish, "Daily Service Call Quota",
+		"Connection reached its daily service call quota, try again tomorrow(UTC) or upgrade your user type",
+		"",
+		"").Save()
+
+	ErrServiceCallRateLimited = er.New("urn:giti:connection.protocol:error:service-call-rate-limited").SetDetail(protocol.LanguageEnglish, domainEnglish, "Service Call Rate Limited",
+		"Connection call services faster than permitted rate, try few seconds later",
+		"",
+		"").Save()
+
+	ErrDailySendQuota = er.New("urn:giti:connection.protocol:error:daily-send-quota").SetDetail(protocol.LanguageEnglish, domainEnglish, "Daily Send Quota",
+		"Connection reached its daily send bytes or packets quota, try again tomorrow(UTC) or upgrade your user type",
+		"",
+		"").Save()
+
+	ErrDailyReceiveQuota = er.New("urn:giti:connection.protocol:error:daily-receive-quota").SetDetail(protocol.LanguageEnglish, domainEnglish, "Daily Receive Quota",
+		"Connection reached its daily receive bytes or packets quota, try again tomorrow(UTC) or upgrade your user type",
+		"",
+		"").Save()
+)
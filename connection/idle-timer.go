@@ -0,0 +1,67 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"../protocol"
+	"../time/earth"
+	"../time/unix"
+	"../timer"
+)
+
+// idleTimer schedule idle check of a registered connection, so registry don't need to sweep all connections.
+// It also hold the connection pinger if connections has any, to start and stop it with the connection registration.
+type idleTimer struct {
+	conn        protocol.Connection
+	connections *Connections
+	pinger      Pinger
+	timer       timer.Async
+
+	// Keys and guest state of the connection on register, so deregister not depend on connection state that can change.
+	userID               [16]byte
+	userIDDelegateUserID [32]byte
+	addr                 [16]byte
+	domain               string
+	guest                bool
+}
+
+func (it *idleTimer) init(conn protocol.Connection, connections *Connections) {
+	it.conn = conn
+	it.connections = connections
+	it.timer.Init(it)
+	if connections.newPinger != nil {
+		it.pinger = connections.newPinger()
+		it.pinger.Init(conn, connections.maxMissedPongs, connections.closeNotResponse)
+	}
+}
+
+func (it *idleTimer) start() (err protocol.Error) {
+	err = it.timer.Start(it.connections.idleTimeout)
+	if err == nil && it.pinger != nil {
+		err = it.pinger.Start(it.connections.idleTimeout)
+	}
+	return
+}
+
+func (it *idleTimer) stop() {
+	it.timer.Stop()
+	if it.pinger != nil {
+		it.pinger.Stop()
+	}
+}
+
+//libgo:impl protocol.TimerListener
+func (it *idleTimer) TimerHandler() {
+	var now = unix.Now()
+	var lastUsage = it.conn.LastUsage()
+	var idle = protocol.Duration(now.SecondElapsed()-lastUsage.SecondElapsed()) * earth.Second
+	var remaining = it.connections.idleTimeout - idle
+	if remaining > 0 {
+		// Connection used after timer started, so check it again when it can be idle.
+		it.timer.Modify(remaining)
+		return
+	}
+
+	// Save and close can be blocking operations, so do them in new goroutine.
+	go it.connections.freeIdleConnection(it)
+}
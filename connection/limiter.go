@@ -0,0 +1,138 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"sync"
+
+	"../protocol"
+	"../time/utc"
+)
+
+// Limiter enforce a Quota on a connection and implement protocol.ConnectionQuotas.
+// Daily counters reset on UTC day boundaries. Zero value is ready to use and enforce no limit until Init.
+type Limiter struct {
+	mutex sync.Mutex
+	quota *Quota
+	day   utc.DayElapsed
+
+	concurrentStreams   uint32
+	streamDaily         uint32
+	serviceCallDaily    uint64
+	bytesSendDaily      uint64
+	bytesReceiveDaily   uint64
+	packetsSendDaily    uint64
+	packetsReceiveDaily uint64
+
+	serviceCallBucket TokenBucket
+}
+
+// Init initialize the limiter by given quota. Use QuotaByUserType() to get the connection quota.
+// Call it again if the connection user type change e.g. guest user login.
+func (l *Limiter) Init(quota *Quota) {
+	if quota == nil {
+		quota = &unlimitedQuota
+	}
+	l.mutex.Lock()
+	l.quota = quota
+	l.day = utc.Now().DayElapsed()
+	l.serviceCallBucket.Init(quota.MaxServiceCallPerSecond)
+	l.mutex.Unlock()
+}
+
+// StreamOpened check concurrent and daily streams quota for new stream.
+func (l *Limiter) StreamOpened() (err protocol.Error) {
+	l.mutex.Lock()
+	l.checkDay()
+	var quota = l.limits()
+	if quota.MaxConcurrentStreams > 0 && l.concurrentStreams >= quota.MaxConcurrentStreams {
+		err = ErrMaxConcurrentStreams
+	} else if quota.MaxStreamDaily > 0 && l.streamDaily >= quota.MaxStreamDaily {
+		err = ErrDailyStreamQuota
+	} else {
+		l.concurrentStreams++
+		l.streamDaily++
+	}
+	l.mutex.Unlock()
+	return
+}
+
+// StreamClosed release the stream from concurrent streams quota.
+func (l *Limiter) StreamClosed() {
+	l.mutex.Lock()
+	if l.concurrentStreams > 0 {
+		l.concurrentStreams--
+	}
+	l.mutex.Unlock()
+}
+
+// ServiceCalled check service call rate and daily quota.
+func (l *Limiter) ServiceCalled() (err protocol.Error) {
+	l.mutex.Lock()
+	l.checkDay()
+	var quota = l.limits()
+	if quota.MaxServiceCallDaily > 0 && l.serviceCallDaily >= quota.MaxServiceCallDaily {
+		err = ErrDailyServiceCallQuota
+	} else if !l.serviceCallBucket.Allow(1) {
+		err = ErrServiceCallRateLimited
+	} else {
+		l.serviceCallDaily++
+	}
+	l.mutex.Unlock()
+	return
+}
+
+// PacketSending check daily send quota for the packet that will send.
+func (l *Limiter) PacketSending(packetLength uint64) (err protocol.Error) {
+	l.mutex.Lock()
+	l.checkDay()
+	var quota = l.limits()
+	if (quota.MaxPacketsSendDaily > 0 && l.packetsSendDaily >= quota.MaxPacketsSendDaily) ||
+		(quota.MaxBytesSendDaily > 0 && l.bytesSendDaily+packetLength > quota.MaxBytesSendDaily) {
+		err = ErrDailySendQuota
+	} else {
+		l.packetsSendDaily++
+		l.bytesSendDaily += packetLength
+	}
+	l.mutex.Unlock()
+	return
+}
+
+// PacketReceiving check daily receive quota for the packet that just received.
+func (l *Limiter) PacketReceiving(packetLength uint64) (err protocol.Error) {
+	l.mutex.Lock()
+	l.checkDay()
+	var quota = l.limits()
+	if (quota.MaxPacketsReceiveDaily > 0 && l.packetsReceiveDaily >= quota.MaxPacketsReceiveDaily) ||
+		(quota.MaxBytesReceiveDaily > 0 && l.bytesReceiveDaily+packetLength > quota.MaxBytesReceiveDaily) {
+		err = ErrDailyReceiveQuota
+	} else {
+		l.packetsReceiveDaily++
+		l.bytesReceiveDaily += packetLength
+	}
+	l.mutex.Unlock()
+	return
+}
+
+// limits return the quota that limiter enforce. Caller must hold the lock.
+func (l *Limiter) limits() *Quota {
+	if l.quota == nil {
+		return &unlimitedQuota
+	}
+	return l.quota
+}
+
+// checkDay reset daily counters if UTC day changed. Caller must hold the lock.
+func (l *Limiter) checkDay() {
+	var today = utc.Now().DayElapsed()
+	if today == l.day {
+		return
+	}
+	l.day = today
+	l.streamDaily = 0
+	l.serviceCallDaily = 0
+	l.bytesSendDaily = 0
+	l.bytesReceiveDaily = 0
+	l.packetsSendDaily = 0
+	l.packetsReceiveDaily = 0
+}
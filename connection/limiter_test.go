@@ -0,0 +1,114 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestLimiterZeroValue(t *testing.T) {
+	var l Limiter
+	for i := 0; i < 1000; i++ {
+		if err := l.StreamOpened(); err != nil {
+			t.Fatalf("Limiter.StreamOpened() without Init error = %v", err)
+		}
+		if err := l.ServiceCalled(); err != nil {
+			t.Fatalf("Limiter.ServiceCalled() without Init error = %v", err)
+		}
+		if err := l.PacketSending(1500); err != nil {
+			t.Fatalf("Limiter.PacketSending() without Init error = %v", err)
+		}
+		if err := l.PacketReceiving(1500); err != nil {
+			t.Fatalf("Limiter.PacketReceiving() without Init error = %v", err)
+		}
+	}
+}
+
+type limiterTest struct {
+	name    string
+	quota   Quota
+	check   func(l *Limiter) protocol.Error
+	allowed int
+	err     protocol.Error
+}
+
+var limiterTests = []limiterTest{
+	{
+		name:    "concurrent-streams",
+		quota:   Quota{MaxConcurrentStreams: 3},
+		check:   (*Limiter).StreamOpened,
+		allowed: 3,
+		err:     ErrMaxConcurrentStreams,
+	}, {
+		name:    "daily-service-call",
+		quota:   Quota{MaxServiceCallDaily: 5},
+		check:   (*Limiter).ServiceCalled,
+		allowed: 5,
+		err:     ErrDailyServiceCallQuota,
+	}, {
+		name:    "service-call-rate",
+		quota:   Quota{MaxServiceCallPerSecond: 4},
+		check:   (*Limiter).ServiceCalled,
+		allowed: 4,
+		err:     ErrServiceCallRateLimited,
+	}, {
+		name:    "daily-packets-send",
+		quota:   Quota{MaxPacketsSendDaily: 10},
+		check:   func(l *Limiter) protocol.Error { return l.PacketSending(100) },
+		allowed: 10,
+		err:     ErrDailySendQuota,
+	}, {
+		name:    "daily-bytes-send",
+		quota:   Quota{MaxBytesSendDaily: 1000},
+		check:   func(l *Limiter) protocol.Error { return l.PacketSending(300) },
+		allowed: 3,
+		err:     ErrDailySendQuota,
+	}, {
+		name:    "daily-packets-receive",
+		quota:   Quota{MaxPacketsReceiveDaily: 10},
+		check:   func(l *Limiter) protocol.Error { return l.PacketReceiving(100) },
+		allowed: 10,
+		err:     ErrDailyReceiveQuota,
+	}, {
+		name:    "daily-bytes-receive",
+		quota:   Quota{MaxBytesReceiveDaily: 1000},
+		check:   func(l *Limiter) protocol.Error { return l.PacketReceiving(250) },
+		allowed: 4,
+		err:     ErrDailyReceiveQuota,
+	},
+}
+
+func TestLimiterExhaustQuota(t *testing.T) {
+	for _, tt := range limiterTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l Limiter
+			l.Init(&tt.quota)
+			for i := 0; i < tt.allowed; i++ {
+				if err := tt.check(&l); err != nil {
+					t.Fatalf("check %d error = %v, want nil", i, err)
+				}
+			}
+			if err := tt.check(&l); err != tt.err {
+				t.Errorf("check over quota error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLimiterStreamClosed(t *testing.T) {
+	var l Limiter
+	l.Init(&Quota{MaxConcurrentStreams: 1, MaxStreamDaily: 2})
+	if err := l.StreamOpened(); err != nil {
+		t.Fatalf("Limiter.StreamOpened() error = %v", err)
+	}
+	l.StreamClosed()
+	if err := l.StreamOpened(); err != nil {
+		t.Fatalf("Limiter.StreamOpened() after close error = %v", err)
+	}
+	l.StreamClosed()
+	if err := l.StreamOpened(); err != ErrDailyStreamQuota {
+		t.Errorf("Limiter.StreamOpened() over daily quota error = %v, want %v", err, ErrDailyStreamQuota)
+	}
+}
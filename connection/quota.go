@@ -0,0 +1,38 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"../protocol"
+)
+
+// Quota store limits of a user type. Zero value of each field means no limit.
+// Application must fill GuestQuota & RegisteredQuota e.g. achaemenid fill them by its manifest NetworkInfo.
+type Quota struct {
+	MaxConcurrentStreams    uint32
+	MaxStreamDaily          uint32 // Max open stream per day. overflow will drop on creation!
+	MaxServiceCallDaily     uint64
+	MaxServiceCallPerSecond uint32 // Use as token bucket rate and burst
+	MaxBytesSendDaily       uint64
+	MaxBytesReceiveDaily    uint64
+	MaxPacketsSendDaily     uint64
+	MaxPacketsReceiveDaily  uint64
+}
+
+// Quotas by user type
+var (
+	GuestQuota      Quota
+	RegisteredQuota Quota
+
+	unlimitedQuota Quota // Use by not initialized limiter
+)
+
+// QuotaByUserType return the quota that must enforce on the connection with given user type.
+func QuotaByUserType(userType protocol.UserType) *Quota {
+	switch userType {
+	case protocol.UserType_Unset, protocol.UserType_Guest:
+		return &GuestQuota
+	default:
+		return &RegisteredQuota
+	}
+}
@@ -0,0 +1,61 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"sync"
+
+	"../protocol"
+)
+
+// userShard store connections indexes that keyed by user ID.
+// UserID+DelegateUserID index is the primary index and hold the connection idle timer.
+type userShard struct {
+	mutex                      sync.RWMutex
+	poolByUserIDDelegateUserID map[[32]byte]*idleTimer
+	poolByUserID               map[[16]byte][]protocol.Connection
+}
+
+func (s *userShard) init() {
+	s.poolByUserIDDelegateUserID = make(map[[32]byte]*idleTimer, 256)
+	s.poolByUserID = make(map[[16]byte][]protocol.Connection, 256)
+}
+
+// addrShard store connections index that keyed by peer address.
+type addrShard struct {
+	mutex          sync.RWMutex
+	poolByPeerAddr map[[16]byte]protocol.Connection
+}
+
+func (s *addrShard) init() {
+	s.poolByPeerAddr = make(map[[16]byte]protocol.Connection, 256)
+}
+
+// domainShard store connections index that keyed by peer domain name.
+type domainShard struct {
+	mutex        sync.RWMutex
+	poolByDomain map[string]protocol.Connection
+}
+
+func (s *domainShard) init() {
+	s.poolByDomain = make(map[string]protocol.Connection, 256)
+}
+
+// shardIndex return shard index of given 16 byte key like UUID or IPv6 address.
+// Fold all key bytes due to some keys like IPv4-mapped IPv6 address has zero bytes in their beginning.
+func shardIndex(key [16]byte) (index uint32) {
+	for i := 0; i < 16; i++ {
+		index = index*31 + uint32(key[i])
+	}
+	return index & (shardsNumber - 1)
+}
+
+// shardIndexString return shard index of given string key by FNV-1a hash.
+func shardIndexString(key string) (index uint32) {
+	index = 2166136261
+	for i := 0; i < len(key); i++ {
+		index ^= uint32(key[i])
+		index *= 16777619
+	}
+	return index & (shardsNumber - 1)
+}
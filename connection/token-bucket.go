@@ -0,0 +1,46 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package connection
+
+import (
+	"../time/monotonic"
+)
+
+// TokenBucket is a rate limiter that let burst of events up to its capacity and then
+// refill tokens by the rate. It isn't safe for concurrent use, so caller must care about it.
+// https://en.wikipedia.org/wiki/Token_bucket
+type TokenBucket struct {
+	rate       float64 // tokens per second. 0 means no limit.
+	capacity   float64
+	tokens     float64
+	lastRefill monotonic.Time
+}
+
+// Init initialize the bucket with given rate per second that also use as bucket capacity.
+func (tb *TokenBucket) Init(rate uint32) {
+	tb.rate = float64(rate)
+	tb.capacity = float64(rate)
+	tb.tokens = float64(rate)
+	tb.lastRefill = monotonic.Now()
+}
+
+// Allow report whether n tokens are available and take them if they are.
+func (tb *TokenBucket) Allow(n uint32) bool {
+	if tb.rate == 0 {
+		return true
+	}
+
+	var now = monotonic.Now()
+	var elapsed = tb.lastRefill.Since(now)
+	tb.lastRefill = now
+	tb.tokens += tb.rate * float64(elapsed) / float64(monotonic.Second)
+	if tb.tokens > tb.capacity {
+		tb.tokens = tb.capacity
+	}
+
+	if tb.tokens < float64(n) {
+		return false
+	}
+	tb.tokens -= float64(n)
+	return true
+}
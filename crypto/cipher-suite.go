@@ -0,0 +1,68 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package crypto
+
+import (
+	"crypto/sha512"
+
+	"../binary"
+)
+
+// Supported cipher suites that a Session can use. Both use ECDHE key exchange in the connection handshake
+// and HMAC-SHA256 by the session secret to sign streams and rekey.
+var (
+	CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_GCM_SHA256 = NewCipherSuite("GP", "ECDHE", "ED25519", "AES", "256", "GCM", "SHA256")
+	CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_CCM_SHA256 = NewCipherSuite("GP", "ECDHE", "ED25519", "AES", "256", "CCM", "SHA256")
+)
+
+func init() {
+	Suites.RegisterCipherSuite(&CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_GCM_SHA256)
+	Suites.RegisterCipherSuite(&CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_CCM_SHA256)
+}
+
+// CipherSuite implement protocol.CipherSuite. ID is the first 8 bytes of the name hash,
+// so peers agree on it without any central registry.
+type CipherSuite struct {
+	name              string
+	id                uint64
+	protocol          string
+	keyExchange       string
+	authentication    string
+	sessionCipher     string
+	encryptionKeySize string
+	encryptionType    string
+	hash              string
+	insecure          bool
+}
+
+// NewCipherSuite return the cipher suite that its name make from given parts e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+func NewCipherSuite(protocol, keyExchange, authentication, sessionCipher, encryptionKeySize, encryptionType, hash string) (cs CipherSuite) {
+	cs = CipherSuite{
+		name:              protocol + "_" + keyExchange + "_" + authentication + "_WITH_" + sessionCipher + "_" + encryptionKeySize + "_" + encryptionType + "_" + hash,
+		protocol:          protocol,
+		keyExchange:       keyExchange,
+		authentication:    authentication,
+		sessionCipher:     sessionCipher,
+		encryptionKeySize: encryptionKeySize,
+		encryptionType:    encryptionType,
+		hash:              hash,
+	}
+	var sum = sha512.Sum512_256([]byte(cs.name))
+	cs.id = binary.LittleEndian.Uint64(sum[:])
+	return
+}
+
+// SetInsecure mark the cipher suite as insecure e.g. when a security issue found in one of its primitives.
+func (cs *CipherSuite) SetInsecure() { cs.insecure = true }
+
+//libgo:impl protocol.CipherSuite
+func (cs *CipherSuite) String() string            { return cs.name }
+func (cs *CipherSuite) ID() uint64                { return cs.id }
+func (cs *CipherSuite) Protocol() string          { return cs.protocol }
+func (cs *CipherSuite) KeyExchange() string       { return cs.keyExchange }
+func (cs *CipherSuite) Authentication() string    { return cs.authentication }
+func (cs *CipherSuite) SessionCipher() string     { return cs.sessionCipher }
+func (cs *CipherSuite) EncryptionKeySize() string { return cs.encryptionKeySize }
+func (cs *CipherSuite) EncryptionType() string    { return cs.encryptionType }
+func (cs *CipherSuite) Hash() string              { return cs.hash }
+func (cs *CipherSuite) Insecure() bool            { return cs.insecure }
@@ -1,3 +1,39 @@
 /* For license and copyright information please see LEGAL file in repository */
 
-package crypto
\ No newline at end of file
+package crypto
+
+import (
+	"sync"
+
+	"../protocol"
+)
+
+// Suites store application cipher suites e.g. to find them by ID in change cipher spec frames.
+var Suites CipherSuites
+
+// CipherSuites store registered cipher suites to find them by ID.
+// It is safe to register and get cipher suites concurrently.
+type CipherSuites struct {
+	mutex    sync.RWMutex
+	poolByID map[uint64]protocol.CipherSuite
+}
+
+//libgo:impl protocol.CipherSuites
+func (css *CipherSuites) RegisterCipherSuite(cs protocol.CipherSuite) {
+	if cs.ID() == 0 {
+		panic("CipherSuite must have valid ID to register it. Can't register cipher suite with 0 ID.")
+	}
+
+	css.mutex.Lock()
+	if css.poolByID == nil {
+		css.poolByID = make(map[uint64]protocol.CipherSuite)
+	}
+	css.poolByID[cs.ID()] = cs
+	css.mutex.Unlock()
+}
+func (css *CipherSuites) GetCipherSuiteByID(id uint64) (cs protocol.CipherSuite) {
+	css.mutex.RLock()
+	cs = css.poolByID[id]
+	css.mutex.RUnlock()
+	return
+}
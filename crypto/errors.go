@@ -0,0 +1,34 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package crypto
+
+import (
+	er "../error"
+	"../protocol"
+)
+
+const domainEnglish = "Cryptography"
+
+// Declare package errors
+var (
+	ErrSignatureNotValid er.Error
+	ErrCipherSuiteNotSet er.Error
+)
+
+func init() {
+	ErrSignatureNotValid.Init("domain/geniuses.group; type=error; package=crypto; name=signature-not-valid")
+	ErrSignatureNotValid.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Signature Not Valid",
+		"Given signature not made by the cipher secret for the given data",
+		"",
+		"",
+		nil)
+
+	ErrCipherSuiteNotSet.Init("domain/geniuses.group; type=error; package=crypto; name=cipher-suite-not-set")
+	ErrCipherSuiteNotSet.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Cipher Suite Not Set",
+		"Cipher can't rekey without a cipher suite",
+		"",
+		"Give a registered cipher suite e.g. get it by Suites.GetCipherSuiteByID()",
+		nil)
+}
@@ -0,0 +1,66 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"../binary"
+	"../protocol"
+)
+
+// Session implement protocol.Cipher by a secret that peers agreed on it in the connection handshake.
+// Signatures are HMAC-SHA256 by a key that derive from the secret, so the secret itself never use directly.
+type Session struct {
+	suite   protocol.CipherSuite
+	secret  [32]byte
+	signKey [32]byte
+}
+
+// Init initialize the session cipher by the handshake agreed suite and secret.
+func (s *Session) Init(suite protocol.CipherSuite, secret [32]byte) {
+	s.suite = suite
+	s.secret = secret
+	s.signKey = s.derive([]byte("sign"))
+}
+
+//libgo:impl protocol.Cipher
+func (s *Session) CipherSuite() protocol.CipherSuite { return s.suite }
+func (s *Session) PublicKey() protocol.Codec         { return nil } // Session secret has no public part.
+func (s *Session) Sign(data []byte) (signature []byte) {
+	var mac = hmac.New(sha256.New, s.signKey[:])
+	mac.Write(data)
+	signature = mac.Sum(nil)
+	return
+}
+func (s *Session) Verify(data, signature []byte) (err protocol.Error) {
+	if !hmac.Equal(s.Sign(data), signature) {
+		err = &ErrSignatureNotValid
+	}
+	return
+}
+
+// Rekey derive next secret from current one and given suite ID, so both peers reach same new secret without any new handshake.
+// Old secret can't derive from new one, so leak of new keys don't expose past traffic.
+func (s *Session) Rekey(suite protocol.CipherSuite) (cipher protocol.Cipher, err protocol.Error) {
+	if suite == nil {
+		return nil, &ErrCipherSuiteNotSet
+	}
+
+	var info = make([]byte, 5+8)
+	copy(info, "rekey")
+	binary.LittleEndian.PutUint64(info[5:], suite.ID())
+
+	var next Session
+	next.Init(suite, s.derive(info))
+	cipher = &next
+	return
+}
+
+func (s *Session) derive(info []byte) (key [32]byte) {
+	var mac = hmac.New(sha256.New, s.secret[:])
+	mac.Write(info)
+	mac.Sum(key[:0])
+	return
+}
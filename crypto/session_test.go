@@ -0,0 +1,77 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package crypto
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"../protocol"
+)
+
+type testCipherSuite struct {
+	protocol.CipherSuite
+	id uint64
+}
+
+func (cs testCipherSuite) ID() uint64 { return cs.id }
+
+func TestSession(t *testing.T) {
+	var data = []byte("stream data")
+	var a, b Session
+	a.Init(testCipherSuite{id: 1}, [32]byte{1, 2, 3})
+	b.Init(testCipherSuite{id: 1}, [32]byte{1, 2, 3})
+
+	var signature = a.Sign(data)
+	if err := b.Verify(data, signature); err != nil {
+		t.Errorf("Session.Verify() same secret error = %v", err)
+	}
+	if err := b.Verify([]byte("other data"), signature); err == nil {
+		t.Errorf("Session.Verify() other data error = nil")
+	}
+
+	var suite = testCipherSuite{id: 2}
+	var nextA, _ = a.Rekey(suite)
+	var nextB, _ = b.Rekey(suite)
+	if nextA.CipherSuite() != protocol.CipherSuite(suite) {
+		t.Errorf("Session.Rekey() suite = %v, want %v", nextA.CipherSuite(), suite)
+	}
+	var nextSignature = nextA.Sign(data)
+	if err := nextB.Verify(data, nextSignature); err != nil {
+		t.Errorf("Session.Rekey() peers not reach same secret, error = %v", err)
+	}
+	if bytes.Equal(signature, nextSignature) {
+		t.Errorf("Session.Rekey() signature not changed")
+	}
+	if err := a.Verify(data, nextSignature); err == nil {
+		t.Errorf("Session.Verify() old secret verify new signature")
+	}
+	if _, err := a.Rekey(nil); err == nil {
+		t.Errorf("Session.Rekey(nil) error = nil")
+	}
+}
+
+func TestCipherSuites(t *testing.T) {
+	var css CipherSuites
+	if cs := css.GetCipherSuiteByID(1); cs != nil {
+		t.Errorf("CipherSuites.GetCipherSuiteByID() on empty = %v, want nil", cs)
+	}
+
+	var wg sync.WaitGroup
+	for i := uint64(1); i <= 8; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			css.RegisterCipherSuite(testCipherSuite{id: id})
+			css.GetCipherSuiteByID(id)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := uint64(1); i <= 8; i++ {
+		if cs := css.GetCipherSuiteByID(i); cs == nil || cs.ID() != i {
+			t.Errorf("CipherSuites.GetCipherSuiteByID(%d) = %v", i, cs)
+		}
+	}
+}
@@ -7,30 +7,76 @@ import (
 )
 
 // NewChain wrap an error and additional information usually use in logging to save more details about error.
-func NewChain(err *Error, info string) (ce *ChainError) {
+// Given error can be a ChainError to make a longer chain. Chain has the ID, media type and details of its root Error.
+func NewChain(err protocol.Error, info string) (ce *ChainError) {
 	if err == nil {
 		return
 	}
+	var root = RootError(err)
+	if root == nil {
+		return
+	}
 	return &ChainError{
-		Err: err,
-		info:  info,
+		Err:  root,
+		past: err,
+		info: info,
 	}
 }
 
 // ChainError is a extended implementation of Error to carry custom details along error.
 type ChainError struct {
 	*Err
+	past protocol.Error
 	info string
 }
 
-func (ce *ChainError) PastChain() protocol.Error { return ce.Err }
+// chainer is implemented by any error that wrap a past error in a chain like ChainError.
+type chainer interface {
+	PastChain() protocol.Error
+}
+
+// RootError return the first Error in the chain of the given error or nil if the chain doesn't end by an Error.
+func RootError(err protocol.Error) *Error {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return e
+		case chainer:
+			err = e.PastChain()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (ce *ChainError) PastChain() protocol.Error { return ce.past }
+func (ce *ChainError) Info() string              { return ce.info }
 func (ce *ChainError) ToString() string {
-	return "\n" + ce.Err.ToString() + "\n	Chain Info: " + ce.info
+	return "\n" + ce.past.Error() + "\n	Chain Info: " + ce.info
 }
 
+// Equal compare the root Error of the chain with the given error chain.
+func (ce *ChainError) Equal(err protocol.Error) bool { return ce.Err.Equal(err) }
+
 // Go compatibility methods. Unwrap provides compatibility for Go 1.13 error chains.
 func (ce *ChainError) Error() string { return ce.ToString() }
-func (ce *ChainError) Cause() error  { return ce.Err }
-func (ce *ChainError) Unwrap() error { return ce.Err }
-// func (ce *ChainError) Is(error) bool
-// func (ce *ChainError) As(any) bool 
+func (ce *ChainError) Cause() error  { return ce.past }
+func (ce *ChainError) Unwrap() error { return ce.past }
+
+// Is report the target is the root Error of the chain by its ID.
+func (ce *ChainError) Is(target error) bool { return ce.Err.Is(target) }
+
+// As set the target to the root Error of the chain if it is a **Error, or to the chain itself if it is a **ChainError.
+// Other targets find by errors.As() in the past errors by Unwrap().
+func (ce *ChainError) As(target any) bool {
+	switch t := target.(type) {
+	case **Error:
+		*t = ce.Err
+		return true
+	case **ChainError:
+		*t = ce
+		return true
+	}
+	return false
+}
@@ -38,15 +38,22 @@ func (e *Error) Init(mediatype string) {
 	}
 }
 
-// Equal compare two Error.
+// Equal compare two Error. The given error can be a chain of errors e.g. ChainError, so it is equal if
+// any error in its chain has the same ID.
 func (e *Error) Equal(err protocol.Error) bool {
-	if e == nil && err == nil {
-		return true
+	if e == nil || err == nil {
+		return e == nil && err == nil
 	}
-	if e != nil && err != nil && e.ID() == err.ID() {
-		return true
+	for err != nil {
+		if e.ID() == err.ID() {
+			return true
+		}
+		var chain, ok = err.(chainer)
+		if !ok {
+			break
+		}
+		err = chain.PastChain()
 	}
-	// TODO::: check err as chain error
 	return false
 }
 
@@ -64,5 +71,9 @@ func (e *Error) Notify() {
 func (e *Error) Error() string { return e.MT.MediaType() }
 func (e *Error) Cause() error  { return nil }
 func (e *Error) Unwrap() error { return nil }
-// func (e *Error) Is(error) bool
-// func (e *Error) As(any) bool 
+
+// Is report the target is the same error by its ID, so errors.Is(err, &ErrX) works on any error in a chain.
+func (e *Error) Is(target error) bool {
+	var targetErr, ok = target.(protocol.Error)
+	return ok && targetErr.ID() == e.ID()
+}
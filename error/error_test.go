@@ -0,0 +1,121 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+var (
+	testErrA Error
+	testErrB Error
+)
+
+func init() {
+	testErrA.Init("domain/geniuses.group; type=error; package=error; name=test_a")
+	testErrB.Init("domain/geniuses.group; type=error; package=error; name=test_b")
+}
+
+func TestEqual(t *testing.T) {
+	var chainA = NewChain(&testErrA, "first")
+	var chainAA = NewChain(chainA, "second")
+	var chainB = NewChain(&testErrB, "first")
+
+	var tests = []struct {
+		name string
+		e    protocol.Error
+		err  protocol.Error
+		want bool
+	}{
+		{"same error", &testErrA, &testErrA, true},
+		{"other error", &testErrA, &testErrB, false},
+		{"chain of error", &testErrA, chainA, true},
+		{"long chain of error", &testErrA, chainAA, true},
+		{"chain of other error", &testErrA, chainB, false},
+		{"chain with error", chainAA, &testErrA, true},
+		{"chain with other error", chainAA, &testErrB, false},
+		{"chain with chain", chainAA, chainA, true},
+		{"chain with other chain", chainAA, chainB, false},
+		{"nil error", &testErrA, nil, false},
+		{"both nil", (*Error)(nil), nil, true},
+	}
+	for _, tt := range tests {
+		if got := tt.e.Equal(tt.err); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIs(t *testing.T) {
+	var chainA = NewChain(&testErrA, "first")
+	var chainAA = NewChain(chainA, "second")
+
+	var tests = []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"same error", &testErrA, &testErrA, true},
+		{"other error", &testErrA, &testErrB, false},
+		{"chain", chainAA, &testErrA, true},
+		{"chain of other error", chainAA, &testErrB, false},
+		{"chain in chain", chainAA, chainA, true},
+		{"wrapped by fmt", fmt.Errorf("wrap: %w", chainAA), &testErrA, true},
+		{"go error", errors.New("test_a"), &testErrA, false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(tt.err, tt.target); got != tt.want {
+			t.Errorf("%s: errors.Is() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAs(t *testing.T) {
+	var chainA = NewChain(&testErrA, "first")
+	var chainAA = NewChain(chainA, "second")
+
+	var tests = []struct {
+		name      string
+		err       error
+		wantError *Error
+		wantChain *ChainError
+	}{
+		{"error", &testErrA, &testErrA, nil},
+		{"chain", chainA, &testErrA, chainA},
+		{"long chain", chainAA, &testErrA, chainAA},
+		{"wrapped by fmt", fmt.Errorf("wrap: %w", chainAA), &testErrA, chainAA},
+	}
+	for _, tt := range tests {
+		var e *Error
+		if ok := errors.As(tt.err, &e); !ok || e != tt.wantError {
+			t.Errorf("%s: errors.As(*Error) = %v, %v, want %v", tt.name, e, ok, tt.wantError)
+		}
+		var ce *ChainError
+		if ok := errors.As(tt.err, &ce); ok != (tt.wantChain != nil) || ce != tt.wantChain {
+			t.Errorf("%s: errors.As(*ChainError) = %v, %v, want %v", tt.name, ce, ok, tt.wantChain)
+		}
+	}
+}
+
+func TestNewChain(t *testing.T) {
+	var chainA = NewChain(&testErrA, "first")
+	var chainAA = NewChain(chainA, "second")
+
+	if chainAA.PastChain() != chainA || chainAA.Info() != "second" {
+		t.Errorf("NewChain() past = %v, info = %q, want %v, %q", chainAA.PastChain(), chainAA.Info(), chainA, "second")
+	}
+	if chainAA.ID() != testErrA.ID() || chainAA.MediaType() != testErrA.MediaType() {
+		t.Errorf("NewChain() ID = %v, media type = %q, want root ones", chainAA.ID(), chainAA.MediaType())
+	}
+	if RootError(chainAA) != &testErrA {
+		t.Errorf("RootError() = %v, want %v", RootError(chainAA), &testErrA)
+	}
+	if NewChain(nil, "info") != nil {
+		t.Errorf("NewChain(nil) must be nil")
+	}
+}
@@ -24,4 +24,11 @@ func init() {
 		"Sorry it's us not your fault! Contact administrator of platform",
 		"Trace error by enable panic recovery to find nil error detection problem",
 		nil)
+
+	ErrProblemMalformed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Problem Malformed",
+		"Given problem details is not a valid RFC 7807 JSON object",
+		"",
+		"",
+		nil)
 }
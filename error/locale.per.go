@@ -24,4 +24,11 @@ func init() {
 		"اشکال بوجود آماده بدلیل نقض عملیات توسعه ما می باشد. خواهشمندیم با پشتیبانی نرم افزار برای رفع این مشکل در تماس باشید",
 		"خطای بوجود آمده را با استفاده از فعال سازی قابلیت کامپایلر زبان برنامه نویسی خود، منشا خطا ناموجود را پیدا کنید",
 		nil)
+
+	ErrProblemMalformed.SetDetail(protocol.LanguagePersian, domainPersian,
+		"جزییات مشکل نامعتبر",
+		"جزییات مشکل داده شده یک شی JSON معتبر طبق RFC 7807 نیست",
+		"",
+		"",
+		nil)
 }
@@ -6,9 +6,12 @@ package error
 var (
 	ErrNotFound Error
 	ErrNotExist Error
+
+	ErrProblemMalformed Error
 )
 
 func init() {
 	ErrNotFound.Init("domain/geniuses.group; type=error; package=error; name=not_found")
 	ErrNotExist.Init("domain/geniuses.group; type=error; package=error; name=not_exist")
+	ErrProblemMalformed.Init("domain/geniuses.group; type=error; package=error; name=problem_malformed")
 }
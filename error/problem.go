@@ -0,0 +1,109 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package error
+
+import (
+	"encoding/json"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// ProblemMediaType is the media type of the RFC 7807 problem details in JSON.
+const ProblemMediaType = "application/problem+json"
+
+// ProblemTypeBlank is the problem type when the error has no reference URI, so the problem has no more semantics
+// than its status. Clients find the error by the mediaType extension member.
+// https://datatracker.ietf.org/doc/html/rfc7807#section-4.2
+const ProblemTypeBlank = "about:blank"
+
+// ProblemDefaultStatus is the problem status when the error occurrence has no status e.g. a service failed without set it.
+const ProblemDefaultStatus = 500
+
+// Problem is the RFC 7807 problem details of an error to send it to clients e.g. as an HTTP response body.
+// https://datatracker.ietf.org/doc/html/rfc7807
+// Chain info of ChainError never add to the problem due to it can expose internal details to clients.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extension members
+	MediaType string `json:"mediaType,omitempty"`
+	Internal  bool   `json:"internal,omitempty"`
+	Temporary bool   `json:"temporary,omitempty"`
+}
+
+// Init fill the problem by the error and its details in the given language, or in English if not exist.
+// status is the HTTP status code and zero means ProblemDefaultStatus.
+// instance is a URI reference of the error occurrence e.g. request path.
+func (p *Problem) Init(err protocol.Error, lang protocol.LanguageID, status int, instance string) {
+	p.MediaType = err.MediaType()
+	p.Type = err.ReferenceURI()
+	if p.Type == "" {
+		p.Type = ProblemTypeBlank
+	}
+	if status == 0 {
+		status = ProblemDefaultStatus
+	}
+	p.Status = status
+	p.Instance = instance
+	p.Internal = err.Internal()
+	p.Temporary = err.Temporary()
+
+	var detail = err.Detail(lang)
+	if detail == nil {
+		detail = err.Detail(protocol.LanguageEnglish)
+	}
+	if detail == nil {
+		var details = err.Details()
+		if len(details) > 0 {
+			detail = details[0]
+		}
+	}
+	if detail != nil {
+		p.Title = detail.Summary()
+		p.Detail = detail.Overview()
+	}
+}
+
+func (p *Problem) Marshal() (data []byte) {
+	// Problem has no type that json can't encode, so ignore the error.
+	data, _ = json.Marshal(p)
+	return
+}
+
+// Unmarshal decode the problem. Absent type means ProblemTypeBlank.
+func (p *Problem) Unmarshal(data []byte) (err protocol.Error) {
+	*p = Problem{}
+	var goErr = json.Unmarshal(data, p)
+	if goErr != nil {
+		return &ErrProblemMalformed
+	}
+	if p.Type == "" {
+		p.Type = ProblemTypeBlank
+	}
+	return
+}
+
+// ErrorMediaType return the media type of the problem error from the mediaType extension member.
+func (p *Problem) ErrorMediaType() string { return p.MediaType }
+
+// RegisteredError return the error of the problem that registered in the given errors e.g. protocol.App,
+// or ErrNotFound if the problem is not a registered error.
+func (p *Problem) RegisteredError(errors protocol.Errors) (err protocol.Error) {
+	var mediaType = p.ErrorMediaType()
+	if mediaType == "" {
+		return &ErrNotFound
+	}
+	return errors.GetErrorByMediaType(mediaType)
+}
+
+// MarshalProblem encode the error as application/problem+json in the given language.
+// ChainError marshal as its root Error.
+func (e *Error) MarshalProblem(lang protocol.LanguageID, status int, instance string) []byte {
+	var p Problem
+	p.Init(e, lang, status, instance)
+	return p.Marshal()
+}
@@ -0,0 +1,109 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package error
+
+import (
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+var (
+	testErrDetailed Error
+	testErrNoDetail Error
+)
+
+func init() {
+	testErrDetailed.Init("domain/geniuses.group; type=error; package=error; name=test_detailed")
+	testErrDetailed.SetDetail(protocol.LanguageEnglish, "Error", "Detailed", "Error with english detail", "", "", nil)
+	testErrDetailed.SetDetail(protocol.LanguagePersian, "خطا", "جزئیات", "خطا با جزئیات فارسی", "", "", nil)
+	testErrDetailed.SetInternal()
+	testErrNoDetail.Init("domain/geniuses.group; type=error; package=error; name=test_no_detail")
+	testErrNoDetail.SetTemporary()
+}
+
+func TestProblemInit(t *testing.T) {
+	var tests = []struct {
+		name   string
+		err    protocol.Error
+		lang   protocol.LanguageID
+		status int
+		want   Problem
+	}{
+		{"detailed", &testErrDetailed, protocol.LanguageEnglish, 400, Problem{Type: ProblemTypeBlank, Title: "Detailed",
+			Status: 400, Detail: "Error with english detail", Instance: "/a", MediaType: testErrDetailed.MediaType(), Internal: true}},
+		{"other language", &testErrDetailed, protocol.LanguagePersian, 400, Problem{Type: ProblemTypeBlank, Title: "جزئیات",
+			Status: 400, Detail: "خطا با جزئیات فارسی", Instance: "/a", MediaType: testErrDetailed.MediaType(), Internal: true}},
+		{"no status", &testErrDetailed, protocol.LanguageEnglish, 0, Problem{Type: ProblemTypeBlank, Title: "Detailed",
+			Status: ProblemDefaultStatus, Detail: "Error with english detail", Instance: "/a", MediaType: testErrDetailed.MediaType(), Internal: true}},
+		{"no detail", &testErrNoDetail, protocol.LanguageEnglish, 503, Problem{Type: ProblemTypeBlank,
+			Status: 503, Instance: "/a", MediaType: testErrNoDetail.MediaType(), Temporary: true}},
+		{"chain", NewChain(&testErrDetailed, "internal info"), protocol.LanguageEnglish, 400, Problem{Type: ProblemTypeBlank, Title: "Detailed",
+			Status: 400, Detail: "Error with english detail", Instance: "/a", MediaType: testErrDetailed.MediaType(), Internal: true}},
+	}
+	for _, tt := range tests {
+		var p Problem
+		p.Init(tt.err, tt.lang, tt.status, "/a")
+		if p != tt.want {
+			t.Errorf("%s: Problem.Init() = %+v, want %+v", tt.name, p, tt.want)
+		}
+	}
+}
+
+func TestProblemMarshal(t *testing.T) {
+	var tests = []struct {
+		name string
+		err  protocol.Error
+		want string
+	}{
+		{"detailed", &testErrDetailed, `{"type":"about:blank","title":"Detailed","status":500,"detail":"Error with english detail",` +
+			`"instance":"/a","mediaType":"` + testErrDetailed.MediaType() + `","internal":true}`},
+		// Chain info must not expose to clients.
+		{"chain", NewChain(&testErrNoDetail, "internal info"), `{"type":"about:blank","status":500,"instance":"/a",` +
+			`"mediaType":"` + testErrNoDetail.MediaType() + `","temporary":true}`},
+	}
+	for _, tt := range tests {
+		var p Problem
+		p.Init(tt.err, protocol.LanguageEnglish, 0, "/a")
+		if got := string(p.Marshal()); got != tt.want {
+			t.Errorf("%s: Problem.Marshal() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProblemUnmarshal(t *testing.T) {
+	var errs Errors
+	errs.Init()
+	errs.RegisterError(&testErrDetailed)
+
+	var tests = []struct {
+		name       string
+		data       string
+		err        protocol.Error
+		wantType   string
+		registered protocol.Error
+	}{
+		{"registered", `{"type":"about:blank","status":400,"mediaType":"` + testErrDetailed.MediaType() + `"}`, nil, ProblemTypeBlank, &testErrDetailed},
+		{"not registered", `{"type":"about:blank","status":400,"mediaType":"` + testErrNoDetail.MediaType() + `"}`, nil, ProblemTypeBlank, &ErrNotFound},
+		{"other type", `{"type":"https://example.com/probs/out-of-credit","status":403}`, nil, "https://example.com/probs/out-of-credit", &ErrNotFound},
+		{"no type", `{"status":404}`, nil, ProblemTypeBlank, &ErrNotFound},
+		{"malformed", `{"type":`, &ErrProblemMalformed, "", nil},
+	}
+	for _, tt := range tests {
+		var p Problem
+		var err = p.Unmarshal([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("%s: Problem.Unmarshal() error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if p.Type != tt.wantType {
+			t.Errorf("%s: Problem.Unmarshal() type = %q, want %q", tt.name, p.Type, tt.wantType)
+		}
+		if got := p.RegisteredError(&errs); got != tt.registered {
+			t.Errorf("%s: Problem.RegisteredError() = %v, want %v", tt.name, got, tt.registered)
+		}
+	}
+}
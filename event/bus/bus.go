@@ -0,0 +1,304 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package bus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// SegmentSize is the number of events store in each segment record of a bus log.
+// Each event is a version of its segment record, so an event offset is segment*SegmentSize + version offset.
+// Changing it makes existing bus logs unreadable.
+const SegmentSize = 4096
+
+// DefaultBufferSize is the number of dispatched events that buffer when the storage is slower than dispatchers.
+const DefaultBufferSize = 1024
+
+// Delays between retries to append a dispatched event when the storage fails.
+const (
+	MinRetryDelay = 10 * time.Millisecond
+	MaxRetryDelay = 5 * time.Second
+)
+
+// Bus append dispatched events to a durable append-only log in the records storage and deliver them to
+// subscriptions that each one has a named cursor, so subscribers resume after crashes. Delivery is at-least-once,
+// so subscribers must be idempotent e.g. invalidate a cache or reindex a record.
+// A dispatched event is accepted when it encoded and buffered. Listeners must not block dispatchers, so when the buffer
+// is full the event drops and counts in Dropped(). Use Append() for events that must not drop.
+// Failed appends of accepted events retry until they succeed or the bus closed.
+// Just one node must append to a bus by its name.
+// Register it as a listener e.g. storage.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &bus, options)
+// Record events of the bus own events and cursors records ignore, so it can register on the storage that it persist to.
+type Bus struct {
+	// BufferSize is the number of dispatched events that buffer before append. Zero means DefaultBufferSize.
+	BufferSize int
+
+	name     string
+	nameHash [8]byte
+	records  protocol.StorageRecords
+	codecs   map[protocol.EventMainType]Codec
+
+	mutex    sync.Mutex
+	next     uint64        // offset of the next appended event
+	appended chan struct{} // close and replace on each append to wake up waiting subscriptions
+
+	events    chan []byte // encoded records of accepted events
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	// closeMutex guard closed, so no event add to the buffer after Close() close it.
+	closeMutex sync.RWMutex
+	closed     bool
+	closeErr   protocol.Error // last append error of an event that dropped on close
+	dropped    uint64         // atomic access
+}
+
+// RegisterCodec register the codec of events of the main type. Call it before Init.
+// Log and storage record events have default codecs.
+func (b *Bus) RegisterCodec(mainType protocol.EventMainType, codec Codec) {
+	if b.codecs == nil {
+		b.codecs = make(map[protocol.EventMainType]Codec)
+	}
+	b.codecs[mainType] = codec
+}
+
+// Init find the end of the bus log in the storage and start to append dispatched events.
+func (b *Bus) Init(name string, records protocol.StorageRecords) (err protocol.Error) {
+	if _, ok := b.codecs[protocol.EventMainType_Log]; !ok {
+		b.RegisterCodec(protocol.EventMainType_Log, logCodec{})
+	}
+	if _, ok := b.codecs[protocol.EventMainType_Storage_Record]; !ok {
+		b.RegisterCodec(protocol.EventMainType_Storage_Record, recordCodec{})
+	}
+	if b.BufferSize == 0 {
+		b.BufferSize = DefaultBufferSize
+	}
+
+	b.name = name
+	var hash = sha3.Sum256([]byte(name))
+	copy(b.nameHash[:], hash[:])
+	b.records = records
+
+	for segment := uint64(0); ; segment++ {
+		var versions uint64
+		versions, err = records.Count(eventMediaTypeID(), b.segmentID(segment), 0, protocol.StorageRecord_LastSourceVersion)
+		if err != nil {
+			return
+		}
+		if versions < SegmentSize {
+			b.next = segment*SegmentSize + versions
+			break
+		}
+	}
+
+	b.appended = make(chan struct{})
+	b.events = make(chan []byte, b.BufferSize)
+	b.quit = make(chan struct{})
+	b.done = make(chan struct{})
+	go b.append()
+	return
+}
+
+// Close stop the bus after append buffered events. Events dispatch after close are not accepted and count as dropped.
+// Failed appends don't retry after close, so the returned error means some accepted events dropped.
+func (b *Bus) Close() (err protocol.Error) {
+	b.closeOnce.Do(func() {
+		// Stop retries of failed append.
+		close(b.quit)
+		b.closeMutex.Lock()
+		b.closed = true
+		b.closeMutex.Unlock()
+		close(b.events)
+		<-b.done
+	})
+	return b.closeErr
+}
+
+func (b *Bus) Name() string { return b.name }
+
+// Len return number of events in the bus log that is the offset of the next appended event.
+func (b *Bus) Len() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.next
+}
+
+// Dropped return number of dispatched events that can't encode, dispatch on full buffer or after close
+// or fail to append on close.
+func (b *Bus) Dropped() uint64 { return atomic.LoadUint64(&b.dropped) }
+
+// EventHandler encode the event and buffer it to append. It never blocks the dispatcher and drop the event
+// if the buffer is full.
+//
+//libgo:impl protocol.EventListener
+func (b *Bus) EventHandler(e protocol.Event) {
+	if _, ok := b.codecs[e.MainType()]; !ok {
+		return
+	}
+	if recordEvent, ok := e.(protocol.RecordEvent); ok {
+		var mediaTypeID = recordEvent.MediaTypeID()
+		if mediaTypeID == eventMediaTypeID() || mediaTypeID == cursorMediaTypeID() {
+			return
+		}
+	}
+	var record, err = b.encode(e)
+	if err != nil {
+		atomic.AddUint64(&b.dropped, 1)
+		return
+	}
+
+	b.closeMutex.RLock()
+	defer b.closeMutex.RUnlock()
+	if b.closed {
+		atomic.AddUint64(&b.dropped, 1)
+		return
+	}
+	select {
+	case b.events <- record:
+	default:
+		atomic.AddUint64(&b.dropped, 1)
+	}
+}
+
+// Append encode and save the event in the bus log synchronously and return its offset.
+// Use it instead of dispatch when the event must be durable before continue.
+func (b *Bus) Append(event protocol.Event) (offset uint64, err protocol.Error) {
+	var record []byte
+	record, err = b.encode(event)
+	if err != nil {
+		return
+	}
+	return b.save(record)
+}
+
+func (b *Bus) encode(event protocol.Event) (record []byte, err protocol.Error) {
+	var mainType = event.MainType()
+	var codec, ok = b.codecs[mainType]
+	if !ok {
+		return nil, &ErrNoCodec
+	}
+
+	// Header is main type, sub type and media type ID of record events, each as 8 byte little-endian,
+	// to filter events without decode them.
+	record = make([]byte, headerLen, 256)
+	binary.LittleEndian.PutUint64(record[0:], uint64(mainType))
+	binary.LittleEndian.PutUint64(record[8:], uint64(event.SubType()))
+	if recordEvent, ok := event.(protocol.RecordEvent); ok {
+		binary.LittleEndian.PutUint64(record[16:], recordEvent.MediaTypeID())
+	}
+	return codec.Encode(record, event)
+}
+
+func (b *Bus) save(record []byte) (offset uint64, err protocol.Error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	offset = b.next
+	var options = protocol.StorageRecord_SaveOptions{
+		MaxVersion: protocol.StorageRecord_LastSourceVersion,
+	}
+	err = b.records.Save(eventMediaTypeID(), b.segmentID(offset/SegmentSize), record, options)
+	if err != nil {
+		return
+	}
+	b.next++
+	close(b.appended)
+	b.appended = make(chan struct{})
+	return
+}
+
+func (b *Bus) append() {
+	defer close(b.done)
+	for record := range b.events {
+		b.appendDispatched(record)
+	}
+}
+
+// appendDispatched save the accepted event record and retry on storage errors until it saved,
+// so the storage failures never drop accepted events. After close, it stop retry and count the event as dropped.
+func (b *Bus) appendDispatched(record []byte) {
+	var delay = MinRetryDelay
+	for {
+		var _, err = b.save(record)
+		if err == nil {
+			return
+		}
+		select {
+		case <-b.quit:
+			atomic.AddUint64(&b.dropped, 1)
+			b.closeErr = err
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > MaxRetryDelay {
+			delay = MaxRetryDelay
+		}
+	}
+}
+
+const headerLen = 24
+
+// read return the stored event in the offset with its header.
+func (b *Bus) read(offset uint64) (record []byte, err protocol.Error) {
+	record, _, err = b.records.Get(eventMediaTypeID(), b.segmentID(offset/SegmentSize), offset%SegmentSize)
+	if err != nil {
+		return
+	}
+	if len(record) < headerLen {
+		return nil, &ErrBadRecord
+	}
+	return
+}
+
+func (b *Bus) decode(record []byte) (event protocol.Event, err protocol.Error) {
+	var mainType = protocol.EventMainType(binary.LittleEndian.Uint64(record))
+	var codec, ok = b.codecs[mainType]
+	if !ok {
+		return nil, &ErrNoCodec
+	}
+	return codec.Decode(record[headerLen:])
+}
+
+// wait return a channel that close when the event in the offset appended.
+func (b *Bus) wait(offset uint64) <-chan struct{} {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if offset < b.next {
+		return closed
+	}
+	return b.appended
+}
+
+// closed return by wait when no need to wait.
+var closed = make(chan struct{})
+
+// segmentID return the record ID of the segment. First 8 byte is the segment number as little-endian
+// and last 8 byte is the first 8 byte of SHA3-256 hash of the bus name.
+func (b *Bus) segmentID(segment uint64) (id [16]byte) {
+	binary.LittleEndian.PutUint64(id[:], segment)
+	copy(id[8:], b.nameHash[:])
+	return
+}
+
+var (
+	eventMediaType  mediatype.MT
+	cursorMediaType mediatype.MT
+)
+
+func init() {
+	close(closed)
+	eventMediaType.Init("domain/geniuses.group; type=record; package=bus; name=event")
+	cursorMediaType.Init("domain/geniuses.group; type=record; package=bus; name=cursor")
+}
+
+func eventMediaTypeID() uint64  { return uint64(eventMediaType.ID()) }
+func cursorMediaTypeID() uint64 { return uint64(cursorMediaType.ID()) }
@@ -0,0 +1,384 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package bus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/event"
+	"github.com/GeniusesGroup/libgo/log"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+// testRecords is an in memory protocol.StorageRecords that keep versions of each record.
+// If block is not nil, Save wait on it after signal saving, to let tests fill the bus buffer.
+type testRecords struct {
+	protocol.StorageRecords
+
+	mutex   sync.Mutex
+	records map[testRecordKey][][]byte
+	saveErr protocol.Error
+	saving  chan struct{}
+	block   chan struct{}
+}
+
+type testRecordKey struct {
+	mediaTypeID uint64
+	id          [16]byte
+}
+
+func (r *testRecords) Save(mediaTypeID uint64, id [16]byte, record []byte, options protocol.StorageRecord_SaveOptions) (err protocol.Error) {
+	if r.block != nil {
+		r.saving <- struct{}{}
+		<-r.block
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	if r.records == nil {
+		r.records = make(map[testRecordKey][][]byte)
+	}
+	var key = testRecordKey{mediaTypeID, id}
+	if options.MaxVersion == protocol.StorageRecord_NoVersion {
+		r.records[key] = [][]byte{record}
+	} else {
+		r.records[key] = append(r.records[key], record)
+	}
+	return
+}
+func (r *testRecords) Count(mediaTypeID uint64, id [16]byte, offset, limit uint64) (numbers uint64, err protocol.Error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return uint64(len(r.records[testRecordKey{mediaTypeID, id}])), nil
+}
+func (r *testRecords) Get(mediaTypeID uint64, id [16]byte, versionOffset uint64) (record []byte, numbers uint64, err protocol.Error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var versions = r.records[testRecordKey{mediaTypeID, id}]
+	return versions[versionOffset], uint64(len(versions)), nil
+}
+
+func (r *testRecords) setSaveErr(err protocol.Error) {
+	r.mutex.Lock()
+	r.saveErr = err
+	r.mutex.Unlock()
+}
+
+const testMediaTypeID = 7
+
+// testEvents are log events in offsets 0, 2 and 4 and record events in offsets 1 and 3.
+func testEvents() []protocol.Event {
+	var created, deleted event.RecordEvent
+	created.Init("test", [16]byte{}, testMediaTypeID, [16]byte{1}, protocol.CRUDCreate, 0, unix.Now())
+	deleted.Init("test", [16]byte{}, testMediaTypeID+1, [16]byte{2}, protocol.CRUDDelete, 0, unix.Now())
+	return []protocol.Event{
+		log.NewEvent(protocol.LogEvent_Error, "test", "0"),
+		&created,
+		log.NewEvent(protocol.LogEvent_Warning, "test", "2"),
+		&deleted,
+		log.NewEvent(protocol.LogEvent_Error, "test", "4"),
+	}
+}
+
+// testBus return a bus that testEvents dispatched and appended to it.
+func testBus(t *testing.T, records *testRecords) *Bus {
+	var b Bus
+	if err := b.Init("test", records); err != nil {
+		t.Fatalf("Bus.Init() error = %v", err)
+	}
+	for _, e := range testEvents() {
+		b.EventHandler(e)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Bus.Close() error = %v", err)
+	}
+	return &b
+}
+
+func deliveredOffsets(deliveries []Delivery) (offsets []uint64) {
+	for _, d := range deliveries {
+		offsets = append(offsets, d.Offset)
+	}
+	return
+}
+
+func equalOffsets(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSubscriptionFilter(t *testing.T) {
+	var records testRecords
+	var b = testBus(t, &records)
+
+	var tests = []struct {
+		name   string
+		filter Filter
+		want   []uint64
+	}{
+		{"all", Filter{}, []uint64{0, 1, 2, 3, 4}},
+		{"main type", Filter{MainType: protocol.EventMainType_Log}, []uint64{0, 2, 4}},
+		{"sub type", Filter{MainType: protocol.EventMainType_Log, SubType: protocol.EventSubType(protocol.LogEvent_Error)}, []uint64{0, 4}},
+		{"record sub type", Filter{SubType: protocol.EventSubType(protocol.CRUDDelete)}, []uint64{3}},
+		{"media type", Filter{MediaTypeID: testMediaTypeID}, []uint64{1}},
+		{"no match", Filter{MainType: protocol.EventMainType_Log, MediaTypeID: testMediaTypeID}, nil},
+	}
+	for _, tt := range tests {
+		var s, err = b.Subscribe(tt.name, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Bus.Subscribe() error = %v", tt.name, err)
+		}
+		var deliveries []Delivery
+		deliveries, err = s.Next(0)
+		if err != nil {
+			t.Fatalf("%s: Subscription.Next() error = %v", tt.name, err)
+		}
+		if got := deliveredOffsets(deliveries); !equalOffsets(got, tt.want) {
+			t.Errorf("%s: Subscription.Next() offsets = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionReplay(t *testing.T) {
+	var records testRecords
+	testBus(t, &records)
+
+	// Reopen the bus like after a restart, so it must resume from the end of the stored log.
+	var b Bus
+	if err := b.Init("test", &records); err != nil {
+		t.Fatalf("Bus.Init() error = %v", err)
+	}
+	defer b.Close()
+	if b.Len() != 5 {
+		t.Fatalf("Bus.Len() = %d, want 5", b.Len())
+	}
+
+	var s, _ = b.Subscribe("cursor", Filter{})
+	var deliveries, _ = s.Next(2)
+	if got := deliveredOffsets(deliveries); !equalOffsets(got, []uint64{0, 1}) {
+		t.Errorf("Subscription.Next(2) offsets = %v, want [0 1]", got)
+	}
+	if message := deliveries[0].Event.(*log.Event).Message(); message != "0" {
+		t.Errorf("delivered event message = %q, want %q", message, "0")
+	}
+	if err := s.Ack(1); err != nil {
+		t.Fatalf("Subscription.Ack() error = %v", err)
+	}
+	deliveries, _ = s.Next(0)
+	if got := deliveredOffsets(deliveries); !equalOffsets(got, []uint64{2, 3, 4}) {
+		t.Errorf("Subscription.Next() offsets = %v, want [2 3 4]", got)
+	}
+
+	// Not acknowledged events redeliver by Rewind and by resume the cursor after a crash.
+	s.Rewind()
+	deliveries, _ = s.Next(0)
+	if got := deliveredOffsets(deliveries); !equalOffsets(got, []uint64{2, 3, 4}) {
+		t.Errorf("Subscription.Next() after Rewind() offsets = %v, want [2 3 4]", got)
+	}
+	var resumed, _ = b.Subscribe("cursor", Filter{})
+	deliveries, _ = resumed.Next(0)
+	if got := deliveredOffsets(deliveries); !equalOffsets(got, []uint64{2, 3, 4}) {
+		t.Errorf("resumed Subscription.Next() offsets = %v, want [2 3 4]", got)
+	}
+
+	if err := resumed.Seek(0); err != nil {
+		t.Fatalf("Subscription.Seek() error = %v", err)
+	}
+	deliveries, _ = resumed.Next(0)
+	if len(deliveries) != 5 {
+		t.Errorf("Subscription.Next() after Seek(0) delivered %d events, want 5", len(deliveries))
+	}
+
+	// New events wake up waiting subscriptions.
+	var wait = resumed.Wait()
+	if _, err := b.Append(log.NewEvent(protocol.LogEvent_Error, "test", "5")); err != nil {
+		t.Fatalf("Bus.Append() error = %v", err)
+	}
+	select {
+	case <-wait:
+	case <-time.After(time.Second):
+		t.Fatalf("Subscription.Wait() don't wake up on append")
+	}
+	deliveries, _ = resumed.Next(0)
+	if got := deliveredOffsets(deliveries); !equalOffsets(got, []uint64{5}) {
+		t.Errorf("Subscription.Next() after Append() offsets = %v, want [5]", got)
+	}
+}
+
+func TestSubscriptionAck(t *testing.T) {
+	var records testRecords
+	var b = testBus(t, &records)
+	var s, _ = b.Subscribe("cursor", Filter{MainType: protocol.EventMainType_Log})
+	s.Next(2)
+
+	var tests = []struct {
+		offset uint64
+		err    protocol.Error
+		cursor uint64
+	}{
+		{2, nil, 3},
+		{0, nil, 3}, // Ack before the cursor don't move it back.
+		{3, &ErrBadOffset, 3},
+		{4, &ErrBadOffset, 3}, // Not delivered yet.
+	}
+	for _, tt := range tests {
+		if err := s.Ack(tt.offset); err != tt.err {
+			t.Errorf("Subscription.Ack(%d) error = %v, want %v", tt.offset, err, tt.err)
+		}
+		if got := s.Cursor(); got != tt.cursor {
+			t.Errorf("Subscription.Ack(%d) Cursor() = %d, want %d", tt.offset, got, tt.cursor)
+		}
+	}
+	if err := s.Seek(6); err != &ErrBadOffset {
+		t.Errorf("Subscription.Seek() after end error = %v, want %v", err, &ErrBadOffset)
+	}
+}
+
+func TestBusFullBuffer(t *testing.T) {
+	var records = testRecords{
+		saving: make(chan struct{}),
+		block:  make(chan struct{}),
+	}
+	var b = Bus{BufferSize: 1}
+	b.Init("test", &records)
+
+	// First event block the storage, second one fill the buffer and third one must drop without block the dispatcher.
+	b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "0"))
+	<-records.saving
+	b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "1"))
+	var dispatched = make(chan struct{})
+	go func() {
+		b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "2"))
+		close(dispatched)
+	}()
+	select {
+	case <-dispatched:
+	case <-time.After(time.Second):
+		t.Fatalf("Bus.EventHandler() block on full buffer")
+	}
+	if b.Dropped() != 1 {
+		t.Errorf("Bus.Dropped() on full buffer = %d, want 1", b.Dropped())
+	}
+
+	go func() {
+		for range records.saving {
+		}
+	}()
+	close(records.block)
+	if err := b.Close(); err != nil {
+		t.Errorf("Bus.Close() error = %v", err)
+	}
+	close(records.saving)
+	if b.Len() != 2 || b.Dropped() != 1 {
+		t.Errorf("Bus.Len() = %d, Dropped() = %d, want 2, 1", b.Len(), b.Dropped())
+	}
+}
+
+// testEventRecords is testRecords that dispatch a record event on each save like a real storage.
+type testEventRecords struct {
+	testRecords
+	event.EventTarget
+}
+
+func (r *testEventRecords) Save(mediaTypeID uint64, id [16]byte, record []byte, options protocol.StorageRecord_SaveOptions) (err protocol.Error) {
+	err = r.testRecords.Save(mediaTypeID, id, record, options)
+	if err != nil {
+		return
+	}
+	var e event.RecordEvent
+	e.Init("test", [16]byte{}, mediaTypeID, id, protocol.CRUDUpdate, 0, unix.Now())
+	r.DispatchEvent(&e)
+	return
+}
+
+// TestBusOwnStorage check the bus that listen to the storage it persist to, don't append its own records events.
+func TestBusOwnStorage(t *testing.T) {
+	var records testEventRecords
+	var b = Bus{BufferSize: 1}
+	if err := b.Init("test", &records); err != nil {
+		t.Fatalf("Bus.Init() error = %v", err)
+	}
+	records.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &b, protocol.AddEventListenerOptions{})
+
+	records.Save(testMediaTypeID, [16]byte{1}, []byte("record"), protocol.StorageRecord_SaveOptions{})
+	var s, _ = b.Subscribe("own", Filter{})
+	select {
+	case <-s.Wait():
+	case <-time.After(time.Second):
+		t.Fatalf("Bus don't append the storage record event")
+	}
+	var deliveries, _ = s.Next(0)
+	if len(deliveries) != 1 || deliveries[0].Event.(protocol.RecordEvent).MediaTypeID() != testMediaTypeID {
+		t.Fatalf("Subscription.Next() delivered %d events, want just the record event", len(deliveries))
+	}
+	if err := s.Ack(deliveries[0].Offset); err != nil {
+		t.Fatalf("Subscription.Ack() error = %v", err)
+	}
+
+	// Appended event and saved cursor records must not append again.
+	time.Sleep(10 * MinRetryDelay)
+	if err := b.Close(); err != nil {
+		t.Errorf("Bus.Close() error = %v", err)
+	}
+	if b.Len() != 1 || b.Dropped() != 0 {
+		t.Errorf("Bus.Len() = %d, Dropped() = %d, want 1, 0", b.Len(), b.Dropped())
+	}
+}
+
+func TestBusAppendRetry(t *testing.T) {
+	var saveErr er.Error
+	var records = testRecords{saveErr: &saveErr}
+	var b Bus
+	b.Init("test", &records)
+
+	b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "0"))
+	time.Sleep(3 * MinRetryDelay)
+	if b.Len() != 0 {
+		t.Fatalf("Bus.Len() with failed storage = %d, want 0", b.Len())
+	}
+	records.setSaveErr(nil)
+	for i := 0; i < 100 && b.Len() == 0; i++ {
+		time.Sleep(MinRetryDelay)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("Bus.Close() error = %v", err)
+	}
+	if b.Len() != 1 || b.Dropped() != 0 {
+		t.Errorf("Bus.Len() = %d, Dropped() = %d, want 1, 0", b.Len(), b.Dropped())
+	}
+}
+
+func TestBusClose(t *testing.T) {
+	var saveErr er.Error
+	var records = testRecords{saveErr: &saveErr}
+	var b Bus
+	b.Init("test", &records)
+
+	// Accepted event that can't append until close must report by Close.
+	b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "0"))
+	if err := b.Close(); err != &saveErr {
+		t.Errorf("Bus.Close() error = %v, want %v", err, &saveErr)
+	}
+	if b.Dropped() != 1 {
+		t.Errorf("Bus.Dropped() = %d, want 1", b.Dropped())
+	}
+
+	// Events after close are not accepted.
+	b.EventHandler(log.NewEvent(protocol.LogEvent_Error, "test", "1"))
+	if b.Dropped() != 2 {
+		t.Errorf("Bus.Dropped() after close = %d, want 2", b.Dropped())
+	}
+}
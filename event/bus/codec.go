@@ -0,0 +1,108 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package bus
+
+import (
+	"github.com/GeniusesGroup/libgo/event"
+	"github.com/GeniusesGroup/libgo/log"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+// Codec encode events of a main type to store in the bus log and decode them on delivery to subscribers.
+type Codec interface {
+	// Encode append the encoded event to the buf and return it.
+	Encode(buf []byte, event protocol.Event) ([]byte, protocol.Error)
+	Decode(payload []byte) (protocol.Event, protocol.Error)
+}
+
+// SyllabEvent is an event that can encode by Syllab.
+type SyllabEvent interface {
+	protocol.Event
+	protocol.Syllab
+}
+
+// SyllabCodec encode events that implement protocol.Syllab. New must return an empty event to decode to.
+type SyllabCodec struct {
+	New func() SyllabEvent
+}
+
+//libgo:impl Codec
+func (c SyllabCodec) Encode(buf []byte, e protocol.Event) ([]byte, protocol.Error) {
+	var se, ok = e.(SyllabEvent)
+	if !ok {
+		return buf, &ErrBadEvent
+	}
+	return appendSyllab(buf, se), nil
+}
+func (c SyllabCodec) Decode(payload []byte) (e protocol.Event, err protocol.Error) {
+	var se = c.New()
+	err = se.CheckSyllab(payload)
+	if err != nil {
+		return
+	}
+	se.FromSyllab(payload, 0)
+	return se, nil
+}
+
+func appendSyllab(buf []byte, se SyllabEvent) []byte {
+	var ln = len(buf)
+	var size = int(se.LenAsSyllab())
+	if cap(buf)-ln < size {
+		var newBuf = make([]byte, ln, ln+size)
+		copy(newBuf, buf)
+		buf = newBuf
+	}
+	buf = buf[:ln+size]
+	se.ToSyllab(buf[ln:], 0, se.LenOfSyllabStack())
+	return buf
+}
+
+// logCodec store any protocol.LogEvent implementation as log.Event.
+type logCodec struct{}
+
+//libgo:impl Codec
+func (logCodec) Encode(buf []byte, e protocol.Event) ([]byte, protocol.Error) {
+	var logEvent, ok = e.(protocol.LogEvent)
+	if !ok {
+		return buf, &ErrBadEvent
+	}
+	var le, isLogEvent = logEvent.(*log.Event)
+	if !isLogEvent {
+		le = new(log.Event)
+		le.FromLogEvent(logEvent)
+	}
+	return appendSyllab(buf, le), nil
+}
+func (logCodec) Decode(payload []byte) (protocol.Event, protocol.Error) {
+	return SyllabCodec{New: func() SyllabEvent { return new(log.Event) }}.Decode(payload)
+}
+
+// recordCodec store any protocol.RecordEvent implementation as event.RecordEvent.
+type recordCodec struct{}
+
+//libgo:impl Codec
+func (recordCodec) Encode(buf []byte, e protocol.Event) ([]byte, protocol.Error) {
+	var recordEvent, ok = e.(protocol.RecordEvent)
+	if !ok {
+		return buf, &ErrBadEvent
+	}
+	var re, isRecordEvent = recordEvent.(*event.RecordEvent)
+	if !isRecordEvent {
+		re = new(event.RecordEvent)
+		re.Init(recordEvent.Domain(), recordEvent.NodeID(), recordEvent.MediaTypeID(), recordEvent.ID(),
+			recordEvent.CRUD(), recordEvent.VersionOffset(), toUnixTime(recordEvent.Version()))
+		re.SetTime(toUnixTime(recordEvent.Time()))
+	}
+	return appendSyllab(buf, re), nil
+}
+func (recordCodec) Decode(payload []byte) (protocol.Event, protocol.Error) {
+	return SyllabCodec{New: func() SyllabEvent { return new(event.RecordEvent) }}.Decode(payload)
+}
+
+func toUnixTime(t protocol.Time) (ut unix.Time) {
+	if t != nil {
+		ut.ChangeTo(unix.SecElapsed(t.SecondElapsed()), t.NanoSecondElapsed())
+	}
+	return
+}
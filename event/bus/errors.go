@@ -0,0 +1,52 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package bus
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "Event Bus"
+
+// Declare package errors
+var (
+	ErrNoCodec   er.Error
+	ErrBadEvent  er.Error
+	ErrBadRecord er.Error
+	ErrBadOffset er.Error
+)
+
+func init() {
+	ErrNoCodec.Init("domain/geniuses.group; type=error; package=bus; name=no_codec")
+	ErrNoCodec.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"No Codec",
+		"No codec registered for the event main type to store it in the bus log",
+		"",
+		"Register a codec for the event main type by Bus.RegisterCodec() before Bus.Init()",
+		nil)
+
+	ErrBadEvent.Init("domain/geniuses.group; type=error; package=bus; name=bad_event")
+	ErrBadEvent.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Event",
+		"Given event type is not the type that the codec of its main type can encode",
+		"",
+		"",
+		nil)
+
+	ErrBadRecord.Init("domain/geniuses.group; type=error; package=bus; name=bad_record")
+	ErrBadRecord.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Record",
+		"Stored bus log record is corrupted and can't decode to an event",
+		"",
+		"",
+		nil)
+
+	ErrBadOffset.Init("domain/geniuses.group; type=error; package=bus; name=bad_offset")
+	ErrBadOffset.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Offset",
+		"Given offset is not delivered to the subscription yet or is after the end of the bus log",
+		"",
+		"",
+		nil)
+}
@@ -0,0 +1,185 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package bus
+
+import (
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Filter indicate which events deliver to a subscription. Zero value of each field means all.
+type Filter struct {
+	MainType    protocol.EventMainType
+	SubType     protocol.EventSubType
+	MediaTypeID uint64 // Just storage record events have media type, so other events never match a non-zero one.
+}
+
+// Match report the event with the given header pass the filter.
+func (f *Filter) Match(mainType protocol.EventMainType, subType protocol.EventSubType, mediaTypeID uint64) bool {
+	return (f.MainType == protocol.EventMainType_Unset || f.MainType == mainType) &&
+		(f.SubType == protocol.EventSubType_Unset || f.SubType == subType) &&
+		(f.MediaTypeID == 0 || f.MediaTypeID == mediaTypeID)
+}
+
+// Delivery is an event delivered to a subscription by its offset in the bus log to acknowledge it.
+type Delivery struct {
+	Offset uint64
+	Event  protocol.Event
+}
+
+// Subscription deliver bus events that match its filter from its named cursor.
+// Cursor is the offset of the first event that not acknowledged yet and save in the records storage on each Ack().
+type Subscription struct {
+	bus      *Bus
+	name     string
+	filter   Filter
+	cursorID [16]byte
+
+	mutex    sync.Mutex
+	cursor   uint64
+	position uint64 // offset of the next event to check for delivery
+}
+
+// Subscribe return the subscription by the given cursor name that resume from its last acknowledged event.
+// New cursor name start from the first event in the bus log, so it replay all events.
+func (b *Bus) Subscribe(name string, filter Filter) (s *Subscription, err protocol.Error) {
+	s = &Subscription{
+		bus:      b,
+		name:     name,
+		filter:   filter,
+		cursorID: cursorID(b.name, name),
+	}
+
+	var versions uint64
+	versions, err = b.records.Count(cursorMediaTypeID(), s.cursorID, 0, protocol.StorageRecord_LastSourceVersion)
+	if err != nil {
+		return nil, err
+	}
+	if versions > 0 {
+		var record []byte
+		record, _, err = b.records.Get(cursorMediaTypeID(), s.cursorID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 8 {
+			return nil, &ErrBadRecord
+		}
+		s.cursor = binary.LittleEndian.Uint64(record)
+	}
+	s.position = s.cursor
+	return
+}
+
+func (s *Subscription) Name() string   { return s.name }
+func (s *Subscription) Filter() Filter { return s.filter }
+
+// Cursor return the offset of the first not acknowledged event.
+func (s *Subscription) Cursor() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cursor
+}
+
+// Next return up to limit events that match the filter and not delivered yet. Zero limit means no limit.
+// It doesn't wait for new events, so use Wait() when it return no event.
+func (s *Subscription) Next(limit int) (deliveries []Delivery, err protocol.Error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var end = s.bus.Len()
+	for ; s.position < end; s.position++ {
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+		var record []byte
+		record, err = s.bus.read(s.position)
+		if err != nil {
+			return
+		}
+		var mainType = protocol.EventMainType(binary.LittleEndian.Uint64(record[0:]))
+		var subType = protocol.EventSubType(binary.LittleEndian.Uint64(record[8:]))
+		var mediaTypeID = binary.LittleEndian.Uint64(record[16:])
+		if !s.filter.Match(mainType, subType, mediaTypeID) {
+			continue
+		}
+		var event protocol.Event
+		event, err = s.bus.decode(record)
+		if err != nil {
+			return
+		}
+		deliveries = append(deliveries, Delivery{Offset: s.position, Event: event})
+	}
+	return
+}
+
+// Wait return a channel that close when a new event append after the last delivered one.
+func (s *Subscription) Wait() <-chan struct{} {
+	s.mutex.Lock()
+	var position = s.position
+	s.mutex.Unlock()
+	return s.bus.wait(position)
+}
+
+// Ack acknowledge the delivered event in the offset and all events before it, and save the cursor after it.
+// Events after the cursor redeliver after restart or Rewind().
+func (s *Subscription) Ack(offset uint64) (err protocol.Error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if offset >= s.position {
+		return &ErrBadOffset
+	}
+	if offset < s.cursor {
+		return
+	}
+	err = s.saveCursor(offset + 1)
+	return
+}
+
+// Rewind redeliver events after the cursor e.g. when handle delivered events failed.
+func (s *Subscription) Rewind() {
+	s.mutex.Lock()
+	s.position = s.cursor
+	s.mutex.Unlock()
+}
+
+// Seek move the cursor to the offset to replay events from it or skip events before it.
+func (s *Subscription) Seek(offset uint64) (err protocol.Error) {
+	if offset > s.bus.Len() {
+		return &ErrBadOffset
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	err = s.saveCursor(offset)
+	if err != nil {
+		return
+	}
+	s.position = offset
+	return
+}
+
+// saveCursor must call with s.mutex locked.
+func (s *Subscription) saveCursor(cursor uint64) (err protocol.Error) {
+	var record = make([]byte, 8)
+	binary.LittleEndian.PutUint64(record, cursor)
+	var options = protocol.StorageRecord_SaveOptions{
+		MaxVersion: protocol.StorageRecord_NoVersion,
+	}
+	err = s.bus.records.Save(cursorMediaTypeID(), s.cursorID, record, options)
+	if err != nil {
+		return
+	}
+	s.cursor = cursor
+	return
+}
+
+// cursorID return the record ID of the cursor as first 16 byte of SHA3-256 hash of "bus-name/cursor-name".
+func cursorID(busName, cursorName string) (id [16]byte) {
+	var hash = sha3.Sum256([]byte(busName + "/" + cursorName))
+	copy(id[:], hash[:])
+	return
+}
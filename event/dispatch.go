@@ -0,0 +1,49 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package event
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Dispatch is the state of one dispatch of an event that EventTarget pass next to the event to DispatchListener listeners.
+// Phase and propagation keep in it and not in the event, so the same event can dispatch concurrently or again
+// without share them between dispatches, and all listeners get the dispatched event itself.
+type Dispatch struct {
+	event   protocol.Event
+	phase   Phase
+	passive bool
+	stopped bool
+}
+
+// Phase return the current phase of the dispatch.
+func (d *Dispatch) Phase() Phase { return d.phase }
+
+// StopPropagation prevent the dispatch to reach next targets in the capture and bubble path.
+// Other listeners of the current target still receive the event.
+func (d *Dispatch) StopPropagation()         { d.stopped = true }
+func (d *Dispatch) PropagationStopped() bool { return d.stopped }
+
+// PreventDefault cancel the event if it is cancelable and the current listener is not passive.
+func (d *Dispatch) PreventDefault() {
+	if !d.passive {
+		d.event.PreventDefault()
+	}
+}
+
+// DispatchListener is an optional interface of protocol.EventListener for listeners that need the dispatch state
+// e.g. to stop propagation or know the phase. EventTarget call DispatchEventHandler instead of EventHandler if the listener implement it.
+type DispatchListener interface {
+	DispatchEventHandler(event protocol.Event, d *Dispatch)
+}
+
+// Phase indicate which phase of the event flow is currently being evaluated.
+// https://developer.mozilla.org/en-US/docs/Web/API/Event/eventPhase
+type Phase uint8
+
+const (
+	Phase_None Phase = iota
+	Phase_Capturing
+	Phase_AtTarget
+	Phase_Bubbling
+)
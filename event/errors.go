@@ -0,0 +1,25 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package event
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "Event"
+
+// Declare package errors
+var (
+	ErrBadSyllab er.Error
+)
+
+func init() {
+	ErrBadSyllab.Init("domain/geniuses.group; type=error; package=event; name=bad_syllab")
+	ErrBadSyllab.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Syllab",
+		"Encoded event is shorter than expected or its dynamic fields point to outside of the payload",
+		"",
+		"",
+		nil)
+}
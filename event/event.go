@@ -3,16 +3,24 @@
 package event
 
 import (
+	"sync/atomic"
+
+	"github.com/GeniusesGroup/libgo/binary"
 	"github.com/GeniusesGroup/libgo/protocol"
 	"github.com/GeniusesGroup/libgo/time/unix"
 )
 
-// Event implement protocol.LogEvent
+// Event implement protocol.Event
 type Event struct {
 	subType protocol.EventSubType
 	domain  string
 	nodeID  protocol.NodeID
 	time    unix.Time
+
+	// Below fields don't encode by Syllab. Phase and propagation are per dispatch and keep in a Dispatch.
+	cancelable       bool
+	bubbles          bool
+	defaultPrevented int32 // atomic access
 }
 
 func (e *Event) Init(subType protocol.EventSubType, domain string, nodeID [16]byte, time unix.Time) {
@@ -27,32 +35,87 @@ func (e *Event) SubType() protocol.EventSubType   { return e.subType }
 func (e *Event) Domain() string                   { return e.domain }
 func (e *Event) NodeID() protocol.NodeID          { return e.nodeID }
 func (e *Event) Time() protocol.Time              { return &e.time }
-func (e *Event) Cancelable() bool                 { return false }
-func (e *Event) DefaultPrevented() bool           { return false }
-func (e *Event) Bubbles() bool                    { return false }
-func (e *Event) PreventDefault()                  {}
+func (e *Event) Cancelable() bool                 { return e.cancelable }
+func (e *Event) DefaultPrevented() bool           { return atomic.LoadInt32(&e.defaultPrevented) != 0 }
+func (e *Event) Bubbles() bool                    { return e.bubbles }
+
+// PreventDefault cancel the event if it is cancelable.
+// Listeners call it on the Dispatch they receive, so it is ignored in passive listeners.
+func (e *Event) PreventDefault() {
+	if e.cancelable {
+		atomic.StoreInt32(&e.defaultPrevented, 1)
+	}
+}
 
-func (e *Event) SetSubType(subType protocol.EventSubType)   { e.subType = subType }
-func (e *Event) SetDomain(domain string)             { e.domain = domain }
-func (e *Event) SetNodeID(nodeID protocol.NodeID)    { e.nodeID = nodeID }
-func (e *Event) SetTime(time unix.Time)              { e.time = time }
+func (e *Event) SetSubType(subType protocol.EventSubType) { e.subType = subType }
+func (e *Event) SetDomain(domain string)                  { e.domain = domain }
+func (e *Event) SetNodeID(nodeID protocol.NodeID)         { e.nodeID = nodeID }
+func (e *Event) SetTime(time unix.Time)                   { e.time = time }
+func (e *Event) SetCancelable(cancelable bool)            { e.cancelable = cancelable }
+func (e *Event) SetBubbles(bubbles bool)                  { e.bubbles = bubbles }
 
 /*
 	-- protocol.Syllab interface Encoder & Decoder --
 */
 func (e *Event) CheckSyllab(payload []byte) (err protocol.Error) {
-	if len(payload) < int(e.LenOfSyllabStack()) {
-		// err = syllab.ErrShortArrayDecode
+	return e.CheckSyllabAt(payload, 0)
+}
+
+// CheckSyllabAt check the event encoded in the payload from the given stack index is valid to decode.
+// Types embed the Event use it to check its part of their stack.
+func (e *Event) CheckSyllabAt(payload []byte, stackIndex uint32) (err protocol.Error) {
+	if len(payload) < int(stackIndex+e.LenOfSyllabStack()) {
+		return &ErrBadSyllab
 	}
-	return
+	return CheckSyllabHeap(payload, stackIndex+8)
 }
 func (e *Event) FromSyllab(payload []byte, stackIndex uint32) {
+	e.subType = protocol.EventSubType(binary.LittleEndian.Uint64(payload[stackIndex:]))
+	e.domain = string(GetSyllabBytes(payload, stackIndex+8))
+	copy(e.nodeID[:], payload[stackIndex+16:])
+	var sec = binary.LittleEndian.Uint64(payload[stackIndex+32:])
+	var nsec = binary.LittleEndian.Uint32(payload[stackIndex+40:])
+	e.time.ChangeTo(unix.SecElapsed(sec), int32(nsec))
 }
 func (e *Event) ToSyllab(payload []byte, stackIndex, heapIndex uint32) (freeHeapIndex uint32) {
+	binary.LittleEndian.PutUint64(payload[stackIndex:], uint64(e.subType))
+	freeHeapIndex = SetSyllabString(payload, e.domain, stackIndex+8, heapIndex)
+	copy(payload[stackIndex+16:], e.nodeID[:])
+	binary.LittleEndian.PutUint64(payload[stackIndex+32:], uint64(e.time.SecondElapsed()))
+	binary.LittleEndian.PutUint32(payload[stackIndex+40:], uint32(e.time.NanoSecondElapsed()))
 	return
 }
 func (e *Event) LenAsSyllab() uint64      { return uint64(e.LenOfSyllabStack() + e.LenOfSyllabHeap()) }
-func (e *Event) LenOfSyllabStack() uint32 { return 33 }
+func (e *Event) LenOfSyllabStack() uint32 { return 44 }
 func (e *Event) LenOfSyllabHeap() (ln uint32) {
 	return uint32(len(e.domain))
 }
+
+// Below helpers are same as syllab package ones, but syllab package import the log package that import this package.
+
+// CheckSyllabHeap check the dynamic size field that its address and length encoded in the stackIndex is in the payload.
+func CheckSyllabHeap(payload []byte, stackIndex uint32) (err protocol.Error) {
+	var add = uint64(binary.LittleEndian.Uint32(payload[stackIndex:]))
+	var ln = uint64(binary.LittleEndian.Uint32(payload[stackIndex+4:]))
+	if add+ln > uint64(len(payload)) {
+		return &ErrBadSyllab
+	}
+	return
+}
+
+// GetSyllabBytes return the dynamic size field that its address and length encoded in the stackIndex.
+// Returned slice is a part of the payload and not a copy of it.
+func GetSyllabBytes(payload []byte, stackIndex uint32) []byte {
+	var add = binary.LittleEndian.Uint32(payload[stackIndex:])
+	var ln = binary.LittleEndian.Uint32(payload[stackIndex+4:])
+	return payload[add : add+ln]
+}
+
+// SetSyllabString encode the string address and length to the stackIndex and the string itself to the heapIndex.
+func SetSyllabString(payload []byte, s string, stackIndex, heapIndex uint32) (freeHeapIndex uint32) {
+	var ln = uint32(len(s))
+	binary.LittleEndian.PutUint32(payload[stackIndex:], heapIndex)
+	binary.LittleEndian.PutUint32(payload[stackIndex+4:], ln)
+	copy(payload[heapIndex:], s)
+	return heapIndex + ln
+}
@@ -0,0 +1,72 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package event
+
+import (
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+// RecordEvent implement protocol.RecordEvent that storage records dispatch on any change of a record.
+// Its sub type is the CRUD of the change, so listeners can listen to e.g. just deletes.
+type RecordEvent struct {
+	Event
+
+	mediaTypeID   uint64
+	id            [16]byte
+	crud          protocol.CRUD
+	versionOffset uint64
+	version       unix.Time
+}
+
+func (e *RecordEvent) Init(domain string, nodeID [16]byte, mediaTypeID uint64, id [16]byte, crud protocol.CRUD, versionOffset uint64, version unix.Time) {
+	e.mediaTypeID = mediaTypeID
+	e.id = id
+	e.crud = crud
+	e.versionOffset = versionOffset
+	e.version = version
+	e.Event.Init(protocol.EventSubType(crud), domain, nodeID, unix.Now())
+}
+
+func (e *RecordEvent) MainType() protocol.EventMainType { return protocol.EventMainType_Storage_Record }
+func (e *RecordEvent) MediaTypeID() uint64              { return e.mediaTypeID }
+func (e *RecordEvent) ID() [16]byte                     { return e.id }
+func (e *RecordEvent) CRUD() protocol.CRUD              { return e.crud }
+func (e *RecordEvent) VersionOffset() uint64            { return e.versionOffset }
+func (e *RecordEvent) Version() protocol.Time           { return &e.version }
+
+/*
+	-- protocol.Syllab interface Encoder & Decoder --
+*/
+func (e *RecordEvent) CheckSyllab(payload []byte) (err protocol.Error) {
+	if len(payload) < int(e.LenOfSyllabStack()) {
+		return &ErrBadSyllab
+	}
+	return e.Event.CheckSyllab(payload)
+}
+func (e *RecordEvent) FromSyllab(payload []byte, stackIndex uint32) {
+	e.Event.FromSyllab(payload, stackIndex)
+	stackIndex += e.Event.LenOfSyllabStack()
+	e.mediaTypeID = binary.LittleEndian.Uint64(payload[stackIndex:])
+	copy(e.id[:], payload[stackIndex+8:])
+	e.crud = protocol.CRUD(payload[stackIndex+24])
+	e.versionOffset = binary.LittleEndian.Uint64(payload[stackIndex+25:])
+	var sec = binary.LittleEndian.Uint64(payload[stackIndex+33:])
+	var nsec = binary.LittleEndian.Uint32(payload[stackIndex+41:])
+	e.version.ChangeTo(unix.SecElapsed(sec), int32(nsec))
+}
+func (e *RecordEvent) ToSyllab(payload []byte, stackIndex, heapIndex uint32) (freeHeapIndex uint32) {
+	freeHeapIndex = e.Event.ToSyllab(payload, stackIndex, heapIndex)
+	stackIndex += e.Event.LenOfSyllabStack()
+	binary.LittleEndian.PutUint64(payload[stackIndex:], e.mediaTypeID)
+	copy(payload[stackIndex+8:], e.id[:])
+	payload[stackIndex+24] = byte(e.crud)
+	binary.LittleEndian.PutUint64(payload[stackIndex+25:], e.versionOffset)
+	binary.LittleEndian.PutUint64(payload[stackIndex+33:], uint64(e.version.SecondElapsed()))
+	binary.LittleEndian.PutUint32(payload[stackIndex+41:], uint32(e.version.NanoSecondElapsed()))
+	return
+}
+func (e *RecordEvent) LenAsSyllab() uint64      { return uint64(e.LenOfSyllabStack() + e.LenOfSyllabHeap()) }
+func (e *RecordEvent) LenOfSyllabStack() uint32 { return 45 + e.Event.LenOfSyllabStack() }
+func (e *RecordEvent) LenOfSyllabHeap() uint32  { return e.Event.LenOfSyllabHeap() }
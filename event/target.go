@@ -4,62 +4,205 @@ package event
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
-// EventTarget must declare separately for each protocol.EventMainType types.
-// otherwise need this struct that use 2KB for each instance.
-// 		cl   [256]*[]customListener // 256 is max protocol.EventMainType
-// Or use map but need some benchmarks to check performance.
+// EventTarget implement protocol.EventTarget.
+// Listeners keep per protocol.EventMainType in a copy-on-write map, so DispatchEvent never lock and
+// can call concurrently with itself and with add or remove listeners.
+// Listeners added by protocol.EventMainType_Unset or protocol.EventSubType_Unset receive all main or sub types.
+// Zero value is ready to use.
 type EventTarget struct {
-	sync sync.Mutex
-	lls  *[]customListener
+	parent    *EventTarget
+	sync      sync.Mutex
+	listeners atomic.Pointer[map[protocol.EventMainType][]*listener]
 }
 
-type customListener struct {
-	eventSubType  protocol.EventSubType
-	eventListener protocol.EventListener
+type listener struct {
+	mainType protocol.EventMainType
+	subType  protocol.EventSubType
+	callback protocol.EventListener
+	dispatch DispatchListener // callback if it implement DispatchListener
+	capture  bool
+	once     bool
+	passive  bool
+	removed  int32 // atomic access
 }
 
-//go:norace
-// TODO::: we know that race exist in line #44,#59 with #28,#40,#52, but it seems ok to have race there. Add atomic mechanism??
+// SetParent set the parent target. Events dispatch on the target propagate to the parent chain
+// in the capture phase from the root and in the bubble phase to the root if the event bubbles.
+// e.g. a connection target can set the application target as its parent.
+func (et *EventTarget) SetParent(parent *EventTarget) { et.parent = parent }
+func (et *EventTarget) Parent() *EventTarget          { return et.parent }
+
+// DispatchEvent invoke listeners of the target and its parents synchronously in the DOM event flow order.
+// Listeners receive the event itself, and DispatchListener listeners also a new Dispatch of the event,
+// so the event can dispatch concurrently or again.
+// https://dom.spec.whatwg.org/#concept-event-dispatch
 func (et *EventTarget) DispatchEvent(event protocol.Event) {
-	var lls = *et.lls
-	var eventSubType = event.SubType()
-	for i := 0; i < len(lls); i++ {
-		var cl = lls[i]
-		if cl.eventSubType == protocol.EventSubType_Unset || cl.eventSubType == eventSubType {
-			cl.eventListener.EventHandler(event)
+	var d = Dispatch{event: event}
+	var mainType = event.MainType()
+
+	var path []*EventTarget
+	for parent := et.parent; parent != nil; parent = parent.parent {
+		path = append(path, parent)
+	}
+
+	for i := len(path) - 1; i >= 0 && !d.stopped; i-- {
+		path[i].invoke(&d, mainType, Phase_Capturing)
+	}
+	if !d.stopped {
+		et.invoke(&d, mainType, Phase_AtTarget)
+	}
+	if event.Bubbles() {
+		for i := 0; i < len(path) && !d.stopped; i++ {
+			path[i].invoke(&d, mainType, Phase_Bubbling)
+		}
+	}
+	d.phase = Phase_None
+	d.passive = false
+}
+
+// invoke call the target listeners that match the event and the phase.
+func (et *EventTarget) invoke(d *Dispatch, mainType protocol.EventMainType, phase Phase) {
+	var listeners = et.listeners.Load()
+	if listeners == nil {
+		return
+	}
+	var typed = (*listeners)[mainType]
+	var all []*listener
+	if mainType != protocol.EventMainType_Unset {
+		all = (*listeners)[protocol.EventMainType_Unset]
+	}
+
+	d.phase = phase
+	if phase == Phase_AtTarget {
+		// At target, capture listeners call before others.
+		et.invokeListeners(d, typed, true)
+		et.invokeListeners(d, all, true)
+		et.invokeListeners(d, typed, false)
+		et.invokeListeners(d, all, false)
+	} else {
+		var capture = phase == Phase_Capturing
+		et.invokeListeners(d, typed, capture)
+		et.invokeListeners(d, all, capture)
+	}
+}
+
+func (et *EventTarget) invokeListeners(d *Dispatch, listeners []*listener, capture bool) {
+	var eventSubType = d.event.SubType()
+	for _, l := range listeners {
+		if l.capture != capture || atomic.LoadInt32(&l.removed) != 0 {
+			continue
+		}
+		if l.subType != protocol.EventSubType_Unset && l.subType != eventSubType {
+			continue
+		}
+		if l.once {
+			// Other dispatches may call the listener at the same time, so just one of them must win.
+			if !atomic.CompareAndSwapInt32(&l.removed, 0, 1) {
+				continue
+			}
+			et.removeListener(l)
+		}
+		d.passive = l.passive
+		if l.dispatch != nil {
+			l.dispatch.DispatchEventHandler(d.event, d)
+		} else {
+			l.callback.EventHandler(d.event)
 		}
 	}
 }
 
+// AddEventListener append the listener if the same main type, sub type, callback and capture not added before.
+// Callback must be comparable e.g. a pointer.
 func (et *EventTarget) AddEventListener(mainType protocol.EventMainType, subType protocol.EventSubType, callback protocol.EventListener, options protocol.AddEventListenerOptions) {
 	et.sync.Lock()
-	var lls = *et.lls
-	var ln = len(lls)
-	var newLLS = make([]customListener, ln+1)
-	copy(newLLS, lls)
-	newLLS[ln-1] = customListener{subType, callback}
-	et.lls = &newLLS
-	// TODO::: handle options here or caller layer must handle it?
-	et.sync.Unlock()
+	defer et.sync.Unlock()
+
+	var old = et.listeners.Load()
+	var typed []*listener
+	if old != nil {
+		typed = (*old)[mainType]
+	}
+	for _, l := range typed {
+		if l.subType == subType && l.callback == callback && l.capture == options.Capture {
+			return
+		}
+	}
+
+	var newTyped = make([]*listener, len(typed), len(typed)+1)
+	copy(newTyped, typed)
+	var dispatch, _ = callback.(DispatchListener)
+	newTyped = append(newTyped, &listener{
+		mainType: mainType,
+		subType:  subType,
+		callback: callback,
+		dispatch: dispatch,
+		capture:  options.Capture,
+		once:     options.Once,
+		passive:  options.Passive,
+	})
+	et.store(old, mainType, newTyped)
 }
 
+// RemoveEventListener remove the listener that added with the same main type, sub type, callback and capture.
+// Removed listener don't receive events even in a dispatch that is in progress.
 func (et *EventTarget) RemoveEventListener(mainType protocol.EventMainType, subType protocol.EventSubType, callback protocol.EventListener, options protocol.EventListenerOptions) {
 	et.sync.Lock()
-	var lls = *et.lls
-	var ln = len(lls)
-	for i := 0; i < ln; i++ {
-		var cl = lls[i]
-		if cl.eventSubType == subType && cl.eventListener == callback {
-			var newLLS = make([]customListener, ln-1)
-			copy(newLLS, lls[:i])
-			copy(newLLS[i:], lls[i+1:])
-			et.lls = &newLLS
-			break
+	defer et.sync.Unlock()
+
+	var old = et.listeners.Load()
+	if old == nil {
+		return
+	}
+	for _, l := range (*old)[mainType] {
+		if l.subType == subType && l.callback == callback && l.capture == options.Capture {
+			atomic.StoreInt32(&l.removed, 1)
+			et.remove(old, l)
+			return
 		}
 	}
+}
+
+// removeListener remove the once listener after its call.
+func (et *EventTarget) removeListener(l *listener) {
+	et.sync.Lock()
+	et.remove(et.listeners.Load(), l)
 	et.sync.Unlock()
 }
+
+// remove must call with et.sync locked.
+func (et *EventTarget) remove(old *map[protocol.EventMainType][]*listener, l *listener) {
+	if old == nil {
+		return
+	}
+	var typed = (*old)[l.mainType]
+	for i, tl := range typed {
+		if tl == l {
+			var newTyped = make([]*listener, 0, len(typed)-1)
+			newTyped = append(newTyped, typed[:i]...)
+			newTyped = append(newTyped, typed[i+1:]...)
+			et.store(old, l.mainType, newTyped)
+			return
+		}
+	}
+}
+
+// store must call with et.sync locked.
+func (et *EventTarget) store(old *map[protocol.EventMainType][]*listener, mainType protocol.EventMainType, typed []*listener) {
+	var listeners = make(map[protocol.EventMainType][]*listener)
+	if old != nil {
+		for mt, ls := range *old {
+			listeners[mt] = ls
+		}
+	}
+	if len(typed) == 0 {
+		delete(listeners, mainType)
+	} else {
+		listeners[mainType] = typed
+	}
+	et.listeners.Store(&listeners)
+}
@@ -0,0 +1,284 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package event
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// testListener record its calls in the shared calls and can stop propagation or prevent default of the dispatch.
+type testListener struct {
+	name           string
+	calls          *[]string
+	stop           bool
+	preventDefault bool
+}
+
+func (l *testListener) EventHandler(e protocol.Event) { *l.calls = append(*l.calls, l.name+":plain") }
+func (l *testListener) DispatchEventHandler(e protocol.Event, d *Dispatch) {
+	*l.calls = append(*l.calls, l.name+":"+phaseNames[d.Phase()])
+	if l.stop {
+		d.StopPropagation()
+	}
+	if l.preventDefault {
+		d.PreventDefault()
+	}
+}
+
+var phaseNames = [...]string{Phase_None: "none", Phase_Capturing: "capture", Phase_AtTarget: "target", Phase_Bubbling: "bubble"}
+
+// testTargets return a target with parent and root targets that each one has a capture and a bubble listener.
+func testTargets(calls *[]string) (target *EventTarget, listeners map[string]*testListener) {
+	var root, parent EventTarget
+	target = new(EventTarget)
+	parent.SetParent(&root)
+	target.SetParent(&parent)
+
+	listeners = make(map[string]*testListener)
+	for _, t := range []struct {
+		name   string
+		target *EventTarget
+	}{{"root", &root}, {"parent", &parent}, {"target", target}} {
+		for _, capture := range []bool{true, false} {
+			var name = t.name + "-bubble"
+			if capture {
+				name = t.name + "-capture"
+			}
+			var l = &testListener{name: name, calls: calls}
+			listeners[name] = l
+			var options protocol.AddEventListenerOptions
+			options.Capture = capture
+			t.target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, l, options)
+		}
+	}
+	return
+}
+
+func TestEventTargetDispatchEvent(t *testing.T) {
+	var tests = []struct {
+		name    string
+		bubbles bool
+		stop    string
+		want    []string
+	}{
+		{"capture and bubble", true, "", []string{"root-capture:capture", "parent-capture:capture",
+			"target-capture:target", "target-bubble:target", "parent-bubble:bubble", "root-bubble:bubble"}},
+		{"not bubbles", false, "", []string{"root-capture:capture", "parent-capture:capture",
+			"target-capture:target", "target-bubble:target"}},
+		{"stop in capture", true, "parent-capture", []string{"root-capture:capture", "parent-capture:capture"}},
+		{"stop at target call other target listeners", true, "target-capture", []string{"root-capture:capture", "parent-capture:capture",
+			"target-capture:target", "target-bubble:target"}},
+		{"stop in bubble", true, "parent-bubble", []string{"root-capture:capture", "parent-capture:capture",
+			"target-capture:target", "target-bubble:target", "parent-bubble:bubble"}},
+	}
+	for _, tt := range tests {
+		var calls []string
+		var target, listeners = testTargets(&calls)
+		if tt.stop != "" {
+			listeners[tt.stop].stop = true
+		}
+		var e Event
+		e.SetBubbles(tt.bubbles)
+
+		target.DispatchEvent(&e)
+		if !equalStrings(calls, tt.want) {
+			t.Errorf("%s: DispatchEvent() calls = %v, want %v", tt.name, calls, tt.want)
+		}
+
+		// Stopped propagation of a dispatch must not leak to the next dispatch of the same event.
+		calls = calls[:0]
+		for _, l := range listeners {
+			l.stop = false
+		}
+		target.DispatchEvent(&e)
+		var wantCalls = 4
+		if tt.bubbles {
+			wantCalls = 6
+		}
+		if len(calls) != wantCalls {
+			t.Errorf("%s: DispatchEvent() again calls = %v, want all listeners", tt.name, calls)
+		}
+	}
+}
+
+func TestEventTargetOnce(t *testing.T) {
+	var calls []string
+	var target EventTarget
+	var once = &testListener{name: "once", calls: &calls}
+	var always = &testListener{name: "always", calls: &calls}
+	var options protocol.AddEventListenerOptions
+	options.Once = true
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, once, options)
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, always, protocol.AddEventListenerOptions{})
+
+	var e Event
+	target.DispatchEvent(&e)
+	target.DispatchEvent(&e)
+	var want = []string{"once:target", "always:target", "always:target"}
+	if !equalStrings(calls, want) {
+		t.Errorf("DispatchEvent() calls = %v, want %v", calls, want)
+	}
+
+	// Once listener can add again after its call.
+	calls = calls[:0]
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, once, options)
+	target.DispatchEvent(&e)
+	want = []string{"always:target", "once:target"}
+	if !equalStrings(calls, want) {
+		t.Errorf("DispatchEvent() after add again calls = %v, want %v", calls, want)
+	}
+}
+
+type countListener struct {
+	mutex sync.Mutex
+	count int
+}
+
+func (l *countListener) EventHandler(e protocol.Event) {
+	l.mutex.Lock()
+	l.count++
+	l.mutex.Unlock()
+}
+
+func TestEventTargetOnceConcurrent(t *testing.T) {
+	var target EventTarget
+	var once countListener
+	var options protocol.AddEventListenerOptions
+	options.Once = true
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &once, options)
+
+	var e Event
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target.DispatchEvent(&e)
+		}()
+	}
+	wg.Wait()
+	if once.count != 1 {
+		t.Errorf("once listener calls = %d, want 1", once.count)
+	}
+}
+
+func TestEventTargetPassive(t *testing.T) {
+	var tests = []struct {
+		name       string
+		cancelable bool
+		passive    bool
+		want       bool
+	}{
+		{"cancelable", true, false, true},
+		{"passive", true, true, false},
+		{"not cancelable", false, false, false},
+	}
+	for _, tt := range tests {
+		var calls []string
+		var target EventTarget
+		var options protocol.AddEventListenerOptions
+		options.Passive = tt.passive
+		target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &testListener{calls: &calls, preventDefault: true}, options)
+
+		var e Event
+		e.SetCancelable(tt.cancelable)
+		target.DispatchEvent(&e)
+		if got := e.DefaultPrevented(); got != tt.want {
+			t.Errorf("%s: DefaultPrevented() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestEventTargetDispatchConcurrent check concurrent dispatches of the same event don't share their phase and propagation.
+func TestEventTargetDispatchConcurrent(t *testing.T) {
+	var target, parent EventTarget
+	target.SetParent(&parent)
+	var stopper stopListener
+	var bubble countListener
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &stopper, protocol.AddEventListenerOptions{})
+	parent.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &bubble, protocol.AddEventListenerOptions{})
+
+	var e Event
+	e.SetBubbles(true)
+	const dispatches = 100
+	var wg sync.WaitGroup
+	for i := 0; i < dispatches; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			target.DispatchEvent(&e)
+		}()
+	}
+	wg.Wait()
+	if stopper.wrongPhase != 0 {
+		t.Errorf("listener saw other phases %d times, want 0", stopper.wrongPhase)
+	}
+	if bubble.count != dispatches-stopper.stopped {
+		t.Errorf("parent bubble listener calls = %d, want %d", bubble.count, dispatches-stopper.stopped)
+	}
+}
+
+// stopListener stop propagation of every other dispatch and count seen phases other than at target.
+type stopListener struct {
+	mutex      sync.Mutex
+	calls      int
+	stopped    int
+	wrongPhase int
+}
+
+func (l *stopListener) EventHandler(e protocol.Event) {}
+func (l *stopListener) DispatchEventHandler(e protocol.Event, d *Dispatch) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.calls++
+	if l.calls%2 == 0 {
+		d.StopPropagation()
+		l.stopped++
+	}
+	if d.Phase() != Phase_AtTarget {
+		l.wrongPhase++
+	}
+}
+
+// eventListener record the events it receive.
+type eventListener struct {
+	events []protocol.Event
+}
+
+func (l *eventListener) EventHandler(e protocol.Event) { l.events = append(l.events, e) }
+
+// TestEventTargetListenerEvent check listeners get the dispatched event itself, so they can check its concrete type.
+func TestEventTargetListenerEvent(t *testing.T) {
+	var target, parent EventTarget
+	target.SetParent(&parent)
+	var l eventListener
+	target.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &l, protocol.AddEventListenerOptions{})
+	parent.AddEventListener(protocol.EventMainType_Unset, protocol.EventSubType_Unset, &l, protocol.AddEventListenerOptions{})
+
+	var e Event
+	e.SetBubbles(true)
+	target.DispatchEvent(&e)
+	if len(l.events) != 2 {
+		t.Fatalf("listener calls = %d, want 2", len(l.events))
+	}
+	for _, got := range l.events {
+		if got, ok := got.(*Event); !ok || got != &e {
+			t.Errorf("listener event = %T(%p), want %T(%p)", got, got, &e, &e)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
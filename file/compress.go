@@ -0,0 +1,49 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package file
+
+import (
+	"../protocol"
+)
+
+// Compress add a compressed copy of the file for each given content encoding next to it with the compress type extension
+// e.g. "main.js.br", "main.js.gz" to let servers serve them without compress on each request.
+// Unknown content encodings and raw one (same as the file itself) are ignored.
+func Compress(file protocol.File, contentEncodings []string, level protocol.CompressLevel) (err protocol.Error) {
+	var data []byte
+	data, err = file.Data().Marshal()
+	if err != nil {
+		return
+	}
+
+	var dir = file.ParentDirectory()
+	var name = file.Metadata().URI().Name()
+	for _, ce := range contentEncodings {
+		var compressType, _ = protocol.OS.GetCompressTypeByContentEncoding(ce)
+		if compressType == nil || compressType.FileExtension() == "" {
+			continue
+		}
+
+		var compressed protocol.Codec
+		compressed, err = compressType.CompressBySlice(data, protocol.CompressOptions{CompressLevel: level})
+		if err != nil {
+			return
+		}
+		var compressedData []byte
+		compressedData, err = compressed.Marshal()
+		if err != nil {
+			return
+		}
+
+		var compressedFile protocol.File
+		compressedFile, err = dir.File(name + "." + compressType.FileExtension())
+		if err != nil {
+			return
+		}
+		_, err = compressedFile.Data().Unmarshal(compressedData)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,14 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package file
+
+import (
+	"../minify"
+	"../protocol"
+)
+
+// Minify replace file data with minify of them by the file media type.
+func Minify(file protocol.File) (err protocol.Error) {
+	err = minify.Minify(file.Data())
+	return
+}
@@ -8,6 +8,8 @@ import (
 
 	"../authorization"
 	"../connection"
+	"../crypto"
+	"../event"
 	"../protocol"
 	"../uuid"
 )
@@ -40,6 +42,8 @@ type Connection struct {
 	cipher        protocol.Cipher // Selected cipher algorithms https://en.wikipedia.org/wiki/Cipher_suite
 
 	connection.Metric
+	connection.Limiter
+	event.EventTarget // parent is the application target when the connection register
 }
 
 func (conn *Connection) ID() uint32                        { return conn.id }
@@ -53,6 +57,23 @@ func (conn *Connection) UserType() UserType                { return conn.userTyp
 func (conn *Connection) DelegateUserID() [32]byte          { return conn.delegateUserID }
 func (conn *Connection) DelegateUserType() UserType        { return conn.delegateUserType }
 func (conn *Connection) Cipher() Cipher                    { return conn.cipher }
+func (conn *Connection) SetCipher(cipher Cipher)           { conn.cipher = cipher }
+
+// SetUser set the peer user e.g. when a guest connection login, and re-init the limiter by the user type quota,
+// so a registered user get RegisteredQuota instead of the guest quota that the connection made by it.
+func (conn *Connection) SetUser(userID [32]byte, userType protocol.UserType) {
+	conn.userID = userID
+	conn.userType = userType
+	conn.Limiter.Init(connection.QuotaByUserType(userType))
+}
+
+// InitSession install the session cipher by the handshake agreed suite and secret.
+// Later the cipher can rotate in-band by srpc.ChangeCipherSpec() without any new handshake.
+func (conn *Connection) InitSession(suite protocol.CipherSuite, secret [32]byte) {
+	var session crypto.Session
+	session.Init(suite, secret)
+	conn.cipher = &session
+}
 
 // SetThingID set thingID only if it is not set before
 func (conn *Connection) SetThingID(thingID [32]byte) {
@@ -70,7 +91,7 @@ func (conn *Connection) Receive(packet []byte) {
 	var frames []byte
 	frames, err = Decrypt(GetPayload(packet), conn.Cipher())
 	if err != nil {
-		conn.FailedPacketsReceived()
+		conn.PacketReceiveFailed()
 		// Send NACK or store and send later
 		// TODO::: DDOS!!??
 		return
@@ -117,7 +138,10 @@ func handleDataFrame(st protocol.Stream, dataFrame []byte, packetID uint32) (err
 // MakeIncomeStream make and return the new stream with income ID!
 // Never make Stream instance by hand, This function can improve by many ways!
 func (conn *Connection) MakeIncomeStream(streamID uint32) (st protocol.Stream, err protocol.Error) {
-	// TODO::: Check user can open new stream first as stream policy!
+	err = conn.StreamOpened()
+	if err != nil {
+		return
+	}
 
 	// if given streamID is 0, return new incremental streamID from pool
 	if streamID == 0 {
@@ -170,8 +194,7 @@ func EstablishNewConnectionByDomainID(domainID [32]byte) (conn *Connection, err
 	if err != nil {
 		return
 	}
-	conn.UserID = domainID
-	conn.UserType = protocol.UserTypeApp
+	conn.SetUser(domainID, protocol.UserType_App)
 	return
 }
 
@@ -189,7 +212,9 @@ func EstablishNewConnectionByPeerAdd(gpAddr Addr) (conn *Connection, err protoco
 		conn, err = MakeNewGuestConnection()
 		if err == nil {
 			conn.Addr = gpAddr
-			// conn.Cipher = crypto.NewGCM(crypto.NewAES256([32]byte{}))
+			// TODO::: agree on the secret by ECDHE with the peer public key that get from peer GP router.
+			var secret [32]byte
+			conn.InitSession(&crypto.CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_GCM_SHA256, secret)
 		}
 	}
 	return
@@ -209,6 +234,7 @@ func MakeNewGuestConnection() (conn *Connection, err protocol.Error) {
 		UserType: protocol.UserTypeGuest,
 	}
 	conn.AccessControl.GiveFullAccess()
+	conn.Limiter.Init(connection.QuotaByUserType(protocol.UserType_Guest))
 	conn.StreamPool.Init()
 	return
 }
@@ -99,6 +99,7 @@ func (st *Stream) Close() {
 	sp.mutex.Lock()
 	delete(st.connection.StreamPool.p, st.id)
 	sp.mutex.Unlock()
+	st.connection.StreamClosed()
 }
 
 // SetState change state of stream and send notification on stream StateChannel.
@@ -14,14 +14,25 @@ import (
 // https://datatracker.ietf.org/doc/html/rfc2616#section-4.3
 type body struct {
 	protocol.Codec
+
+	// unread is the marshaled codec data that Read() not return yet. marshaled is true after first Read()
+	// of a codec that can't read as io.Reader.
+	unread    []byte
+	marshaled bool
 }
 
-func (b *body) Init()   {}
-func (b *body) Reinit() { b.Codec = nil }
+func (b *body) Init() {}
+func (b *body) Reinit() {
+	b.Codec = nil
+	b.resetRead()
+}
 func (b *body) Deinit() {}
 
-func (b *body) Body() protocol.Codec         { return b }
-func (b *body) SetBody(codec protocol.Codec) { b.Codec = codec }
+func (b *body) Body() protocol.Codec { return b }
+func (b *body) SetBody(codec protocol.Codec) {
+	b.Codec = codec
+	b.resetRead()
+}
 
 //libgo:impl protocol.Codec
 func (b *body) Len() int {
@@ -79,6 +90,34 @@ func (b *body) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error)
 	return
 }
 
+// Read read next part of the body data. If the codec is an io.Reader, body read from it as the data arrive,
+// so large bodies e.g. multipart files can decode without hold whole body in memory.
+// Otherwise codec marshal once and each call return at most len(p) bytes of it.
+//
+//libgo:impl protocol.Reader
+func (b *body) Read(p []byte) (n int, goErr error) {
+	if b.Codec == nil {
+		return 0, io.EOF
+	}
+	if reader, ok := b.Codec.(io.Reader); ok {
+		return reader.Read(p)
+	}
+	if !b.marshaled {
+		var data, err = b.Codec.Marshal()
+		if err != nil {
+			return 0, err
+		}
+		b.unread = data
+		b.marshaled = true
+	}
+	if len(b.unread) == 0 {
+		return 0, io.EOF
+	}
+	n = copy(p, b.unread)
+	b.unread = b.unread[n:]
+	return
+}
+
 // ReadFrom decodes r *Request data by read from given io.Reader
 //
 //libgo:impl io.ReaderFrom
@@ -99,6 +138,11 @@ func (b *body) WriteTo(writer io.Writer) (n int64, err error) {
 ********** local methods **********
  */
 
+func (b *body) resetRead() {
+	b.unread = nil
+	b.marshaled = false
+}
+
 func (b *body) checkAndSetCodecAsIncomeBody(maybeBody []byte, c protocol.Codec, h *header) (err protocol.Error) {
 	var transferEncoding, _ = h.TransferEncoding()
 	switch transferEncoding {
@@ -125,11 +169,11 @@ func (b *body) checkAndSetCodecAsIncomeBody(maybeBody []byte, c protocol.Codec,
 			}
 		}
 	case HeaderValueChunked:
-		// TODO:::
+		err = b.setChunkedIncomeBody(maybeBody, h, &codecReader{codec: c})
 	default:
 		// Like nginx, due to security, we only support a single Transfer-Encoding header field, and
 		// only if set to "chunked".
-		// err =
+		err = &ErrTransferEncodingUnsupported
 	}
 	return
 }
@@ -164,17 +208,24 @@ func (b *body) checkAndSetReaderAsIncomeBody(maybeBody []byte, reader protocol.R
 			}
 		}
 	case HeaderValueChunked:
-		// TODO:::
+		err = b.setChunkedIncomeBody(maybeBody, h, reader)
 	default:
 		// Like nginx, due to security, we only support a single Transfer-Encoding header field, and
 		// only if set to "chunked".
-		// err =
+		err = &ErrTransferEncodingUnsupported
 	}
 	return
 }
 
 // Call this method just if body marshaled with first line and headers.
 func (b *body) checkAndSetIncomeBody(maybeBody []byte, h *header) (err protocol.Error) {
+	var transferEncoding, _ = h.TransferEncoding()
+	if transferEncoding == HeaderValueChunked {
+		// Whole body must be in given slice, so no more data can read.
+		err = b.setChunkedIncomeBody(maybeBody, h, nil)
+		return
+	}
+
 	var maybeBodyLength = len(maybeBody)
 	if maybeBodyLength > 0 {
 		var contentLength = h.ContentLength()
@@ -187,6 +238,45 @@ func (b *body) checkAndSetIncomeBody(maybeBody []byte, h *header) (err protocol.
 	return
 }
 
+// setChunkedIncomeBody decode chunked transfer coding body from maybeBody. If the body not end in maybeBody,
+// remaining chunks decode from reader on demand as the body read, so whole body never hold in memory.
+// Trailer fields if any add to given header when the last chunk decoded.
+func (b *body) setChunkedIncomeBody(maybeBody []byte, h *header, reader protocol.Reader) (err protocol.Error) {
+	var cd chunkedDecoder
+	cd.init(h, MaxChunkSize, MaxChunkedBodySize)
+	_, err = cd.decode(maybeBody)
+	if err != nil {
+		return
+	}
+
+	if cd.Done() {
+		err = b.setReadedIncomeBody(cd.Body(), h)
+		return
+	}
+	if reader == nil {
+		return &ErrChunkedMalformed
+	}
+	var cb chunkedBody
+	cb.init(cd, reader)
+	err = b.setCodecAsIncomeBody(&cb, h)
+	return
+}
+
+// SetChunkedBody wrap the body codec to encode it by chunked transfer coding e.g. when body length is unknown.
+// It returns the trailer fields that can fill until the body fully encoded.
+func (b *body) SetChunkedBody() (trailer protocol.HTTPHeader) {
+	if b.Codec == nil {
+		return
+	}
+	if cc, ok := b.Codec.(*ChunkedCodec); ok {
+		return cc.Trailer()
+	}
+	var cc ChunkedCodec
+	cc.Init(b.Codec, 0)
+	b.Codec = &cc
+	return cc.Trailer()
+}
+
 func (b *body) setCodecAsIncomeBody(c protocol.Codec, h *header) (err protocol.Error) {
 	var contentEncoding, _ = h.ContentEncoding()
 	if contentEncoding == "" {
@@ -234,3 +324,25 @@ func (b *body) setReadedIncomeBody(body []byte, h *header) (err protocol.Error)
 	b.Codec, err = compressType.DecompressFromSlice(body)
 	return
 }
+
+// codecReader read a codec that its MarshalTo() append next part of its data on each call e.g. a stream.
+type codecReader struct {
+	codec   protocol.Codec
+	pending []byte
+}
+
+func (cr *codecReader) Read(p []byte) (n int, goErr error) {
+	if len(cr.pending) == 0 {
+		var err protocol.Error
+		cr.pending, err = cr.codec.MarshalTo(cr.pending[:0])
+		if err != nil {
+			return 0, err
+		}
+		if len(cr.pending) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n = copy(p, cr.pending)
+	cr.pending = cr.pending[n:]
+	return
+}
@@ -0,0 +1,91 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+)
+
+// testBodyData is larger than read buffers and not a multiple of them to read last part partially.
+var testBodyData = bytes.Repeat([]byte("0123456789abcdef"), 3*ChunkSize/16+7)
+
+// testIncomeBodies return bodies of the data as they make from income raw slice, reader and chunked reader.
+func testIncomeBodies(t *testing.T, data []byte) map[string]*body {
+	t.Helper()
+	var bodies = map[string]*body{}
+
+	var sliceHeader header
+	sliceHeader.Init()
+	var slice body
+	if err := slice.setReadedIncomeBody(data, &sliceHeader); err != nil {
+		t.Fatalf("setReadedIncomeBody() error = %v", err)
+	}
+	bodies["raw slice"] = &slice
+
+	var readerHeader header
+	readerHeader.Init()
+	readerHeader.Set(HeaderKeyContentLength, strconv.Itoa(len(data)))
+	var reader body
+	if err := reader.checkAndSetReaderAsIncomeBody(nil, bytes.NewReader(data), &readerHeader); err != nil {
+		t.Fatalf("checkAndSetReaderAsIncomeBody() error = %v", err)
+	}
+	bodies["reader"] = &reader
+
+	var cc ChunkedCodec
+	cc.Init(&testCodec{data: data}, 1000)
+	var chunkedData, _ = cc.Marshal()
+	var chunkedHeader header
+	chunkedHeader.Init()
+	chunkedHeader.SetTransferEncoding(HeaderValueChunked)
+	var chunked body
+	if err := chunked.checkAndSetReaderAsIncomeBody(nil, bytes.NewReader(chunkedData), &chunkedHeader); err != nil {
+		t.Fatalf("checkAndSetReaderAsIncomeBody(chunked) error = %v", err)
+	}
+	bodies["chunked"] = &chunked
+	return bodies
+}
+
+func TestBodyRead(t *testing.T) {
+	for name, b := range testIncomeBodies(t, testBodyData) {
+		var got []byte
+		var buf = make([]byte, 1000)
+		for i := 0; ; i++ {
+			var n, goErr = b.Read(buf)
+			if n > len(buf) {
+				t.Fatalf("%s: body.Read() n = %d, more than buffer %d", name, n, len(buf))
+			}
+			got = append(got, buf[:n]...)
+			if goErr == io.EOF {
+				break
+			}
+			if goErr != nil {
+				t.Fatalf("%s: body.Read() error = %v", name, goErr)
+			}
+			if i > len(testBodyData) {
+				t.Fatalf("%s: body.Read() never return io.EOF", name)
+			}
+		}
+		if !bytes.Equal(got, testBodyData) {
+			t.Errorf("%s: body.Read() data len = %d, want %d", name, len(got), len(testBodyData))
+		}
+		if n, goErr := b.Read(buf); n != 0 || goErr != io.EOF {
+			t.Errorf("%s: body.Read() after EOF = %d, %v, want 0, EOF", name, n, goErr)
+		}
+	}
+}
+
+func TestBodyReadReset(t *testing.T) {
+	var b body
+	b.SetBody(&testCodec{data: []byte("first")})
+	var buf = make([]byte, 3)
+	b.Read(buf)
+
+	b.SetBody(&testCodec{data: []byte("second")})
+	var got, _ = io.ReadAll(&b)
+	if string(got) != "second" {
+		t.Errorf("body.Read() after SetBody() = %q, want %q", got, "second")
+	}
+}
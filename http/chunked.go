@@ -0,0 +1,491 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/convert"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+Chunked transfer coding
+https://datatracker.ietf.org/doc/html/rfc9112#section-7.1
+
+	chunked-body   = *chunk
+	                 last-chunk
+	                 trailer-section
+	                 CRLF
+
+	chunk          = chunk-size [ chunk-ext ] CRLF
+	                 chunk-data CRLF
+	chunk-size     = 1*HEXDIG
+	last-chunk     = 1*("0") [ chunk-ext ] CRLF
+*/
+
+// ChunkedCodec encode given source codec as chunked transfer coding.
+// It is useful when source length is unknown until fully write e.g. stream compress data by gzip,
+// so any source that its Len() return -1 can encode by it.
+type ChunkedCodec struct {
+	source    protocol.Codec
+	trailer   header
+	chunkSize int
+}
+
+// Init set source of the chunked body.
+// chunkSize indicate max size of each chunk, 0 means ChunkSize.
+func (cc *ChunkedCodec) Init(source protocol.Codec, chunkSize int) {
+	if chunkSize <= 0 {
+		chunkSize = ChunkSize
+	}
+	cc.source = source
+	cc.chunkSize = chunkSize
+	cc.trailer.Init()
+}
+
+// Source return the codec that encode as chunked. Other protocols that frame body in their own way
+// e.g. HTTP/2 use it to send body without chunked transfer coding.
+func (cc *ChunkedCodec) Source() protocol.Codec { return cc.source }
+
+// Trailer return trailer fields that send after last chunk. Fill it before the body encode complete.
+// Don't forget to announce trailer fields names in "Trailer" header.
+func (cc *ChunkedCodec) Trailer() protocol.HTTPHeader { return &cc.trailer }
+
+//libgo:impl protocol.Codec
+func (cc *ChunkedCodec) MediaType() protocol.MediaType       { return cc.source.MediaType() }
+func (cc *ChunkedCodec) CompressType() protocol.CompressType { return cc.source.CompressType() }
+
+// Len return -1 due to can't tell the len until fully encode.
+func (cc *ChunkedCodec) Len() (ln int) { return -1 }
+
+func (cc *ChunkedCodec) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+
+// Encode write each part of the source encoded data as a chunk to destination.
+func (cc *ChunkedCodec) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	var cw = chunkWriter{destination: destination, chunkSize: cc.chunkSize}
+	_, err = cc.source.Encode(&cw)
+	n = cw.wrote
+	if err != nil {
+		return
+	}
+
+	var end = cc.marshalLastChunk(make([]byte, 0, cc.lastChunkLen()))
+	var wrote int
+	wrote, err = destination.Unmarshal(end)
+	n += wrote
+	return
+}
+func (cc *ChunkedCodec) Marshal() (data []byte, err protocol.Error) {
+	var sourceLen = cc.source.Len()
+	if sourceLen < 0 {
+		sourceLen = cc.chunkSize
+	}
+	data = make([]byte, 0, sourceLen+cc.lastChunkLen()+16)
+	return cc.MarshalTo(data)
+}
+func (cc *ChunkedCodec) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	var raw []byte
+	raw, err = cc.source.Marshal()
+	if err != nil {
+		return data, err
+	}
+	for len(raw) > 0 {
+		var ln = len(raw)
+		if ln > cc.chunkSize {
+			ln = cc.chunkSize
+		}
+		data = appendChunk(data, raw[:ln])
+		raw = raw[ln:]
+	}
+	added = cc.marshalLastChunk(data)
+	return
+}
+func (cc *ChunkedCodec) Unmarshal(data []byte) (n int, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+func (cc *ChunkedCodec) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+
+func (cc *ChunkedCodec) lastChunkLen() (ln int) {
+	return 1 + len(CRLF) + cc.trailer.Len() + len(CRLF) // "0" CRLF trailer-section CRLF
+}
+
+func (cc *ChunkedCodec) marshalLastChunk(data []byte) []byte {
+	data = append(data, '0')
+	data = append(data, CRLF...)
+	data = cc.trailer.MarshalTo(data)
+	data = append(data, CRLF...)
+	return data
+}
+
+// chunkWriter is a protocol.Codec that just accept write by Unmarshal method
+// and write any given data as one or more chunks to destination.
+type chunkWriter struct {
+	destination protocol.Codec
+	chunkSize   int
+	wrote       int
+}
+
+//libgo:impl protocol.Codec
+func (cw *chunkWriter) MediaType() protocol.MediaType       { return nil }
+func (cw *chunkWriter) CompressType() protocol.CompressType { return nil }
+func (cw *chunkWriter) Len() (ln int)                       { return cw.wrote }
+func (cw *chunkWriter) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return cw.Unmarshal(data)
+}
+func (cw *chunkWriter) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+func (cw *chunkWriter) Marshal() (data []byte, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+func (cw *chunkWriter) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return data, &ErrChunkedSourceNotChangeable
+}
+func (cw *chunkWriter) Unmarshal(data []byte) (n int, err protocol.Error) {
+	for len(data) > 0 {
+		var ln = len(data)
+		if ln > cw.chunkSize {
+			ln = cw.chunkSize
+		}
+		var chunk = appendChunk(make([]byte, 0, ln+chunkSizeLineMaxLength+len(CRLF)), data[:ln])
+		var wrote int
+		wrote, err = cw.destination.Unmarshal(chunk)
+		cw.wrote += wrote
+		if err != nil {
+			return
+		}
+		n += ln
+		data = data[ln:]
+	}
+	return
+}
+func (cw *chunkWriter) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = cw.Unmarshal(data)
+	return
+}
+
+func appendChunk(data, chunk []byte) []byte {
+	data = strconv.AppendUint(data, uint64(len(chunk)), 16)
+	data = append(data, CRLF...)
+	data = append(data, chunk...)
+	data = append(data, CRLF...)
+	return data
+}
+
+type chunkedState uint8
+
+const (
+	chunkedState_Size chunkedState = iota
+	chunkedState_Data
+	chunkedState_DataCRLF
+	chunkedState_Trailer
+	chunkedState_Done
+)
+
+// chunkedDecoder decode chunked transfer coding body in a non-blocking manner.
+// Caller can pass data in any number of parts, e.g. as received from network, until Done() return true.
+type chunkedDecoder struct {
+	maxChunkSize int
+	maxBodySize  int
+	trailer      *header // trailer fields add to it if not nil
+
+	state       chunkedState
+	chunkRemain int
+	body        []byte // decoded chunks data. chunkedBody drain it after each read, so it never hold whole body.
+	bodyLen     int    // all decoded chunks data length to enforce maxBodySize
+	line        []byte // buffer to hold a chunk-size or trailer line that not fully received yet
+	trailerLen  int
+}
+
+func (cd *chunkedDecoder) init(trailer *header, maxChunkSize, maxBodySize int) {
+	cd.maxChunkSize = maxChunkSize
+	cd.maxBodySize = maxBodySize
+	cd.trailer = trailer
+}
+
+func (cd *chunkedDecoder) Done() bool   { return cd.state == chunkedState_Done }
+func (cd *chunkedDecoder) Body() []byte { return cd.body }
+
+// decode consume given data and return any remaining data after chunked body end.
+func (cd *chunkedDecoder) decode(data []byte) (remaining []byte, err protocol.Error) {
+	for len(data) > 0 && cd.state != chunkedState_Done {
+		switch cd.state {
+		case chunkedState_Size, chunkedState_Trailer:
+			var line []byte
+			line, data, err = cd.readLine(data)
+			if err != nil || line == nil {
+				return data, err
+			}
+			if cd.state == chunkedState_Size {
+				err = cd.parseChunkSize(line)
+			} else {
+				err = cd.parseTrailer(line)
+			}
+			if err != nil {
+				return data, err
+			}
+		case chunkedState_Data:
+			var ln = cd.chunkRemain
+			if ln > len(data) {
+				ln = len(data)
+			}
+			cd.body = append(cd.body, data[:ln]...)
+			cd.bodyLen += ln
+			cd.chunkRemain -= ln
+			data = data[ln:]
+			if cd.chunkRemain == 0 {
+				cd.state = chunkedState_DataCRLF
+			}
+		case chunkedState_DataCRLF:
+			var line []byte
+			line, data, err = cd.readLine(data)
+			if err != nil || line == nil {
+				return data, err
+			}
+			if len(line) != 0 {
+				return data, &ErrChunkedMalformed
+			}
+			cd.state = chunkedState_Size
+		}
+	}
+	return data, nil
+}
+
+// readLine return a line without CRLF or nil line if CRLF not received yet.
+func (cd *chunkedDecoder) readLine(data []byte) (line, remaining []byte, err protocol.Error) {
+	var lfIndex = bytes.IndexByte(data, '\n')
+	if lfIndex == -1 {
+		cd.line = append(cd.line, data...)
+		if len(cd.line) > chunkSizeLineMaxLength && cd.state != chunkedState_Trailer {
+			err = &ErrChunkedMalformed
+		} else if len(cd.line) > MaxHTTPHeaderSize {
+			err = &ErrChunkedTrailerTooLarge
+		}
+		return
+	}
+
+	if len(cd.line) > 0 {
+		cd.line = append(cd.line, data[:lfIndex+1]...)
+		line = cd.line
+		cd.line = cd.line[:0]
+	} else {
+		line = data[:lfIndex+1]
+	}
+	remaining = data[lfIndex+1:]
+
+	var ln = len(line)
+	if ln < 2 || line[ln-2] != '\r' {
+		err = &ErrChunkedMalformed
+		return
+	}
+	line = line[:ln-2]
+	return
+}
+
+func (cd *chunkedDecoder) parseChunkSize(line []byte) (err protocol.Error) {
+	// Ignore any chunk extension
+	var semiColonIndex = bytes.IndexByte(line, ';')
+	if semiColonIndex != -1 {
+		line = line[:semiColonIndex]
+	}
+	var sizeStr = strings.TrimRight(convert.UnsafeByteSliceToString(line), " \t")
+	if len(sizeStr) == 0 || len(sizeStr) > 16 {
+		return &ErrChunkedMalformed
+	}
+
+	var size, goErr = strconv.ParseUint(sizeStr, 16, 64)
+	if goErr != nil {
+		return &ErrChunkedMalformed
+	}
+	if size > uint64(cd.maxChunkSize) {
+		return &ErrChunkTooLarge
+	}
+	if cd.bodyLen+int(size) > cd.maxBodySize {
+		return &ErrChunkedBodyTooLarge
+	}
+
+	if size == 0 {
+		cd.state = chunkedState_Trailer
+		return
+	}
+	cd.chunkRemain = int(size)
+	cd.state = chunkedState_Data
+	return
+}
+
+func (cd *chunkedDecoder) parseTrailer(line []byte) (err protocol.Error) {
+	if len(line) == 0 {
+		cd.state = chunkedState_Done
+		return
+	}
+
+	cd.trailerLen += len(line)
+	if cd.trailerLen > MaxHTTPHeaderSize {
+		return &ErrChunkedTrailerTooLarge
+	}
+
+	var colonIndex = bytes.IndexByte(line, ':')
+	if colonIndex < 1 {
+		return &ErrChunkedMalformed
+	}
+	if cd.trailer != nil {
+		var key = CanonicalHeaderKey(string(line[:colonIndex]))
+		var value = strings.TrimSpace(string(line[colonIndex+1:]))
+		if trailerAllowed(key) {
+			cd.trailer.Add(key, value)
+		}
+	}
+	return
+}
+
+// trailerAllowed report false for fields that must not send in trailer section. Field names are case-insensitive.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-6.5.1
+func trailerAllowed(key string) bool {
+	switch CanonicalHeaderKey(key) {
+	case HeaderKeyTransferEncoding, HeaderKeyContentLength, HeaderKeyContentType, HeaderKeyContentEncoding,
+		HeaderKeyTrailer, HeaderKeyHost, HeaderKeyAuthorization, HeaderKeyCookie, HeaderKeySetCookie:
+		return false
+	}
+	return true
+}
+
+// chunkedBody is the income body codec of a chunked transfer coding body that decode from the reader on demand.
+// Just the chunks data that decoded by last read hold in memory and not whole body,
+// so a body can be large as MaxChunkedBodySize while each chunk limit by MaxChunkSize.
+type chunkedBody struct {
+	cd        chunkedDecoder
+	reader    protocol.Reader
+	buf       []byte // read buffer
+	remaining []byte // read data that not decoded yet
+	off       int    // read offset in decoded chunks data
+}
+
+// init continue decode the body by given decoder that decode first part of the body.
+func (cb *chunkedBody) init(cd chunkedDecoder, reader protocol.Reader) {
+	cb.cd = cd
+	cb.reader = reader
+	cb.buf = make([]byte, ChunkSize)
+}
+
+//libgo:impl protocol.Codec
+func (cb *chunkedBody) MediaType() protocol.MediaType       { return nil }
+func (cb *chunkedBody) CompressType() protocol.CompressType { return nil }
+
+// Len return -1 due to can't tell the len until fully decode.
+func (cb *chunkedBody) Len() (ln int) { return -1 }
+
+func (cb *chunkedBody) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+
+// Encode write the body to destination part by part as it decode from the reader.
+func (cb *chunkedBody) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	var part = make([]byte, ChunkSize)
+	for {
+		var readLength, goErr = cb.Read(part)
+		if readLength > 0 {
+			var wrote int
+			wrote, err = destination.Unmarshal(part[:readLength])
+			n += wrote
+			if err != nil {
+				return
+			}
+		}
+		if goErr == io.EOF {
+			return
+		}
+		if goErr != nil {
+			err = cb.readError(goErr)
+			return
+		}
+	}
+}
+
+// Marshal decode and return remaining body. It hold whole body in memory, so use Read() or Encode() if possible.
+func (cb *chunkedBody) Marshal() (data []byte, err protocol.Error) {
+	return cb.MarshalTo(make([]byte, 0, ChunkSize))
+}
+func (cb *chunkedBody) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	for {
+		if len(data) == cap(data) {
+			data = append(data, 0)[:len(data)]
+		}
+		var readLength, goErr = cb.Read(data[len(data):cap(data)])
+		data = data[:len(data)+readLength]
+		if goErr == io.EOF {
+			return data, nil
+		}
+		if goErr != nil {
+			return data, cb.readError(goErr)
+		}
+	}
+}
+func (cb *chunkedBody) Unmarshal(data []byte) (n int, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+func (cb *chunkedBody) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	err = &ErrChunkedSourceNotChangeable
+	return
+}
+
+// Read read next decoded part of the body.
+//
+//libgo:impl protocol.Reader
+func (cb *chunkedBody) Read(p []byte) (n int, goErr error) {
+	for cb.off == len(cb.cd.body) {
+		cb.cd.body = cb.cd.body[:0]
+		cb.off = 0
+		if cb.cd.Done() {
+			return 0, io.EOF
+		}
+
+		if len(cb.remaining) == 0 {
+			var readLength int
+			readLength, goErr = cb.reader.Read(cb.buf)
+			if readLength == 0 {
+				if goErr == nil || goErr == io.EOF {
+					goErr = &ErrChunkedMalformed
+				}
+				return
+			}
+			cb.remaining = cb.buf[:readLength]
+		}
+
+		var err protocol.Error
+		cb.remaining, err = cb.cd.decode(cb.remaining)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n = copy(p, cb.cd.body[cb.off:])
+	cb.off += n
+	return
+}
+
+func (cb *chunkedBody) readError(goErr error) (err protocol.Error) {
+	if err, ok := goErr.(protocol.Error); ok {
+		return err
+	}
+	return &ErrChunkedMalformed
+}
@@ -0,0 +1,228 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// testCodec is a simple codec that just hold given data.
+type testCodec struct {
+	data []byte
+}
+
+func (tc *testCodec) MediaType() protocol.MediaType       { return nil }
+func (tc *testCodec) CompressType() protocol.CompressType { return nil }
+func (tc *testCodec) Len() int                            { return len(tc.data) }
+func (tc *testCodec) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	return
+}
+func (tc *testCodec) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	return destination.Unmarshal(tc.data)
+}
+func (tc *testCodec) Marshal() (data []byte, err protocol.Error) { return tc.data, nil }
+func (tc *testCodec) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return append(data, tc.data...), nil
+}
+func (tc *testCodec) Unmarshal(data []byte) (n int, err protocol.Error) {
+	tc.data = append(tc.data, data...)
+	return len(data), nil
+}
+func (tc *testCodec) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = tc.Unmarshal(data)
+	return
+}
+
+type chunkedDecodeTest struct {
+	name    string
+	parts   []string // data receive in many parts
+	body    string
+	trailer map[string]string
+	err     bool
+}
+
+var chunkedDecodeTests = []chunkedDecodeTest{
+	{
+		name:  "simple",
+		parts: []string{"4\r\nWiki\r\n5\r\npedia\r\n0\r\n\r\n"},
+		body:  "Wikipedia",
+	}, {
+		name:  "split",
+		parts: []string{"4\r", "\nWi", "ki\r\n5;ext=1\r\npe", "dia\r\n0\r\n", "\r\n"},
+		body:  "Wikipedia",
+	}, {
+		name:    "trailer",
+		parts:   []string{"3\r\nabc\r\n0\r\nExpires: never\r\nContent-Length: 3\r\n\r\n"},
+		body:    "abc",
+		trailer: map[string]string{"Expires": "never", "Content-Length": ""},
+	}, {
+		name:    "trailer-lowercase",
+		parts:   []string{"3\r\nabc\r\n0\r\nexpires: never\r\ncontent-length: 3\r\n\r\n"},
+		body:    "abc",
+		trailer: map[string]string{"Expires": "never", "Content-Length": ""},
+	}, {
+		name:  "bad-size",
+		parts: []string{"x\r\nabc\r\n0\r\n\r\n"},
+		err:   true,
+	}, {
+		name:  "bad-data-crlf",
+		parts: []string{"3\r\nabcd\r\n0\r\n\r\n"},
+		err:   true,
+	}, {
+		name:  "chunk-too-large",
+		parts: []string{"fffffff\r\n"},
+		err:   true,
+	},
+}
+
+func TestChunkedDecoder(t *testing.T) {
+	for _, tt := range chunkedDecodeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h header
+			h.Init()
+			var cd chunkedDecoder
+			cd.init(&h, MaxChunkSize, MaxChunkedBodySize)
+
+			var err error
+			for _, part := range tt.parts {
+				var _, decodeErr = cd.decode([]byte(part))
+				if decodeErr != nil {
+					err = decodeErr
+					break
+				}
+			}
+			if tt.err {
+				if err == nil {
+					t.Errorf("chunkedDecoder.decode() expected error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("chunkedDecoder.decode() error = %v", err)
+			}
+			if !cd.Done() {
+				t.Fatalf("chunkedDecoder not done")
+			}
+			if string(cd.Body()) != tt.body {
+				t.Errorf("chunkedDecoder.Body() = %q, want %q", cd.Body(), tt.body)
+			}
+			for key, value := range tt.trailer {
+				if h.Get(key) != value {
+					t.Errorf("trailer %s = %q, want %q", key, h.Get(key), value)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkedCodecMarshal(t *testing.T) {
+	var raw = bytes.Repeat([]byte("0123456789"), 5)
+
+	var cc ChunkedCodec
+	cc.Init(&testCodec{data: raw}, 16)
+	cc.Trailer().Set("Expires", "never")
+
+	var chunked, err = cc.Marshal()
+	if err != nil {
+		t.Fatalf("ChunkedCodec.Marshal() error = %v", err)
+	}
+
+	var h header
+	h.Init()
+	var cd chunkedDecoder
+	cd.init(&h, MaxChunkSize, MaxChunkedBodySize)
+	_, err = cd.decode(chunked)
+	if err != nil {
+		t.Fatalf("chunkedDecoder.decode() error = %v", err)
+	}
+	if !cd.Done() || !bytes.Equal(cd.Body(), raw) {
+		t.Errorf("round trip body = %q, want %q", cd.Body(), raw)
+	}
+	if h.Get("Expires") != "never" {
+		t.Errorf("round trip trailer = %q, want %q", h.Get("Expires"), "never")
+	}
+}
+
+func TestChunkedCodecEncode(t *testing.T) {
+	var raw = bytes.Repeat([]byte("0123456789"), 5)
+	var cc ChunkedCodec
+	cc.Init(&testCodec{data: raw}, 16)
+
+	var destination testCodec
+	var _, err = cc.Encode(&destination)
+	if err != nil {
+		t.Fatalf("ChunkedCodec.Encode() error = %v", err)
+	}
+
+	var cd chunkedDecoder
+	cd.init(nil, MaxChunkSize, MaxChunkedBodySize)
+	_, err = cd.decode(destination.data)
+	if err != nil {
+		t.Fatalf("chunkedDecoder.decode() error = %v", err)
+	}
+	if !cd.Done() || !bytes.Equal(cd.Body(), raw) {
+		t.Errorf("round trip body = %q, want %q", cd.Body(), raw)
+	}
+}
+
+func TestChunkedBodyStream(t *testing.T) {
+	var raw = bytes.Repeat([]byte("0123456789abcdef"), 8*ChunkSize/16)
+	var cc ChunkedCodec
+	cc.Init(&testCodec{data: raw}, 4096)
+	cc.Trailer().Set("Expires", "never")
+	var chunked, _ = cc.Marshal()
+
+	var h header
+	h.Init()
+	h.SetTransferEncoding(HeaderValueChunked)
+	var b body
+	var err = b.checkAndSetReaderAsIncomeBody(chunked[:100], bytes.NewReader(chunked[100:]), &h)
+	if err != nil {
+		t.Fatalf("checkAndSetReaderAsIncomeBody() error = %v", err)
+	}
+	var cb, ok = b.Codec.(*chunkedBody)
+	if !ok {
+		t.Fatalf("chunked income body codec = %T, want *chunkedBody", b.Codec)
+	}
+
+	var got []byte
+	var buf = make([]byte, 1000)
+	for {
+		var n, goErr = b.Read(buf)
+		got = append(got, buf[:n]...)
+		if cap(cb.cd.body) > 2*ChunkSize {
+			t.Fatalf("chunkedBody hold %d bytes, want at most one read buffer", cap(cb.cd.body))
+		}
+		if goErr == io.EOF {
+			break
+		}
+		if goErr != nil {
+			t.Fatalf("body.Read() error = %v", goErr)
+		}
+	}
+	if !bytes.Equal(got, raw) {
+		t.Errorf("body.Read() data len = %d, want %d", len(got), len(raw))
+	}
+	if h.Get("Expires") != "never" {
+		t.Errorf("trailer Expires = %q, want %q", h.Get("Expires"), "never")
+	}
+}
+
+func TestChunkedBodyTruncated(t *testing.T) {
+	var h header
+	h.Init()
+	h.SetTransferEncoding(HeaderValueChunked)
+	var b body
+	var err = b.checkAndSetReaderAsIncomeBody(nil, bytes.NewReader([]byte("4\r\nWiki\r\n5\r\npe")), &h)
+	if err != nil {
+		t.Fatalf("checkAndSetReaderAsIncomeBody() error = %v", err)
+	}
+	var _, marshalErr = b.Marshal()
+	if marshalErr != &ErrChunkedMalformed {
+		t.Errorf("body.Marshal() error = %v, want %v", marshalErr, &ErrChunkedMalformed)
+	}
+}
@@ -0,0 +1,207 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/monotonic"
+)
+
+// DialFunc return a connection to given authority e.g. "www.sabz.city:443". scheme can use to choose transport e.g. TLS for "https".
+// shared must be true if the connection is not dialed just for the client and others use it too e.g. application connections,
+// so the client never close it.
+type DialFunc func(scheme, authority string) (conn protocol.Connection, shared bool, err protocol.Error)
+
+// DialByDomain is default DialFunc that get the connection from application connections by authority host as domain.
+// Application connections are shared with other services, so the client never close them.
+func DialByDomain(scheme, authority string) (conn protocol.Connection, shared bool, err protocol.Error) {
+	shared = true
+	var host = authority
+	if i := strings.LastIndexByte(host, Colon); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	conn, err = protocol.App.GetConnectionByDomain(host)
+	if err == nil && conn == nil {
+		err = &ErrNoConnection
+	}
+	return
+}
+
+// clientPool keep idle connections per authority to reuse them for next requests (keep-alive).
+// Each connection serve just one request at a time like HTTP/1.1 without pipelining.
+type clientPool struct {
+	dial                 DialFunc
+	maxConnsPerAuthority int               // 0 means no limit
+	maxIdlePerAuthority  int               //
+	idleTimeout          protocol.Duration // 0 means idle connections never expire
+
+	mutex       sync.Mutex
+	authorities map[string]*poolAuthority
+}
+
+type poolAuthority struct {
+	idle   []poolConn // newest is the last one
+	active int        // number of connections that dialed and not closed yet (idle + in use)
+	// released close when a connection release or close, to wake up callers that wait for a free slot.
+	released chan struct{}
+}
+
+type poolConn struct {
+	conn      protocol.Connection
+	shared    bool // pool didn't dial the connection itself, so must not close it.
+	idleSince monotonic.Time
+}
+
+// close close the connection just if the pool own it.
+func (pc *poolConn) close() {
+	if !pc.shared {
+		pc.conn.Close()
+	}
+}
+
+func closePoolConns(pcs []poolConn) {
+	for i := range pcs {
+		pcs[i].close()
+	}
+}
+
+func (p *clientPool) init(dial DialFunc, maxConnsPerAuthority int, idleTimeout protocol.Duration) {
+	if dial == nil {
+		dial = DialByDomain
+	}
+	p.dial = dial
+	p.maxConnsPerAuthority = maxConnsPerAuthority
+	p.maxIdlePerAuthority = DefaultMaxIdleConnsPerAuthority
+	if maxConnsPerAuthority > 0 && maxConnsPerAuthority < p.maxIdlePerAuthority {
+		p.maxIdlePerAuthority = maxConnsPerAuthority
+	}
+	p.idleTimeout = idleTimeout
+	p.authorities = make(map[string]*poolAuthority)
+}
+
+// acquire return an idle connection or dial a new one if the authority connections not reach the limit.
+// Otherwise block until a connection release or deadline reached. nil deadline means wait forever.
+func (p *clientPool) acquire(scheme, authority string, deadline <-chan struct{}) (pc poolConn, err protocol.Error) {
+	var key = scheme + "://" + authority
+	for {
+		p.mutex.Lock()
+		var pa = p.authorities[key]
+		if pa == nil {
+			pa = &poolAuthority{}
+			p.authorities[key] = pa
+		}
+		var expired = p.evictExpired(pa)
+
+		var ln = len(pa.idle)
+		if ln > 0 {
+			pc = pa.idle[ln-1]
+			pa.idle[ln-1] = poolConn{}
+			pa.idle = pa.idle[:ln-1]
+			p.mutex.Unlock()
+			closePoolConns(expired)
+			return
+		}
+
+		if p.maxConnsPerAuthority == 0 || pa.active < p.maxConnsPerAuthority {
+			pa.active++
+			p.mutex.Unlock()
+			closePoolConns(expired)
+
+			pc.conn, pc.shared, err = p.dial(scheme, authority)
+			if err != nil {
+				p.mutex.Lock()
+				pa.active--
+				pa.notify()
+				p.mutex.Unlock()
+			}
+			return
+		}
+
+		if pa.released == nil {
+			pa.released = make(chan struct{})
+		}
+		var released = pa.released
+		p.mutex.Unlock()
+		closePoolConns(expired)
+
+		select {
+		case <-released:
+		case <-deadline:
+			return poolConn{}, &ErrClientTimeout
+		}
+	}
+}
+
+// release put given connection back to the pool if it is reusable, otherwise close it if the pool own it.
+func (p *clientPool) release(scheme, authority string, pc poolConn, reusable bool) {
+	var key = scheme + "://" + authority
+	p.mutex.Lock()
+	var pa = p.authorities[key]
+	if pa == nil {
+		// Pool closed idle connections of the authority, so just close it.
+		p.mutex.Unlock()
+		pc.close()
+		return
+	}
+	var pooled = reusable && len(pa.idle) < p.maxIdlePerAuthority
+	if pooled {
+		pc.idleSince = monotonic.Now()
+		pa.idle = append(pa.idle, pc)
+	} else {
+		pa.active--
+	}
+	pa.notify()
+	p.mutex.Unlock()
+
+	if !pooled {
+		pc.close()
+	}
+}
+
+// closeIdle close all idle connections of all authorities.
+func (p *clientPool) closeIdle() {
+	var idle []poolConn
+	p.mutex.Lock()
+	for _, pa := range p.authorities {
+		idle = append(idle, pa.idle...)
+		pa.active -= len(pa.idle)
+		pa.idle = nil
+		pa.notify()
+	}
+	p.mutex.Unlock()
+
+	closePoolConns(idle)
+}
+
+// evictExpired remove idle connections that their idle time pass the idle timeout and return them.
+// Caller must hold the pool mutex and close returned connections after unlock it.
+func (p *clientPool) evictExpired(pa *poolAuthority) (expired []poolConn) {
+	if p.idleTimeout == 0 {
+		return
+	}
+	var ln int
+	for ln < len(pa.idle) && pa.idle[ln].idleSince.SinceNow() > p.idleTimeout {
+		ln++
+	}
+	if ln > 0 {
+		expired = make([]poolConn, ln)
+		copy(expired, pa.idle[:ln])
+		for i := 0; i < ln; i++ {
+			pa.idle[i] = poolConn{}
+		}
+		pa.idle = pa.idle[ln:]
+		pa.active -= ln
+		pa.notify()
+	}
+	return
+}
+
+func (pa *poolAuthority) notify() {
+	if pa.released != nil {
+		close(pa.released)
+		pa.released = nil
+	}
+}
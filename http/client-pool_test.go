@@ -0,0 +1,133 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// poolTestConnection count its Close calls.
+type poolTestConnection struct {
+	protocol.Connection
+	closed int
+}
+
+func (conn *poolTestConnection) Close() (err protocol.Error) { conn.closed++; return }
+
+type clientPoolTest struct {
+	name        string
+	shared      bool
+	reusable    bool
+	idleTimeout protocol.Duration
+	closeIdle   bool
+	closed      int
+}
+
+var clientPoolTests = []clientPoolTest{
+	{
+		name:     "owned-not-reusable",
+		reusable: false,
+		closed:   1,
+	}, {
+		name:     "shared-not-reusable",
+		shared:   true,
+		reusable: false,
+		closed:   0,
+	}, {
+		name:     "owned-reusable",
+		reusable: true,
+		closed:   0,
+	}, {
+		name:      "owned-close-idle",
+		reusable:  true,
+		closeIdle: true,
+		closed:    1,
+	}, {
+		name:      "shared-close-idle",
+		shared:    true,
+		reusable:  true,
+		closeIdle: true,
+		closed:    0,
+	}, {
+		name:        "owned-expired",
+		reusable:    true,
+		idleTimeout: -1,
+		closed:      1,
+	}, {
+		name:        "shared-expired",
+		shared:      true,
+		reusable:    true,
+		idleTimeout: -1,
+		closed:      0,
+	},
+}
+
+func TestClientPoolClose(t *testing.T) {
+	for _, tt := range clientPoolTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dialed []*poolTestConnection
+			var dial = func(scheme, authority string) (conn protocol.Connection, shared bool, err protocol.Error) {
+				var c = &poolTestConnection{}
+				dialed = append(dialed, c)
+				return c, tt.shared, nil
+			}
+			var p clientPool
+			p.init(dial, 1, 0)
+			// Negative timeout means every idle connection expired on next acquire.
+			p.idleTimeout = tt.idleTimeout
+
+			var pc, err = p.acquire("https", "sabz.city", nil)
+			if err != nil {
+				t.Fatalf("clientPool.acquire() error = %v", err)
+			}
+			p.release("https", "sabz.city", pc, tt.reusable)
+			if tt.closeIdle {
+				p.closeIdle()
+			}
+			if tt.idleTimeout != 0 {
+				pc, err = p.acquire("https", "sabz.city", nil)
+				if err != nil {
+					t.Fatalf("clientPool.acquire() after expire error = %v", err)
+				}
+				if pc.conn == dialed[0] {
+					t.Errorf("clientPool.acquire() return expired connection")
+				}
+			}
+
+			if dialed[0].closed != tt.closed {
+				t.Errorf("connection closed %v times, want %v", dialed[0].closed, tt.closed)
+			}
+		})
+	}
+}
+
+func TestClientPoolLimit(t *testing.T) {
+	var dial = func(scheme, authority string) (conn protocol.Connection, shared bool, err protocol.Error) {
+		return &poolTestConnection{}, false, nil
+	}
+	var p clientPool
+	p.init(dial, 1, 0)
+
+	var pc, err = p.acquire("https", "sabz.city", nil)
+	if err != nil {
+		t.Fatalf("clientPool.acquire() error = %v", err)
+	}
+	var deadline = make(chan struct{})
+	close(deadline)
+	_, err = p.acquire("https", "sabz.city", deadline)
+	if err != &ErrClientTimeout {
+		t.Errorf("clientPool.acquire() over limit error = %v, want %v", err, &ErrClientTimeout)
+	}
+
+	p.release("https", "sabz.city", pc, true)
+	var reused poolConn
+	reused, err = p.acquire("https", "sabz.city", deadline)
+	if err != nil {
+		t.Fatalf("clientPool.acquire() after release error = %v", err)
+	}
+	if reused.conn != pc.conn {
+		t.Errorf("clientPool.acquire() not reuse released idle connection")
+	}
+}
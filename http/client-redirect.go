@@ -0,0 +1,189 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// RedirectPolicy indicate how a Client follow redirect responses.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-15.4
+type RedirectPolicy struct {
+	// MaxRedirects is max number of redirects to follow. 0 means DefaultMaxRedirects and negative means don't follow any redirect.
+	MaxRedirects int
+	// SameAuthorityOnly don't follow redirects to other authorities.
+	SameAuthorityOnly bool
+	// AllowSchemeDowngrade let follow redirect from "https" to "http".
+	AllowSchemeDowngrade bool
+	// Check call before follow each redirect if not nil. via is the requests made already, oldest first.
+	// Return error to stop follow the redirect, the error returns to Client.Do caller with last response.
+	Check func(req *Request, via []*Request) (err protocol.Error)
+}
+
+// isRedirect report the response status code is a redirect that must follow
+// and the next request must keep the method and the body or not.
+func isRedirect(statusCode string) (redirect bool, keepMethod bool) {
+	switch statusCode {
+	case StatusMovedPermanentlyCode, StatusFoundCode, StatusSeeOtherCode:
+		return true, false
+	case StatusTemporaryRedirectCode, StatusPermanentRedirectCode:
+		return true, true
+	}
+	return false, false
+}
+
+// redirectRequest make next request by the redirect response of the given request.
+func (rp *RedirectPolicy) redirectRequest(httpReq *Request, httpRes *Response, via []*Request) (next *Request, err protocol.Error) {
+	var maxRedirects = rp.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	if maxRedirects < 0 {
+		return nil, nil
+	}
+	if len(via) >= maxRedirects {
+		return nil, &ErrClientTooManyRedirects
+	}
+
+	var redirect, keepMethod = isRedirect(httpRes.StatusCode())
+	var location = httpRes.H.Get(HeaderKeyLocation)
+	if !redirect || location == "" {
+		return nil, nil
+	}
+
+	var reqURI = httpReq.URI()
+	var target = resolveReference(reqURI.Scheme(), reqURI.Authority(), reqURI.Path(), location)
+	next = &Request{}
+	next.Init()
+	next.uri.Init(target)
+	if next.uri.Authority() == "" {
+		return nil, &ErrClientBadURI
+	}
+
+	var sameAuthority = strings.EqualFold(next.uri.Authority(), reqURI.Authority())
+	if rp.SameAuthorityOnly && !sameAuthority {
+		return nil, &ErrClientRedirectNotAllowed
+	}
+	if !rp.AllowSchemeDowngrade && reqURI.Scheme() == "https" && next.uri.Scheme() != "https" {
+		return nil, &ErrClientRedirectNotAllowed
+	}
+
+	var method = httpReq.Method()
+	// 301 and 302 change POST to GET like all user agents do, and 303 change any method except HEAD to GET.
+	// https://datatracker.ietf.org/doc/html/rfc9110#section-15.4.4
+	var changeMethod = !keepMethod && method != MethodHEAD && (method == MethodPOST || httpRes.StatusCode() == StatusSeeOtherCode)
+	if changeMethod {
+		next.SetMethod(MethodGET)
+	} else {
+		next.SetMethod(method)
+		next.SetBody(httpReq.body.Codec)
+	}
+	next.SetVersion(httpReq.Version())
+
+	httpReq.H.Range(func(key, value string) {
+		switch key {
+		case HeaderKeyHost, HeaderKeyCookie:
+			// Host set by new authority and Cookie set again by the client jar for new URI.
+			return
+		case HeaderKeyAuthorization, HeaderKeyProxyAuthorization:
+			// Don't leak credentials to other authorities.
+			if !sameAuthority {
+				return
+			}
+		case HeaderKeyContentType, HeaderKeyContentLength, HeaderKeyContentEncoding, HeaderKeyTransferEncoding:
+			if changeMethod {
+				return
+			}
+		}
+		next.H.Add(key, value)
+	})
+	next.H.Set(HeaderKeyHost, next.uri.Authority())
+	if reqURI.Scheme() == next.uri.Scheme() || next.uri.Scheme() == "https" {
+		next.H.Set(HeaderKeyReferer, reqURI.Scheme()+"://"+reqURI.Authority()+reqURI.Path())
+	}
+
+	if rp.Check != nil {
+		err = rp.Check(next, via)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// resolveReference resolve given reference e.g. Location header value against base URI parts to an absolute URI.
+// https://datatracker.ietf.org/doc/html/rfc3986#section-5.2
+func resolveReference(scheme, authority, basePath, reference string) (target string) {
+	// Fragment not send in requests.
+	if i := strings.IndexByte(reference, NumberSign); i != -1 {
+		reference = reference[:i]
+	}
+
+	switch {
+	case strings.Contains(reference, "://"):
+		target = reference
+	case strings.HasPrefix(reference, "//"):
+		target = scheme + ":" + reference
+	case strings.HasPrefix(reference, "/"):
+		target = scheme + "://" + authority + removeDotSegments(reference)
+	case strings.HasPrefix(reference, "?"):
+		target = scheme + "://" + authority + basePath + reference
+	default:
+		var dir = basePath[:strings.LastIndexByte(basePath, Slash)+1]
+		if dir == "" {
+			dir = "/"
+		}
+		target = scheme + "://" + authority + removeDotSegments(dir+reference)
+	}
+
+	// uri.URI parser need path to find authority end.
+	var authorityStart = strings.Index(target, "://") + 3
+	if strings.IndexByte(target[authorityStart:], Slash) == -1 {
+		if q := strings.IndexByte(target[authorityStart:], Question); q != -1 {
+			target = target[:authorityStart+q] + "/" + target[authorityStart+q:]
+		} else {
+			target += "/"
+		}
+	}
+	return
+}
+
+// removeDotSegments remove "." and ".." segments from given path that can has query.
+// https://datatracker.ietf.org/doc/html/rfc3986#section-5.2.4
+func removeDotSegments(path string) string {
+	var query string
+	if i := strings.IndexByte(path, Question); i != -1 {
+		path, query = path[:i], path[i:]
+	}
+	if !strings.Contains(path, ".") {
+		return path + query
+	}
+
+	var segments = strings.Split(path, "/")
+	var out = make([]string, 0, len(segments))
+	for i, segment := range segments {
+		var last = i == len(segments)-1
+		switch segment {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, segment)
+		}
+	}
+	path = strings.Join(out, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path + query
+}
@@ -0,0 +1,240 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/monotonic"
+	"github.com/GeniusesGroup/libgo/timer"
+)
+
+const (
+	// DefaultClientTimeout use by RoundTrip callers when they don't have any deadline e.g. handlers.SendBidirectionalRequest
+	DefaultClientTimeout = 60 * monotonic.Second
+	// DefaultMaxRedirects is max number of redirects that a Client follow if its RedirectPolicy not set any.
+	DefaultMaxRedirects = 10
+	// DefaultMaxIdleConnsPerAuthority is max number of idle connections that a Client keep for each authority.
+	DefaultMaxIdleConnsPerAuthority = 4
+)
+
+// Client send HTTP requests over pooled connections and follow redirects, handle timeouts,
+// decompress responses and keep cookies if it has a jar.
+// Client is safe to use concurrently after Init. Change exported fields just before first use.
+type Client struct {
+	// Timeout is overall deadline of Do() include all redirects and wait for a free connection. 0 means no deadline.
+	Timeout protocol.Duration
+	// RequestTimeout is deadline of each request to get its response. 0 means DefaultClientTimeout.
+	RequestTimeout protocol.Duration
+	Redirect       RedirectPolicy
+	// Jar keep cookies of responses and send them with next requests. nil means don't handle cookies.
+	Jar *CookieJar
+	// DisableCompression don't ask peers for compressed responses by Accept-Encoding header.
+	DisableCompression bool
+
+	pool clientPool
+}
+
+// Init initialize the client. nil dial means DialByDomain, maxConnsPerAuthority 0 means no limit and
+// idleTimeout 0 means idle connections never expire by the client.
+func (c *Client) Init(dial DialFunc, maxConnsPerAuthority int, idleTimeout protocol.Duration) {
+	c.pool.init(dial, maxConnsPerAuthority, idleTimeout)
+}
+
+// CloseIdleConnections close all connections that not in use now.
+func (c *Client) CloseIdleConnections() { c.pool.closeIdle() }
+
+// Get send a GET request to given absolute URI.
+func (c *Client) Get(service protocol.Service, uri string) (httpRes *Response, err protocol.Error) {
+	var httpReq Request
+	httpReq.Init()
+	httpReq.SetMethod(MethodGET)
+	httpReq.SetVersion(VersionHTTP11)
+	httpReq.uri.Init(uri)
+	return c.Do(service, &httpReq)
+}
+
+// Do send given request and return its response. It follow redirects by client RedirectPolicy and
+// return last response. Request URI must be in absolute-form or request must have Host header.
+func (c *Client) Do(service protocol.Service, httpReq *Request) (httpRes *Response, err protocol.Error) {
+	// Signal() return nil channel if deadline not initialized, so it never selected.
+	var deadline timer.Sync
+	if c.Timeout > 0 {
+		deadline.Init()
+		err = deadline.Start(c.Timeout)
+		if err != nil {
+			return
+		}
+		defer deadline.Stop()
+	}
+
+	var via []*Request
+	for {
+		httpRes, err = c.send(service, httpReq, deadline.Signal())
+		if err != nil {
+			return
+		}
+
+		var next *Request
+		next, err = c.Redirect.redirectRequest(httpReq, httpRes, via)
+		if err != nil || next == nil {
+			return
+		}
+		via = append(via, httpReq)
+		httpReq = next
+	}
+}
+
+// send send just one request over a pooled connection.
+func (c *Client) send(service protocol.Service, httpReq *Request, deadline <-chan struct{}) (httpRes *Response, err protocol.Error) {
+	var reqURI = httpReq.URI()
+	var scheme = reqURI.Scheme()
+	if scheme == "" {
+		scheme = "http"
+	}
+	var authority = reqURI.Authority()
+	if authority == "" {
+		authority = httpReq.H.Get(HeaderKeyHost)
+	} else if httpReq.H.Get(HeaderKeyHost) == "" {
+		httpReq.H.Set(HeaderKeyHost, authority)
+	}
+	if authority == "" {
+		return nil, &ErrClientBadURI
+	}
+	if httpReq.Version() == "" {
+		httpReq.SetVersion(VersionHTTP11)
+	}
+	if httpReq.H.Get(HeaderKeyUserAgent) == "" {
+		httpReq.H.Set(HeaderKeyUserAgent, DefaultUserAgent)
+	}
+
+	var path = reqURI.Path()
+	if c.Jar != nil {
+		var cookies = c.Jar.Cookies(scheme, authority, path)
+		if len(cookies) > 0 {
+			httpReq.H.MarshalCookies(cookies)
+		}
+	}
+
+	var askedCompression bool
+	if !c.DisableCompression && httpReq.H.Get(HeaderKeyAcceptEncoding) == "" && httpReq.Method() != MethodHEAD {
+		var contentEncodings = CompressContentEncodings()
+		if len(contentEncodings) > 0 {
+			httpReq.H.Set(HeaderKeyAcceptEncoding, strings.Join(contentEncodings, ", "))
+			askedCompression = true
+		}
+	}
+
+	var pc poolConn
+	pc, err = c.pool.acquire(scheme, authority, deadline)
+	if err != nil {
+		return
+	}
+
+	var timeout = c.RequestTimeout
+	if timeout == 0 {
+		timeout = DefaultClientTimeout
+	}
+	httpRes, err = roundTrip(pc.conn, service, httpReq, timeout, deadline)
+	c.pool.release(scheme, authority, pc, keepAlive(httpReq, httpRes))
+	if httpRes == nil {
+		return
+	}
+
+	if c.Jar != nil {
+		c.Jar.SetCookies(scheme, authority, path, httpRes.H.SetCookies())
+	}
+	if askedCompression && httpRes.H.Get(HeaderKeyContentEncoding) != "" {
+		// Body decompressed already when response decoded, so these headers not valid anymore.
+		httpRes.H.Del(HeaderKeyContentEncoding)
+		httpRes.H.Del(HeaderKeyContentLength)
+	}
+	return
+}
+
+// RoundTrip send given request on a new stream of given connection and block caller until get response,
+// timeout reached or error occur. It doesn't follow redirects or handle cookies, use Client for that.
+func RoundTrip(conn protocol.Connection, service protocol.Service, httpReq *Request, timeout protocol.Duration) (httpRes *Response, err protocol.Error) {
+	return roundTrip(conn, service, httpReq, timeout, nil)
+}
+
+func roundTrip(conn protocol.Connection, service protocol.Service, httpReq *Request, timeout protocol.Duration, deadline <-chan struct{}) (httpRes *Response, err protocol.Error) {
+	var st protocol.Stream
+	st, err = conn.OutcomeStream(service)
+	if err != nil {
+		return
+	}
+	defer st.Close()
+
+	err = conn.PacketSending(uint64(httpReq.Len()))
+	if err != nil {
+		return
+	}
+	_, err = st.Encode(httpReq)
+	if err != nil {
+		return
+	}
+
+	var requestDeadline timer.Sync
+	requestDeadline.Init()
+	err = requestDeadline.Start(timeout)
+	if err != nil {
+		return
+	}
+	defer requestDeadline.Stop()
+
+	var streamStatus = st.State()
+	for {
+		select {
+		case status := <-streamStatus:
+			switch status {
+			case protocol.NetworkStatus_ReceivedCompletely:
+				var res Response
+				res.Init()
+				_, err = res.Decode(st)
+				if err != nil {
+					return
+				}
+				err = conn.PacketReceiving(uint64(res.Len()))
+				if err != nil {
+					return
+				}
+				httpRes = &res
+				err = res.GetError()
+				return
+			case protocol.NetworkStatus_Timeout:
+				err = &ErrClientTimeout
+				return
+			case protocol.NetworkStatus_Closed, protocol.NetworkStatus_BrokenPacket:
+				err = st.Error()
+				if err == nil {
+					err = &ErrNoConnection
+				}
+				return
+			}
+		case <-requestDeadline.Signal():
+			err = &ErrClientTimeout
+			return
+		case <-deadline:
+			err = &ErrClientTimeout
+			return
+		}
+	}
+}
+
+// keepAlive report the connection can reuse for next requests after given request and its response.
+// https://datatracker.ietf.org/doc/html/rfc9112#section-9.3
+func keepAlive(httpReq *Request, httpRes *Response) bool {
+	if httpRes == nil || strings.EqualFold(httpReq.H.Get(HeaderKeyConnection), HeaderValueClose) {
+		return false
+	}
+	var connection = httpRes.H.Get(HeaderKeyConnection)
+	if strings.EqualFold(connection, HeaderValueClose) {
+		return false
+	}
+	if httpRes.Version() == VersionHTTP1 {
+		return strings.EqualFold(connection, HeaderValueKeepAlive)
+	}
+	return true
+}
@@ -5,7 +5,7 @@ package http
 /*
 				********************PAY ATTENTION:*******************
 	We believe HTTP version 2 and above are new protocol not new version of HTTP.
-	So we don't support HTTP2 and HTTP3 specs in this package.
+	So we don't support HTTP2 and HTTP3 specs in this package. HTTP2 implement in h2 sub package by reuse this package types.
 */
 
 const (
@@ -15,6 +15,35 @@ const (
 	// MaxHTTPHeaderSize is max HTTP header size.
 	MaxHTTPHeaderSize = 8192
 
+	// ChunkSize is default max size of each chunk when encode a body by chunked transfer coding.
+	ChunkSize = 16 * 1024
+	// MaxChunkSize is max size of each chunk that accept in a chunked transfer coding body.
+	MaxChunkSize = 1024 * 1024
+	// MaxChunkedBodySize is max size of whole body that accept in a chunked transfer coding body.
+	MaxChunkedBodySize = 64 * 1024 * 1024
+	// chunkSizeLineMaxLength is max length of chunk-size line with its chunk-ext if any.
+	chunkSizeLineMaxLength = 1024
+
+	// CompressMinLength is min length of a body with known length to compress it by negotiated content encoding.
+	// Compress smaller body can make it larger and just waste CPU.
+	CompressMinLength = 1024
+
+	// MaxByteRanges is max number of ranges in a Range header that serve, more ranges ignore and whole representation serve.
+	MaxByteRanges = 16
+
+	// MaxFormSize is max size of an application/x-www-form-urlencoded body that accept.
+	MaxFormSize = 10 * 1024 * 1024
+	// MaxFormFields is max number of fields in an application/x-www-form-urlencoded body or query that accept.
+	MaxFormFields = 1000
+	// MaxProblemSize is max size of an application/problem+json body that decode to an error.
+	MaxProblemSize = 64 * 1024
+	// MaxMultipartSize is default max size of a multipart/form-data body that accept.
+	MaxMultipartSize = 64 * 1024 * 1024
+	// MaxMultipartMemory is default max size of each multipart/form-data part that hold in memory.
+	MaxMultipartMemory = 1024 * 1024
+	// MaxMultipartParts is max number of parts in a multipart/form-data body that accept.
+	MaxMultipartParts = 1000
+
 	// TimeFormat is the time format to use when generating times in HTTP
 	// headers. It is like time.RFC1123 but hard-codes GMT as the time
 	// zone. The time being formatted must be in UTC for Format to
@@ -185,6 +214,10 @@ const (
 	HeaderValueCompress = "compress"
 	HeaderValueDeflate  = "deflate"
 	HeaderValueGZIP     = "gzip"
+
+	// Range requests
+	HeaderValueBytes = "bytes"
+	HeaderValueNone  = "none"
 )
 
 // HTTP Status codes
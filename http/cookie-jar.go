@@ -0,0 +1,243 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CookieJar store cookies that received by Set-Cookie header of responses and
+// return cookies that must send with next requests by RFC 6265 rules.
+// It doesn't know public suffixes, so a server can set a cookie for e.g. "com" domain. Use it just with trusted servers.
+// https://datatracker.ietf.org/doc/html/rfc6265#section-5.3
+type CookieJar struct {
+	mutex    sync.Mutex
+	entries  map[string][]jarEntry // key is cookie domain in lower case
+	sequence uint64                // increase for each new cookie to order cookies by creation
+}
+
+type jarEntry struct {
+	name     string
+	value    string
+	domain   string
+	path     string
+	hostOnly bool
+	secure   bool
+	expires  time.Time // zero means session cookie
+	creation uint64    // jar sequence when the cookie created
+}
+
+func (jar *CookieJar) Init() {
+	jar.entries = make(map[string][]jarEntry)
+}
+
+// SetCookies store given setCookies that received in response to a request to given scheme, host and path.
+func (jar *CookieJar) SetCookies(scheme, host, path string, setCookies []SetCookie) {
+	if len(setCookies) == 0 {
+		return
+	}
+	host = canonicalCookieHost(host)
+	var now = time.Now()
+
+	jar.mutex.Lock()
+	defer jar.mutex.Unlock()
+	if jar.entries == nil {
+		jar.entries = make(map[string][]jarEntry)
+	}
+
+	for i := 0; i < len(setCookies); i++ {
+		var sc = &setCookies[i]
+		if sc.Name == "" {
+			continue
+		}
+
+		var entry = jarEntry{
+			name:   sc.Name,
+			value:  sc.Value,
+			path:   sc.Path,
+			secure: sc.Secure,
+		}
+		if sc.Secure && scheme != "https" {
+			continue
+		}
+
+		var domain = strings.ToLower(strings.TrimPrefix(sc.Domain, "."))
+		if domain == "" {
+			entry.domain = host
+			entry.hostOnly = true
+		} else {
+			// IP address can't set cookie for other domain.
+			if net.ParseIP(host) != nil && domain != host {
+				continue
+			}
+			if !cookieDomainMatch(host, domain) {
+				continue
+			}
+			entry.domain = domain
+		}
+
+		if entry.path == "" || entry.path[0] != Slash {
+			entry.path = defaultCookiePath(path)
+		}
+
+		var remove bool
+		if sc.MaxAge != "" {
+			var maxAge, err = strconv.Atoi(sc.MaxAge)
+			if err == nil {
+				if maxAge <= 0 {
+					remove = true
+				} else {
+					entry.expires = now.Add(time.Duration(maxAge) * time.Second)
+				}
+			}
+		} else if sc.Expires != "" {
+			entry.expires = sc.GetExpire()
+			if !entry.expires.IsZero() && !entry.expires.After(now) {
+				remove = true
+			}
+		}
+
+		jar.replace(entry, remove)
+	}
+}
+
+// Cookies return cookies that must send with a request to given scheme, host and path.
+// Longer paths listed first and for same path length, earlier creation time first.
+func (jar *CookieJar) Cookies(scheme, host, path string) (cookies []Cookie) {
+	host = canonicalCookieHost(host)
+	if path == "" || path[0] != Slash {
+		path = "/"
+	}
+	var secure = scheme == "https"
+	var now = time.Now()
+
+	jar.mutex.Lock()
+	defer jar.mutex.Unlock()
+
+	var matched []jarEntry
+	// Check host domain and all its parent domains.
+	for domain := host; domain != ""; {
+		var entries = jar.entries[domain]
+		var alive = entries[:0]
+		for _, entry := range entries {
+			if !entry.expires.IsZero() && !entry.expires.After(now) {
+				continue
+			}
+			alive = append(alive, entry)
+
+			if entry.hostOnly && entry.domain != host {
+				continue
+			}
+			if entry.secure && !secure {
+				continue
+			}
+			if !cookiePathMatch(path, entry.path) {
+				continue
+			}
+			matched = append(matched, entry)
+		}
+		if len(alive) == 0 {
+			delete(jar.entries, domain)
+		} else {
+			jar.entries[domain] = alive
+		}
+
+		var dot = strings.IndexByte(domain, '.')
+		if dot == -1 || net.ParseIP(host) != nil {
+			break
+		}
+		domain = domain[dot+1:]
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if len(matched[i].path) != len(matched[j].path) {
+			return len(matched[i].path) > len(matched[j].path)
+		}
+		return matched[i].creation < matched[j].creation
+	})
+
+	cookies = make([]Cookie, len(matched))
+	for i, entry := range matched {
+		cookies[i] = Cookie{Name: entry.name, Value: entry.value}
+	}
+	return
+}
+
+// Clear remove all stored cookies.
+func (jar *CookieJar) Clear() {
+	jar.mutex.Lock()
+	jar.entries = make(map[string][]jarEntry)
+	jar.mutex.Unlock()
+}
+
+// replace add or replace the cookie with same name, domain and path. Caller must hold the jar mutex.
+func (jar *CookieJar) replace(entry jarEntry, remove bool) {
+	var entries = jar.entries[entry.domain]
+	for i := 0; i < len(entries); i++ {
+		var old = &entries[i]
+		if old.name == entry.name && old.path == entry.path {
+			if remove {
+				jar.entries[entry.domain] = append(entries[:i], entries[i+1:]...)
+				return
+			}
+			// Keep the creation time of the old cookie.
+			// https://datatracker.ietf.org/doc/html/rfc6265#section-5.3 step 11.3
+			entry.creation = old.creation
+			*old = entry
+			return
+		}
+	}
+	if !remove {
+		jar.sequence++
+		entry.creation = jar.sequence
+		jar.entries[entry.domain] = append(entries, entry)
+	}
+}
+
+// canonicalCookieHost remove port and brackets of IPv6 and return host in lower case.
+func canonicalCookieHost(host string) string {
+	if i := strings.LastIndexByte(host, Colon); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// cookieDomainMatch report host domain-match the cookie domain.
+// https://datatracker.ietf.org/doc/html/rfc6265#section-5.1.3
+func cookieDomainMatch(host, domain string) bool {
+	if host == domain {
+		return true
+	}
+	return strings.HasSuffix(host, domain) && host[len(host)-len(domain)-1] == '.' && net.ParseIP(host) == nil
+}
+
+// defaultCookiePath return the directory of the request path.
+// https://datatracker.ietf.org/doc/html/rfc6265#section-5.1.4
+func defaultCookiePath(path string) string {
+	if path == "" || path[0] != Slash {
+		return "/"
+	}
+	var i = strings.LastIndexByte(path, Slash)
+	if i == 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+// cookiePathMatch report request path path-match the cookie path.
+// https://datatracker.ietf.org/doc/html/rfc6265#section-5.1.4
+func cookiePathMatch(path, cookiePath string) bool {
+	if path == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return cookiePath[len(cookiePath)-1] == Slash || path[len(cookiePath)] == Slash
+}
@@ -0,0 +1,71 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"testing"
+)
+
+func TestCookieJar(t *testing.T) {
+	var jar CookieJar
+	jar.Init()
+	jar.SetCookies("https", "www.sabz.city:443", "/login", []SetCookie{
+		{Name: "host", Value: "1"},
+		{Name: "domain", Value: "2", Domain: ".sabz.city"},
+		{Name: "path", Value: "3", Path: "/apps"},
+		{Name: "secure", Value: "4", Secure: true},
+		{Name: "other", Value: "5", Domain: "other.city"},
+		{Name: "expired", Value: "6", MaxAge: "0"},
+	})
+
+	var tests = []struct {
+		scheme, host, path string
+		want               string
+	}{
+		{"https", "www.sabz.city", "/apps/list", "path=3; host=1; domain=2; secure=4"},
+		{"http", "www.sabz.city", "/apps", "path=3; host=1; domain=2"},
+		{"https", "www.sabz.city", "/", "host=1; domain=2; secure=4"},
+		{"https", "sabz.city", "/apps", "domain=2"},
+		{"https", "api.sabz.city", "/applications", "domain=2"},
+		{"https", "other.city", "/", ""},
+	}
+	for _, tt := range tests {
+		var h header
+		h.Init()
+		var cookies = jar.Cookies(tt.scheme, tt.host, tt.path)
+		if len(cookies) > 0 {
+			h.MarshalCookies(cookies)
+		}
+		if got := h.Get(HeaderKeyCookie); got != tt.want {
+			t.Errorf("CookieJar.Cookies(%s, %s, %s) = %q, want %q", tt.scheme, tt.host, tt.path, got, tt.want)
+		}
+	}
+
+	jar.SetCookies("https", "www.sabz.city", "/", []SetCookie{{Name: "domain", Value: "", Domain: "sabz.city", MaxAge: "-1"}})
+	if cookies := jar.Cookies("https", "sabz.city", "/"); len(cookies) != 0 {
+		t.Errorf("CookieJar not delete cookie by Max-Age, got %v", cookies)
+	}
+}
+
+func TestResolveReference(t *testing.T) {
+	var tests = []struct {
+		reference string
+		want      string
+	}{
+		{"https://www.sabz.city/login", "https://www.sabz.city/login"},
+		{"https://www.sabz.city", "https://www.sabz.city/"},
+		{"https://www.sabz.city?a=1", "https://www.sabz.city/?a=1"},
+		{"//cdn.sabz.city/a.js", "http://cdn.sabz.city/a.js"},
+		{"/login?next=/", "http://sabz.city/login?next=/"},
+		{"?page=2", "http://sabz.city/a/b/c?page=2"},
+		{"d", "http://sabz.city/a/b/d"},
+		{"../d#top", "http://sabz.city/a/d"},
+		{"./", "http://sabz.city/a/b/"},
+		{"../../../d", "http://sabz.city/d"},
+	}
+	for _, tt := range tests {
+		if got := resolveReference("http", "sabz.city", "/a/b/c", tt.reference); got != tt.want {
+			t.Errorf("resolveReference(%q) = %q, want %q", tt.reference, got, tt.want)
+		}
+	}
+}
@@ -26,6 +26,28 @@ var (
 	ErrCookieBadDomain      er.Error
 	ErrNotFound             er.Error
 	ErrUnsupportedMediaType er.Error
+
+	ErrChunkedMalformed            er.Error
+	ErrChunkTooLarge               er.Error
+	ErrChunkedBodyTooLarge         er.Error
+	ErrChunkedTrailerTooLarge      er.Error
+	ErrChunkedSourceNotChangeable  er.Error
+	ErrTransferEncodingUnsupported er.Error
+
+	ErrClientTimeout            er.Error
+	ErrClientTooManyRedirects   er.Error
+	ErrClientBadURI             er.Error
+	ErrClientRedirectNotAllowed er.Error
+
+	ErrRangeNotSatisfiable er.Error
+
+	ErrFormMalformed         er.Error
+	ErrFormTooLarge          er.Error
+	ErrFormFieldNotExist     er.Error
+	ErrFormFieldValue        er.Error
+	ErrMultipartMalformed    er.Error
+	ErrMultipartTooLarge     er.Error
+	ErrMultipartTooManyParts er.Error
 )
 
 func init() {
@@ -146,4 +168,149 @@ func init() {
 		"",
 		"",
 		nil)
+
+	ErrChunkedMalformed.Init("domain/http.protocol; type=error; name=chunked-malformed")
+	ErrChunkedMalformed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Chunked Malformed",
+		"Received body by chunked transfer coding is malformed or not complete",
+		"",
+		"",
+		nil)
+
+	ErrChunkTooLarge.Init("domain/http.protocol; type=error; name=chunk-too-large")
+	ErrChunkTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Chunk Too Large",
+		"Size of a received chunk in chunked transfer coding body is larger than allowed",
+		"",
+		"",
+		nil)
+
+	ErrChunkedBodyTooLarge.Init("domain/http.protocol; type=error; name=chunked-body-too-large")
+	ErrChunkedBodyTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Chunked Body Too Large",
+		"Total size of received chunked transfer coding body is larger than allowed",
+		"",
+		"",
+		nil)
+
+	ErrChunkedTrailerTooLarge.Init("domain/http.protocol; type=error; name=chunked-trailer-too-large")
+	ErrChunkedTrailerTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Chunked Trailer Too Large",
+		"Trailer section of received chunked transfer coding body is larger than allowed",
+		"",
+		"",
+		nil)
+
+	ErrChunkedSourceNotChangeable.Init("domain/http.protocol; type=error; name=chunked-source-not-changeable")
+	ErrChunkedSourceNotChangeable.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Chunked Source Not Changeable",
+		"Chunked codec just encode its source and can't decode||unmarshal to it",
+		"",
+		"",
+		nil)
+
+	ErrTransferEncodingUnsupported.Init("domain/http.protocol; type=error; name=transfer-encoding-unsupported")
+	ErrTransferEncodingUnsupported.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Transfer Encoding Unsupported",
+		"Just a single chunked transfer coding is supported",
+		"",
+		"",
+		nil)
+
+	ErrClientTimeout.Init("domain/http.protocol; type=error; name=client-timeout")
+	ErrClientTimeout.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Client Timeout",
+		"Client can't get the response before the request or overall deadline reached",
+		"",
+		"",
+		nil)
+	ErrClientTimeout.SetTemporary()
+
+	ErrClientTooManyRedirects.Init("domain/http.protocol; type=error; name=client-too-many-redirects")
+	ErrClientTooManyRedirects.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Client Too Many Redirects",
+		"Client stop following redirects due to reach the redirect policy max redirects",
+		"",
+		"",
+		nil)
+
+	ErrClientBadURI.Init("domain/http.protocol; type=error; name=client-bad-uri")
+	ErrClientBadURI.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Client Bad URI",
+		"Request URI or Location header of a redirect response has no authority to send the request to",
+		"",
+		"",
+		nil)
+
+	ErrClientRedirectNotAllowed.Init("domain/http.protocol; type=error; name=client-redirect-not-allowed")
+	ErrClientRedirectNotAllowed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Client Redirect Not Allowed",
+		"Redirect target authority or scheme is not allowed by the client redirect policy",
+		"",
+		"",
+		nil)
+
+	ErrRangeNotSatisfiable.Init("domain/http.protocol; type=error; name=range-not-satisfiable")
+	ErrRangeNotSatisfiable.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Range Not Satisfiable",
+		"None of the requested ranges overlap the current extent of the selected representation",
+		"",
+		"",
+		nil)
+
+	ErrFormMalformed.Init("domain/http.protocol; type=error; name=form-malformed")
+	ErrFormMalformed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Form Malformed",
+		"Form data in application/x-www-form-urlencoded format has a bad percent-encoded octet",
+		"",
+		"",
+		nil)
+
+	ErrFormTooLarge.Init("domain/http.protocol; type=error; name=form-too-large")
+	ErrFormTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Form Too Large",
+		"Form data in application/x-www-form-urlencoded format is larger than MaxFormSize or has more fields than MaxFormFields",
+		"",
+		"",
+		nil)
+
+	ErrFormFieldNotExist.Init("domain/http.protocol; type=error; name=form-field-not-exist")
+	ErrFormFieldNotExist.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Form Field Not Exist",
+		"Requested field not exist in the form data",
+		"",
+		"",
+		nil)
+
+	ErrFormFieldValue.Init("domain/http.protocol; type=error; name=form-field-value")
+	ErrFormFieldValue.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Form Field Value",
+		"Value of the form field is not in the requested type format",
+		"",
+		"",
+		nil)
+
+	ErrMultipartMalformed.Init("domain/http.protocol; type=error; name=multipart-malformed")
+	ErrMultipartMalformed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Multipart Malformed",
+		"Multipart body has no boundary, bad part header or end before close delimiter",
+		"",
+		"",
+		nil)
+
+	ErrMultipartTooLarge.Init("domain/http.protocol; type=error; name=multipart-too-large")
+	ErrMultipartTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Multipart Too Large",
+		"Multipart body is larger than max size or a part is larger than max memory and can't spill to storage",
+		"",
+		"",
+		nil)
+
+	ErrMultipartTooManyParts.Init("domain/http.protocol; type=error; name=multipart-too-many-parts")
+	ErrMultipartTooManyParts.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Multipart Too Many Parts",
+		"Multipart body has more parts than MaxMultipartParts",
+		"",
+		"",
+		nil)
 }
@@ -0,0 +1,556 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+MultipartForm is multipart/form-data codec.
+https://datatracker.ietf.org/doc/html/rfc7578
+https://datatracker.ietf.org/doc/html/rfc2046#section-5.1.1
+
+	--boundary CRLF
+	Content-Disposition: form-data; name="field1" CRLF
+	CRLF
+	value1 CRLF
+	--boundary CRLF
+	Content-Disposition: form-data; name="file1"; filename="a.txt" CRLF
+	Content-Type: text/plain CRLF
+	CRLF
+	...contents of a.txt... CRLF
+	--boundary-- CRLF
+*/
+type MultipartForm struct {
+	// MaxSize is max size of whole body that accept, 0 means MaxMultipartSize.
+	MaxSize int
+	// MaxMemory is max size of each part data that hold in memory, 0 means MaxMultipartMemory.
+	// Larger file parts spill to SpillDirectory if it is not nil, otherwise ErrMultipartTooLarge return.
+	MaxMemory int
+	// SpillDirectory is where large file parts store. Call Deinit() to delete them after use.
+	SpillDirectory protocol.FileDirectory
+
+	boundary string
+	parts    []*FormPart
+}
+
+// Init set the boundary. Empty boundary means make a random one e.g. to send a form.
+func (mf *MultipartForm) Init(boundary string) {
+	if boundary == "" {
+		boundary = newBoundary()
+	}
+	mf.boundary = boundary
+}
+func (mf *MultipartForm) Reinit() {
+	mf.Deinit()
+	mf.parts = mf.parts[:0]
+}
+
+// Deinit delete any spilled part from SpillDirectory.
+func (mf *MultipartForm) Deinit() {
+	for _, part := range mf.parts {
+		if part.file != nil {
+			mf.SpillDirectory.Delete(part.file.Metadata().URI().Path())
+			part.file = nil
+		}
+	}
+}
+
+func (mf *MultipartForm) Boundary() string { return mf.boundary }
+
+// ContentType return Content-Type header value that carry the boundary.
+func (mf *MultipartForm) ContentType() string {
+	return MediaTypeMultipartForm.MediaType() + "; boundary=" + mf.boundary
+}
+
+// Parts return all parts in receive order.
+func (mf *MultipartForm) Parts() []*FormPart { return mf.parts }
+
+// Part return first part with given name or nil if not exist.
+func (mf *MultipartForm) Part(name string) *FormPart {
+	for _, part := range mf.parts {
+		if part.name == name {
+			return part
+		}
+	}
+	return nil
+}
+
+// Value return data of first not file part with given name as string.
+func (mf *MultipartForm) Value(name string) string {
+	for _, part := range mf.parts {
+		if part.name == name && !part.isFile {
+			return string(part.data)
+		}
+	}
+	return ""
+}
+
+// Files return all file parts with given name e.g. from an input with multiple attribute.
+func (mf *MultipartForm) Files(name string) (files []*FormPart) {
+	for _, part := range mf.parts {
+		if part.name == name && part.isFile {
+			files = append(files, part)
+		}
+	}
+	return
+}
+
+// AddField add a text field part.
+func (mf *MultipartForm) AddField(name, value string) {
+	var part = mf.newPart(name)
+	part.data = []byte(value)
+	part.size = len(value)
+}
+
+// AddFile add a file part. Empty contentType means "application/octet-stream".
+func (mf *MultipartForm) AddFile(name, fileName, contentType string, data []byte) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	var part = mf.newPart(name)
+	part.fileName = fileName
+	part.isFile = true
+	part.H.Set(HeaderKeyContentDisposition, formDataDisposition(name)+`; filename="`+quoteEscape(fileName)+`"`)
+	part.H.Set(HeaderKeyContentType, contentType)
+	part.data = data
+	part.size = len(data)
+}
+
+func (mf *MultipartForm) newPart(name string) (part *FormPart) {
+	part = new(FormPart)
+	part.H.Init()
+	part.H.Set(HeaderKeyContentDisposition, formDataDisposition(name))
+	part.name = name
+	mf.parts = append(mf.parts, part)
+	return
+}
+
+// DecodeFrom decode the form from given reader as the data arrive, so large file parts can spill to SpillDirectory
+// without hold whole body in memory.
+func (mf *MultipartForm) DecodeFrom(reader protocol.Reader) (n int, err protocol.Error) {
+	var md multipartDecoder
+	md.init(mf)
+	var buf = make([]byte, ChunkSize)
+	for !md.Done() {
+		var readLength, goErr = reader.Read(buf)
+		n += readLength
+		if readLength > 0 {
+			err = md.decode(buf[:readLength])
+			if err != nil {
+				return
+			}
+		}
+		if goErr != nil {
+			if goErr == io.EOF {
+				break
+			}
+			return n, &ErrMultipartMalformed
+		}
+	}
+	if !md.Done() {
+		err = &ErrMultipartMalformed
+	}
+	return
+}
+
+//libgo:impl protocol.Codec
+func (mf *MultipartForm) MediaType() protocol.MediaType       { return &MediaTypeMultipartForm }
+func (mf *MultipartForm) CompressType() protocol.CompressType { return nil }
+func (mf *MultipartForm) Len() (ln int) {
+	for _, part := range mf.parts {
+		ln += 2 + len(mf.boundary) + 2 + part.H.Len() + 2 + part.size + 2
+	}
+	ln += 2 + len(mf.boundary) + 4
+	return
+}
+func (mf *MultipartForm) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return mf.Unmarshal(data)
+}
+func (mf *MultipartForm) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = mf.Marshal()
+	if err != nil {
+		return
+	}
+	return destination.Unmarshal(data)
+}
+func (mf *MultipartForm) Marshal() (data []byte, err protocol.Error) {
+	return mf.MarshalTo(make([]byte, 0, mf.Len()))
+}
+func (mf *MultipartForm) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	for _, part := range mf.parts {
+		var partData []byte
+		partData, err = part.Data()
+		if err != nil {
+			return data, err
+		}
+		data = append(data, "--"...)
+		data = append(data, mf.boundary...)
+		data = append(data, CRLF...)
+		data = part.H.MarshalTo(data)
+		data = append(data, CRLF...)
+		data = append(data, partData...)
+		data = append(data, CRLF...)
+	}
+	data = append(data, "--"...)
+	data = append(data, mf.boundary...)
+	data = append(data, "--"...)
+	data = append(data, CRLF...)
+	return data, nil
+}
+func (mf *MultipartForm) Unmarshal(data []byte) (n int, err protocol.Error) {
+	var md multipartDecoder
+	md.init(mf)
+	err = md.decode(data)
+	if err != nil {
+		return
+	}
+	if !md.Done() {
+		return 0, &ErrMultipartMalformed
+	}
+	return len(data), nil
+}
+func (mf *MultipartForm) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = mf.Unmarshal(data)
+	return
+}
+
+// MultipartForm decode request body as multipart/form-data.
+// Large file parts spill to given directory if it is not nil. Call form Deinit() to delete them after use.
+func (r *Request) MultipartForm(maxMemory int, spill protocol.FileDirectory) (form *MultipartForm, err protocol.Error) {
+	var contentType = r.H.Get(HeaderKeyContentType)
+	if !strings.HasPrefix(contentType, MediaTypeMultipartForm.MediaType()) {
+		return nil, &ErrUnsupportedMediaType
+	}
+	var boundary = mediaTypeParameter(contentType, "boundary")
+	if boundary == "" || len(boundary) > 70 {
+		return nil, &ErrMultipartMalformed
+	}
+
+	form = new(MultipartForm)
+	form.Init(boundary)
+	form.MaxMemory = maxMemory
+	form.SpillDirectory = spill
+	_, err = form.DecodeFrom(&r.body)
+	return
+}
+
+// FormPart is a part of a multipart/form-data body.
+type FormPart struct {
+	H header
+
+	name     string
+	fileName string
+	isFile   bool
+	data     []byte        // part data if it is hold in memory
+	file     protocol.File // part data if it spill to storage
+	size     int
+}
+
+func (p *FormPart) Header() protocol.HTTPHeader { return &p.H }
+func (p *FormPart) Name() string                { return p.name }
+func (p *FormPart) FileName() string            { return p.fileName }
+func (p *FormPart) IsFile() bool                { return p.isFile }
+func (p *FormPart) Size() int                   { return p.size }
+
+// File return the file that part data spill to it or nil if data is in memory.
+func (p *FormPart) File() protocol.File { return p.file }
+
+// ContentType return media type of the part. Default is "text/plain" due to RFC 7578.
+func (p *FormPart) ContentType() string {
+	var contentType = p.H.Get(HeaderKeyContentType)
+	if contentType == "" {
+		return "text/plain"
+	}
+	return contentType
+}
+
+// Data return whole part data from memory or from the spilled file.
+func (p *FormPart) Data() (data []byte, err protocol.Error) {
+	if p.file != nil {
+		return p.file.Data().Marshal()
+	}
+	return p.data, nil
+}
+
+type multipartState uint8
+
+const (
+	multipartState_Preamble multipartState = iota
+	multipartState_Delimiter
+	multipartState_Header
+	multipartState_Data
+	multipartState_Done
+)
+
+// multipartDecoder decode multipart body in a non-blocking manner.
+// Caller can pass data in any number of parts, e.g. as received from network, until Done() return true.
+type multipartDecoder struct {
+	form      *MultipartForm
+	delimiter []byte // CRLF "--" boundary
+	maxSize   int
+	maxMemory int
+	state     multipartState
+	buf       []byte // received data that not consumed yet
+	size      int
+	part      *FormPart
+	discarded bool // some preamble discarded, so first boundary must have leading CRLF
+}
+
+func (md *multipartDecoder) init(form *MultipartForm) {
+	md.form = form
+	md.delimiter = []byte("\r\n--" + form.boundary)
+	md.maxSize = form.MaxSize
+	if md.maxSize <= 0 {
+		md.maxSize = MaxMultipartSize
+	}
+	md.maxMemory = form.MaxMemory
+	if md.maxMemory <= 0 {
+		md.maxMemory = MaxMultipartMemory
+	}
+}
+
+func (md *multipartDecoder) Done() bool { return md.state == multipartState_Done }
+
+func (md *multipartDecoder) decode(data []byte) (err protocol.Error) {
+	md.size += len(data)
+	if md.size > md.maxSize {
+		return &ErrMultipartTooLarge
+	}
+	if len(md.buf) > 0 {
+		md.buf = append(md.buf, data...)
+		data = md.buf
+	}
+
+	var consumed int
+	for md.state != multipartState_Done {
+		consumed, err = md.step(data)
+		if err != nil {
+			return
+		}
+		if consumed == 0 {
+			break
+		}
+		data = data[consumed:]
+	}
+	// Keep not consumed data for next call. Epilogue after close delimiter ignore.
+	md.buf = append(md.buf[:0], data...)
+	return
+}
+
+// step consume a section of data and return 0 if more data is needed.
+func (md *multipartDecoder) step(data []byte) (consumed int, err protocol.Error) {
+	switch md.state {
+	case multipartState_Preamble:
+		// First boundary may not have leading CRLF.
+		var dashBoundary = md.delimiter[2:]
+		if !md.discarded && bytes.HasPrefix(data, dashBoundary) {
+			md.state = multipartState_Delimiter
+			return len(dashBoundary), nil
+		}
+		var index = bytes.Index(data, md.delimiter)
+		if index == -1 {
+			// Discard preamble but keep a possible partial delimiter.
+			if len(data) > len(md.delimiter) {
+				md.discarded = true
+				return len(data) - len(md.delimiter), nil
+			}
+			return 0, nil
+		}
+		md.state = multipartState_Delimiter
+		return index + len(md.delimiter), nil
+
+	case multipartState_Delimiter:
+		// Transport padding may add after boundary.
+		var padding = len(data) - len(bytes.TrimLeft(data, " \t"))
+		var rest = data[padding:]
+		if len(rest) < 2 {
+			return 0, nil
+		}
+		if rest[0] == '-' && rest[1] == '-' {
+			md.state = multipartState_Done
+			return padding + 2, nil
+		}
+		if rest[0] != '\r' || rest[1] != '\n' {
+			return 0, &ErrMultipartMalformed
+		}
+		if len(md.form.parts) >= MaxMultipartParts {
+			return 0, &ErrMultipartTooManyParts
+		}
+		md.state = multipartState_Header
+		return padding + 2, nil
+
+	case multipartState_Header:
+		var headerEnd int
+		if bytes.HasPrefix(data, headerSectionEnd[:2]) {
+			headerEnd = 0
+		} else {
+			headerEnd = bytes.Index(data, headerSectionEnd)
+			if headerEnd == -1 {
+				if len(data) > MaxHTTPHeaderSize {
+					return 0, &ErrMultipartMalformed
+				}
+				return 0, nil
+			}
+			headerEnd += 2 // keep CRLF of last field line
+		}
+		err = md.newPart(data[:headerEnd])
+		if err != nil {
+			return
+		}
+		md.state = multipartState_Data
+		return headerEnd + 2, nil
+
+	case multipartState_Data:
+		var index = bytes.Index(data, md.delimiter)
+		if index == -1 {
+			// Keep a possible partial delimiter at end of data for next call.
+			var safe = len(data) - len(md.delimiter) + 1
+			if safe <= 0 {
+				return 0, nil
+			}
+			err = md.write(data[:safe])
+			return safe, err
+		}
+		err = md.write(data[:index])
+		if err != nil {
+			return
+		}
+		err = md.endPart()
+		md.state = multipartState_Delimiter
+		return index + len(md.delimiter), err
+	}
+	return 0, nil
+}
+
+var headerSectionEnd = []byte("\r\n\r\n")
+
+func (md *multipartDecoder) newPart(fields []byte) (err protocol.Error) {
+	var part = new(FormPart)
+	part.H.Init()
+	for len(fields) > 0 {
+		var line []byte
+		line, fields, _ = bytes.Cut(fields, headerSectionEnd[:2])
+		var key, value, found = bytes.Cut(line, []byte{':'})
+		if !found || len(key) == 0 {
+			return &ErrMultipartMalformed
+		}
+		part.H.Add(CanonicalHeaderKey(string(key)), strings.TrimSpace(string(value)))
+	}
+
+	var disposition = part.H.Get(HeaderKeyContentDisposition)
+	if !strings.HasPrefix(strings.ToLower(disposition), "form-data") {
+		return &ErrMultipartMalformed
+	}
+	part.name = mediaTypeParameter(disposition, "name")
+	if strings.Contains(strings.ToLower(disposition), "filename=") {
+		part.isFile = true
+		part.fileName = mediaTypeParameter(disposition, "filename")
+	}
+	md.form.parts = append(md.form.parts, part)
+	md.part = part
+	return
+}
+
+// write add data to the current part and spill it to storage if it is a file part and is larger than max memory.
+func (md *multipartDecoder) write(data []byte) (err protocol.Error) {
+	if len(data) == 0 {
+		return
+	}
+	var part = md.part
+	part.size += len(data)
+	if part.file != nil {
+		part.file.Data().Append(data)
+		return
+	}
+	if part.size > md.maxMemory {
+		var dir = md.form.SpillDirectory
+		if !part.isFile || dir == nil {
+			return &ErrMultipartTooLarge
+		}
+		part.file, err = dir.File("multipart-" + newBoundary())
+		if err != nil {
+			return
+		}
+		part.file.Data().Append(part.data)
+		part.file.Data().Append(data)
+		part.data = nil
+		return
+	}
+	part.data = append(part.data, data...)
+	return
+}
+
+func (md *multipartDecoder) endPart() (err protocol.Error) {
+	if md.part.file != nil {
+		err = md.part.file.Data().Save()
+	}
+	md.part = nil
+	return
+}
+
+// mediaTypeParameter return value of given parameter name in a header value like Content-Type or Content-Disposition.
+// Quoted-string value return unquoted.
+func mediaTypeParameter(value, name string) string {
+	var _, params, _ = strings.Cut(value, ";")
+	for len(params) > 0 {
+		params = strings.TrimLeft(params, " \t;")
+		var key string
+		var eqIndex = strings.IndexByte(params, '=')
+		if eqIndex == -1 {
+			return ""
+		}
+		key, params = strings.TrimSpace(params[:eqIndex]), strings.TrimLeft(params[eqIndex+1:], " \t")
+
+		var paramValue string
+		if strings.HasPrefix(params, `"`) {
+			var buf strings.Builder
+			var i = 1
+			for ; i < len(params) && params[i] != '"'; i++ {
+				if params[i] == '\\' && i+1 < len(params) {
+					i++
+				}
+				buf.WriteByte(params[i])
+			}
+			paramValue = buf.String()
+			if i < len(params) {
+				i++ // closing quote
+			}
+			params = params[i:]
+		} else {
+			var end = strings.IndexByte(params, ';')
+			if end == -1 {
+				end = len(params)
+			}
+			paramValue = strings.TrimSpace(params[:end])
+			params = params[end:]
+		}
+		if strings.EqualFold(key, name) {
+			return paramValue
+		}
+	}
+	return ""
+}
+
+func formDataDisposition(name string) string {
+	return `form-data; name="` + quoteEscape(name) + `"`
+}
+
+// quoteEscape escape characters that can't be in a quoted-string as browsers do.
+// https://html.spec.whatwg.org/multipage/form-control-infrastructure.html#multipart-form-data
+func quoteEscape(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+var quoteEscaper = strings.NewReplacer("\r", "%0D", "\n", "%0A", `"`, "%22")
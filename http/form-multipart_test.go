@@ -0,0 +1,149 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"bytes"
+	"testing"
+)
+
+const testMultipartBody = "preamble to ignore\r\n" +
+	"--XyZ \t\r\n" +
+	"Content-Disposition: form-data; name=\"title\"\r\n" +
+	"\r\n" +
+	"Hello\r\nWorld\r\n" +
+	"--XyZ\r\n" +
+	"content-disposition: form-data; name=\"file\"; filename=\"a \\\"b\\\".txt\"\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"--XyZ is not a delimiter without CRLF\r\n" +
+	"--XyZ--\r\n" +
+	"epilogue to ignore"
+
+func TestMultipartDecoder(t *testing.T) {
+	// Feed data byte by byte to test boundary detection over any split.
+	var form MultipartForm
+	form.Init("XyZ")
+	var md multipartDecoder
+	md.init(&form)
+	for i := 0; i < len(testMultipartBody); i++ {
+		var err = md.decode([]byte{testMultipartBody[i]})
+		if err != nil {
+			t.Fatalf("multipartDecoder.decode() at %d error = %v", i, err)
+		}
+	}
+	if !md.Done() {
+		t.Fatalf("multipartDecoder not done")
+	}
+
+	if len(form.Parts()) != 2 {
+		t.Fatalf("MultipartForm.Parts() len = %d, want 2", len(form.Parts()))
+	}
+	if form.Value("title") != "Hello\r\nWorld" {
+		t.Errorf("MultipartForm.Value(title) = %q", form.Value("title"))
+	}
+	var files = form.Files("file")
+	if len(files) != 1 {
+		t.Fatalf("MultipartForm.Files(file) len = %d, want 1", len(files))
+	}
+	var data, _ = files[0].Data()
+	if files[0].FileName() != `a "b".txt` || files[0].ContentType() != "text/plain" ||
+		string(data) != "--XyZ is not a delimiter without CRLF" || files[0].Size() != len(data) {
+		t.Errorf("file part = %q, %q, %q", files[0].FileName(), files[0].ContentType(), data)
+	}
+}
+
+func TestMultipartFormRoundTrip(t *testing.T) {
+	var form MultipartForm
+	form.Init("")
+	form.AddField("title", "Hello")
+	form.AddFile("file", "a.bin", "", bytes.Repeat([]byte{0, '\r', '\n', '-'}, 100))
+
+	var encoded, err = form.Marshal()
+	if err != nil {
+		t.Fatalf("MultipartForm.Marshal() error = %v", err)
+	}
+
+	var decoded MultipartForm
+	decoded.Init(mediaTypeParameter(form.ContentType(), "boundary"))
+	_, err = decoded.Unmarshal(encoded)
+	if err != nil {
+		t.Fatalf("MultipartForm.Unmarshal() error = %v", err)
+	}
+	var file = decoded.Part("file")
+	if decoded.Value("title") != "Hello" || file == nil || !bytes.Equal(file.data, form.parts[1].data) ||
+		file.ContentType() != "application/octet-stream" {
+		t.Errorf("round trip parts = %+v", decoded.Parts())
+	}
+
+	// File part larger than max memory can't spill without SpillDirectory.
+	decoded.Reinit()
+	decoded.MaxMemory = 100
+	_, err = decoded.Unmarshal(encoded)
+	if err != &ErrMultipartTooLarge {
+		t.Errorf("MultipartForm.Unmarshal() error = %v, want ErrMultipartTooLarge", err)
+	}
+
+	// Body without close delimiter.
+	decoded.Reinit()
+	decoded.MaxMemory = 0
+	_, err = decoded.Unmarshal(encoded[:len(encoded)-4])
+	if err != &ErrMultipartMalformed {
+		t.Errorf("MultipartForm.Unmarshal() error = %v, want ErrMultipartMalformed", err)
+	}
+}
+
+func TestMediaTypeParameter(t *testing.T) {
+	var tests = []struct {
+		value string
+		name  string
+		want  string
+	}{
+		{`multipart/form-data; boundary=abc`, "boundary", "abc"},
+		{`multipart/form-data;boundary="a;b c"; charset=utf-8`, "boundary", "a;b c"},
+		{`form-data; name="f"; filename="x\"y.txt"`, "filename", `x"y.txt`},
+		{`form-data; name="f"`, "filename", ""},
+	}
+	for _, tt := range tests {
+		if got := mediaTypeParameter(tt.value, tt.name); got != tt.want {
+			t.Errorf("mediaTypeParameter(%q, %q) = %q, want %q", tt.value, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRequestMultipartForm(t *testing.T) {
+	var largeBody = "--XyZ\r\n" +
+		"Content-Disposition: form-data; name=\"title\"\r\n" +
+		"\r\n" +
+		"Hello\r\nWorld\r\n" +
+		"--XyZ\r\n" +
+		"Content-Disposition: form-data; name=\"file\"; filename=\"a.txt\"\r\n" +
+		"\r\n" +
+		string(testBodyData) + "\r\n" +
+		"--XyZ--\r\n"
+
+	for _, data := range []string{testMultipartBody, largeBody} {
+		for name, b := range testIncomeBodies(t, []byte(data)) {
+			var r Request
+			r.Init()
+			r.H.Set(HeaderKeyContentType, MediaTypeMultipartForm.MediaType()+"; boundary=XyZ")
+			r.body = *b
+
+			var form, err = r.MultipartForm(0, nil)
+			if err != nil {
+				t.Errorf("%s: Request.MultipartForm() len %d error = %v", name, len(data), err)
+				continue
+			}
+			var files = form.Files("file")
+			if form.Value("title") != "Hello\r\nWorld" || len(files) != 1 {
+				t.Errorf("%s: Request.MultipartForm() len %d parts = %v", name, len(data), form.Parts())
+				continue
+			}
+			if data == largeBody {
+				if fileData, _ := files[0].Data(); !bytes.Equal(fileData, testBodyData) {
+					t.Errorf("%s: Request.MultipartForm() file len = %d, want %d", name, len(fileData), len(testBodyData))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,342 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/convert"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+URLEncodedForm is application/x-www-form-urlencoded codec that hold fields in receive order.
+It can decode request body or URI query that use the same format.
+https://url.spec.whatwg.org/#application/x-www-form-urlencoded
+
+	name1=value1&name2=value+2&name3=%D8%B3%D9%84%D8%A7%D9%85
+*/
+type URLEncodedForm struct {
+	fields []formField
+}
+
+type formField struct {
+	name  string
+	value string
+}
+
+func (f *URLEncodedForm) Init()   {}
+func (f *URLEncodedForm) Reinit() { f.fields = f.fields[:0] }
+func (f *URLEncodedForm) Deinit() {}
+
+// Get return first value of the field or empty string if the field not exist.
+func (f *URLEncodedForm) Get(name string) string {
+	for _, field := range f.fields {
+		if field.name == name {
+			return field.value
+		}
+	}
+	return ""
+}
+
+// Gets return all values of the field e.g. from a multiple select element.
+func (f *URLEncodedForm) Gets(name string) (values []string) {
+	for _, field := range f.fields {
+		if field.name == name {
+			values = append(values, field.value)
+		}
+	}
+	return
+}
+
+func (f *URLEncodedForm) Exist(name string) bool {
+	for _, field := range f.fields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *URLEncodedForm) Add(name, value string) {
+	f.fields = append(f.fields, formField{name: name, value: value})
+}
+
+// Set replace all values of the field with given value.
+func (f *URLEncodedForm) Set(name, value string) {
+	f.Del(name)
+	f.Add(name, value)
+}
+
+func (f *URLEncodedForm) Del(name string) {
+	var fields = f.fields[:0]
+	for _, field := range f.fields {
+		if field.name != name {
+			fields = append(fields, field)
+		}
+	}
+	f.fields = fields
+}
+
+// Range call given function for each field in receive order.
+func (f *URLEncodedForm) Range(fn func(name, value string)) {
+	for _, field := range f.fields {
+		fn(field.name, field.value)
+	}
+}
+
+// Int return first value of the field as a base 10 signed integer.
+func (f *URLEncodedForm) Int(name string) (v int64, err protocol.Error) {
+	var value string
+	value, err = f.value(name)
+	if err != nil {
+		return
+	}
+	var goErr error
+	v, goErr = strconv.ParseInt(value, 10, 64)
+	if goErr != nil {
+		err = &ErrFormFieldValue
+	}
+	return
+}
+
+// Uint return first value of the field as a base 10 unsigned integer.
+func (f *URLEncodedForm) Uint(name string) (v uint64, err protocol.Error) {
+	var value string
+	value, err = f.value(name)
+	if err != nil {
+		return
+	}
+	var goErr error
+	v, goErr = strconv.ParseUint(value, 10, 64)
+	if goErr != nil {
+		err = &ErrFormFieldValue
+	}
+	return
+}
+
+// Float return first value of the field as a float number.
+func (f *URLEncodedForm) Float(name string) (v float64, err protocol.Error) {
+	var value string
+	value, err = f.value(name)
+	if err != nil {
+		return
+	}
+	var goErr error
+	v, goErr = strconv.ParseFloat(value, 64)
+	if goErr != nil {
+		err = &ErrFormFieldValue
+	}
+	return
+}
+
+// Bool return first value of the field as a boolean. Browsers send "on" for a checked checkbox without value attribute,
+// and don't send unchecked checkbox at all, so not exist field is false without any error.
+func (f *URLEncodedForm) Bool(name string) (v bool, err protocol.Error) {
+	if !f.Exist(name) {
+		return
+	}
+	var value = f.Get(name)
+	switch value {
+	case "on":
+		v = true
+	case "off":
+	default:
+		var goErr error
+		v, goErr = strconv.ParseBool(value)
+		if goErr != nil {
+			err = &ErrFormFieldValue
+		}
+	}
+	return
+}
+
+func (f *URLEncodedForm) value(name string) (value string, err protocol.Error) {
+	for _, field := range f.fields {
+		if field.name == name {
+			return field.value, nil
+		}
+	}
+	return "", &ErrFormFieldNotExist
+}
+
+// UnmarshalFromString decode given form data e.g. URI query.
+func (f *URLEncodedForm) UnmarshalFromString(data string) (err protocol.Error) {
+	for len(data) > 0 {
+		var pair string
+		var ampIndex = strings.IndexByte(data, '&')
+		if ampIndex == -1 {
+			pair, data = data, ""
+		} else {
+			pair, data = data[:ampIndex], data[ampIndex+1:]
+		}
+		if pair == "" {
+			continue
+		}
+		if len(f.fields) >= MaxFormFields {
+			return &ErrFormTooLarge
+		}
+
+		var name, value, _ = strings.Cut(pair, "=")
+		name, err = PercentDecode(name)
+		if err != nil {
+			return
+		}
+		value, err = PercentDecode(value)
+		if err != nil {
+			return
+		}
+		f.Add(name, value)
+	}
+	return
+}
+
+//libgo:impl protocol.Codec
+func (f *URLEncodedForm) MediaType() protocol.MediaType       { return &MediaTypeURLEncodedForm }
+func (f *URLEncodedForm) CompressType() protocol.CompressType { return nil }
+func (f *URLEncodedForm) Len() (ln int) {
+	for i, field := range f.fields {
+		if i > 0 {
+			ln++ // '&'
+		}
+		ln += percentEncodeLen(field.name) + 1 + percentEncodeLen(field.value)
+	}
+	return
+}
+func (f *URLEncodedForm) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	if source.Len() > MaxFormSize {
+		return 0, &ErrFormTooLarge
+	}
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return f.Unmarshal(data)
+}
+func (f *URLEncodedForm) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	var data, _ = f.Marshal()
+	return destination.Unmarshal(data)
+}
+func (f *URLEncodedForm) Marshal() (data []byte, err protocol.Error) {
+	return f.MarshalTo(make([]byte, 0, f.Len()))
+}
+func (f *URLEncodedForm) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	for i, field := range f.fields {
+		if i > 0 {
+			data = append(data, '&')
+		}
+		data = appendPercentEncoded(data, field.name)
+		data = append(data, '=')
+		data = appendPercentEncoded(data, field.value)
+	}
+	return data, nil
+}
+func (f *URLEncodedForm) Unmarshal(data []byte) (n int, err protocol.Error) {
+	if len(data) > MaxFormSize {
+		return 0, &ErrFormTooLarge
+	}
+	// Copy due to fields must not point to given data that can be a reused buffer.
+	err = f.UnmarshalFromString(string(data))
+	if err == nil {
+		n = len(data)
+	}
+	return
+}
+func (f *URLEncodedForm) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = f.Unmarshal(data)
+	return
+}
+
+// Form decode request body as application/x-www-form-urlencoded.
+// Use URLEncodedForm UnmarshalFromString() with URI Query() to decode a query.
+func (r *Request) Form() (form *URLEncodedForm, err protocol.Error) {
+	if !strings.HasPrefix(r.H.Get(HeaderKeyContentType), MediaTypeURLEncodedForm.MediaType()) {
+		return nil, &ErrUnsupportedMediaType
+	}
+	form = new(URLEncodedForm)
+	_, err = form.Decode(&r.body)
+	return
+}
+
+// PercentDecode decode percent-encoded octets and '+' as space in given form name or value.
+func PercentDecode(s string) (decoded string, err protocol.Error) {
+	var needDecode bool
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' || s[i] == '+' {
+			needDecode = true
+			break
+		}
+	}
+	if !needDecode {
+		return s, nil
+	}
+
+	var buf = make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '+':
+			buf = append(buf, ' ')
+		case '%':
+			if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+				return "", &ErrFormMalformed
+			}
+			buf = append(buf, unHex(s[i+1])<<4|unHex(s[i+2]))
+			i += 2
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return convert.UnsafeByteSliceToString(buf), nil
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// appendPercentEncoded encode s by application/x-www-form-urlencoded byte serializer.
+// https://url.spec.whatwg.org/#concept-urlencoded-byte-serializer
+func appendPercentEncoded(data []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		var c = s[i]
+		switch {
+		case c == ' ':
+			data = append(data, '+')
+		case formUnreserved(c):
+			data = append(data, c)
+		default:
+			data = append(data, '%', upperHex[c>>4], upperHex[c&0x0F])
+		}
+	}
+	return data
+}
+
+func percentEncodeLen(s string) (ln int) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' || formUnreserved(s[i]) {
+			ln++
+		} else {
+			ln += 3
+		}
+	}
+	return
+}
+
+func formUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '*' || c == '-' || c == '.' || c == '_'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unHex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,59 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"testing"
+)
+
+func TestURLEncodedForm(t *testing.T) {
+	var form URLEncodedForm
+	form.Init()
+	var err = form.UnmarshalFromString("name=Ali+Reza&age=31&&tag=a&tag=b%26c&check=on&ratio=0.5&empty=&%D8%B3=%D9%84")
+	if err != nil {
+		t.Fatalf("URLEncodedForm.UnmarshalFromString() error = %v", err)
+	}
+	if form.Get("name") != "Ali Reza" || form.Get("س") != "ل" || form.Get("empty") != "" || !form.Exist("empty") {
+		t.Errorf("URLEncodedForm.Get() = %q, %q, %q", form.Get("name"), form.Get("س"), form.Get("empty"))
+	}
+	if tags := form.Gets("tag"); len(tags) != 2 || tags[1] != "b&c" {
+		t.Errorf("URLEncodedForm.Gets(tag) = %q", tags)
+	}
+
+	if age, err := form.Int("age"); err != nil || age != 31 {
+		t.Errorf("URLEncodedForm.Int(age) = %d, %v", age, err)
+	}
+	if ratio, err := form.Float("ratio"); err != nil || ratio != 0.5 {
+		t.Errorf("URLEncodedForm.Float(ratio) = %f, %v", ratio, err)
+	}
+	if check, err := form.Bool("check"); err != nil || !check {
+		t.Errorf("URLEncodedForm.Bool(check) = %v, %v", check, err)
+	}
+	if check, err := form.Bool("not-sent"); err != nil || check {
+		t.Errorf("URLEncodedForm.Bool(not-sent) = %v, %v", check, err)
+	}
+	if _, err := form.Uint("name"); err != &ErrFormFieldValue {
+		t.Errorf("URLEncodedForm.Uint(name) error = %v, want ErrFormFieldValue", err)
+	}
+	if _, err := form.Int("not-sent"); err != &ErrFormFieldNotExist {
+		t.Errorf("URLEncodedForm.Int(not-sent) error = %v, want ErrFormFieldNotExist", err)
+	}
+
+	var encoded, _ = form.Marshal()
+	if len(encoded) != form.Len() {
+		t.Errorf("URLEncodedForm.Len() = %d, want %d", form.Len(), len(encoded))
+	}
+	var decoded URLEncodedForm
+	decoded.Init()
+	_, err = decoded.Unmarshal(encoded)
+	if err != nil || decoded.Get("tag") != "a" || decoded.Gets("tag")[1] != "b&c" || decoded.Get("name") != "Ali Reza" {
+		t.Errorf("round trip of %q = %v, %v", encoded, decoded.fields, err)
+	}
+
+	for _, bad := range []string{"a=%", "a=%2", "a=%zz"} {
+		var form URLEncodedForm
+		if err := form.UnmarshalFromString(bad); err != &ErrFormMalformed {
+			t.Errorf("URLEncodedForm.UnmarshalFromString(%q) error = %v, want ErrFormMalformed", bad, err)
+		}
+	}
+}
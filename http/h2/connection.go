@@ -0,0 +1,762 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"sync"
+
+	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Server is the interface that serve HTTP requests that receive on HTTP/2 streams.
+// It is the same method that HTTP/1 handlers has, so services' ServeHTTP works unchanged.
+type Server interface {
+	ServeHTTP(st protocol.Stream, httpReq *http.Request, httpRes *http.Response) (err protocol.Error)
+}
+
+// Connection is an HTTP/2 connection over a reliable transport stream e.g. TCP, TLS, ...
+// https://www.rfc-editor.org/rfc/rfc9113#section-3
+type Connection struct {
+	transport protocol.Stream
+	server    Server // nil in client side
+	isClient  bool
+
+	local settings // our settings that send to peer
+	peer  settings // guard by flowMutex
+
+	writeMutex sync.Mutex
+	encoder    hpackEncoder // guard by writeMutex to encode field blocks in order of send
+	decoder    hpackDecoder // use just by read loop
+
+	streamsMutex     sync.Mutex
+	streams          map[uint32]*Stream
+	lastPeerStreamID uint32
+	nextStreamID     uint32
+	goAway           bool
+	closed           bool
+
+	// Flow control. https://www.rfc-editor.org/rfc/rfc9113#section-5.2
+	flowMutex  sync.Mutex
+	flowCond   sync.Cond
+	sendWindow int64 // guard by flowMutex
+	recvWindow int64 // use just by read loop
+
+	// Field block that not completed yet and wait for CONTINUATION frames.
+	continuationStreamID  uint32
+	continuationEndStream bool
+	fieldBlock            []byte
+}
+
+// Init initialize the connection over given transport stream.
+// Give nil server to use the connection as a client.
+func (c *Connection) Init(transport protocol.Stream, server Server) {
+	c.transport = transport
+	c.server = server
+	c.isClient = server == nil
+	c.local.initLocal()
+	c.peer.init()
+	c.encoder.init()
+	c.decoder.init(c.local.HeaderTableSize, c.local.MaxHeaderListSize)
+	c.streams = make(map[uint32]*Stream, 16)
+	c.nextStreamID = 1
+	c.flowCond.L = &c.flowMutex
+	c.sendWindow = DefaultInitialWindowSize
+	c.recvWindow = DefaultInitialWindowSize
+}
+
+// Serve read client preface and serve income streams until connection closed.
+// It block the caller, so call it in the transport stream handler goroutine.
+func (c *Connection) Serve() (err protocol.Error) {
+	var buf []byte
+	buf, err = c.readPreface()
+	if err != nil {
+		c.close(err)
+		return
+	}
+	err = c.writeFrames(appendSettingsFrame(nil, &c.local))
+	if err != nil {
+		c.close(err)
+		return
+	}
+	return c.readLoop(buf)
+}
+
+// Start send client preface and our SETTINGS, and read frames in a new goroutine.
+func (c *Connection) Start() (err protocol.Error) {
+	var preface = append(make([]byte, 0, 128), ClientPreface...)
+	preface = appendSettingsFrame(preface, &c.local)
+	err = c.writeFrames(preface)
+	if err != nil {
+		return
+	}
+	go c.readLoop(nil)
+	return
+}
+
+// SendRequest open a new stream and send given request on it.
+// It block caller until get response or error.
+func (c *Connection) SendRequest(httpReq *http.Request) (httpRes *http.Response, err protocol.Error) {
+	var st Stream
+	st.init(c, 0, false)
+	err = st.writeRequest(httpReq)
+	if err != nil {
+		st.Close()
+		return
+	}
+
+	<-st.done
+	c.flowMutex.Lock()
+	err = st.resetErr
+	c.flowMutex.Unlock()
+	if err != nil {
+		return
+	}
+	httpRes = &st.res
+	err = httpRes.SetIncomeBody(st.body)
+	if err == nil {
+		err = httpRes.GetError()
+	}
+	st.Close()
+	return
+}
+
+// Close send GOAWAY frame to let peer know no more stream accepted, and then close the connection.
+func (c *Connection) Close() (err protocol.Error) {
+	c.streamsMutex.Lock()
+	var lastPeerStreamID = c.lastPeerStreamID
+	c.goAway = true
+	c.streamsMutex.Unlock()
+	err = c.writeFrames(appendGoAwayFrame(nil, lastPeerStreamID, ErrorCodeNoError, ""))
+	c.close(&ErrGoAway)
+	return
+}
+
+func (c *Connection) readPreface() (remaining []byte, err protocol.Error) {
+	var buf = make([]byte, 0, 4096)
+	for len(buf) < len(ClientPreface) {
+		buf, err = c.transport.MarshalTo(buf)
+		if err != nil {
+			return
+		}
+	}
+	if string(buf[:len(ClientPreface)]) != ClientPreface {
+		return nil, &ErrPreface
+	}
+	return buf[len(ClientPreface):], nil
+}
+
+// readLoop read and handle frames until connection closed.
+// Frames refer to the read buffer, so handlers must copy any data that need after they return.
+func (c *Connection) readLoop(buf []byte) (err protocol.Error) {
+	var base = buf
+	var f frame
+	for {
+		for {
+			f, buf, err = cutFrame(buf, c.local.MaxFrameSize)
+			if err != nil {
+				c.connectionError(err)
+				return
+			}
+			if f == nil {
+				break
+			}
+			err = c.handleFrame(f)
+			if err != nil {
+				c.connectionError(err)
+				return
+			}
+		}
+
+		// Move not completed frame to the start of buffer to reuse its capacity.
+		buf = append(base[:0], buf...)
+		buf, err = c.transport.MarshalTo(buf)
+		if err != nil {
+			c.close(err)
+			return
+		}
+		base = buf
+	}
+}
+
+func (c *Connection) handleFrame(f frame) (err protocol.Error) {
+	var streamID = f.StreamID()
+	if c.continuationStreamID != 0 && (f.Type() != frameTypeContinuation || streamID != c.continuationStreamID) {
+		return &ErrProtocol
+	}
+
+	switch f.Type() {
+	case frameTypeData:
+		err = c.handleData(f)
+	case frameTypeHeaders:
+		err = c.handleHeaders(f)
+	case frameTypeContinuation:
+		err = c.handleContinuation(f)
+	case frameTypePriority:
+		if streamID == 0 {
+			return &ErrProtocol
+		}
+		if f.Length() != priorityFrameLength {
+			c.resetStream(streamID, ErrorCodeFrameSizeError)
+		}
+	case frameTypeRSTStream:
+		err = c.handleRSTStream(f)
+	case frameTypeSettings:
+		err = c.handleSettings(f)
+	case frameTypePushPromise:
+		// We never enable push, so peer must not send it.
+		err = &ErrProtocol
+	case frameTypePing:
+		if streamID != 0 {
+			return &ErrProtocol
+		}
+		if f.Length() != pingFrameLength {
+			return &ErrFrameSize
+		}
+		if !f.Has(flagAck) {
+			err = c.writeFrames(appendPingFrame(nil, true, f.Payload()))
+		}
+	case frameTypeGoAway:
+		err = c.handleGoAway(f)
+	case frameTypeWindowUpdate:
+		err = c.handleWindowUpdate(f)
+	default:
+		// Implementations MUST ignore and discard frames of unknown types.
+	}
+	return
+}
+
+func (c *Connection) handleData(f frame) (err protocol.Error) {
+	var streamID = f.StreamID()
+	if streamID == 0 {
+		return &ErrProtocol
+	}
+
+	// Entire DATA frame payload including padding is subject to flow control.
+	var flowLen = int64(f.Length())
+	if flowLen > c.recvWindow {
+		return &ErrFlowControl
+	}
+	c.recvWindow -= flowLen
+
+	var data []byte
+	data, err = removePadding(f)
+	if err != nil {
+		return
+	}
+
+	var updates []byte
+	if flowLen > 0 {
+		// Body buffered until stream end, so release connection window immediately.
+		updates = appendWindowUpdateFrame(make([]byte, 0, 2*(frameHeaderLength+windowUpdateFrameLength)), 0, uint32(flowLen))
+		c.recvWindow += flowLen
+	}
+
+	var st = c.stream(streamID)
+	switch {
+	case st == nil:
+		if c.isIdleStream(streamID) {
+			return &ErrProtocol
+		}
+		updates = appendRSTStreamFrame(updates, streamID, ErrorCodeStreamClosed)
+	case !st.headersDone || st.isRemoteClosed():
+		updates = appendRSTStreamFrame(updates, streamID, ErrorCodeStreamClosed)
+		defer st.reset(&ErrStreamClosed)
+	case flowLen > st.recvWindow:
+		updates = appendRSTStreamFrame(updates, streamID, ErrorCodeFlowControlError)
+		defer st.reset(&ErrFlowControl)
+	case len(st.body)+len(data) > MaxBodySize:
+		updates = appendRSTStreamFrame(updates, streamID, ErrorCodeEnhanceYourCalm)
+		defer st.reset(&ErrRefusedStream)
+	default:
+		st.body = append(st.body, data...)
+		st.recvWindow -= flowLen
+		if flowLen > 0 && !f.Has(flagEndStream) {
+			updates = appendWindowUpdateFrame(updates, streamID, uint32(flowLen))
+			st.recvWindow += flowLen
+		}
+		if f.Has(flagEndStream) {
+			defer st.remoteEnd()
+		}
+	}
+
+	if len(updates) > 0 {
+		err = c.writeFrames(updates)
+	}
+	return
+}
+
+func (c *Connection) handleHeaders(f frame) (err protocol.Error) {
+	var streamID = f.StreamID()
+	if streamID == 0 {
+		return &ErrProtocol
+	}
+	var fragment []byte
+	fragment, err = headersFragment(f)
+	if err != nil {
+		return
+	}
+	if f.Has(flagEndHeaders) {
+		return c.handleFieldBlock(streamID, f.Has(flagEndStream), fragment)
+	}
+	c.continuationStreamID = streamID
+	c.continuationEndStream = f.Has(flagEndStream)
+	c.fieldBlock = append(c.fieldBlock[:0], fragment...)
+	return
+}
+
+func (c *Connection) handleContinuation(f frame) (err protocol.Error) {
+	if c.continuationStreamID == 0 {
+		return &ErrProtocol
+	}
+	c.fieldBlock = append(c.fieldBlock, f.Payload()...)
+	// Don't let peer use CONTINUATION frames to exhaust our memory.
+	if len(c.fieldBlock) > int(c.local.MaxHeaderListSize) {
+		return &ErrHeaderListTooLarge
+	}
+	if !f.Has(flagEndHeaders) {
+		return
+	}
+	var streamID = c.continuationStreamID
+	c.continuationStreamID = 0
+	return c.handleFieldBlock(streamID, c.continuationEndStream, c.fieldBlock)
+}
+
+// handleFieldBlock decode a complete field block. Field block must decode even for streams that
+// will be refused or reset to keep HPACK dynamic table in sync with peer encoder.
+func (c *Connection) handleFieldBlock(streamID uint32, endStream bool, block []byte) (err protocol.Error) {
+	var st = c.stream(streamID)
+	if st == nil {
+		var refuse ErrorCode
+		st, refuse, err = c.newPeerStream(streamID)
+		if err != nil {
+			return
+		}
+		if refuse != ErrorCodeNoError {
+			err = c.decoder.decodeFieldBlock(block, func(hf headerField) {})
+			if err != nil && err != &ErrHeaderListTooLarge {
+				return
+			}
+			return c.writeFrames(appendRSTStreamFrame(nil, streamID, refuse))
+		}
+	} else if st.isRemoteClosed() {
+		return &ErrStreamClosed
+	}
+
+	err = c.decoder.decodeFieldBlock(block, st.addField)
+	if err == &ErrHeaderListTooLarge {
+		st.reset(err)
+		return c.writeFrames(appendRSTStreamFrame(nil, streamID, ErrorCodeEnhanceYourCalm))
+	} else if err != nil {
+		return
+	}
+
+	if st.fieldBlockEnd(endStream) {
+		st.reset(&ErrProtocol)
+		return c.writeFrames(appendRSTStreamFrame(nil, streamID, ErrorCodeProtocolError))
+	}
+	if endStream {
+		st.remoteEnd()
+	}
+	return
+}
+
+// newPeerStream register a new stream that peer open it by HEADERS frame.
+// If the stream must refuse, nil stream with the RST_STREAM error code return.
+func (c *Connection) newPeerStream(streamID uint32) (st *Stream, refuse ErrorCode, err protocol.Error) {
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+
+	if c.isClient {
+		// Push is disabled, so server must not open any stream. Closed stream is not exist in streams anymore.
+		if streamID%2 == 0 || streamID >= c.nextStreamID {
+			return nil, 0, &ErrProtocol
+		}
+		return nil, ErrorCodeStreamClosed, nil
+	}
+	if streamID%2 == 0 {
+		return nil, 0, &ErrProtocol
+	}
+	if streamID <= c.lastPeerStreamID {
+		return nil, 0, &ErrStreamClosed
+	}
+	c.lastPeerStreamID = streamID
+	if c.goAway || c.closed {
+		return nil, ErrorCodeRefusedStream, nil
+	}
+	if uint32(len(c.streams)) >= c.local.MaxConcurrentStreams {
+		return nil, ErrorCodeRefusedStream, nil
+	}
+
+	st = new(Stream)
+	st.init(c, streamID, true)
+	// peer settings just change in read loop that call this method, so it is safe to read it without flowMutex.
+	st.sendWindow = int64(c.peer.InitialWindowSize)
+	c.streams[streamID] = st
+	return
+}
+
+func (c *Connection) handleRSTStream(f frame) (err protocol.Error) {
+	var streamID = f.StreamID()
+	if streamID == 0 {
+		return &ErrProtocol
+	}
+	if f.Length() != rstStreamFrameLength {
+		return &ErrFrameSize
+	}
+	var st = c.stream(streamID)
+	if st == nil {
+		if c.isIdleStream(streamID) {
+			return &ErrProtocol
+		}
+		return
+	}
+	if rstStreamFrame(f.Payload()).ErrorCode() == ErrorCodeRefusedStream {
+		st.reset(&ErrRefusedStream)
+	} else {
+		st.reset(&ErrStreamReset)
+	}
+	return
+}
+
+func (c *Connection) handleSettings(f frame) (err protocol.Error) {
+	if f.StreamID() != 0 {
+		return &ErrProtocol
+	}
+	if f.Has(flagAck) {
+		if f.Length() != 0 {
+			return &ErrFrameSize
+		}
+		c.decoder.setMaxSizeLimit(c.local.HeaderTableSize)
+		return
+	}
+
+	c.flowMutex.Lock()
+	var oldInitialWindowSize = c.peer.InitialWindowSize
+	err = c.peer.unmarshal(f.Payload())
+	if err == nil && c.peer.InitialWindowSize != oldInitialWindowSize {
+		// https://www.rfc-editor.org/rfc/rfc9113#section-6.9.2
+		var delta = int64(c.peer.InitialWindowSize) - int64(oldInitialWindowSize)
+		c.streamsMutex.Lock()
+		for _, st := range c.streams {
+			st.sendWindow += delta
+			if st.sendWindow > maxWindowSize {
+				err = &ErrFlowControl
+			}
+		}
+		c.streamsMutex.Unlock()
+		c.flowCond.Broadcast()
+	}
+	var headerTableSize = c.peer.HeaderTableSize
+	c.flowMutex.Unlock()
+	if err != nil {
+		return
+	}
+
+	// Don't let peer force us to use more memory than default for its decoder.
+	if headerTableSize > DefaultHeaderTableSize {
+		headerTableSize = DefaultHeaderTableSize
+	}
+	c.writeMutex.Lock()
+	if headerTableSize != c.encoder.maxSizeLimit {
+		c.encoder.setMaxSizeLimit(headerTableSize)
+	}
+	_, err = c.transport.Unmarshal(appendSettingsAckFrame(nil))
+	c.writeMutex.Unlock()
+	return
+}
+
+func (c *Connection) handleGoAway(f frame) (err protocol.Error) {
+	if f.StreamID() != 0 {
+		return &ErrProtocol
+	}
+	if f.Length() < goAwayFrameFixedLength {
+		return &ErrFrameSize
+	}
+	var lastStreamID = goAwayFrame(f.Payload()).LastStreamID()
+
+	c.streamsMutex.Lock()
+	c.goAway = true
+	var refused = make([]*Stream, 0, len(c.streams))
+	for id, st := range c.streams {
+		// Streams that we initiate after last stream ID not processed by peer and safe to retry.
+		if !st.peerInitiated && id > lastStreamID {
+			refused = append(refused, st)
+		}
+	}
+	c.streamsMutex.Unlock()
+
+	for _, st := range refused {
+		st.reset(&ErrGoAway)
+	}
+	return
+}
+
+func (c *Connection) handleWindowUpdate(f frame) (err protocol.Error) {
+	if f.Length() != windowUpdateFrameLength {
+		return &ErrFrameSize
+	}
+	var streamID = f.StreamID()
+	var increment = int64(windowUpdateFrame(f.Payload()).Increment())
+
+	if streamID == 0 {
+		if increment == 0 {
+			return &ErrProtocol
+		}
+		c.flowMutex.Lock()
+		c.sendWindow += increment
+		if c.sendWindow > maxWindowSize {
+			err = &ErrFlowControl
+		}
+		c.flowCond.Broadcast()
+		c.flowMutex.Unlock()
+		return
+	}
+
+	var st = c.stream(streamID)
+	if st == nil {
+		if c.isIdleStream(streamID) {
+			return &ErrProtocol
+		}
+		return
+	}
+	if increment == 0 {
+		st.reset(&ErrProtocol)
+		return c.writeFrames(appendRSTStreamFrame(nil, streamID, ErrorCodeProtocolError))
+	}
+	c.flowMutex.Lock()
+	st.sendWindow += increment
+	var overflow = st.sendWindow > maxWindowSize
+	c.flowCond.Broadcast()
+	c.flowMutex.Unlock()
+	if overflow {
+		st.reset(&ErrFlowControl)
+		return c.writeFrames(appendRSTStreamFrame(nil, streamID, ErrorCodeFlowControlError))
+	}
+	return
+}
+
+/*
+********** Write methods **********
+ */
+
+func (c *Connection) writeFrames(frames []byte) (err protocol.Error) {
+	c.writeMutex.Lock()
+	_, err = c.transport.Unmarshal(frames)
+	c.writeMutex.Unlock()
+	return
+}
+
+// writeHeaders encode and send given fields as a field block.
+// New outcome streams get their ID here, because stream IDs must send in increasing order.
+func (c *Connection) writeHeaders(st *Stream, fields []headerField, endStream bool) (err protocol.Error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	if st.id == 0 {
+		err = c.registerStream(st)
+		if err != nil {
+			return
+		}
+	}
+
+	c.flowMutex.Lock()
+	var maxFrameSize = int(c.peer.MaxFrameSize)
+	c.flowMutex.Unlock()
+
+	var block = c.encoder.appendFieldBlock(make([]byte, 0, 512), fields)
+	var frames = appendHeadersFrames(make([]byte, 0, len(block)+2*frameHeaderLength), st.id, endStream, block, maxFrameSize)
+	_, err = c.transport.Unmarshal(frames)
+	return
+}
+
+// writeData send given data as DATA frames by respect to connection and stream send windows.
+// It block caller until peer open the windows enough to send all data.
+func (c *Connection) writeData(st *Stream, data []byte, endStream bool) (err protocol.Error) {
+	for {
+		c.flowMutex.Lock()
+		for len(data) > 0 && (c.sendWindow <= 0 || st.sendWindow <= 0) && st.resetErr == nil && !c.closed {
+			c.flowCond.Wait()
+		}
+		if st.resetErr != nil {
+			err = st.resetErr
+		} else if c.closed {
+			err = &ErrGoAway
+		}
+		if err != nil {
+			c.flowMutex.Unlock()
+			return
+		}
+
+		var ln = int64(len(data))
+		if ln > c.sendWindow {
+			ln = c.sendWindow
+		}
+		if ln > st.sendWindow {
+			ln = st.sendWindow
+		}
+		if ln > int64(c.peer.MaxFrameSize) {
+			ln = int64(c.peer.MaxFrameSize)
+		}
+		c.sendWindow -= ln
+		st.sendWindow -= ln
+		c.flowMutex.Unlock()
+
+		var end = endStream && ln == int64(len(data))
+		err = c.writeFrames(appendDataFrame(make([]byte, 0, frameHeaderLength+ln), st.id, end, data[:ln]))
+		data = data[ln:]
+		if err != nil || len(data) == 0 {
+			return
+		}
+	}
+}
+
+/*
+********** Streams methods **********
+ */
+
+func (c *Connection) stream(streamID uint32) (st *Stream) {
+	c.streamsMutex.Lock()
+	st = c.streams[streamID]
+	c.streamsMutex.Unlock()
+	return
+}
+
+// registerStream give new stream ID to given stream that we initiate and register it.
+func (c *Connection) registerStream(st *Stream) (err protocol.Error) {
+	// Lock in same order as handleSettings to not miss any initial window size change.
+	c.flowMutex.Lock()
+	defer c.flowMutex.Unlock()
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+
+	if c.goAway || c.closed {
+		return &ErrGoAway
+	}
+	if c.peer.MaxConcurrentStreams != 0 && uint32(len(c.streams)) >= c.peer.MaxConcurrentStreams {
+		return &ErrRefusedStream
+	}
+	if c.nextStreamID > maxWindowSize {
+		// Stream IDs exhausted, so a new connection must use.
+		return &ErrGoAway
+	}
+
+	st.id = c.nextStreamID
+	st.sendWindow = int64(c.peer.InitialWindowSize)
+	c.nextStreamID += 2
+	c.streams[st.id] = st
+	return
+}
+
+func (c *Connection) removeStream(st *Stream) {
+	c.streamsMutex.Lock()
+	if c.streams[st.id] == st {
+		delete(c.streams, st.id)
+	}
+	c.streamsMutex.Unlock()
+}
+
+// isIdleStream report given stream ID not opened yet by any endpoint.
+func (c *Connection) isIdleStream(streamID uint32) bool {
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+	if streamID%2 == 1 {
+		if c.isClient {
+			return streamID >= c.nextStreamID
+		}
+		return streamID > c.lastPeerStreamID
+	}
+	// Even stream IDs are for server push that we never enable.
+	return true
+}
+
+// resetStream send RST_STREAM for given stream and reset it locally if exist.
+func (c *Connection) resetStream(streamID uint32, code ErrorCode) (err protocol.Error) {
+	var st = c.stream(streamID)
+	if st != nil {
+		st.reset(&ErrStreamReset)
+	}
+	return c.writeFrames(appendRSTStreamFrame(nil, streamID, code))
+}
+
+// serveStream serve a completely received request by the server and send its response if the server not send it.
+func (c *Connection) serveStream(st *Stream) {
+	var err protocol.Error
+	// Request body of h2c upgrade request had been set by HTTP/1.
+	if st.req.Codec == nil {
+		err = st.req.SetIncomeBody(st.body)
+	}
+	if err != nil {
+		st.res.SetStatus(http.StatusBadRequestCode, http.StatusBadRequestPhrase)
+		st.res.SetError(err)
+	} else {
+		c.server.ServeHTTP(st, &st.req, &st.res)
+	}
+
+	c.flowMutex.Lock()
+	var sent = st.localClosed
+	c.flowMutex.Unlock()
+	if !sent {
+		st.writeResponse(&st.res)
+	}
+}
+
+/*
+********** Close methods **********
+ */
+
+// connectionError send GOAWAY with related error code to peer and close the connection.
+// https://www.rfc-editor.org/rfc/rfc9113#section-5.4.1
+func (c *Connection) connectionError(err protocol.Error) {
+	c.streamsMutex.Lock()
+	var lastPeerStreamID = c.lastPeerStreamID
+	c.goAway = true
+	c.streamsMutex.Unlock()
+	c.writeFrames(appendGoAwayFrame(nil, lastPeerStreamID, errorCode(err), ""))
+	c.close(err)
+}
+
+// close reset all remaining streams and close the transport stream.
+// closed guard by both flowMutex and streamsMutex, so writers can check it under any of them.
+func (c *Connection) close(err protocol.Error) {
+	c.flowMutex.Lock()
+	c.streamsMutex.Lock()
+	if c.closed {
+		c.streamsMutex.Unlock()
+		c.flowMutex.Unlock()
+		return
+	}
+	c.closed = true
+	var streams = make([]*Stream, 0, len(c.streams))
+	for _, st := range c.streams {
+		streams = append(streams, st)
+	}
+	c.streamsMutex.Unlock()
+	c.flowCond.Broadcast()
+	c.flowMutex.Unlock()
+
+	for _, st := range streams {
+		st.reset(err)
+	}
+	c.transport.Close()
+}
+
+func errorCode(err protocol.Error) ErrorCode {
+	switch err {
+	case &ErrProtocol, &ErrPreface:
+		return ErrorCodeProtocolError
+	case &ErrFrameSize:
+		return ErrorCodeFrameSizeError
+	case &ErrFlowControl:
+		return ErrorCodeFlowControlError
+	case &ErrCompression:
+		return ErrorCodeCompressionError
+	case &ErrStreamClosed:
+		return ErrorCodeStreamClosed
+	case &ErrRefusedStream:
+		return ErrorCodeRefusedStream
+	case &ErrHeaderListTooLarge:
+		return ErrorCodeEnhanceYourCalm
+	}
+	return ErrorCodeInternalError
+}
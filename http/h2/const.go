@@ -0,0 +1,97 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+				********************PAY ATTENTION:*******************
+	We believe HTTP version 2 and above are new protocol not new version of HTTP.
+	So it implement in this package not the http package, but reuse http.Request && http.Response
+	to let services ServeHTTP() work unchanged.
+	https://www.rfc-editor.org/rfc/rfc9113
+*/
+
+const (
+	// ClientPreface is connection preface that client must send first.
+	ClientPreface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+	// UpgradeToken is token of cleartext HTTP/2 in Upgrade header.
+	UpgradeToken = "h2c"
+	// ALPNToken is token of HTTP/2 over TLS in ALPN extension.
+	ALPNToken = "h2"
+	// HeaderKeyHTTP2Settings is header key that carry client SETTINGS in h2c upgrade request.
+	HeaderKeyHTTP2Settings = "Http2-Settings"
+
+	// Default values of SETTINGS parameters by RFC
+	DefaultHeaderTableSize   = 4096
+	DefaultInitialWindowSize = 65535
+	DefaultMaxFrameSize      = 16384
+
+	// Default values that this package suggest to peer.
+	DefaultMaxConcurrentStreams = 250
+	DefaultMaxHeaderListSize    = 1 << 20
+	// MaxBodySize is max size of a request||response body that buffer before serve it.
+	MaxBodySize = 64 << 20
+
+	maxFrameSizeLimit = 1<<24 - 1
+	maxWindowSize     = 1<<31 - 1
+	frameHeaderLength = 9
+)
+
+// https://www.rfc-editor.org/rfc/rfc9113#section-6
+type frameType uint8
+
+const (
+	frameTypeData         frameType = 0x0
+	frameTypeHeaders      frameType = 0x1
+	frameTypePriority     frameType = 0x2
+	frameTypeRSTStream    frameType = 0x3
+	frameTypeSettings     frameType = 0x4
+	frameTypePushPromise  frameType = 0x5
+	frameTypePing         frameType = 0x6
+	frameTypeGoAway       frameType = 0x7
+	frameTypeWindowUpdate frameType = 0x8
+	frameTypeContinuation frameType = 0x9
+)
+
+type frameFlags uint8
+
+const (
+	flagEndStream  frameFlags = 0x1
+	flagAck        frameFlags = 0x1 // SETTINGS && PING
+	flagEndHeaders frameFlags = 0x4
+	flagPadded     frameFlags = 0x8
+	flagPriority   frameFlags = 0x20
+)
+
+// SettingID indicate a SETTINGS frame parameter.
+// https://www.rfc-editor.org/rfc/rfc9113#section-6.5.2
+type SettingID uint16
+
+const (
+	SettingHeaderTableSize      SettingID = 0x1
+	SettingEnablePush           SettingID = 0x2
+	SettingMaxConcurrentStreams SettingID = 0x3
+	SettingInitialWindowSize    SettingID = 0x4
+	SettingMaxFrameSize         SettingID = 0x5
+	SettingMaxHeaderListSize    SettingID = 0x6
+)
+
+// ErrorCode use in RST_STREAM and GOAWAY frames to tell peer reason of stream||connection error.
+// https://www.rfc-editor.org/rfc/rfc9113#section-7
+type ErrorCode uint32
+
+const (
+	ErrorCodeNoError            ErrorCode = 0x0
+	ErrorCodeProtocolError      ErrorCode = 0x1
+	ErrorCodeInternalError      ErrorCode = 0x2
+	ErrorCodeFlowControlError   ErrorCode = 0x3
+	ErrorCodeSettingsTimeout    ErrorCode = 0x4
+	ErrorCodeStreamClosed       ErrorCode = 0x5
+	ErrorCodeFrameSizeError     ErrorCode = 0x6
+	ErrorCodeRefusedStream      ErrorCode = 0x7
+	ErrorCodeCancel             ErrorCode = 0x8
+	ErrorCodeCompressionError   ErrorCode = 0x9
+	ErrorCodeConnectError       ErrorCode = 0xa
+	ErrorCodeEnhanceYourCalm    ErrorCode = 0xb
+	ErrorCodeInadequateSecurity ErrorCode = 0xc
+	ErrorCodeHTTP11Required     ErrorCode = 0xd
+)
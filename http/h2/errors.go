@@ -0,0 +1,118 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "HTTP/2"
+const domainPersian = "HTTP/2"
+
+// Declare package errors
+var (
+	ErrPreface            er.Error
+	ErrProtocol           er.Error
+	ErrFrameSize          er.Error
+	ErrFlowControl        er.Error
+	ErrCompression        er.Error
+	ErrStreamClosed       er.Error
+	ErrRefusedStream      er.Error
+	ErrStreamReset        er.Error
+	ErrGoAway             er.Error
+	ErrHeaderListTooLarge er.Error
+	ErrUpgradeSettings    er.Error
+)
+
+func init() {
+	ErrPreface.Init("domain/http2.protocol; type=error; name=preface")
+	ErrPreface.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Preface",
+		"Connection preface that received from peer is not valid",
+		"",
+		"",
+		nil)
+
+	ErrProtocol.Init("domain/http2.protocol; type=error; name=protocol")
+	ErrProtocol.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Protocol",
+		"Peer violate HTTP/2 protocol rules",
+		"",
+		"",
+		nil)
+
+	ErrFrameSize.Init("domain/http2.protocol; type=error; name=frame-size")
+	ErrFrameSize.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Frame Size",
+		"Received frame size is larger than allowed or smaller than needed for its type",
+		"",
+		"",
+		nil)
+
+	ErrFlowControl.Init("domain/http2.protocol; type=error; name=flow-control")
+	ErrFlowControl.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Flow Control",
+		"Peer violate flow control window or send a window update that overflow window",
+		"",
+		"",
+		nil)
+
+	ErrCompression.Init("domain/http2.protocol; type=error; name=compression")
+	ErrCompression.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Compression",
+		"Received HPACK header block is not valid and connection compression state can't be trusted anymore",
+		"",
+		"",
+		nil)
+
+	ErrStreamClosed.Init("domain/http2.protocol; type=error; name=stream-closed")
+	ErrStreamClosed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Stream Closed",
+		"Frame received for a stream that had been closed",
+		"",
+		"",
+		nil)
+
+	ErrRefusedStream.Init("domain/http2.protocol; type=error; name=refused-stream")
+	ErrRefusedStream.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Refused Stream",
+		"Stream refused before any processing e.g. due to max concurrent streams limit",
+		"",
+		"",
+		nil)
+	ErrRefusedStream.SetTemporary()
+
+	ErrStreamReset.Init("domain/http2.protocol; type=error; name=stream-reset")
+	ErrStreamReset.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Stream Reset",
+		"Peer reset the stream by RST_STREAM frame",
+		"",
+		"",
+		nil)
+
+	ErrGoAway.Init("domain/http2.protocol; type=error; name=go-away")
+	ErrGoAway.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Go Away",
+		"Connection is going away and can't accept new streams",
+		"",
+		"",
+		nil)
+	ErrGoAway.SetTemporary()
+
+	ErrHeaderListTooLarge.Init("domain/http2.protocol; type=error; name=header-list-too-large")
+	ErrHeaderListTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Header List Too Large",
+		"Size of decoded header list is larger than SETTINGS_MAX_HEADER_LIST_SIZE",
+		"",
+		"",
+		nil)
+
+	ErrUpgradeSettings.Init("domain/http2.protocol; type=error; name=upgrade-settings")
+	ErrUpgradeSettings.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Upgrade Settings",
+		"HTTP2-Settings header in h2c upgrade request is missing or not valid",
+		"",
+		"",
+		nil)
+}
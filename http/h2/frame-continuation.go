@@ -0,0 +1,13 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.10
+type continuationFrame struct {
+	FieldBlockFragment []byte
+}
+A CONTINUATION frame MUST be preceded by a HEADERS, PUSH_PROMISE or CONTINUATION frame without the END_HEADERS flag set
+on the same stream, so no other frame type can interleave a field block.
+Build of CONTINUATION frames is in appendHeadersFrames().
+*/
@@ -0,0 +1,22 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.1
+type dataFrame struct {
+	PadLength uint8 // if PADDED flag set
+	Data      []byte
+	Padding   []byte
+}
+*/
+
+// appendDataFrame append DATA frame without any padding.
+func appendDataFrame(buf []byte, streamID uint32, endStream bool, data []byte) []byte {
+	var flags frameFlags
+	if endStream {
+		flags |= flagEndStream
+	}
+	buf = appendFrameHeader(buf, len(data), frameTypeData, flags, streamID)
+	return append(buf, data...)
+}
@@ -0,0 +1,31 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.8
+type goAwayFrame struct {
+	LastStreamID        [4]byte // 1 bit reserved + uint31
+	ErrorCode           ErrorCode
+	AdditionalDebugData []byte
+}
+*/
+
+type goAwayFrame []byte
+
+func (f goAwayFrame) LastStreamID() uint32 { return binary.BigEndian.Uint32(f) & maxWindowSize }
+func (f goAwayFrame) ErrorCode() ErrorCode { return ErrorCode(binary.BigEndian.Uint32(f[4:])) }
+func (f goAwayFrame) DebugData() []byte    { return f[goAwayFrameFixedLength:] }
+
+const goAwayFrameFixedLength = 8
+
+func appendGoAwayFrame(buf []byte, lastStreamID uint32, code ErrorCode, debugData string) []byte {
+	buf = appendFrameHeader(buf, goAwayFrameFixedLength+len(debugData), frameTypeGoAway, 0, 0)
+	buf = binary.BigEndian.AppendUint32(buf, lastStreamID&maxWindowSize)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(code))
+	return append(buf, debugData...)
+}
@@ -0,0 +1,60 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.2
+type headersFrame struct {
+	PadLength           uint8   // if PADDED flag set
+	Exclusive+StreamDep [4]byte // if PRIORITY flag set
+	Weight              uint8   // if PRIORITY flag set
+	FieldBlockFragment  []byte
+	Padding             []byte
+}
+*/
+
+const headersFramePriorityLength = 5 // Exclusive+StreamDependency + Weight
+
+// headersFragment return field block fragment of given HEADERS frame without padding and priority fields.
+// Priority signals are deprecated by RFC 9113 and just ignored.
+func headersFragment(f frame) (fragment []byte, err protocol.Error) {
+	fragment, err = removePadding(f)
+	if err != nil {
+		return
+	}
+	if f.Has(flagPriority) {
+		if len(fragment) < headersFramePriorityLength {
+			return nil, &ErrFrameSize
+		}
+		fragment = fragment[headersFramePriorityLength:]
+	}
+	return
+}
+
+// appendHeadersFrames append a HEADERS frame and as many CONTINUATION frames as needed
+// to carry given encoded field block by respect to maxFrameSize.
+func appendHeadersFrames(buf []byte, streamID uint32, endStream bool, block []byte, maxFrameSize int) []byte {
+	var ft = frameTypeHeaders
+	for first := true; first || len(block) > 0; first = false {
+		var ln = len(block)
+		if ln > maxFrameSize {
+			ln = maxFrameSize
+		}
+		var flags frameFlags
+		if first && endStream {
+			flags |= flagEndStream
+		}
+		if ln == len(block) {
+			flags |= flagEndHeaders
+		}
+		buf = appendFrameHeader(buf, ln, ft, flags, streamID)
+		buf = append(buf, block[:ln]...)
+		block = block[ln:]
+		ft = frameTypeContinuation
+	}
+	return buf
+}
@@ -0,0 +1,21 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.7
+type pingFrame struct {
+	OpaqueData [8]byte
+}
+*/
+
+const pingFrameLength = 8
+
+func appendPingFrame(buf []byte, ack bool, data []byte) []byte {
+	var flags frameFlags
+	if ack {
+		flags |= flagAck
+	}
+	buf = appendFrameHeader(buf, pingFrameLength, frameTypePing, flags, 0)
+	return append(buf, data[:pingFrameLength]...)
+}
@@ -0,0 +1,14 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.3
+type priorityFrame struct {
+	Exclusive+StreamDep [4]byte
+	Weight              uint8
+}
+Prioritization scheme of RFC 7540 is deprecated, so we just check the frame and ignore it.
+*/
+
+const priorityFrameLength = 5
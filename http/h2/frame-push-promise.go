@@ -0,0 +1,14 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.6
+type pushPromiseFrame struct {
+	PadLength          uint8 // if PADDED flag set
+	PromisedStreamID   [4]byte
+	FieldBlockFragment []byte
+	Padding            []byte
+}
+This package always send SETTINGS_ENABLE_PUSH=0 and never push, so receive this frame is a connection error.
+*/
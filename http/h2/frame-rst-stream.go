@@ -0,0 +1,25 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.4
+type rstStreamFrame struct {
+	ErrorCode ErrorCode
+}
+*/
+
+type rstStreamFrame []byte
+
+func (f rstStreamFrame) ErrorCode() ErrorCode { return ErrorCode(binary.BigEndian.Uint32(f)) }
+
+const rstStreamFrameLength = 4
+
+func appendRSTStreamFrame(buf []byte, streamID uint32, code ErrorCode) []byte {
+	buf = appendFrameHeader(buf, rstStreamFrameLength, frameTypeRSTStream, 0, streamID)
+	return binary.BigEndian.AppendUint32(buf, uint32(code))
+}
@@ -0,0 +1,38 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.5
+type settingsFrame struct {
+	Parameters []struct {
+		ID    SettingID
+		Value uint32
+	}
+}
+*/
+
+type settingsFrame []byte
+
+func (f settingsFrame) Len() int { return len(f) / settingLength }
+func (f settingsFrame) Setting(i int) (id SettingID, value uint32) {
+	var setting = f[i*settingLength:]
+	return SettingID(binary.BigEndian.Uint16(setting)), binary.BigEndian.Uint32(setting[2:])
+}
+
+const settingLength = 6 // ID + Value
+
+// appendSettingsFrame append a SETTINGS frame contain just settings that not equal to the default values.
+func appendSettingsFrame(buf []byte, s *settings) []byte {
+	var payload = s.marshalTo(make([]byte, 0, 6*settingLength))
+	buf = appendFrameHeader(buf, len(payload), frameTypeSettings, 0, 0)
+	return append(buf, payload...)
+}
+
+func appendSettingsAckFrame(buf []byte) []byte {
+	return appendFrameHeader(buf, 0, frameTypeSettings, flagAck, 0)
+}
@@ -0,0 +1,25 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-6.9
+type windowUpdateFrame struct {
+	WindowSizeIncrement [4]byte // 1 bit reserved + uint31
+}
+*/
+
+type windowUpdateFrame []byte
+
+func (f windowUpdateFrame) Increment() uint32 { return binary.BigEndian.Uint32(f) & maxWindowSize }
+
+const windowUpdateFrameLength = 4
+
+func appendWindowUpdateFrame(buf []byte, streamID uint32, increment uint32) []byte {
+	buf = appendFrameHeader(buf, windowUpdateFrameLength, frameTypeWindowUpdate, 0, streamID)
+	return binary.BigEndian.AppendUint32(buf, increment&maxWindowSize)
+}
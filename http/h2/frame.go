@@ -0,0 +1,106 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+https://www.rfc-editor.org/rfc/rfc9113#section-4.1
+
+	type frame struct {
+		Length   [3]byte // uint24 length of the payload
+		Type     frameType
+		Flags    frameFlags
+		StreamID [4]byte // 1 bit reserved + uint31
+		Payload  []byte
+	}
+*/
+type frame []byte
+
+func (f frame) Length() int {
+	return int(f[0])<<16 | int(f[1])<<8 | int(f[2])
+}
+func (f frame) Type() frameType          { return frameType(f[3]) }
+func (f frame) Flags() frameFlags        { return frameFlags(f[4]) }
+func (f frame) Has(flag frameFlags) bool { return f.Flags()&flag != 0 }
+func (f frame) StreamID() uint32         { return binary.BigEndian.Uint32(f[5:]) & maxWindowSize }
+func (f frame) Payload() []byte          { return f[frameHeaderLength : frameHeaderLength+f.Length()] }
+func (f frame) NextFrame() []byte        { return f[frameHeaderLength+f.Length():] }
+func (f frame) String() (s string)       { return f.Type().String() }
+func (f frame) CheckFrame() (complete bool) {
+	return len(f) >= frameHeaderLength && len(f) >= frameHeaderLength+f.Length()
+}
+
+// cutFrame return first frame in given data if fully received.
+// If the frame not received completely, return nil frame and unchanged data to let caller read more data.
+func cutFrame(data []byte, maxFrameSize uint32) (f frame, remaining []byte, err protocol.Error) {
+	if len(data) < frameHeaderLength {
+		return nil, data, nil
+	}
+	var fr = frame(data)
+	if uint32(fr.Length()) > maxFrameSize {
+		return nil, data, &ErrFrameSize
+	}
+	if !fr.CheckFrame() {
+		return nil, data, nil
+	}
+	var frameLen = frameHeaderLength + fr.Length()
+	return fr[:frameLen:frameLen], data[frameLen:], nil
+}
+
+// appendFrameHeader append a frame header to given buf. payloadLength must not exceed peer max frame size.
+func appendFrameHeader(buf []byte, payloadLength int, ft frameType, flags frameFlags, streamID uint32) []byte {
+	return append(buf,
+		byte(payloadLength>>16), byte(payloadLength>>8), byte(payloadLength),
+		byte(ft), byte(flags),
+		byte(streamID>>24)&0x7f, byte(streamID>>16), byte(streamID>>8), byte(streamID),
+	)
+}
+
+// removePadding return the payload without pad length field and padding if the frame has PADDED flag.
+// https://www.rfc-editor.org/rfc/rfc9113#section-6.1
+func removePadding(f frame) (payload []byte, err protocol.Error) {
+	payload = f.Payload()
+	if !f.Has(flagPadded) {
+		return
+	}
+	if len(payload) < 1 {
+		return nil, &ErrFrameSize
+	}
+	var padLength = int(payload[0])
+	payload = payload[1:]
+	if padLength > len(payload) {
+		return nil, &ErrProtocol
+	}
+	return payload[:len(payload)-padLength], nil
+}
+
+func (ft frameType) String() string {
+	switch ft {
+	case frameTypeData:
+		return "DATA"
+	case frameTypeHeaders:
+		return "HEADERS"
+	case frameTypePriority:
+		return "PRIORITY"
+	case frameTypeRSTStream:
+		return "RST_STREAM"
+	case frameTypeSettings:
+		return "SETTINGS"
+	case frameTypePushPromise:
+		return "PUSH_PROMISE"
+	case frameTypePing:
+		return "PING"
+	case frameTypeGoAway:
+		return "GOAWAY"
+	case frameTypeWindowUpdate:
+		return "WINDOW_UPDATE"
+	case frameTypeContinuation:
+		return "CONTINUATION"
+	}
+	return "UNKNOWN"
+}
@@ -0,0 +1,142 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// hpackDecoder decode field blocks to header lists.
+// It is not concurrent safe and must use in order of receive the field blocks on the connection.
+type hpackDecoder struct {
+	table dynamicTable
+
+	// maxSizeLimit is our SETTINGS_HEADER_TABLE_SIZE that peer encoder can't exceed it.
+	maxSizeLimit uint32
+	// maxHeaderListSize is our SETTINGS_MAX_HEADER_LIST_SIZE.
+	maxHeaderListSize uint32
+}
+
+func (d *hpackDecoder) init(maxSizeLimit, maxHeaderListSize uint32) {
+	d.table.init(maxSizeLimit)
+	d.maxSizeLimit = maxSizeLimit
+	d.maxHeaderListSize = maxHeaderListSize
+}
+
+// decodeFieldBlock decode a complete field block and call emit for each field.
+// Any error is a connection error of type COMPRESSION_ERROR except ErrHeaderListTooLarge.
+// Even on ErrHeaderListTooLarge whole block decode to keep dynamic table in sync with peer encoder.
+func (d *hpackDecoder) decodeFieldBlock(block []byte, emit func(hf headerField)) (err protocol.Error) {
+	var listSize uint32
+	var tooLarge bool
+	var fieldsStarted bool
+	for len(block) > 0 {
+		var hf headerField
+		var b = block[0]
+		switch {
+		case b&0x80 != 0:
+			// Indexed Header Field Representation
+			var index uint64
+			index, block, err = readHPACKInteger(block, 7)
+			if err != nil {
+				return
+			}
+			var ihf *headerField
+			ihf, err = d.indexedField(index)
+			if err != nil {
+				return
+			}
+			hf = *ihf
+		case b&0xc0 == 0x40:
+			// Literal Header Field with Incremental Indexing
+			hf, block, err = d.readLiteral(block, 6)
+			if err != nil {
+				return
+			}
+			d.table.add(hf)
+		case b&0xe0 == 0x20:
+			// Dynamic Table Size Update, MUST occur at the beginning of the first field block after the size change.
+			if fieldsStarted {
+				return &ErrCompression
+			}
+			var maxSize uint64
+			maxSize, block, err = readHPACKInteger(block, 5)
+			if err != nil {
+				return
+			}
+			if maxSize > uint64(d.maxSizeLimit) {
+				return &ErrCompression
+			}
+			d.table.setMaxSize(uint32(maxSize))
+			continue
+		default:
+			// Literal Header Field without Indexing(0000) or Never Indexed(0001)
+			var neverIndexed = b&0xf0 == 0x10
+			hf, block, err = d.readLiteral(block, 4)
+			if err != nil {
+				return
+			}
+			hf.Sensitive = neverIndexed
+		}
+		fieldsStarted = true
+
+		listSize += hf.Size()
+		if listSize > d.maxHeaderListSize {
+			tooLarge = true
+		}
+		if !tooLarge {
+			emit(hf)
+		}
+	}
+	if tooLarge {
+		err = &ErrHeaderListTooLarge
+	}
+	return
+}
+
+// setMaxSizeLimit must call when our SETTINGS_HEADER_TABLE_SIZE acknowledged by peer.
+func (d *hpackDecoder) setMaxSizeLimit(maxSize uint32) {
+	d.maxSizeLimit = maxSize
+	if d.table.maxSize > maxSize {
+		d.table.setMaxSize(maxSize)
+	}
+}
+
+func (d *hpackDecoder) indexedField(index uint64) (hf *headerField, err protocol.Error) {
+	if index == 0 {
+		return nil, &ErrCompression
+	}
+	if index <= staticTableLen {
+		return &staticTable[index-1], nil
+	}
+	var ok bool
+	hf, ok = d.table.get(index - staticTableLen)
+	if !ok {
+		return nil, &ErrCompression
+	}
+	return
+}
+
+func (d *hpackDecoder) readLiteral(block []byte, n uint8) (hf headerField, remaining []byte, err protocol.Error) {
+	var index uint64
+	index, remaining, err = readHPACKInteger(block, n)
+	if err != nil {
+		return
+	}
+	var maxLen = uint64(d.maxHeaderListSize)
+	if index == 0 {
+		hf.Name, remaining, err = readHPACKString(remaining, maxLen)
+		if err != nil {
+			return
+		}
+	} else {
+		var ihf *headerField
+		ihf, err = d.indexedField(index)
+		if err != nil {
+			return
+		}
+		hf.Name = ihf.Name
+	}
+	hf.Value, remaining, err = readHPACKString(remaining, maxLen)
+	return
+}
@@ -0,0 +1,84 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+/*
+https://www.rfc-editor.org/rfc/rfc7541#section-2.3.2
+dynamicTable is a FIFO that newest entry has the lowest index.
+Entries store in fields slice in insertion order, so the newest one is the last element.
+*/
+type dynamicTable struct {
+	fields  []headerField
+	size    uint32 // sum of entries size
+	maxSize uint32 // current max size that can be changed by dynamic table size update
+}
+
+func (dt *dynamicTable) init(maxSize uint32) {
+	dt.maxSize = maxSize
+}
+
+func (dt *dynamicTable) Len() uint64 { return uint64(len(dt.fields)) }
+
+// setMaxSize change max size and evict entries if needed.
+func (dt *dynamicTable) setMaxSize(maxSize uint32) {
+	dt.maxSize = maxSize
+	dt.evict()
+}
+
+// add insert given field as newest entry.
+// Add an entry larger than max size cause table to be emptied.
+// https://www.rfc-editor.org/rfc/rfc7541#section-4.4
+func (dt *dynamicTable) add(hf headerField) {
+	var size = hf.Size()
+	if size > dt.maxSize {
+		dt.fields = dt.fields[:0]
+		dt.size = 0
+		return
+	}
+	hf.Sensitive = false
+	dt.fields = append(dt.fields, hf)
+	dt.size += size
+	dt.evict()
+}
+
+func (dt *dynamicTable) evict() {
+	var evicted int
+	for dt.size > dt.maxSize && evicted < len(dt.fields) {
+		dt.size -= dt.fields[evicted].Size()
+		evicted++
+	}
+	if evicted > 0 {
+		var remain = copy(dt.fields, dt.fields[evicted:])
+		for i := remain; i < len(dt.fields); i++ {
+			dt.fields[i] = headerField{}
+		}
+		dt.fields = dt.fields[:remain]
+	}
+}
+
+// get return field by 1-based dynamic table index.
+func (dt *dynamicTable) get(index uint64) (hf *headerField, ok bool) {
+	if index < 1 || index > dt.Len() {
+		return nil, false
+	}
+	return &dt.fields[uint64(len(dt.fields))-index], true
+}
+
+// search return 1-based dynamic table index of exact match or name match field.
+// Zero index means not found.
+func (dt *dynamicTable) search(hf *headerField) (index uint64, nameValueMatch bool) {
+	for i := len(dt.fields) - 1; i >= 0; i-- {
+		var df = &dt.fields[i]
+		if df.Name != hf.Name {
+			continue
+		}
+		var dtIndex = uint64(len(dt.fields) - i)
+		if index == 0 {
+			index = dtIndex
+		}
+		if df.Value == hf.Value {
+			return dtIndex, true
+		}
+	}
+	return
+}
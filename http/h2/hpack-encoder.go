@@ -0,0 +1,79 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+// hpackEncoder encode header lists to field blocks.
+// It is not concurrent safe and must use in order of send the field blocks on the connection.
+type hpackEncoder struct {
+	table dynamicTable
+
+	// maxSizeLimit is SETTINGS_HEADER_TABLE_SIZE of the peer decoder.
+	maxSizeLimit uint32
+	// minSize is the smallest max size that set after last field block, to signal it before new size.
+	minSize          uint32
+	tableSizeUpdated bool
+}
+
+func (e *hpackEncoder) init() {
+	e.table.init(DefaultHeaderTableSize)
+	e.maxSizeLimit = DefaultHeaderTableSize
+}
+
+// setMaxSizeLimit must call when peer change SETTINGS_HEADER_TABLE_SIZE.
+// Encoder use this value as its dynamic table max size.
+func (e *hpackEncoder) setMaxSizeLimit(maxSize uint32) {
+	e.maxSizeLimit = maxSize
+	if !e.tableSizeUpdated || maxSize < e.minSize {
+		e.minSize = maxSize
+	}
+	e.tableSizeUpdated = true
+	e.table.setMaxSize(maxSize)
+}
+
+// appendFieldBlock encode given fields and append them to buf.
+func (e *hpackEncoder) appendFieldBlock(buf []byte, fields []headerField) []byte {
+	if e.tableSizeUpdated {
+		// https://www.rfc-editor.org/rfc/rfc7541#section-4.2
+		if e.minSize < e.table.maxSize {
+			buf = appendHPACKInteger(buf, 0x20, 5, uint64(e.minSize))
+		}
+		buf = appendHPACKInteger(buf, 0x20, 5, uint64(e.table.maxSize))
+		e.tableSizeUpdated = false
+	}
+	for i := range fields {
+		buf = e.appendField(buf, &fields[i])
+	}
+	return buf
+}
+
+// https://www.rfc-editor.org/rfc/rfc7541#section-6
+func (e *hpackEncoder) appendField(buf []byte, hf *headerField) []byte {
+	var index, nameValueMatch = staticTableSearch(hf)
+	if !nameValueMatch && !hf.Sensitive {
+		var dtIndex, dtMatch = e.table.search(hf)
+		if dtMatch || (index == 0 && dtIndex != 0) {
+			index, nameValueMatch = dtIndex+staticTableLen, dtMatch
+		}
+	}
+
+	if nameValueMatch && !hf.Sensitive {
+		// Indexed Header Field Representation
+		return appendHPACKInteger(buf, 0x80, 7, index)
+	}
+
+	if hf.Sensitive {
+		// Literal Header Field Never Indexed
+		buf = appendHPACKInteger(buf, 0x10, 4, index)
+	} else if hf.Size() > e.table.maxSize {
+		// Literal Header Field without Indexing, due to can't fit in dynamic table
+		buf = appendHPACKInteger(buf, 0x00, 4, index)
+	} else {
+		// Literal Header Field with Incremental Indexing
+		buf = appendHPACKInteger(buf, 0x40, 6, index)
+		e.table.add(*hf)
+	}
+	if index == 0 {
+		buf = appendHPACKString(buf, hf.Name)
+	}
+	return appendHPACKString(buf, hf.Value)
+}
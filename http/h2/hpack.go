@@ -0,0 +1,195 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+HPACK: Header Compression for HTTP/2
+https://www.rfc-editor.org/rfc/rfc7541
+*/
+
+// headerField is a name-value pair that encode||decode by HPACK.
+type headerField struct {
+	Name  string
+	Value string
+	// Sensitive indicate the field must never be indexed by any intermediary e.g. authorization, cookie, ...
+	Sensitive bool
+}
+
+// Size return field size that use in dynamic table size calculation.
+// https://www.rfc-editor.org/rfc/rfc7541#section-4.1
+func (hf *headerField) Size() uint32   { return uint32(len(hf.Name) + len(hf.Value) + 32) }
+func (hf *headerField) IsPseudo() bool { return len(hf.Name) > 0 && hf.Name[0] == ':' }
+
+// https://www.rfc-editor.org/rfc/rfc7541#appendix-A
+var staticTable = [...]headerField{
+	{Name: ":authority"},
+	{Name: ":method", Value: "GET"},
+	{Name: ":method", Value: "POST"},
+	{Name: ":path", Value: "/"},
+	{Name: ":path", Value: "/index.html"},
+	{Name: ":scheme", Value: "http"},
+	{Name: ":scheme", Value: "https"},
+	{Name: ":status", Value: "200"},
+	{Name: ":status", Value: "204"},
+	{Name: ":status", Value: "206"},
+	{Name: ":status", Value: "304"},
+	{Name: ":status", Value: "400"},
+	{Name: ":status", Value: "404"},
+	{Name: ":status", Value: "500"},
+	{Name: "accept-charset"},
+	{Name: "accept-encoding", Value: "gzip, deflate"},
+	{Name: "accept-language"},
+	{Name: "accept-ranges"},
+	{Name: "accept"},
+	{Name: "access-control-allow-origin"},
+	{Name: "age"},
+	{Name: "allow"},
+	{Name: "authorization"},
+	{Name: "cache-control"},
+	{Name: "content-disposition"},
+	{Name: "content-encoding"},
+	{Name: "content-language"},
+	{Name: "content-length"},
+	{Name: "content-location"},
+	{Name: "content-range"},
+	{Name: "content-type"},
+	{Name: "cookie"},
+	{Name: "date"},
+	{Name: "etag"},
+	{Name: "expect"},
+	{Name: "expires"},
+	{Name: "from"},
+	{Name: "host"},
+	{Name: "if-match"},
+	{Name: "if-modified-since"},
+	{Name: "if-none-match"},
+	{Name: "if-range"},
+	{Name: "if-unmodified-since"},
+	{Name: "last-modified"},
+	{Name: "link"},
+	{Name: "location"},
+	{Name: "max-forwards"},
+	{Name: "proxy-authenticate"},
+	{Name: "proxy-authorization"},
+	{Name: "range"},
+	{Name: "referer"},
+	{Name: "refresh"},
+	{Name: "retry-after"},
+	{Name: "server"},
+	{Name: "set-cookie"},
+	{Name: "strict-transport-security"},
+	{Name: "transfer-encoding"},
+	{Name: "user-agent"},
+	{Name: "vary"},
+	{Name: "via"},
+	{Name: "www-authenticate"},
+}
+
+const staticTableLen = uint64(len(staticTable))
+
+// staticTableSearch return 1-based index of exact match field or name match field in static table.
+// Zero means not found.
+func staticTableSearch(hf *headerField) (index uint64, nameValueMatch bool) {
+	for i := range staticTable {
+		var sf = &staticTable[i]
+		if sf.Name != hf.Name {
+			continue
+		}
+		if index == 0 {
+			index = uint64(i + 1)
+		}
+		if sf.Value == hf.Value {
+			return uint64(i + 1), true
+		}
+	}
+	return
+}
+
+// appendHPACKInteger append integer representation with N-bit prefix.
+// first is the first byte that its high bits(e.g. representation pattern) already set.
+// https://www.rfc-editor.org/rfc/rfc7541#section-5.1
+func appendHPACKInteger(buf []byte, first byte, n uint8, i uint64) []byte {
+	var max = uint64(1)<<n - 1
+	if i < max {
+		return append(buf, first|byte(i))
+	}
+	buf = append(buf, first|byte(max))
+	i -= max
+	for i >= 128 {
+		buf = append(buf, byte(i&0x7f|0x80))
+		i >>= 7
+	}
+	return append(buf, byte(i))
+}
+
+// readHPACKInteger read integer representation with N-bit prefix.
+func readHPACKInteger(data []byte, n uint8) (i uint64, remaining []byte, err protocol.Error) {
+	if len(data) == 0 {
+		return 0, data, &ErrCompression
+	}
+	var max = uint64(1)<<n - 1
+	i = uint64(data[0]) & max
+	data = data[1:]
+	if i < max {
+		return i, data, nil
+	}
+
+	var m uint
+	for len(data) > 0 {
+		var b = data[0]
+		data = data[1:]
+		i += uint64(b&0x7f) << m
+		if b&0x80 == 0 {
+			return i, data, nil
+		}
+		m += 7
+		// Don't let an attacker overflow the integer.
+		if m >= 63 {
+			break
+		}
+	}
+	return 0, data, &ErrCompression
+}
+
+// appendHPACKString append string literal representation.
+// Huffman encoding use just if it make the string shorter.
+// https://www.rfc-editor.org/rfc/rfc7541#section-5.2
+func appendHPACKString(buf []byte, s string) []byte {
+	var huffmanLen = huffmanEncodeLength(s)
+	if huffmanLen < uint64(len(s)) {
+		buf = appendHPACKInteger(buf, 0x80, 7, huffmanLen)
+		return appendHuffmanString(buf, s)
+	}
+	buf = appendHPACKInteger(buf, 0, 7, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// readHPACKString read string literal representation.
+func readHPACKString(data []byte, maxLen uint64) (s string, remaining []byte, err protocol.Error) {
+	if len(data) == 0 {
+		return "", data, &ErrCompression
+	}
+	var huffman = data[0]&0x80 != 0
+	var strLen uint64
+	strLen, data, err = readHPACKInteger(data, 7)
+	if err != nil {
+		return
+	}
+	if strLen > uint64(len(data)) {
+		return "", data, &ErrCompression
+	}
+	if strLen > maxLen {
+		return "", data, &ErrCompression
+	}
+	var raw = data[:strLen]
+	remaining = data[strLen:]
+	if !huffman {
+		return string(raw), remaining, nil
+	}
+	s, err = huffmanDecode(raw, maxLen)
+	return
+}
@@ -0,0 +1,152 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestHPACKInteger(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc7541#appendix-C.1
+	var tests = []struct {
+		n       uint8
+		i       uint64
+		encoded string
+	}{
+		{n: 5, i: 10, encoded: "0a"},
+		{n: 5, i: 1337, encoded: "1f9a0a"},
+		{n: 8, i: 42, encoded: "2a"},
+	}
+	for _, tt := range tests {
+		var encoded = appendHPACKInteger(nil, 0, tt.n, tt.i)
+		if hex.EncodeToString(encoded) != tt.encoded {
+			t.Errorf("appendHPACKInteger(%d) = %x, want %s", tt.i, encoded, tt.encoded)
+		}
+		var i, remaining, err = readHPACKInteger(encoded, tt.n)
+		if err != nil || i != tt.i || len(remaining) != 0 {
+			t.Errorf("readHPACKInteger(%s) = %d, %v, want %d", tt.encoded, i, err, tt.i)
+		}
+	}
+}
+
+func TestHuffman(t *testing.T) {
+	var tests = []string{"www.example.com", "no-cache", "custom-key", "custom-value", "Mon, 21 Oct 2013 20:13:21 GMT", ""}
+	for _, s := range tests {
+		var encoded = appendHuffmanString(nil, s)
+		if uint64(len(encoded)) != huffmanEncodeLength(s) {
+			t.Errorf("huffmanEncodeLength(%q) = %d, want %d", s, huffmanEncodeLength(s), len(encoded))
+		}
+		var decoded, err = huffmanDecode(encoded, 1024)
+		if err != nil || decoded != s {
+			t.Errorf("huffmanDecode(%x) = %q, %v, want %q", encoded, decoded, err, s)
+		}
+	}
+
+	// Padding not correspond to EOS most significant bits.
+	if _, err := huffmanDecode([]byte{0xf1, 0xe3, 0x00}, 1024); err == nil {
+		t.Errorf("huffmanDecode() expected error for invalid padding")
+	}
+	// Padding longer than 7 bits.
+	if _, err := huffmanDecode([]byte{0xff, 0xff}, 1024); err == nil {
+		t.Errorf("huffmanDecode() expected error for long padding")
+	}
+}
+
+// https://www.rfc-editor.org/rfc/rfc7541#appendix-C.4
+var hpackRequestTests = []struct {
+	fields    []headerField
+	encoded   string
+	tableSize uint32
+}{
+	{
+		fields: []headerField{
+			{Name: ":method", Value: "GET"},
+			{Name: ":scheme", Value: "http"},
+			{Name: ":path", Value: "/"},
+			{Name: ":authority", Value: "www.example.com"},
+		},
+		encoded:   "828684418cf1e3c2e5f23a6ba0ab90f4ff",
+		tableSize: 57,
+	}, {
+		fields: []headerField{
+			{Name: ":method", Value: "GET"},
+			{Name: ":scheme", Value: "http"},
+			{Name: ":path", Value: "/"},
+			{Name: ":authority", Value: "www.example.com"},
+			{Name: "cache-control", Value: "no-cache"},
+		},
+		encoded:   "828684be5886a8eb10649cbf",
+		tableSize: 110,
+	}, {
+		fields: []headerField{
+			{Name: ":method", Value: "GET"},
+			{Name: ":scheme", Value: "https"},
+			{Name: ":path", Value: "/index.html"},
+			{Name: ":authority", Value: "www.example.com"},
+			{Name: "custom-key", Value: "custom-value"},
+		},
+		encoded:   "828785bf408825a849e95ba97d7f8925a849e95bb8e8b4bf",
+		tableSize: 164,
+	},
+}
+
+func TestHPACKEncoder(t *testing.T) {
+	var e hpackEncoder
+	e.init()
+	for i, tt := range hpackRequestTests {
+		var encoded = e.appendFieldBlock(nil, tt.fields)
+		if hex.EncodeToString(encoded) != tt.encoded {
+			t.Errorf("request %d: appendFieldBlock() = %x, want %s", i+1, encoded, tt.encoded)
+		}
+		if e.table.size != tt.tableSize {
+			t.Errorf("request %d: dynamic table size = %d, want %d", i+1, e.table.size, tt.tableSize)
+		}
+	}
+}
+
+func TestHPACKDecoder(t *testing.T) {
+	var d hpackDecoder
+	d.init(DefaultHeaderTableSize, DefaultMaxHeaderListSize)
+	for i, tt := range hpackRequestTests {
+		var block, _ = hex.DecodeString(tt.encoded)
+		var fields []headerField
+		var err = d.decodeFieldBlock(block, func(hf headerField) { fields = append(fields, hf) })
+		if err != nil {
+			t.Fatalf("request %d: decodeFieldBlock() error = %v", i+1, err)
+		}
+		if len(fields) != len(tt.fields) {
+			t.Fatalf("request %d: decodeFieldBlock() got %d fields, want %d", i+1, len(fields), len(tt.fields))
+		}
+		for j := range fields {
+			if fields[j] != tt.fields[j] {
+				t.Errorf("request %d: field %d = %v, want %v", i+1, j, fields[j], tt.fields[j])
+			}
+		}
+		if d.table.size != tt.tableSize {
+			t.Errorf("request %d: dynamic table size = %d, want %d", i+1, d.table.size, tt.tableSize)
+		}
+	}
+}
+
+func TestHPACKDecoderLimits(t *testing.T) {
+	var e hpackEncoder
+	e.init()
+	var block = e.appendFieldBlock(nil, []headerField{{Name: "x-large", Value: strings.Repeat("a", 200)}})
+
+	var d hpackDecoder
+	d.init(DefaultHeaderTableSize, 100)
+	var err = d.decodeFieldBlock(block, func(hf headerField) {})
+	if err == nil {
+		t.Errorf("decodeFieldBlock() expected error for too large header list")
+	}
+
+	// Dynamic table size update larger than our limit is a decoding error.
+	d.init(DefaultHeaderTableSize, DefaultMaxHeaderListSize)
+	block = appendHPACKInteger(nil, 0x20, 5, DefaultHeaderTableSize+1)
+	err = d.decodeFieldBlock(block, func(hf headerField) {})
+	if err == nil {
+		t.Errorf("decodeFieldBlock() expected error for table size update")
+	}
+}
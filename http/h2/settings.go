@@ -0,0 +1,115 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/binary"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// settings hold SETTINGS parameters of one endpoint.
+// https://www.rfc-editor.org/rfc/rfc9113#section-6.5.2
+type settings struct {
+	HeaderTableSize      uint32
+	EnablePush           bool
+	MaxConcurrentStreams uint32 // 0 means unlimited
+	InitialWindowSize    uint32
+	MaxFrameSize         uint32
+	MaxHeaderListSize    uint32 // 0 means unlimited
+}
+
+// init set default values that must assume before receive any SETTINGS frame.
+func (s *settings) init() {
+	s.HeaderTableSize = DefaultHeaderTableSize
+	s.EnablePush = true
+	s.MaxConcurrentStreams = 0
+	s.InitialWindowSize = DefaultInitialWindowSize
+	s.MaxFrameSize = DefaultMaxFrameSize
+	s.MaxHeaderListSize = 0
+}
+
+// initLocal set values that this package suggest to the peer.
+func (s *settings) initLocal() {
+	s.init()
+	s.EnablePush = false
+	s.MaxConcurrentStreams = DefaultMaxConcurrentStreams
+	s.MaxHeaderListSize = DefaultMaxHeaderListSize
+}
+
+// apply validate and set given parameter. Unknown parameters MUST be ignored.
+func (s *settings) apply(id SettingID, value uint32) (err protocol.Error) {
+	switch id {
+	case SettingHeaderTableSize:
+		s.HeaderTableSize = value
+	case SettingEnablePush:
+		if value > 1 {
+			return &ErrProtocol
+		}
+		s.EnablePush = value == 1
+	case SettingMaxConcurrentStreams:
+		s.MaxConcurrentStreams = value
+	case SettingInitialWindowSize:
+		if value > maxWindowSize {
+			return &ErrFlowControl
+		}
+		s.InitialWindowSize = value
+	case SettingMaxFrameSize:
+		if value < DefaultMaxFrameSize || value > maxFrameSizeLimit {
+			return &ErrProtocol
+		}
+		s.MaxFrameSize = value
+	case SettingMaxHeaderListSize:
+		s.MaxHeaderListSize = value
+	}
+	return
+}
+
+// unmarshal apply all parameters of a SETTINGS frame payload.
+func (s *settings) unmarshal(payload []byte) (err protocol.Error) {
+	if len(payload)%settingLength != 0 {
+		return &ErrFrameSize
+	}
+	var sf = settingsFrame(payload)
+	for i := 0; i < sf.Len(); i++ {
+		err = s.apply(sf.Setting(i))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// marshalTo append parameters that differ from the default values.
+func (s *settings) marshalTo(buf []byte) []byte {
+	var def settings
+	def.init()
+	if s.HeaderTableSize != def.HeaderTableSize {
+		buf = appendSetting(buf, SettingHeaderTableSize, s.HeaderTableSize)
+	}
+	if s.EnablePush != def.EnablePush {
+		var enablePush uint32
+		if s.EnablePush {
+			enablePush = 1
+		}
+		buf = appendSetting(buf, SettingEnablePush, enablePush)
+	}
+	if s.MaxConcurrentStreams != def.MaxConcurrentStreams {
+		buf = appendSetting(buf, SettingMaxConcurrentStreams, s.MaxConcurrentStreams)
+	}
+	if s.InitialWindowSize != def.InitialWindowSize {
+		buf = appendSetting(buf, SettingInitialWindowSize, s.InitialWindowSize)
+	}
+	if s.MaxFrameSize != def.MaxFrameSize {
+		buf = appendSetting(buf, SettingMaxFrameSize, s.MaxFrameSize)
+	}
+	if s.MaxHeaderListSize != def.MaxHeaderListSize {
+		buf = appendSetting(buf, SettingMaxHeaderListSize, s.MaxHeaderListSize)
+	}
+	return buf
+}
+
+func appendSetting(buf []byte, id SettingID, value uint32) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(id))
+	return binary.BigEndian.AppendUint32(buf, value)
+}
@@ -0,0 +1,463 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Stream is an HTTP/2 stream that map to protocol.Stream, so services can use it like any other stream.
+// Methods that not related to HTTP/2 stream e.g. Connection(), Handler(), Timeout, ... serve by the underlying
+// transport stream of the connection.
+type Stream struct {
+	protocol.Stream // underlying transport stream of the connection
+
+	connection    *Connection
+	id            uint32
+	peerInitiated bool
+
+	service  protocol.Service
+	err      protocol.Error
+	status   protocol.NetworkStatus
+	state    chan protocol.NetworkStatus
+	request  any
+	response any
+
+	req http.Request
+	res http.Response
+
+	/* Receive side, use just by connection read loop until remote end */
+	pseudo      pseudoHeaders
+	malformed   bool
+	headersDone bool // initial field block received, so any other field block is trailer section
+	body        []byte
+	recvWindow  int64
+
+	/* Send side, guard by connection flowMutex */
+	sendWindow   int64
+	localClosed  bool
+	remoteClosed bool
+	resetErr     protocol.Error
+	done         chan struct{} // close when stream remote end or reset
+}
+
+// pseudoHeaders hold values of pseudo-header fields of a field block.
+// https://www.rfc-editor.org/rfc/rfc9113#section-8.3
+type pseudoHeaders struct {
+	method    string
+	scheme    string
+	authority string
+	path      string
+	status    string
+}
+
+func (st *Stream) init(c *Connection, id uint32, peerInitiated bool) {
+	st.Stream = c.transport
+	st.connection = c
+	st.id = id
+	st.peerInitiated = peerInitiated
+	st.state = make(chan protocol.NetworkStatus, 4)
+	st.done = make(chan struct{})
+	st.recvWindow = int64(c.local.InitialWindowSize)
+	st.req.Init()
+	st.res.Init()
+}
+
+//libgo:impl protocol.Stream
+func (st *Stream) Service() protocol.Service       { return st.service }
+func (st *Stream) Error() protocol.Error           { return st.err }
+func (st *Stream) SetService(ser protocol.Service) { st.service = ser }
+func (st *Stream) SetError(err protocol.Error)     { st.err = err }
+
+//libgo:impl protocol.Stream_ID
+func (st *Stream) StreamID() protocol.StreamID { return protocol.StreamID(st.id) }
+func (st *Stream) PeerInitiated() bool         { return st.peerInitiated }
+func (st *Stream) Bidirectional() bool         { return true }
+
+//libgo:impl protocol.Network_Status
+func (st *Stream) Status() protocol.NetworkStatus {
+	return protocol.NetworkStatus(atomic.LoadUint32((*uint32)(&st.status)))
+}
+func (st *Stream) State() chan protocol.NetworkStatus { return st.state }
+func (st *Stream) SetStatus(ns protocol.NetworkStatus) {
+	// Status change by both read loop and the stream writer.
+	atomic.StoreUint32((*uint32)(&st.status), uint32(ns))
+	select {
+	case st.state <- ns:
+	default:
+		// Don't block caller if no one listen to the state channel
+	}
+}
+
+//libgo:impl protocol.StreamLowLevelAPIs
+func (st *Stream) Request() any              { return st.request }
+func (st *Stream) Response() any             { return st.response }
+func (st *Stream) SetRequest(req any)        { st.request = req }
+func (st *Stream) SetResponse(res any)       { st.response = res }
+func (st *Stream) ScheduleProcessingStream() {}
+func (st *Stream) Send(data protocol.Codec) (err protocol.Error) {
+	_, err = st.Encode(data)
+	return
+}
+
+// Close reset the stream if it is not closed in both directions yet and deregister it from the connection.
+func (st *Stream) Close() (err protocol.Error) {
+	var c = st.connection
+	c.flowMutex.Lock()
+	var open = !st.localClosed || !st.remoteClosed
+	c.flowMutex.Unlock()
+	if open && st.id != 0 {
+		err = c.writeFrames(appendRSTStreamFrame(nil, st.id, ErrorCodeCancel))
+		st.reset(&ErrStreamClosed)
+	}
+	c.removeStream(st)
+	return
+}
+
+//libgo:impl protocol.Codec
+func (st *Stream) MediaType() protocol.MediaType       { return nil }
+func (st *Stream) CompressType() protocol.CompressType { return nil }
+func (st *Stream) Len() (ln int)                       { return len(st.body) }
+func (st *Stream) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return st.Unmarshal(data)
+}
+
+// Encode send given HTTP request or response as HEADERS and DATA frames.
+// Any other codec send as DATA frames without end the stream.
+func (st *Stream) Encode(source protocol.Codec) (n int, err protocol.Error) {
+	switch message := source.(type) {
+	case *http.Response:
+		err = st.writeResponse(message)
+	case *http.Request:
+		err = st.writeRequest(message)
+	default:
+		var data []byte
+		data, err = source.Marshal()
+		if err != nil {
+			return
+		}
+		n, err = st.Unmarshal(data)
+	}
+	return
+}
+func (st *Stream) Marshal() (data []byte, err protocol.Error) { return st.body, nil }
+func (st *Stream) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return append(data, st.body...), nil
+}
+
+// Unmarshal send given data as DATA frames without end the stream.
+func (st *Stream) Unmarshal(data []byte) (n int, err protocol.Error) {
+	err = st.connection.writeData(st, data, false)
+	if err == nil {
+		n = len(data)
+	}
+	return
+}
+func (st *Stream) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = st.Unmarshal(data)
+	return
+}
+
+/*
+********** Send side methods **********
+ */
+
+func (st *Stream) writeResponse(httpRes *http.Response) (err protocol.Error) {
+	var fields = make([]headerField, 0, 16)
+	fields = append(fields, headerField{Name: ":status", Value: httpRes.StatusCode()})
+	fields = appendHTTPFields(fields, httpRes.H.Range)
+	return st.writeMessage(fields, httpRes.Codec)
+}
+
+func (st *Stream) writeRequest(httpReq *http.Request) (err protocol.Error) {
+	var uri = httpReq.URI()
+	var scheme = uri.Scheme()
+	if scheme == "" {
+		scheme = "https"
+	}
+	var authority = uri.Authority()
+	if authority == "" {
+		authority = httpReq.H.Get(http.HeaderKeyHost)
+	}
+	var path = uri.Path()
+	if path == "" {
+		path = "/"
+	}
+	if query := uri.Query(); query != "" {
+		path += "?" + query
+	}
+
+	var fields = make([]headerField, 0, 16)
+	fields = append(fields,
+		headerField{Name: ":method", Value: httpReq.Method()},
+		headerField{Name: ":scheme", Value: scheme},
+		headerField{Name: ":authority", Value: authority},
+		headerField{Name: ":path", Value: path},
+	)
+	fields = appendHTTPFields(fields, httpReq.H.Range)
+	return st.writeMessage(fields, httpReq.Codec)
+}
+
+// writeMessage send field section and then the body and the trailer section if any exist.
+// HTTP/1 chunked transfer coding is not allowed in HTTP/2, so chunked body unwrap to its source.
+func (st *Stream) writeMessage(fields []headerField, body protocol.Codec) (err protocol.Error) {
+	var trailer []headerField
+	if cc, ok := body.(*http.ChunkedCodec); ok {
+		body = cc.Source()
+		if tr, ok := cc.Trailer().(headerRanger); ok {
+			trailer = appendHTTPFields(nil, tr.Range)
+		}
+	}
+
+	var noBody = body == nil || body.Len() == 0
+	err = st.connection.writeHeaders(st, fields, noBody && len(trailer) == 0)
+	if err != nil || noBody && len(trailer) == 0 {
+		st.localEnd()
+		return
+	}
+
+	if !noBody {
+		if body.Len() < 0 {
+			// Length is unknown e.g. stream compressed data, so let the source write itself to the stream as DATA frames.
+			_, err = body.Encode(st)
+			if err == nil && len(trailer) == 0 {
+				err = st.connection.writeData(st, nil, true)
+			}
+		} else {
+			var data []byte
+			data, err = body.Marshal()
+			if err == nil {
+				err = st.connection.writeData(st, data, len(trailer) == 0)
+			}
+		}
+	}
+	if err == nil && len(trailer) > 0 {
+		err = st.connection.writeHeaders(st, trailer, true)
+	}
+	st.localEnd()
+	return
+}
+
+// headerRanger is implemented by HTTP/1 header to iterate over its fields.
+type headerRanger interface {
+	Range(f func(key, value string))
+}
+
+// appendHTTPFields append fields of an HTTP/1 header to fields by respect to HTTP/2 rules.
+// Field names must be lowercase and connection-specific fields must not send.
+// https://www.rfc-editor.org/rfc/rfc9113#section-8.2
+func appendHTTPFields(fields []headerField, rangeFunc func(f func(key, value string))) []headerField {
+	rangeFunc(func(key, value string) {
+		var name = strings.ToLower(key)
+		switch name {
+		case "connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade", "host", "http2-settings":
+			return
+		case "te":
+			if value != "trailers" {
+				return
+			}
+		}
+		fields = append(fields, headerField{Name: name, Value: value, Sensitive: sensitiveField(name)})
+	})
+	return fields
+}
+
+// sensitiveField report fields that must never be indexed to protect them from compression-based attacks.
+// https://www.rfc-editor.org/rfc/rfc7541#section-7.1.3
+func sensitiveField(name string) bool {
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	return false
+}
+
+// localEnd mark stream closed from local side and deregister it from connection if remote side closed too.
+func (st *Stream) localEnd() {
+	var c = st.connection
+	c.flowMutex.Lock()
+	st.localClosed = true
+	var closed = st.remoteClosed
+	c.flowMutex.Unlock()
+	st.SetStatus(protocol.NetworkStatus_SentCompletely)
+	if closed {
+		c.removeStream(st)
+	}
+}
+
+/*
+********** Receive side methods **********
+ */
+
+// addField add decoded field to the request in server side or the response in client side.
+// Malformed fields just mark the stream as malformed to reset it after whole field block decoded.
+func (st *Stream) addField(hf headerField) {
+	if hf.IsPseudo() {
+		// Pseudo-header fields must appear before regular fields and not allowed in trailers.
+		if st.headersDone || st.malformed {
+			st.malformed = true
+			return
+		}
+		var target *string
+		switch hf.Name {
+		case ":method":
+			target = &st.pseudo.method
+		case ":scheme":
+			target = &st.pseudo.scheme
+		case ":authority":
+			target = &st.pseudo.authority
+		case ":path":
+			target = &st.pseudo.path
+		case ":status":
+			target = &st.pseudo.status
+		}
+		if target == nil || *target != "" {
+			st.malformed = true
+			return
+		}
+		*target = hf.Value
+		return
+	}
+
+	for i := 0; i < len(hf.Name); i++ {
+		if 'A' <= hf.Name[i] && hf.Name[i] <= 'Z' {
+			st.malformed = true
+			return
+		}
+	}
+	switch hf.Name {
+	case "connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade":
+		st.malformed = true
+		return
+	case "te":
+		if hf.Value != "trailers" {
+			st.malformed = true
+			return
+		}
+	}
+
+	var h = &st.req.H
+	if st.connection.isClient {
+		h = &st.res.H
+	}
+	var key = http.CanonicalHeaderKey(hf.Name)
+	if key == http.HeaderKeyCookie {
+		// HTTP/2 allow split cookie to many fields, but HTTP/1 just accept one field.
+		// https://www.rfc-editor.org/rfc/rfc9113#section-8.2.3
+		if cookie := h.Get(key); cookie != "" {
+			h.Set(key, cookie+"; "+hf.Value)
+			return
+		}
+	}
+	h.Add(key, hf.Value)
+}
+
+// fieldBlockEnd check and set decoded field block. It return true if the stream must reset as malformed.
+// https://www.rfc-editor.org/rfc/rfc9113#section-8.1.1
+func (st *Stream) fieldBlockEnd(endStream bool) (malformed bool) {
+	if st.malformed {
+		return true
+	}
+	if st.headersDone {
+		// Trailer section must end the stream.
+		return !endStream
+	}
+
+	var p = &st.pseudo
+	if st.connection.isClient {
+		if len(p.status) != 3 || p.method != "" || p.path != "" {
+			return true
+		}
+		if p.status[0] == '1' {
+			// Informational responses e.g. 100-continue just ignored
+			if endStream {
+				return true
+			}
+			st.pseudo = pseudoHeaders{}
+			st.res.H.Reinit()
+			return false
+		}
+		st.res.SetVersion(http.VersionHTTP2)
+		st.res.SetStatus(p.status, "")
+		if code, err := st.res.GetStatusCode(); err == nil {
+			st.res.SetStatus(p.status, http.GetStatusText(code))
+		}
+	} else {
+		if p.status != "" || p.method == "" || p.scheme == "" || p.path == "" {
+			return true
+		}
+		var path, query = p.path, ""
+		if i := strings.IndexByte(path, '?'); i != -1 {
+			path, query = path[:i], path[i+1:]
+		}
+		st.req.SetMethod(p.method)
+		st.req.SetVersion(http.VersionHTTP2)
+		st.req.URI().Set(p.scheme, p.authority, path, query, "")
+		if p.authority != "" && st.req.H.Get(http.HeaderKeyHost) == "" {
+			st.req.H.Set(http.HeaderKeyHost, p.authority)
+		}
+	}
+	st.headersDone = true
+	return false
+}
+
+// remoteEnd mark stream closed from remote side.
+// In server side the request is complete and ready to serve, and in client side the response is ready to read.
+func (st *Stream) remoteEnd() {
+	var c = st.connection
+	c.flowMutex.Lock()
+	if st.remoteClosed {
+		c.flowMutex.Unlock()
+		return
+	}
+	st.remoteClosed = true
+	var closed = st.localClosed
+	c.flowMutex.Unlock()
+	st.SetStatus(protocol.NetworkStatus_ReceivedCompletely)
+	close(st.done)
+
+	if !c.isClient {
+		go c.serveStream(st)
+	} else if closed {
+		c.removeStream(st)
+	}
+}
+
+func (st *Stream) isRemoteClosed() (closed bool) {
+	st.connection.flowMutex.Lock()
+	closed = st.remoteClosed
+	st.connection.flowMutex.Unlock()
+	return
+}
+
+// reset close the stream in both directions due to given error e.g. RST_STREAM received or connection closed.
+func (st *Stream) reset(err protocol.Error) {
+	var c = st.connection
+	c.flowMutex.Lock()
+	if st.resetErr != nil || (st.localClosed && st.remoteClosed) {
+		c.flowMutex.Unlock()
+		return
+	}
+	st.resetErr = err
+	var remoteClosed = st.remoteClosed
+	st.localClosed = true
+	st.remoteClosed = true
+	c.flowCond.Broadcast()
+	c.flowMutex.Unlock()
+
+	if !remoteClosed {
+		close(st.done)
+	}
+	st.SetError(err)
+	st.SetStatus(protocol.NetworkStatus_Closed)
+	c.removeStream(st)
+}
@@ -0,0 +1,72 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package h2
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const upgradeResponse = "HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: h2c\r\n\r\n"
+
+// IsUpgradeRequest report given HTTP/1.1 request ask to upgrade the connection to cleartext HTTP/2.
+// https://www.rfc-editor.org/rfc/rfc7540#section-3.2
+func IsUpgradeRequest(httpReq *http.Request) bool {
+	return strings.EqualFold(httpReq.H.Get(http.HeaderKeyUpgrade), UpgradeToken) &&
+		len(httpReq.H.Gets(HeaderKeyHTTP2Settings)) == 1
+}
+
+// ServeUpgrade send "101 Switching Protocols" for given h2c upgrade request and serve the connection as HTTP/2.
+// The upgrade request serve as stream 1 and its response send by HTTP/2 on it.
+// It block the caller until connection closed like Serve() method.
+func ServeUpgrade(transport protocol.Stream, server Server, httpReq *http.Request) (err protocol.Error) {
+	var payload, goErr = base64.RawURLEncoding.DecodeString(strings.TrimRight(httpReq.H.Get(HeaderKeyHTTP2Settings), "="))
+	if goErr != nil {
+		return &ErrUpgradeSettings
+	}
+
+	var c Connection
+	c.Init(transport, server)
+	err = c.peer.unmarshal(payload)
+	if err != nil {
+		return &ErrUpgradeSettings
+	}
+	if c.peer.HeaderTableSize < DefaultHeaderTableSize {
+		c.encoder.setMaxSizeLimit(c.peer.HeaderTableSize)
+	}
+
+	_, err = transport.Unmarshal([]byte(upgradeResponse))
+	if err != nil {
+		return
+	}
+
+	// Upgrade request is in half-closed (remote) state as stream 1.
+	var st = new(Stream)
+	st.init(&c, 1, true)
+	st.sendWindow = int64(c.peer.InitialWindowSize)
+	st.req = *httpReq
+	st.req.SetVersion(http.VersionHTTP2)
+	st.req.H.Del(http.HeaderKeyConnection)
+	st.req.H.Del(http.HeaderKeyUpgrade)
+	st.req.H.Del(HeaderKeyHTTP2Settings)
+	st.headersDone = true
+	c.streams[1] = st
+	c.lastPeerStreamID = 1
+
+	var buf []byte
+	buf, err = c.readPreface()
+	if err != nil {
+		c.close(err)
+		return
+	}
+	err = c.writeFrames(appendSettingsFrame(nil, &c.local))
+	if err != nil {
+		c.close(err)
+		return
+	}
+	st.remoteEnd()
+	return c.readLoop(buf)
+}
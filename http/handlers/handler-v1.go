@@ -3,9 +3,13 @@
 package hh
 
 import (
+	"github.com/GeniusesGroup/libgo/authorization"
+	"github.com/GeniusesGroup/libgo/compress/raw"
 	"github.com/GeniusesGroup/libgo/convert"
 	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/http/h2"
 	hs "github.com/GeniusesGroup/libgo/http/services"
+	"github.com/GeniusesGroup/libgo/http/ws"
 	"github.com/GeniusesGroup/libgo/log"
 	"github.com/GeniusesGroup/libgo/protocol"
 )
@@ -27,6 +31,22 @@ func (h *V1) HandleIncomeRequest(st protocol.Stream) (err protocol.Error) {
 		h.HandleOutcomeResponse(st, &httpReq, &httpRes)
 		return
 	}
+	err = st.Connection().PacketReceiving(uint64(httpReq.Len()))
+	if err != nil {
+		httpRes.SetStatus(http.StatusTooManyRequestsCode, http.StatusTooManyRequestsPhrase)
+		httpRes.SetProblem(err, &httpReq)
+		h.HandleOutcomeResponse(st, &httpReq, &httpRes)
+		return
+	}
+
+	// Client ask to continue the connection as cleartext HTTP/2, so serve this request and any other on HTTP/2 streams.
+	if h2.IsUpgradeRequest(&httpReq) {
+		return h2.ServeUpgrade(st, h, &httpReq)
+	}
+	// Client ask to continue the connection as WebSocket e.g. browsers to call sRPC services.
+	if ws.IsUpgradeRequest(&httpReq) {
+		return h.serveWebSocket(st, &httpReq, &httpRes)
+	}
 
 	err = h.ServeHTTP(st, &httpReq, &httpRes)
 	return
@@ -43,7 +63,12 @@ func (h *V1) ServeHTTP(st protocol.Stream, httpReq *http.Request, httpRes *http.
 	var path = httpReq.URI().Path()
 	switch path {
 	case hs.MuxService_Path:
-		err = hs.MuxService.ServeHTTP(st, httpReq, httpRes)
+		if err = authorization.ServiceCalled(st); err != nil {
+			httpRes.SetStatus(http.StatusTooManyRequestsCode, http.StatusTooManyRequestsPhrase)
+			httpRes.SetProblem(err, httpReq)
+		} else {
+			err = hs.MuxService.ServeHTTP(st, httpReq, httpRes)
+		}
 	case hs.LandingService_Path:
 		// TODO:::
 	default:
@@ -52,7 +77,14 @@ func (h *V1) ServeHTTP(st protocol.Stream, httpReq *http.Request, httpRes *http.
 		service, err = protocol.App.GetServiceByURI(path)
 		if service == nil {
 			// If project don't have any logic that support data on e.g. HTTP (restful, ...) we send platform GUI app for web
+			// Static assets are not service calls, so they don't count in the service call quota.
 			err = hs.ServeWWWService.ServeHTTP(st, httpReq, httpRes)
+		} else if err = authorization.ServiceCalled(st); err != nil {
+			httpRes.SetStatus(http.StatusTooManyRequestsCode, http.StatusTooManyRequestsPhrase)
+			httpRes.SetProblem(err, httpReq)
+		} else if err = authorization.AuthorizeService(st, service); err != nil {
+			httpRes.SetStatus(http.StatusForbiddenCode, http.StatusForbiddenPhrase)
+			httpRes.SetProblem(err, httpReq)
 		} else {
 			err = service.ServeHTTP(st, httpReq, httpRes)
 		}
@@ -65,20 +97,30 @@ func (h *V1) ServeHTTP(st protocol.Stream, httpReq *http.Request, httpRes *http.
 func (h *V1) HandleOutcomeResponse(st protocol.Stream, httpReq *http.Request, httpRes *http.Response) {
 	// Do some global assignment to response
 	httpRes.SetVersion(httpReq.Version())
-	if httpRes.Body() != nil {
+	negotiateResponse(httpReq, httpRes)
+	if !responseHasBody(httpRes.StatusCode()) {
+		// Content-Length of 1xx and 204 is not allowed, and in 304 it must be the selected representation length
+		// that service can set, so set nothing.
+		// https://datatracker.ietf.org/doc/html/rfc9110#section-8.6
+	} else if httpRes.Codec != nil {
 		var mediaType = httpRes.Body().MediaType()
 		if mediaType != nil {
 			httpRes.H.Set(http.HeaderKeyContentType, mediaType.ToString())
 		}
 		var compressType = httpRes.Body().CompressType()
-		if compressType != nil {
+		if compressType != nil && compressType.ContentEncoding() != raw.RawContentEncoding {
 			httpRes.H.Set(http.HeaderKeyContentEncoding, compressType.ContentEncoding())
 		}
 
-		if httpRes.Body().Len() > 0 {
+		var bodyLen = httpRes.Body().Len()
+		if bodyLen > 0 {
 			httpRes.SetContentLength()
+		} else if bodyLen == 0 {
+			httpRes.H.SetZeroContentLength()
 		} else {
+			// Body length is unknown e.g. stream compressed data, so send it chunk by chunk.
 			httpRes.H.SetTransferEncoding(http.HeaderValueChunked)
+			httpRes.SetChunkedBody()
 		}
 	} else {
 		httpRes.H.SetZeroContentLength()
@@ -86,6 +128,18 @@ func (h *V1) HandleOutcomeResponse(st protocol.Stream, httpReq *http.Request, ht
 
 	// httpRes.H.Set(HeaderKeyAccessControlAllowOrigin, "*")
 
+	var err = st.Connection().PacketSending(uint64(httpRes.Len()))
+	if err != nil {
+		// Peer used all its daily send quota, so just tell it why by a response without body and close the stream.
+		httpRes.Reinit()
+		httpRes.SetVersion(httpReq.Version())
+		httpRes.SetStatus(http.StatusTooManyRequestsCode, http.StatusTooManyRequestsPhrase)
+		httpRes.SetError(err)
+		httpRes.H.SetZeroContentLength()
+		st.Encode(httpRes)
+		st.Close()
+		return
+	}
 	st.Encode(httpRes)
 
 	if protocol.AppMode_Dev {
@@ -98,40 +152,10 @@ func (h *V1) HandleOutcomeResponse(st protocol.Stream, httpReq *http.Request, ht
 }
 
 // SendBidirectionalRequest use to handle outcoming HTTP request stream
-// It block caller until get response or error
+// It block caller until get response, http.DefaultClientTimeout reached or error occur.
+// Use http.Client to reuse connections, follow redirects and handle cookies.
 func SendBidirectionalRequest(conn protocol.Connection, service protocol.Service, httpReq *http.Request) (httpRes *http.Response, err protocol.Error) {
-	var st protocol.Stream
-	st, err = conn.OutcomeStream(service)
-	if err != nil {
-		return
-	}
-
-	_, err = st.Encode(httpReq)
-	if err != nil {
-		return
-	}
-
-	var streamStatus = st.State()
-	for {
-		var status = <-streamStatus
-		switch status {
-		case protocol.NetworkStatus_Timeout:
-			// err =
-		case protocol.NetworkStatus_ReceivedCompletely:
-			var res http.Response
-			res.Init()
-			_, err = res.Decode(st)
-			if err == nil {
-				err = res.GetError()
-			}
-			httpRes = &res
-		default:
-			continue
-		}
-		st.Close()
-		break
-	}
-	return
+	return http.RoundTrip(conn, service, httpReq, http.DefaultClientTimeout)
 }
 
 // SendUnidirectionalRequest use to send outcome HTTP request and don't expect any response.
@@ -164,3 +188,16 @@ func SendUnidirectionalRequest(conn protocol.Connection, service protocol.Servic
 	}
 	return
 }
+
+// responseHasBody report whether a response with given status code can have a body.
+// 1xx, 204 and 304 responses never have a body.
+func responseHasBody(statusCode string) bool {
+	if len(statusCode) > 0 && statusCode[0] == '1' {
+		return false
+	}
+	switch statusCode {
+	case http.StatusNoContentCode, http.StatusNotModifiedCode:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package hh
+
+import (
+	"github.com/GeniusesGroup/libgo/http/h2"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Protocol Standard - HTTP/2 : https://www.rfc-editor.org/rfc/rfc9113
+// V2 serve connections that start with HTTP/2 connection preface e.g. TLS with "h2" ALPN or h2c with prior knowledge.
+// Requests serve by V1 ServeHTTP, so services' ServeHTTP works unchanged.
+type V2 struct {
+	V1
+}
+
+// HandleIncomeRequest serve the transport stream as an HTTP/2 connection until it closed.
+func (h *V2) HandleIncomeRequest(st protocol.Stream) (err protocol.Error) {
+	var conn h2.Connection
+	conn.Init(st, &h.V1)
+	err = conn.Serve()
+	return
+}
@@ -0,0 +1,92 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package hh
+
+import (
+	"github.com/GeniusesGroup/libgo/compress/raw"
+	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/log"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// negotiateResponse select the response representation by request Accept header
+// and compress it by request Accept-Encoding header.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12
+func negotiateResponse(httpReq *http.Request, httpRes *http.Response) {
+	if representations, ok := httpRes.Codec.(http.Representations); ok {
+		if len(representations) > 1 {
+			httpRes.H.AddVary(http.HeaderKeyAcceptContent)
+		}
+		var codec protocol.Codec
+		if len(representations) > 0 {
+			codec = representations.Select(httpReq.H.Get(http.HeaderKeyAcceptContent))
+		}
+		if codec == nil {
+			httpRes.SetStatus(http.StatusNotAcceptableCode, http.StatusNotAcceptablePhrase)
+			httpRes.SetBody(nil)
+			return
+		}
+		httpRes.SetBody(codec)
+	}
+
+	compressResponse(httpReq, httpRes)
+}
+
+// compressResponse compress the response body by the best content encoding that both peer and OS support.
+func compressResponse(httpReq *http.Request, httpRes *http.Response) {
+	var body = httpRes.Codec
+	if body == nil || httpRes.H.Get(http.HeaderKeyContentEncoding) != "" {
+		return
+	}
+	if compressType := body.CompressType(); compressType != nil && compressType.ContentEncoding() != raw.RawContentEncoding {
+		// Service compressed it already e.g. precompressed www assets.
+		return
+	}
+	switch httpRes.StatusCode() {
+	case http.StatusNoContentCode, http.StatusPartialContentCode, http.StatusNotModifiedCode, http.StatusRangeNotSatisfiableCode:
+		// Ranges and their Content-Range indicate identity representation data.
+		return
+	}
+
+	var mediaType = httpRes.H.Get(http.HeaderKeyContentType)
+	if mediaType == "" && body.MediaType() != nil {
+		mediaType = body.MediaType().MediaType()
+	}
+	if http.IsCompressedMediaType(mediaType) {
+		return
+	}
+	// Len() -1 means unknown length e.g. stream data, so compress it.
+	var bodyLen = body.Len()
+	if bodyLen >= 0 && bodyLen < http.CompressMinLength {
+		return
+	}
+
+	var offers = http.CompressContentEncodings()
+	if len(offers) == 0 {
+		return
+	}
+	httpRes.H.AddVary(http.HeaderKeyAcceptEncoding)
+
+	// Like most servers, send identity even if peer doesn't accept it instead of 406 response,
+	// due to it is more useful for peer than an error.
+	var contentEncoding, _ = http.NegotiateContentEncoding(httpReq.H.Get(http.HeaderKeyAcceptEncoding), offers)
+	if contentEncoding == "" {
+		return
+	}
+
+	var compressType, err = protocol.OS.GetCompressTypeByContentEncoding(contentEncoding)
+	if err != nil {
+		return
+	}
+	var compressed protocol.Codec
+	compressed, err = compressType.Compress(body, protocol.CompressOptions{CompressLevel: protocol.CompressLevel_Default})
+	if err != nil || compressed == nil {
+		protocol.App.Log(log.WarnEvent(domainEnglish, "Can't compress response body by "+contentEncoding+" content encoding, send it as identity"))
+		return
+	}
+	httpRes.SetBody(compressed)
+	httpRes.H.Set(http.HeaderKeyContentEncoding, contentEncoding)
+	if etag := httpRes.H.Get(http.HeaderKeyETag); etag != "" {
+		httpRes.H.Set(http.HeaderKeyETag, http.ETagWithContentEncoding(etag, contentEncoding))
+	}
+}
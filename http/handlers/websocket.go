@@ -0,0 +1,71 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package hh
+
+import (
+	"github.com/GeniusesGroup/libgo/authorization"
+	"github.com/GeniusesGroup/libgo/http"
+	hs "github.com/GeniusesGroup/libgo/http/services"
+	"github.com/GeniusesGroup/libgo/http/ws"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// serveWebSocket upgrade the connection to WebSocket on service multiplexer path and serve sRPC services
+// call on it until the connection closed. Browsers can't open raw sRPC connections, so it is their way to call services
+// without HTTP overhead for each call.
+func (h *V1) serveWebSocket(st protocol.Stream, httpReq *http.Request, httpRes *http.Response) (err protocol.Error) {
+	if httpReq.URI().Path() != hs.MuxService_Path {
+		httpRes.SetStatus(http.StatusNotFoundCode, http.StatusNotFoundPhrase)
+		httpRes.SetProblem(&ErrNotFound, httpReq)
+		h.HandleOutcomeResponse(st, httpReq, httpRes)
+		return
+	}
+
+	var conn *ws.Conn
+	conn, err = ws.Upgrade(st, httpReq, httpRes, ws.UpgradeOptions{
+		Subprotocols: []string{ws.SubprotocolSRPC},
+		Compression:  true,
+	})
+	if err != nil {
+		httpRes.SetProblem(err, httpReq)
+		h.HandleOutcomeResponse(st, httpReq, httpRes)
+		return
+	}
+
+	for {
+		var callSt *ws.Stream
+		callSt, err = conn.AcceptStream()
+		if err != nil {
+			return
+		}
+		// Serve each call concurrently so a slow call don't block next calls on the connection.
+		// Connection concurrent streams quota bound the number of calls in serve.
+		var quotaErr = callSt.Connection().StreamOpened()
+		if quotaErr != nil {
+			callSt.SetError(quotaErr)
+			callSt.Close()
+			continue
+		}
+		go h.serveWebSocketCall(callSt)
+	}
+}
+
+func (h *V1) serveWebSocketCall(st *ws.Stream) {
+	defer st.Connection().StreamClosed()
+
+	var service = st.Service()
+	if service == nil {
+		// Stream error indicate why service not found.
+		st.Close()
+		return
+	}
+
+	var err = authorization.AuthorizeServiceCall(st, service)
+	if err == nil {
+		err = service.ServeSRPC(st)
+	}
+	if err != nil {
+		st.SetError(err)
+	}
+	st.Close()
+}
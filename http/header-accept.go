@@ -5,44 +5,214 @@ package http
 import (
 	"strconv"
 	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
 )
 
-// https://www.w3.org/Protocols/rfc2616/rfc2616-sec14.html#sec14.1
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12.4.2
 type mime struct {
-	media   string
+	media   string // media range e.g. "text/*" or coding e.g. "gzip" in lower case without any parameters
 	quality float64
 }
 
 // insertMime adds a mime to a list and keeps it sorted by quality.
+// Mimes with same quality keep their order in the header.
 func insertMime(l []mime, e mime) []mime {
 	for i, each := range l {
 		// if current mime has lower quality then insert before
 		if e.quality > each.quality {
-			left := append([]mime{}, l[0:i]...)
-			return append(append(left, e), l[i:]...)
+			l = append(l, mime{})
+			copy(l[i+1:], l[i:])
+			l[i] = e
+			return l
 		}
 	}
 	return append(l, e)
 }
 
 // sortMimes returns a list of mime sorted (desc) by its specified quality.
+// It can parse any header with weight like Accept, Accept-Encoding, Accept-Language, ...
+// Elements with invalid weight ignored.
 func sortMimes(accept string) (sorted []mime) {
 	for _, each := range strings.Split(accept, ",") {
-		typeAndQuality := strings.Split(strings.Trim(each, " "), ";")
-		if len(typeAndQuality) == 1 {
-			sorted = insertMime(sorted, mime{typeAndQuality[0], 1.0})
-		} else {
-			// take factor
-			parts := strings.Split(typeAndQuality[1], "=")
-			if len(parts) == 2 {
-				f, err := strconv.ParseFloat(parts[1], 64)
-				if err != nil {
-					// traceLogger.Printf("unable to parse quality in %s, %v", each, err)
-				} else {
-					sorted = insertMime(sorted, mime{typeAndQuality[0], f})
+		var params = strings.Split(each, ";")
+		var e = mime{
+			media:   strings.ToLower(strings.TrimSpace(params[0])),
+			quality: 1.0,
+		}
+		if e.media == "" {
+			continue
+		}
+		var valid = true
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if len(param) < 2 || (param[0] != 'q' && param[0] != 'Q') || param[1] != '=' {
+				// Other parameters of media range e.g. "charset=utf-8" don't change the quality.
+				continue
+			}
+			var q, err = strconv.ParseFloat(param[2:], 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			e.quality = q
+		}
+		if valid {
+			sorted = insertMime(sorted, e)
+		}
+	}
+	return
+}
+
+// NegotiateMediaType return index of the offer that best match the given Accept header value,
+// or -1 if none of them is acceptable. Empty accept means any media type is acceptable, so first offer returns.
+// Offers with same quality selected by their order, so put server preferred media types first.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12.5.1
+func NegotiateMediaType(accept string, offers []string) (best int) {
+	best = -1
+	if len(offers) == 0 {
+		return
+	}
+	if strings.TrimSpace(accept) == "" {
+		return 0
+	}
+
+	var ranges = sortMimes(accept)
+	var bestQuality float64
+	for i, offer := range offers {
+		offer = strings.ToLower(offer)
+		if semiColon := strings.IndexByte(offer, ';'); semiColon != -1 {
+			offer = strings.TrimSpace(offer[:semiColon])
+		}
+		var offerType, _, _ = strings.Cut(offer, "/")
+
+		// The most specific media range that match the offer indicate its quality.
+		var quality float64
+		var specificity = 0
+		for _, r := range ranges {
+			var s int
+			switch {
+			case r.media == offer:
+				s = 3
+			case r.media == "*/*":
+				s = 1
+			case strings.HasSuffix(r.media, "/*") && r.media[:len(r.media)-2] == offerType:
+				s = 2
+			default:
+				continue
+			}
+			if s > specificity {
+				specificity = s
+				quality = r.quality
+			}
+		}
+		if quality > bestQuality {
+			best = i
+			bestQuality = quality
+		}
+	}
+	return
+}
+
+// NegotiateContentEncoding return the offer that best match the given Accept-Encoding header value.
+// Empty best means identity i.e. don't compress. Offers with same quality selected by their order,
+// and any offer that has same quality as identity preferred on it.
+// It returns acceptable false if even identity not acceptable by the peer.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12.5.3
+func NegotiateContentEncoding(acceptEncoding string, offers []string) (best string, acceptable bool) {
+	acceptEncoding = strings.TrimSpace(acceptEncoding)
+	if acceptEncoding == "" {
+		// Peer doesn't ask for any coding, so identity is the only choice.
+		return "", true
+	}
+
+	var codings = sortMimes(acceptEncoding)
+	var identityQuality, anyQuality = -1.0, -1.0
+	for _, coding := range codings {
+		switch coding.media {
+		case "identity":
+			identityQuality = coding.quality
+		case "*":
+			anyQuality = coding.quality
+		}
+	}
+	if identityQuality < 0 {
+		// identity is always acceptable unless excluded explicitly or by "*;q=0".
+		identityQuality = 1
+		if anyQuality == 0 {
+			identityQuality = 0
+		}
+	}
+
+	var bestQuality float64
+	for _, offer := range offers {
+		offer = strings.ToLower(offer)
+		var quality = -1.0
+		for _, coding := range codings {
+			if coding.media == offer {
+				quality = coding.quality
+				break
+			}
+		}
+		if quality < 0 {
+			quality = anyQuality
+		}
+		if quality > bestQuality {
+			best = offer
+			bestQuality = quality
+		}
+	}
+
+	if best != "" && bestQuality >= identityQuality {
+		return best, true
+	}
+	return "", identityQuality > 0
+}
+
+// languageTags map primary subtag of language tags in Accept-Language to protocol.LanguageID.
+// https://www.iana.org/assignments/language-subtag-registry/language-subtag-registry
+var languageTags = map[string]protocol.LanguageID{
+	"fa": protocol.LanguagePersian,
+	"en": protocol.LanguageEnglish,
+	"ru": protocol.LanguageRussian,
+	"ar": protocol.LanguageArabic,
+}
+
+// NegotiateLanguage return the offer that best match the given Accept-Language header value by primary subtag
+// e.g. "en-US" match LanguageEnglish. Offers with same quality selected by their order, and first offer return
+// if none of them is acceptable, so always a language return if any offer exist.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12.5.4
+func NegotiateLanguage(acceptLanguage string, offers []protocol.LanguageID) (best protocol.LanguageID) {
+	if len(offers) == 0 {
+		return protocol.LanguageUnset
+	}
+	best = offers[0]
+
+	var ranges = sortMimes(acceptLanguage)
+	var bestQuality float64
+	for _, offer := range offers {
+		var quality = -1.0
+		var anyQuality = -1.0
+		for _, r := range ranges {
+			if r.media == "*" {
+				if anyQuality < 0 {
+					anyQuality = r.quality
 				}
+				continue
+			}
+			var primary, _, _ = strings.Cut(r.media, "-")
+			if languageTags[primary] == offer {
+				quality = r.quality
+				break
 			}
 		}
+		if quality < 0 {
+			quality = anyQuality
+		}
+		if quality > bestQuality {
+			best = offer
+			bestQuality = quality
+		}
 	}
 	return
 }
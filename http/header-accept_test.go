@@ -0,0 +1,93 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package http
+
+import (
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	var offers = []string{"application/json", "text/html; charset=utf-8", "application/srpc"}
+	var tests = []struct {
+		accept string
+		want   int
+	}{
+		{"", 0},
+		{"*/*", 0},
+		{"text/html", 1},
+		{"text/*, application/json;q=0.5", 1},
+		{"application/*;q=0.2, application/srpc", 2},
+		{"text/html;level=1;q=0.3, application/json;q=0.4", 0},
+		{"*/*;q=0.1, application/json;q=0", 1},
+		{"image/png", -1},
+		{"text/html;q=abc", -1},
+	}
+	for _, tt := range tests {
+		if got := NegotiateMediaType(tt.accept, offers); got != tt.want {
+			t.Errorf("NegotiateMediaType(%q) = %d, want %d", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestNegotiateContentEncoding(t *testing.T) {
+	var offers = []string{"br", "gzip", "deflate"}
+	var tests = []struct {
+		acceptEncoding string
+		want           string
+		acceptable     bool
+	}{
+		{"", "", true},
+		{"gzip, deflate, br", "br", true},
+		{"gzip;q=1.0, br;q=0.8", "gzip", true},
+		{"identity", "", true},
+		{"*", "br", true},
+		{"*;q=0.5, gzip", "gzip", true},
+		{"br;q=0, gzip;q=0", "", true},
+		{"identity;q=0.9, deflate;q=0.5", "", true},
+		{"identity;q=0, compress", "", false},
+		{"*;q=0", "", false},
+	}
+	for _, tt := range tests {
+		var got, acceptable = NegotiateContentEncoding(tt.acceptEncoding, offers)
+		if got != tt.want || acceptable != tt.acceptable {
+			t.Errorf("NegotiateContentEncoding(%q) = %q, %v, want %q, %v", tt.acceptEncoding, got, acceptable, tt.want, tt.acceptable)
+		}
+	}
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	var offers = []protocol.LanguageID{protocol.LanguageEnglish, protocol.LanguagePersian}
+	var tests = []struct {
+		acceptLanguage string
+		want           protocol.LanguageID
+	}{
+		{"", protocol.LanguageEnglish},
+		{"fa", protocol.LanguagePersian},
+		{"fa-IR, en;q=0.8", protocol.LanguagePersian},
+		{"en-US;q=0.5, fa;q=0.9", protocol.LanguagePersian},
+		{"de, *;q=0.1", protocol.LanguageEnglish},
+		{"ru", protocol.LanguageEnglish},
+		{"fa;q=0, *", protocol.LanguageEnglish},
+	}
+	for _, tt := range tests {
+		if got := NegotiateLanguage(tt.acceptLanguage, offers); got != tt.want {
+			t.Errorf("NegotiateLanguage(%q) = %d, want %d", tt.acceptLanguage, got, tt.want)
+		}
+	}
+	if got := NegotiateLanguage("fa", nil); got != protocol.LanguageUnset {
+		t.Errorf("NegotiateLanguage() without offers = %d, want LanguageUnset", got)
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	var h header
+	h.Init()
+	h.AddVary(HeaderKeyAcceptContent)
+	h.AddVary(HeaderKeyAcceptEncoding)
+	h.AddVary("accept-encoding")
+	if got := h.Get(HeaderKeyVary); got != "Accept, Accept-Encoding" {
+		t.Errorf("header.AddVary() = %q, want %q", got, "Accept, Accept-Encoding")
+	}
+}
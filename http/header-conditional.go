@@ -0,0 +1,137 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package http
+
+import (
+	"strings"
+	"time"
+)
+
+// ETagMatch report given etag match any entity-tag in a condition header value e.g. If-None-Match.
+// In weak comparison W/ prefix ignored and etags that a compressed response add its content encoding to them
+// e.g. "abc-gzip" match the original one.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-8.8.3.2
+func ETagMatch(condition, etag string, weak bool) bool {
+	condition = strings.TrimSpace(condition)
+	if condition == "" || etag == "" {
+		return false
+	}
+	if condition == "*" {
+		return true
+	}
+
+	var etagWeak = strings.HasPrefix(etag, "W/")
+	if !weak && etagWeak {
+		return false
+	}
+	var opaque = strings.TrimPrefix(etag, "W/")
+
+	for condition != "" {
+		var tag string
+		tag, condition = nextETag(condition)
+		if tag == "" {
+			continue
+		}
+		var tagWeak = strings.HasPrefix(tag, "W/")
+		if !weak && tagWeak {
+			continue
+		}
+		tag = strings.TrimPrefix(tag, "W/")
+		if tag == opaque {
+			return true
+		}
+		if weak && len(tag) > 2 && len(opaque) > 2 && strings.HasPrefix(tag, opaque[:len(opaque)-1]+"-") {
+			var contentEncoding = tag[len(opaque) : len(tag)-1]
+			for _, ce := range CompressContentEncodings() {
+				if contentEncoding == ce {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ETagWithContentEncoding return strong etag of the representation that encoded by given content encoding.
+// Weak etag return as is due to it is semantically equivalent.
+func ETagWithContentEncoding(etag, contentEncoding string) string {
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + contentEncoding + `"`
+}
+
+// nextETag return first entity-tag in a list of entity-tags and remain of the list.
+// entity-tag = [ weak ] opaque-tag and opaque-tag = DQUOTE *etagc DQUOTE, so it can't has a comma in its value.
+func nextETag(list string) (tag, remain string) {
+	var commaIndex = strings.IndexByte(list, Comma)
+	if commaIndex == -1 {
+		return strings.TrimSpace(list), ""
+	}
+	return strings.TrimSpace(list[:commaIndex]), list[commaIndex+1:]
+}
+
+// FormatTime return given time in HTTP-date format. Time in other location change to UTC.
+func FormatTime(t time.Time) string { return t.UTC().Format(TimeFormat) }
+
+// ParseTime parse given HTTP-date. Obsolete formats also accepted as recipient must.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-5.6.7
+func ParseTime(httpDate string) (t time.Time, ok bool) {
+	for _, layout := range [...]string{TimeFormat, time.RFC850, time.ANSIC} {
+		var goErr error
+		t, goErr = time.Parse(layout, httpDate)
+		if goErr == nil {
+			return t, true
+		}
+	}
+	return
+}
+
+// EvaluatePreconditions evaluate conditional request headers against the selected representation etag and
+// modified time by RFC order. Zero modified means unknown. It returns status code and phrase that must respond
+// instead of the representation e.g. 304 or 412, or empty status code to serve the request normally.
+// If-Range is not a precondition and check by RangeApplicable.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-13.2.2
+func (r *Request) EvaluatePreconditions(etag string, modified time.Time) (statusCode, reasonPhrase string) {
+	var method = r.Method()
+	var safe = method == MethodGET || method == MethodHEAD
+
+	if ifMatch := r.H.Get(HeaderKeyIfMatch); ifMatch != "" {
+		if !ETagMatch(ifMatch, etag, false) {
+			return StatusPreconditionFailedCode, StatusPreconditionFailedPhrase
+		}
+	} else if ifUnmodifiedSince, ok := ParseTime(r.H.Get(HeaderKeyIfUnmodifiedSince)); ok && !modified.IsZero() {
+		if modified.Truncate(time.Second).After(ifUnmodifiedSince) {
+			return StatusPreconditionFailedCode, StatusPreconditionFailedPhrase
+		}
+	}
+
+	if ifNoneMatch := r.H.Get(HeaderKeyIfNoneMatch); ifNoneMatch != "" {
+		if ETagMatch(ifNoneMatch, etag, true) {
+			if safe {
+				return StatusNotModifiedCode, StatusNotModifiedPhrase
+			}
+			return StatusPreconditionFailedCode, StatusPreconditionFailedPhrase
+		}
+	} else if ifModifiedSince, ok := ParseTime(r.H.Get(HeaderKeyIfModifiedSince)); ok && safe && !modified.IsZero() {
+		if !modified.Truncate(time.Second).After(ifModifiedSince) {
+			return StatusNotModifiedCode, StatusNotModifiedPhrase
+		}
+	}
+	return
+}
+
+// RangeApplicable report Range header must apply by If-Range header if exist.
+// If-Range entity-tag compare by strong comparison and HTTP-date must be exactly same as modified time.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-13.1.5
+func (r *Request) RangeApplicable(etag string, modified time.Time) bool {
+	var ifRange = strings.TrimSpace(r.H.Get(HeaderKeyIfRange))
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+		return ETagMatch(ifRange, etag, false)
+	}
+	var ifRangeTime, ok = ParseTime(ifRange)
+	return ok && !modified.IsZero() && modified.Truncate(time.Second).Equal(ifRangeTime)
+}
@@ -2,7 +2,12 @@
 
 package http
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/compress/raw"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
 
 type ContentEncodings string
 
@@ -36,3 +41,37 @@ func (h *header) ContentEncoding() (ce string, multiple bool) {
 func (h *header) SetContentEncoding(contentEncodings ...string) {
 	h.Sets(HeaderKeyContentEncoding, contentEncodings)
 }
+
+// CompressContentEncodings return content encodings of registered compress types that can use in
+// Accept-Encoding and Content-Encoding headers. raw compress type is identity in HTTP, so it is not in the list.
+func CompressContentEncodings() (contentEncodings []string) {
+	for _, ce := range protocol.OS.ContentEncodings() {
+		if ce != raw.RawContentEncoding {
+			contentEncodings = append(contentEncodings, ce)
+		}
+	}
+	return
+}
+
+// IsCompressedMediaType report data in given media type is compressed already e.g. images, videos, archives, ...
+// so compress it again just waste CPU.
+func IsCompressedMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(mediaType)
+	if semiColon := strings.IndexByte(mediaType, ';'); semiColon != -1 {
+		mediaType = strings.TrimSpace(mediaType[:semiColon])
+	}
+	switch {
+	case mediaType == "image/svg+xml", mediaType == "image/bmp", mediaType == "image/x-icon":
+		return false
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "video/"), strings.HasPrefix(mediaType, "audio/"):
+		return true
+	}
+	switch mediaType {
+	case "font/woff", "font/woff2", "application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+		"application/x-bzip2", "application/x-xz", "application/x-7z-compressed", "application/x-rar-compressed":
+		return true
+	}
+	// Media types of registered compress types e.g. "domain/gzip.protocol.data-structure"
+	var _, err = protocol.OS.GetCompressTypeByMediaType(mediaType)
+	return err == nil
+}
@@ -50,16 +50,13 @@ func (h *header) MarshalCookies(cookies []Cookie) {
 	// TODO::: make buffer by needed size.
 	var b strings.Builder
 	var ln = len(cookies)
-	var i int
-	for ; ; i++ {
+	for i := 0; i < ln; i++ {
+		if i > 0 {
+			b.WriteString(SemiColonSpace)
+		}
 		b.WriteString(cookies[i].Name)
 		b.WriteByte('=')
 		b.WriteString(cookies[i].Value)
-		if i < ln {
-			b.WriteString(SemiColonSpace)
-		} else {
-			break
-		}
 	}
 	h.Set(HeaderKeyCookie, b.String())
 }
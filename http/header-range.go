@@ -0,0 +1,145 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// ByteRange is a range of representation data. Last is inclusive like Range and Content-Range headers.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-14.1.2
+type ByteRange struct {
+	First uint64
+	Last  uint64
+}
+
+func (br ByteRange) Len() uint64 { return br.Last - br.First + 1 }
+
+// ContentRange return value of Content-Range header for the range of a representation with given size.
+func (br ByteRange) ContentRange(size uint64) string {
+	return HeaderValueBytes + " " + strconv.FormatUint(br.First, 10) + "-" + strconv.FormatUint(br.Last, 10) + "/" + strconv.FormatUint(size, 10)
+}
+
+// ByteRanges parse Range header for a representation with given size.
+// nil ranges without error means Range header not exist or must ignore e.g. invalid syntax, other range units or
+// more than MaxByteRanges ranges, so whole representation must serve.
+// It returns ErrRangeNotSatisfiable if none of the ranges overlap the representation.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-14.2
+func (h *header) ByteRanges(size uint64) (ranges []ByteRange, err protocol.Error) {
+	var rangeHeader = strings.TrimSpace(h.Get(HeaderKeyRange))
+	var unit, specs, found = strings.Cut(rangeHeader, "=")
+	if !found || !strings.EqualFold(strings.TrimSpace(unit), HeaderValueBytes) {
+		return
+	}
+
+	var specsNumber int
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		specsNumber++
+		if specsNumber > MaxByteRanges {
+			return nil, nil
+		}
+
+		var firstStr, lastStr, found = strings.Cut(spec, "-")
+		if !found {
+			return nil, nil
+		}
+		if firstStr == "" {
+			// suffix-range e.g. "-500" means last 500 bytes
+			var suffixLength, goErr = strconv.ParseUint(lastStr, 10, 64)
+			if goErr != nil {
+				return nil, nil
+			}
+			if suffixLength == 0 || size == 0 {
+				continue
+			}
+			if suffixLength > size {
+				suffixLength = size
+			}
+			ranges = append(ranges, ByteRange{First: size - suffixLength, Last: size - 1})
+			continue
+		}
+
+		var first, goErr = strconv.ParseUint(firstStr, 10, 64)
+		if goErr != nil {
+			return nil, nil
+		}
+		var last = size - 1
+		if lastStr != "" {
+			last, goErr = strconv.ParseUint(lastStr, 10, 64)
+			if goErr != nil || last < first {
+				return nil, nil
+			}
+			if last >= size {
+				last = size - 1
+			}
+		}
+		if first >= size {
+			continue
+		}
+		ranges = append(ranges, ByteRange{First: first, Last: last})
+	}
+
+	if specsNumber > 0 && len(ranges) == 0 {
+		err = &ErrRangeNotSatisfiable
+	}
+	return
+}
+
+// MarshalMultipartByteRanges make multipart/byteranges body of given ranges of data.
+// It returns the body and value of Content-Type header that has the boundary.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-14.6
+func MarshalMultipartByteRanges(data []byte, ranges []ByteRange, contentType string) (body []byte, multipartContentType string) {
+	var boundary = newBoundary()
+	var size = uint64(len(data))
+
+	var ln = len(boundary) + 6 // last boundary
+	for _, br := range ranges {
+		ln += len(boundary) + len(contentType) + int(br.Len()) + 96
+	}
+	body = make([]byte, 0, ln)
+	for _, br := range ranges {
+		body = append(body, "--"...)
+		body = append(body, boundary...)
+		body = append(body, CRLF...)
+		if contentType != "" {
+			body = append(body, HeaderKeyContentType...)
+			body = append(body, ColonSpace...)
+			body = append(body, contentType...)
+			body = append(body, CRLF...)
+		}
+		body = append(body, HeaderKeyContentRange...)
+		body = append(body, ColonSpace...)
+		body = append(body, br.ContentRange(size)...)
+		body = append(body, CRLF...)
+		body = append(body, CRLF...)
+		body = append(body, data[br.First:br.Last+1]...)
+		body = append(body, CRLF...)
+	}
+	body = append(body, "--"...)
+	body = append(body, boundary...)
+	body = append(body, "--"...)
+	body = append(body, CRLF...)
+
+	multipartContentType = "multipart/byteranges; boundary=" + boundary
+	return
+}
+
+// newBoundary return a random boundary to separate parts of a multipart body.
+func newBoundary() string {
+	var random [16]byte
+	var _, goErr = rand.Read(random[:])
+	if goErr != nil {
+		// Use a fixed boundary that is hard to be in data.
+		return "libgo-multipart-boundary-d41d8cd98f00b204e9800998ecf8427e"
+	}
+	return hex.EncodeToString(random[:])
+}
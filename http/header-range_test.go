@@ -0,0 +1,81 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package http
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderByteRanges(t *testing.T) {
+	var tests = []struct {
+		rangeHeader string
+		want        []ByteRange
+		err         bool
+	}{
+		{"", nil, false},
+		{"bytes=0-499", []ByteRange{{0, 499}}, false},
+		{"bytes=500-", []ByteRange{{500, 999}}, false},
+		{"bytes=-200", []ByteRange{{800, 999}}, false},
+		{"bytes=-2000", []ByteRange{{0, 999}}, false},
+		{"bytes=900-1500", []ByteRange{{900, 999}}, false},
+		{"Bytes=0-0, 10-19 ,-1", []ByteRange{{0, 0}, {10, 19}, {999, 999}}, false},
+		{"bytes=0-9, 2000-3000", []ByteRange{{0, 9}}, false},
+		{"bytes=1000-", nil, true},
+		{"bytes=-0", nil, true},
+		{"bytes=9-1", nil, false},
+		{"bytes=a-b", nil, false},
+		{"items=0-9", nil, false},
+		{"bytes=" + strings.Repeat("0-1,", MaxByteRanges+1), nil, false},
+	}
+	for _, tt := range tests {
+		var h header
+		h.Init()
+		h.Set(HeaderKeyRange, tt.rangeHeader)
+		var ranges, err = h.ByteRanges(1000)
+		if (err != nil) != tt.err {
+			t.Errorf("header.ByteRanges(%q) error = %v, want error %v", tt.rangeHeader, err, tt.err)
+			continue
+		}
+		if !reflect.DeepEqual(ranges, tt.want) {
+			t.Errorf("header.ByteRanges(%q) = %v, want %v", tt.rangeHeader, ranges, tt.want)
+		}
+	}
+}
+
+func TestMarshalMultipartByteRanges(t *testing.T) {
+	var data = []byte("0123456789")
+	var body, contentType = MarshalMultipartByteRanges(data, []ByteRange{{0, 1}, {8, 9}}, "text/plain")
+	var boundary = strings.TrimPrefix(contentType, "multipart/byteranges; boundary=")
+	var want = "--" + boundary + "\r\nContent-Type: text/plain\r\nContent-Range: bytes 0-1/10\r\n\r\n01\r\n" +
+		"--" + boundary + "\r\nContent-Type: text/plain\r\nContent-Range: bytes 8-9/10\r\n\r\n89\r\n" +
+		"--" + boundary + "--\r\n"
+	if boundary == "" || !bytes.Equal(body, []byte(want)) {
+		t.Errorf("MarshalMultipartByteRanges() = %q, want %q", body, want)
+	}
+}
+
+func TestETagMatch(t *testing.T) {
+	var tests = []struct {
+		condition string
+		etag      string
+		weak      bool
+		want      bool
+	}{
+		{`"abc"`, `"abc"`, false, true},
+		{`"xyz", "abc"`, `"abc"`, false, true},
+		{`*`, `"abc"`, false, true},
+		{`W/"abc"`, `"abc"`, false, false},
+		{`W/"abc"`, `"abc"`, true, true},
+		{`"abc"`, `W/"abc"`, true, true},
+		{`"abc"`, `"abcd"`, true, false},
+		{``, `"abc"`, true, false},
+	}
+	for _, tt := range tests {
+		if got := ETagMatch(tt.condition, tt.etag, tt.weak); got != tt.want {
+			t.Errorf("ETagMatch(%q, %q, %v) = %v, want %v", tt.condition, tt.etag, tt.weak, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package http
+
+import "strings"
+
+// AddVary add given request header key to Vary header if not exist already.
+// Use it when response content select by value of that request header e.g. Accept-Encoding.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-12.5.5
+func (h *header) AddVary(key string) {
+	var vary = h.Get(HeaderKeyVary)
+	if vary == "" {
+		h.Set(HeaderKeyVary, key)
+		return
+	}
+	for _, each := range strings.Split(vary, ",") {
+		each = strings.TrimSpace(each)
+		if each == "*" || strings.EqualFold(each, key) {
+			return
+		}
+	}
+	h.Set(HeaderKeyVary, vary+", "+key)
+}
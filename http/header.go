@@ -98,6 +98,16 @@ func (h *header) Del(key string) {
 	delete(h.headers, key)
 }
 
+// Range calls f for each value of each header key in no specific order.
+// Multi values keys like Set-Cookie call f once per value.
+func (h *header) Range(f func(key, value string)) {
+	for key, values := range h.headers {
+		for _, value := range values {
+			f(key, value)
+		}
+	}
+}
+
 // Exclude eliminate headers by given keys!
 func (h *header) Exclude(exclude map[string]bool) {
 	for key := range exclude {
@@ -165,6 +175,39 @@ func (h *header) Len() (ln int) {
 ********** Other methods **********
  */
 
+// CanonicalHeaderKey returns the canonical format of the header key.
+// The canonicalization converts the first letter and any letter following a hyphen to upper case; the rest are converted to lowercase.
+// e.g. "content-type" canonical key is "Content-Type". Key with any invalid character returned without any change.
+func CanonicalHeaderKey(key string) string {
+	var upper = true
+	var needChange bool
+	for i := 0; i < len(key); i++ {
+		var c = key[i]
+		if c == ' ' || c >= 0x7f {
+			return key
+		}
+		if (upper && 'a' <= c && c <= 'z') || (!upper && 'A' <= c && c <= 'Z') {
+			needChange = true
+		}
+		upper = c == '-'
+	}
+	if !needChange {
+		return key
+	}
+
+	var canonical = []byte(key)
+	upper = true
+	for i, c := range canonical {
+		if upper && 'a' <= c && c <= 'z' {
+			canonical[i] = c - ('a' - 'A')
+		} else if !upper && 'A' <= c && c <= 'Z' {
+			canonical[i] = c + ('a' - 'A')
+		}
+		upper = c == '-'
+	}
+	return string(canonical)
+}
+
 // unmarshal parses and decodes data of given httpPacket(without first line) to (h *header).
 // This method not respect to some RFCs like field-name in RFC7230, ... due to be more liberal in what it accept!
 // In some bad packet may occur panic, handle panic by recover otherwise app will crash and exit!
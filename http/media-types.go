@@ -4,6 +4,7 @@ package http
 
 import (
 	"github.com/GeniusesGroup/libgo/detail"
+	er "github.com/GeniusesGroup/libgo/error"
 	"github.com/GeniusesGroup/libgo/mediatype"
 	"github.com/GeniusesGroup/libgo/protocol"
 )
@@ -12,6 +13,10 @@ var (
 	MediaType         mediaType
 	MediaTypeRequest  mediaTypeRequest
 	MediaTypeResponse mediaTypeResponse
+
+	MediaTypeURLEncodedForm mediaTypeURLEncodedForm
+	MediaTypeMultipartForm  mediaTypeMultipartForm
+	MediaTypeProblem        mediaTypeProblem
 )
 
 func init() {
@@ -28,6 +33,33 @@ func init() {
 
 	MediaTypeResponse.Init("application/http; response")
 	MediaTypeResponse.SetDetail(protocol.LanguageEnglish, domainEnglish, "Hypertext Transfer Protocol Response", "", "", "", []string{})
+
+	MediaTypeURLEncodedForm.Init("application/x-www-form-urlencoded")
+	MediaTypeURLEncodedForm.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"URL Encoded Form",
+		"Form data as name and value pairs that percent-encoded and separated by '&'",
+		"",
+		"",
+		[]string{})
+	mediatype.RegisterMediaType(&MediaTypeURLEncodedForm)
+
+	MediaTypeMultipartForm.Init("multipart/form-data")
+	MediaTypeMultipartForm.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Multipart Form",
+		"Form data as parts separated by a boundary that each part can be a file with its own media type",
+		"",
+		"",
+		[]string{})
+	mediatype.RegisterMediaType(&MediaTypeMultipartForm)
+
+	MediaTypeProblem.Init(er.ProblemMediaType)
+	MediaTypeProblem.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Problem Details",
+		"Details of an error in an HTTP response as a JSON object to let clients know what happened without define new response formats",
+		"",
+		"",
+		[]string{})
+	mediatype.RegisterMediaType(&MediaTypeProblem)
 }
 
 type mediaType struct {
@@ -77,3 +109,51 @@ func (m *mediaTypeResponse) IssueDate() protocol.Time            { return nil }
 func (m *mediaTypeResponse) ExpiryDate() protocol.Time           { return nil }
 func (m *mediaTypeResponse) ExpireInFavorOf() protocol.MediaType { return nil }
 func (m *mediaTypeResponse) Fields() []protocol.Field            { return nil }
+
+type mediaTypeURLEncodedForm struct {
+	detail.DS
+	mediatype.MT
+}
+
+//libgo:impl protocol.MediaType
+func (m *mediaTypeURLEncodedForm) FileExtension() string           { return "" }
+func (m *mediaTypeURLEncodedForm) Status() protocol.SoftwareStatus { return protocol.Software_PreAlpha }
+func (m *mediaTypeURLEncodedForm) ReferenceURI() string {
+	return "https://url.spec.whatwg.org/#application/x-www-form-urlencoded"
+}
+func (m *mediaTypeURLEncodedForm) IssueDate() protocol.Time            { return nil }
+func (m *mediaTypeURLEncodedForm) ExpiryDate() protocol.Time           { return nil }
+func (m *mediaTypeURLEncodedForm) ExpireInFavorOf() protocol.MediaType { return nil }
+func (m *mediaTypeURLEncodedForm) Fields() []protocol.Field            { return nil }
+
+type mediaTypeMultipartForm struct {
+	detail.DS
+	mediatype.MT
+}
+
+//libgo:impl protocol.MediaType
+func (m *mediaTypeMultipartForm) FileExtension() string           { return "" }
+func (m *mediaTypeMultipartForm) Status() protocol.SoftwareStatus { return protocol.Software_PreAlpha }
+func (m *mediaTypeMultipartForm) ReferenceURI() string {
+	return "https://www.iana.org/assignments/media-types/multipart/form-data"
+}
+func (m *mediaTypeMultipartForm) IssueDate() protocol.Time            { return nil }
+func (m *mediaTypeMultipartForm) ExpiryDate() protocol.Time           { return nil }
+func (m *mediaTypeMultipartForm) ExpireInFavorOf() protocol.MediaType { return nil }
+func (m *mediaTypeMultipartForm) Fields() []protocol.Field            { return nil }
+
+type mediaTypeProblem struct {
+	detail.DS
+	mediatype.MT
+}
+
+//libgo:impl protocol.MediaType
+func (m *mediaTypeProblem) FileExtension() string           { return "" }
+func (m *mediaTypeProblem) Status() protocol.SoftwareStatus { return protocol.Software_PreAlpha }
+func (m *mediaTypeProblem) ReferenceURI() string {
+	return "https://datatracker.ietf.org/doc/html/rfc7807"
+}
+func (m *mediaTypeProblem) IssueDate() protocol.Time            { return nil }
+func (m *mediaTypeProblem) ExpiryDate() protocol.Time           { return nil }
+func (m *mediaTypeProblem) ExpireInFavorOf() protocol.MediaType { return nil }
+func (m *mediaTypeProblem) Fields() []protocol.Field            { return nil }
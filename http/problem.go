@@ -0,0 +1,101 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"strings"
+
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Problem is application/problem+json codec to send errors as RFC 7807 problem details in response body.
+// https://datatracker.ietf.org/doc/html/rfc7807
+type Problem struct {
+	er.Problem
+	data []byte
+}
+
+// Init fill the problem by the error details in the given language and encode it.
+func (p *Problem) Init(err protocol.Error, lang protocol.LanguageID, status int, instance string) {
+	p.Problem.Init(err, lang, status, instance)
+	p.data = p.Problem.Marshal()
+}
+
+//libgo:impl protocol.Codec
+func (p *Problem) MediaType() protocol.MediaType       { return &MediaTypeProblem }
+func (p *Problem) CompressType() protocol.CompressType { return nil }
+func (p *Problem) Len() int                            { return len(p.data) }
+func (p *Problem) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	if source.Len() > MaxProblemSize {
+		return 0, &ErrPacketTooLong
+	}
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return p.Unmarshal(data)
+}
+func (p *Problem) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	return destination.Unmarshal(p.data)
+}
+func (p *Problem) Marshal() (data []byte, err protocol.Error) { return p.data, nil }
+func (p *Problem) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return append(data, p.data...), nil
+}
+func (p *Problem) Unmarshal(data []byte) (n int, err protocol.Error) {
+	if len(data) > MaxProblemSize {
+		return 0, &ErrPacketTooLong
+	}
+	err = p.Problem.Unmarshal(data)
+	if err != nil {
+		return
+	}
+	p.data = data
+	return len(data), nil
+}
+func (p *Problem) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = p.Unmarshal(data)
+	return
+}
+
+// SetProblem set the error to the response as application/problem+json body in the language that request accept.
+// Set an error status before call it to add it to the problem, otherwise the response status set to 500 Internal Server Error
+// e.g. when a service return an error without set the status.
+// Error ID header also set for peers that don't decode the body.
+func (r *Response) SetProblem(err protocol.Error, req *Request) {
+	var details = err.Details()
+	var langs = make([]protocol.LanguageID, 0, len(details))
+	for _, detail := range details {
+		langs = append(langs, detail.Language())
+	}
+	var lang = NegotiateLanguage(req.H.Get(HeaderKeyAcceptLanguage), langs)
+	var status, _ = r.GetStatusCode()
+	if status < 400 {
+		status = StatusInternalServerError
+		r.SetStatus(StatusInternalServerErrorCode, StatusInternalServerErrorPhrase)
+	}
+
+	var problem Problem
+	problem.Init(err, lang, int(status), req.URI().Path())
+	r.SetError(err)
+	r.H.Set(HeaderKeyContentType, er.ProblemMediaType)
+	r.H.AddVary(HeaderKeyAcceptLanguage)
+	r.SetBody(&problem)
+}
+
+// isProblem report the response body is application/problem+json.
+func (r *Response) isProblem() bool {
+	return strings.HasPrefix(r.H.Get(HeaderKeyContentType), er.ProblemMediaType)
+}
+
+// getProblemError decode the response body as problem details and return its registered error.
+func (r *Response) getProblemError() (err protocol.Error) {
+	var problem Problem
+	_, err = problem.Decode(&r.body)
+	if err != nil {
+		return
+	}
+	return problem.RegisteredError(protocol.App)
+}
@@ -0,0 +1,35 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	var data = []byte(`{"type":"about:blank","title":"Not Found","status":404,"instance":"/m","mediaType":"domain/example.com; type=error; name=not_found","internal":true}`)
+	var p Problem
+	var n, err = p.Unmarshal(data)
+	if err != nil || n != len(data) {
+		t.Fatalf("Problem.Unmarshal() = %d, %v", n, err)
+	}
+	if p.Status != 404 || p.Title != "Not Found" || p.Instance != "/m" || !p.Internal || p.Temporary {
+		t.Errorf("Problem.Unmarshal() = %+v", p.Problem)
+	}
+	if got := p.ErrorMediaType(); got != "domain/example.com; type=error; name=not_found" {
+		t.Errorf("Problem.ErrorMediaType() = %q", got)
+	}
+
+	var encoded []byte
+	encoded, err = p.MarshalTo([]byte("x"))
+	if err != nil || string(encoded[1:]) != string(data) || p.Len() != len(data) {
+		t.Errorf("Problem.MarshalTo() = %q, %v", encoded, err)
+	}
+
+	if _, err = p.Unmarshal([]byte(`{"title":"no type"}`)); err != nil || p.Type != "about:blank" {
+		t.Errorf("Problem.Unmarshal() without type = %q, %v, want about:blank type", p.Type, err)
+	}
+	if _, err = p.Unmarshal([]byte(`{"type":`)); err == nil {
+		t.Errorf("Problem.Unmarshal() malformed JSON must fail")
+	}
+}
@@ -0,0 +1,50 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package http
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Representations is a body that hold the same resource in several media types e.g. JSON and sRPC.
+// Services set it as response body and let the handler select one of them by the request Accept header.
+// Put server preferred representation first. Without any negotiation it act as its first representation.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-3.2
+type Representations []protocol.Codec
+
+// Select return the representation that best match the given Accept header value or nil if none acceptable.
+func (r Representations) Select(accept string) (codec protocol.Codec) {
+	var offers = make([]string, len(r))
+	for i, c := range r {
+		var mediaType = c.MediaType()
+		if mediaType != nil {
+			offers[i] = mediaType.MediaType()
+		}
+	}
+	var best = NegotiateMediaType(accept, offers)
+	if best == -1 {
+		return nil
+	}
+	return r[best]
+}
+
+//libgo:impl protocol.Codec
+func (r Representations) MediaType() protocol.MediaType       { return r[0].MediaType() }
+func (r Representations) CompressType() protocol.CompressType { return r[0].CompressType() }
+func (r Representations) Len() int                            { return r[0].Len() }
+func (r Representations) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	return r[0].Decode(source)
+}
+func (r Representations) Encode(destination protocol.Codec) (n int, err protocol.Error) {
+	return r[0].Encode(destination)
+}
+func (r Representations) Marshal() (data []byte, err protocol.Error) { return r[0].Marshal() }
+func (r Representations) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return r[0].MarshalTo(data)
+}
+func (r Representations) Unmarshal(data []byte) (n int, err protocol.Error) {
+	return r[0].Unmarshal(data)
+}
+func (r Representations) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	return r[0].UnmarshalFrom(data)
+}
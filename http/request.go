@@ -46,6 +46,11 @@ func (r *Request) SetMethod(method string)     { r.method = method }
 func (r *Request) SetVersion(version string)   { r.version = version }
 func (r *Request) Header() protocol.HTTPHeader { return &r.H }
 
+// SetIncomeBody set given fully received body e.g. from other protocols like HTTP/2 that frame body in their own way.
+func (r *Request) SetIncomeBody(body []byte) (err protocol.Error) {
+	return r.setReadedIncomeBody(body, &r.H)
+}
+
 //libgo:impl protocol.Codec
 func (r *Request) MediaType() protocol.MediaType       { return &MediaTypeRequest }
 func (r *Request) CompressType() protocol.CompressType { return nil }
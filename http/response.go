@@ -46,6 +46,11 @@ func (r *Response) SetVersion(version string)     { r.version = version }
 func (r *Response) SetStatus(code, phrase string) { r.statusCode = code; r.reasonPhrase = phrase }
 func (r *Response) Header() protocol.HTTPHeader   { return &r.H }
 
+// SetIncomeBody set given fully received body e.g. from other protocols like HTTP/2 that frame body in their own way.
+func (r *Response) SetIncomeBody(body []byte) (err protocol.Error) {
+	return r.setReadedIncomeBody(body, &r.H)
+}
+
 // GetStatusCode get status code as uit16
 func (r *Response) GetStatusCode() (code uint16, err protocol.Error) {
 	// TODO::: don't use strconv for such simple task
@@ -56,8 +61,15 @@ func (r *Response) GetStatusCode() (code uint16, err protocol.Error) {
 	return uint16(c), nil
 }
 
-// GetError return related protocol.Error in header of the Response
+// GetError return related protocol.Error in application/problem+json body or in header of the Response
 func (r *Response) GetError() (err protocol.Error) {
+	if r.isProblem() {
+		err = r.getProblemError()
+		if err != nil {
+			return
+		}
+	}
+
 	var errIDString = r.H.Get(HeaderKeyErrorID)
 	var errID, _ = strconv.ParseUint(errIDString, 10, 64)
 	if errID == 0 {
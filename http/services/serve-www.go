@@ -3,6 +3,9 @@
 package hs
 
 import (
+	"strconv"
+
+	"github.com/GeniusesGroup/libgo/compress/raw"
 	"github.com/GeniusesGroup/libgo/detail"
 	"github.com/GeniusesGroup/libgo/http"
 	"github.com/GeniusesGroup/libgo/mediatype"
@@ -47,21 +50,141 @@ func (s *serveWWWService) CRUDType() protocol.CRUD     { return protocol.CRUD_Al
 func (s *serveWWWService) UserType() protocol.UserType { return protocol.UserType_All }
 
 // ServeWWW will serve WWW assets to request
+// It support conditional requests by ETag and Last-Modified and range requests.
 func (s *serveWWWService) ServeHTTP(stream protocol.Stream, httpReq *http.Request, httpRes *http.Response) (err protocol.Error) {
 	var reqFile, _ = s.WWW.GUI.FileByPath(httpReq.URI().Path())
+	var mainHTML bool
 	if reqFile == nil {
 		// TODO::: SSR to serve-to-robots
 		// TODO::: Have default error pages and can get customizes!
 		// Send beauty HTML response in http error situation like 500, 404, ...
 
 		const supportedLang = "en" // TODO::: get from header
-		reqFile, err = s.WWW.MainHTMLDir.File(supportedLang)
-		// if err != nil {
-		// TODO::: check other user language and at the end send better error
-		// }
+		// TODO::: check other user language on error and at the end send better error
+		reqFile, _ = s.WWW.MainHTMLDir.File(supportedLang)
+		mainHTML = true
+	}
+	if reqFile == nil {
+		// Not found response served to the peer, so it isn't a service error.
+		httpRes.SetStatus(http.StatusNotFoundCode, http.StatusNotFoundPhrase)
+		return nil
+	}
+
+	var asset = s.WWW.Asset(reqFile)
+	var body = reqFile.Data()
+	var etag = asset.ETag
+	if len(asset.Precompressed) > 0 {
+		httpRes.H.AddVary(http.HeaderKeyAcceptEncoding)
+		// Ranges and their Content-Range indicate identity representation data, so serve them from the file itself.
+		if httpReq.H.Get(http.HeaderKeyRange) == "" || mainHTML {
+			var contentEncoding, _ = http.NegotiateContentEncoding(httpReq.H.Get(http.HeaderKeyAcceptEncoding), asset.PrecompressedContentEncodings())
+			if precompressed := asset.PrecompressedByContentEncoding(contentEncoding); precompressed != nil {
+				body = precompressedBody{
+					Codec:        precompressed.File.Data(),
+					mediaType:    body.MediaType(),
+					compressType: precompressed.CompressType,
+				}
+				// Each content coding is a different representation, so must have different strong entity tag.
+				etag = http.ETagWithContentEncoding(etag, contentEncoding)
+			}
+		}
+	}
+	httpRes.H.Set(http.HeaderKeyETag, etag)
+	if !asset.Modified.IsZero() {
+		httpRes.H.Set(http.HeaderKeyLastModified, http.FormatTime(asset.Modified))
+	}
+	if asset.Immutable && !mainHTML {
+		httpRes.H.Set(http.HeaderKeyCacheControl, "public, max-age=31536000, immutable")
+	} else {
+		// Path data can change by new version of the GUI, so cache it but always revalidate by ETag.
+		httpRes.H.Set(http.HeaderKeyCacheControl, "no-cache")
+	}
+
+	var statusCode, reasonPhrase = httpReq.EvaluatePreconditions(etag, asset.Modified)
+	if statusCode != "" {
+		httpRes.SetStatus(statusCode, reasonPhrase)
+		return
+	}
+
+	if mainHTML {
+		// main HTML serve for any unknown path, so ranges of it is meaningless.
+		httpRes.SetStatus(http.StatusOKCode, http.StatusOKPhrase)
+		httpRes.SetBody(body)
+		return
+	}
+
+	httpRes.H.Set(http.HeaderKeyAcceptRanges, http.HeaderValueBytes)
+	if httpReq.Method() != http.MethodGET || httpReq.H.Get(http.HeaderKeyRange) == "" ||
+		!httpReq.RangeApplicable(asset.ETag, asset.Modified) {
+		httpRes.SetStatus(http.StatusOKCode, http.StatusOKPhrase)
+		httpRes.SetBody(body)
+		return
 	}
-	httpRes.SetStatus(http.StatusOKCode, http.StatusOKPhrase)
-	httpRes.H.Set(http.HeaderKeyCacheControl, "max-age=31536000, immutable")
-	httpRes.SetBody(reqFile.Data())
+
+	err = s.serveRanges(reqFile, httpReq, httpRes)
 	return
 }
+
+// serveRanges serve requested ranges of the file as single part or multipart/byteranges response.
+// https://datatracker.ietf.org/doc/html/rfc9110#section-14
+func (s *serveWWWService) serveRanges(reqFile protocol.File, httpReq *http.Request, httpRes *http.Response) (err protocol.Error) {
+	var data []byte
+	data, err = reqFile.Data().Marshal()
+	if err != nil {
+		return
+	}
+	var size = uint64(len(data))
+
+	var ranges []http.ByteRange
+	ranges, err = httpReq.H.ByteRanges(size)
+	if err != nil {
+		httpRes.SetStatus(http.StatusRangeNotSatisfiableCode, http.StatusRangeNotSatisfiablePhrase)
+		httpRes.H.Set(http.HeaderKeyContentRange, http.HeaderValueBytes+" */"+strconv.FormatUint(size, 10))
+		// Peer can't do anything about it, so don't return it as service error.
+		return nil
+	}
+	if ranges == nil {
+		httpRes.SetStatus(http.StatusOKCode, http.StatusOKPhrase)
+		httpRes.SetBody(reqFile.Data())
+		return
+	}
+
+	var contentType string
+	if mediaType := reqFile.Data().MediaType(); mediaType != nil {
+		contentType = mediaType.ToString()
+	}
+
+	var body []byte
+	if len(ranges) == 1 {
+		var br = ranges[0]
+		body = data[br.First : br.Last+1]
+		httpRes.H.Set(http.HeaderKeyContentRange, br.ContentRange(size))
+	} else {
+		var multipartContentType string
+		body, multipartContentType = http.MarshalMultipartByteRanges(data, ranges, contentType)
+		httpRes.H.Set(http.HeaderKeyContentType, multipartContentType)
+	}
+
+	var bodyCodec protocol.Codec
+	bodyCodec, err = raw.RAW.DecompressFromSlice(body)
+	if err != nil {
+		return
+	}
+	if len(ranges) == 1 && contentType != "" {
+		httpRes.H.Set(http.HeaderKeyContentType, contentType)
+	}
+	httpRes.SetStatus(http.StatusPartialContentCode, http.StatusPartialContentPhrase)
+	httpRes.SetBody(bodyCodec)
+	return
+}
+
+// precompressedBody is data of a precompressed file that report media type of the original file and the compress type of it,
+// so the handler set Content-Type and Content-Encoding headers by them and don't compress it again.
+type precompressedBody struct {
+	protocol.Codec
+	mediaType    protocol.MediaType
+	compressType protocol.CompressType
+}
+
+func (b precompressedBody) MediaType() protocol.MediaType       { return b.mediaType }
+func (b precompressedBody) CompressType() protocol.CompressType { return b.compressType }
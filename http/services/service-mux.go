@@ -3,6 +3,7 @@
 package hs
 
 import (
+	"github.com/GeniusesGroup/libgo/authorization"
 	"github.com/GeniusesGroup/libgo/detail"
 	"github.com/GeniusesGroup/libgo/http"
 	"github.com/GeniusesGroup/libgo/mediatype"
@@ -61,13 +62,20 @@ func (ser *muxService) ServeHTTP(st protocol.Stream, httpReq *http.Request, http
 		service, err = protocol.App.GetServiceByID(protocol.ID(serviceID))
 		if err != nil {
 			httpRes.SetStatus(http.StatusNotFoundCode, http.StatusNotFoundPhrase)
-			httpRes.SetError(&ErrNotFound)
+			httpRes.SetProblem(&ErrNotFound, httpReq)
 			// err = &ErrNotFound
 			return
 		}
 	} else {
 		httpRes.SetStatus(http.StatusBadRequestCode, http.StatusBadRequestPhrase)
-		httpRes.SetError(err)
+		httpRes.SetProblem(err, httpReq)
+		return
+	}
+
+	err = authorization.AuthorizeService(st, service)
+	if err != nil {
+		httpRes.SetStatus(http.StatusForbiddenCode, http.StatusForbiddenPhrase)
+		httpRes.SetProblem(err, httpReq)
 		return
 	}
 
@@ -79,7 +87,7 @@ func (ser *muxService) ServeHTTP(st protocol.Stream, httpReq *http.Request, http
 	// TODO::: can't easily call service and must schedule it by its weight.
 	err = service.ServeHTTP(st, httpReq, httpRes)
 	if err != nil {
-		httpRes.SetError(err)
+		httpRes.SetProblem(err, httpReq)
 	}
 	return
 }
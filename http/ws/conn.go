@@ -0,0 +1,311 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/timer"
+)
+
+// Conn is a WebSocket connection on an upgraded transport stream.
+// ReadMessage must call just by one goroutine, but WriteMessage, Ping and Close are safe to call concurrently.
+type Conn struct {
+	transport      protocol.Stream
+	isClient       bool
+	subprotocol    string
+	deflate        *permessageDeflate
+	maxMessageSize int
+	maxFrameSize   int
+
+	readBuf []byte // received data that not consumed as frame yet
+
+	writeMutex sync.Mutex
+	closeSent  bool // guard by writeMutex
+	closeOnce  sync.Once
+	closed     chan struct{}
+}
+
+func (c *Conn) init(transport protocol.Stream, isClient bool, maxMessageSize, maxFrameSize int) {
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultMaxMessageSize
+	}
+	if maxFrameSize <= 0 {
+		maxFrameSize = DefaultMaxFrameSize
+	}
+	c.transport = transport
+	c.isClient = isClient
+	c.maxMessageSize = maxMessageSize
+	c.maxFrameSize = maxFrameSize
+	c.closed = make(chan struct{})
+}
+
+// Subprotocol return negotiated subprotocol in opening handshake or empty string if nothing negotiated.
+func (c *Conn) Subprotocol() string { return c.subprotocol }
+
+// Compressed report permessage-deflate negotiated in opening handshake.
+func (c *Conn) Compressed() bool { return c.deflate != nil }
+
+// Transport return the underlying transport stream.
+func (c *Conn) Transport() protocol.Stream { return c.transport }
+
+// ReadMessage block until a whole data message received. Control frames receive in between handle internally:
+// ping answer by pong and close frame answer by close frame and ErrClosed return.
+func (c *Conn) ReadMessage() (mt MessageType, data []byte, err protocol.Error) {
+	var started, compressed bool
+	for {
+		var fh frameHeader
+		var payload []byte
+		fh, payload, err = c.readFrame()
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		if fh.opcode.isControl() {
+			err = c.handleControl(fh.opcode, payload)
+			if err != nil {
+				return
+			}
+			continue
+		}
+
+		if fh.opcode == opcodeContinuation {
+			if !started || fh.rsv1 {
+				err = &ErrProtocol
+				c.fail(err)
+				return
+			}
+		} else {
+			if started {
+				err = &ErrProtocol
+				c.fail(err)
+				return
+			}
+			started = true
+			mt = MessageType(fh.opcode)
+			compressed = fh.rsv1
+		}
+
+		if len(data)+len(payload) > c.maxMessageSize {
+			err = &ErrMessageTooLarge
+			c.fail(err)
+			return
+		}
+		data = append(data, payload...)
+		if fh.fin {
+			break
+		}
+	}
+
+	if compressed {
+		data, err = c.deflate.decompress(data, c.maxMessageSize)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+	}
+	if mt == MessageType_Text && !utf8.Valid(data) {
+		err = &ErrInvalidPayload
+		c.fail(err)
+	}
+	return
+}
+
+// WriteMessage send given data as a message. Message fragment to many frames if it is larger than max frame size.
+func (c *Conn) WriteMessage(mt MessageType, data []byte) (err protocol.Error) {
+	var rsv1 bool
+	if c.deflate != nil {
+		data, err = c.deflate.compress(data)
+		if err != nil {
+			return
+		}
+		rsv1 = true
+	}
+
+	var op = opcode(mt)
+	var buf = make([]byte, 0, len(data)+(len(data)/c.maxFrameSize+1)*maxFrameHeaderLength)
+	for {
+		var ln = len(data)
+		if ln > c.maxFrameSize {
+			ln = c.maxFrameSize
+		}
+		var fin = ln == len(data)
+		buf = appendFrame(buf, fin, rsv1, op, data[:ln], c.newMask())
+		data = data[ln:]
+		if fin {
+			break
+		}
+		op = opcodeContinuation
+		rsv1 = false // RSV1 just set on the first frame of a compressed message
+	}
+	return c.write(buf, false)
+}
+
+// Ping send a ping frame with given application data. Application data can't be larger than 125 bytes.
+func (c *Conn) Ping(data []byte) (err protocol.Error) {
+	if len(data) > maxControlPayloadLength {
+		return &ErrProtocol
+	}
+	return c.write(appendFrame(nil, true, false, opcodePing, data, c.newMask()), false)
+}
+
+// Close start the closing handshake by send close frame with given code and reason.
+// Peer close frame receive by ReadMessage that close the transport,
+// otherwise transport close after DefaultCloseTimeout.
+// https://datatracker.ietf.org/doc/html/rfc6455#section-7.1.2
+func (c *Conn) Close(code CloseCode, reason string) (err protocol.Error) {
+	if !code.validToSend() || len(reason) > maxControlPayloadLength-2 || !utf8.ValidString(reason) {
+		code = CloseCode_InternalError
+		reason = ""
+	}
+	var payload = binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(reason)), uint16(code))
+	payload = append(payload, reason...)
+	err = c.write(appendFrame(nil, true, false, opcodeClose, payload, c.newMask()), true)
+	if err != nil {
+		c.closeTransport()
+		return
+	}
+	go c.closeAfterTimeout()
+	return
+}
+
+// readFrame return next frame with unmasked payload.
+func (c *Conn) readFrame() (fh frameHeader, payload []byte, err protocol.Error) {
+	for {
+		var ok bool
+		fh, ok, err = parseFrameHeader(c.readBuf)
+		if err != nil {
+			return
+		}
+		if ok {
+			// Client must mask all frames that send to server, and server must not mask any frame.
+			// https://datatracker.ietf.org/doc/html/rfc6455#section-5.1
+			if fh.masked == c.isClient || (fh.rsv1 && c.deflate == nil) {
+				err = &ErrProtocol
+				return
+			}
+			if fh.length > uint64(c.maxMessageSize) {
+				err = &ErrMessageTooLarge
+				return
+			}
+			var frameLen = fh.dataPos + int(fh.length)
+			if len(c.readBuf) >= frameLen {
+				payload = append([]byte(nil), c.readBuf[fh.dataPos:frameLen]...)
+				if fh.masked {
+					maskBytes(fh.mask, 0, payload)
+				}
+				c.readBuf = c.readBuf[:copy(c.readBuf, c.readBuf[frameLen:])]
+				return
+			}
+		}
+
+		c.readBuf, err = c.transport.MarshalTo(c.readBuf)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (c *Conn) handleControl(op opcode, payload []byte) (err protocol.Error) {
+	switch op {
+	case opcodePing:
+		err = c.write(appendFrame(nil, true, false, opcodePong, payload, c.newMask()), false)
+	case opcodePong:
+		// Unsolicited pong serve as unidirectional heartbeat, so nothing to do.
+	case opcodeClose:
+		var code = CloseCode_NoStatus
+		switch {
+		case len(payload) == 1:
+			err = &ErrProtocol
+		case len(payload) >= 2:
+			code = CloseCode(binary.BigEndian.Uint16(payload))
+			if !code.validToSend() {
+				err = &ErrProtocol
+			} else if !utf8.Valid(payload[2:]) {
+				err = &ErrInvalidPayload
+			}
+		}
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		// Echo the status code in reply if we don't start the closing handshake.
+		var reply []byte
+		if code != CloseCode_NoStatus {
+			reply = payload[:2]
+		}
+		c.write(appendFrame(nil, true, false, opcodeClose, reply, c.newMask()), true)
+		c.closeTransport()
+		err = &ErrClosed
+	}
+	return
+}
+
+// fail close the connection due to given error.
+// https://datatracker.ietf.org/doc/html/rfc6455#section-7.1.7
+func (c *Conn) fail(err protocol.Error) {
+	if err == &ErrClosed {
+		return
+	}
+	var code CloseCode
+	switch err {
+	case &ErrProtocol:
+		code = CloseCode_ProtocolError
+	case &ErrMessageTooLarge:
+		code = CloseCode_MessageTooBig
+	case &ErrInvalidPayload, &ErrCompression:
+		code = CloseCode_InvalidPayload
+	default:
+		// Transport error, so no way to send close frame.
+		c.closeTransport()
+		return
+	}
+	var payload = binary.BigEndian.AppendUint16(nil, uint16(code))
+	c.write(appendFrame(nil, true, false, opcodeClose, payload, c.newMask()), true)
+	c.closeTransport()
+}
+
+// write send given frames. After close frame send, no other frame can send.
+func (c *Conn) write(frames []byte, closeFrame bool) (err protocol.Error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closeSent {
+		return &ErrClosed
+	}
+	if closeFrame {
+		c.closeSent = true
+	}
+	_, err = c.transport.Unmarshal(frames)
+	return
+}
+
+func (c *Conn) closeAfterTimeout() {
+	select {
+	case <-c.closed:
+	case <-timer.After(DefaultCloseTimeout):
+		c.closeTransport()
+	}
+}
+
+func (c *Conn) closeTransport() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.transport.Close()
+	})
+}
+
+// newMask return a new random masking key if connection is a client, otherwise nil.
+func (c *Conn) newMask() (mask *[4]byte) {
+	if !c.isClient {
+		return
+	}
+	mask = new([4]byte)
+	rand.Read(mask[:])
+	return
+}
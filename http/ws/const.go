@@ -0,0 +1,91 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"github.com/GeniusesGroup/libgo/time/monotonic"
+)
+
+/*
+	WebSocket protocol over HTTP/1.1 upgraded transport stream.
+	https://datatracker.ietf.org/doc/html/rfc6455
+*/
+
+const (
+	// GUID is the globally unique identifier that concatenate to Sec-WebSocket-Key to make Sec-WebSocket-Accept.
+	GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// UpgradeToken is token of WebSocket in Upgrade header.
+	UpgradeToken = "websocket"
+	// Version is the only WebSocket version that support by this package.
+	Version = "13"
+	// SubprotocolSRPC is subprotocol name that carry service calls in messages like sRPC, see Stream.
+	SubprotocolSRPC = "srpc"
+	// ExtensionPermessageDeflate is token of compression extension in Sec-WebSocket-Extensions header.
+	// https://datatracker.ietf.org/doc/html/rfc7692
+	ExtensionPermessageDeflate = "permessage-deflate"
+
+	HeaderKeySecWebSocketKey        = "Sec-WebSocket-Key"
+	HeaderKeySecWebSocketAccept     = "Sec-WebSocket-Accept"
+	HeaderKeySecWebSocketVersion    = "Sec-WebSocket-Version"
+	HeaderKeySecWebSocketProtocol   = "Sec-WebSocket-Protocol"
+	HeaderKeySecWebSocketExtensions = "Sec-WebSocket-Extensions"
+
+	// DefaultMaxMessageSize is max size of a received message after reassemble fragments and decompress.
+	DefaultMaxMessageSize = 16 << 20
+	// DefaultMaxFrameSize is max payload size of each frame that send. Larger messages send in fragments.
+	DefaultMaxFrameSize = 64 << 10
+	// DefaultCloseTimeout is max time to wait for peer close frame after send ours, then transport closed anyway.
+	DefaultCloseTimeout = 5 * monotonic.Second
+
+	maxControlPayloadLength = 125
+	maxFrameHeaderLength    = 14 // 2 + 8 extended payload length + 4 masking key
+)
+
+// https://datatracker.ietf.org/doc/html/rfc6455#section-5.2
+type opcode uint8
+
+const (
+	opcodeContinuation opcode = 0x0
+	opcodeText         opcode = 0x1
+	opcodeBinary       opcode = 0x2
+	opcodeClose        opcode = 0x8
+	opcodePing         opcode = 0x9
+	opcodePong         opcode = 0xA
+)
+
+func (op opcode) isControl() bool { return op&0x8 != 0 }
+
+// MessageType indicate type of a data message.
+type MessageType uint8
+
+const (
+	MessageType_Text   = MessageType(opcodeText)   // UTF-8 encoded text data
+	MessageType_Binary = MessageType(opcodeBinary) //
+)
+
+// CloseCode indicate reason of closure in close frame.
+// https://datatracker.ietf.org/doc/html/rfc6455#section-7.4.1
+type CloseCode uint16
+
+const (
+	CloseCode_Normal             CloseCode = 1000
+	CloseCode_GoingAway          CloseCode = 1001
+	CloseCode_ProtocolError      CloseCode = 1002
+	CloseCode_UnsupportedData    CloseCode = 1003
+	CloseCode_NoStatus           CloseCode = 1005 // must not send in close frame
+	CloseCode_Abnormal           CloseCode = 1006 // must not send in close frame
+	CloseCode_InvalidPayload     CloseCode = 1007
+	CloseCode_PolicyViolation    CloseCode = 1008
+	CloseCode_MessageTooBig      CloseCode = 1009
+	CloseCode_MandatoryExtension CloseCode = 1010
+	CloseCode_InternalError      CloseCode = 1011
+)
+
+// validToSend report the code can send in a close frame.
+func (cc CloseCode) validToSend() bool {
+	switch {
+	case cc >= 1000 && cc <= 1003, cc >= 1007 && cc <= 1011, cc >= 3000 && cc <= 4999:
+		return true
+	}
+	return false
+}
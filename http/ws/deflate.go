@@ -0,0 +1,123 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/compress/flate"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+Compression Extensions for WebSocket
+https://datatracker.ietf.org/doc/html/rfc7692
+
+Each message compress independently (no context takeover), so connection don't need to hold
+any compressor state between messages and can serve many idle connections with low memory.
+*/
+
+const (
+	deflateOffer    = ExtensionPermessageDeflate + "; client_no_context_takeover; server_no_context_takeover"
+	deflateResponse = ExtensionPermessageDeflate + "; server_no_context_takeover; client_no_context_takeover"
+)
+
+// deflateTail is the empty stored block that sync flush add to end of compressed data.
+// Sender remove it and receiver add it back before decompress.
+// https://datatracker.ietf.org/doc/html/rfc7692#section-7.2.1
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// deflateFinal is an empty final stored block to let decompressor report io.EOF after message end.
+var deflateFinal = []byte{0x01, 0x00, 0x00, 0xff, 0xff}
+
+type permessageDeflate struct{}
+
+// negotiateDeflate return non nil if any of extension offers is an acceptable permessage-deflate.
+// https://datatracker.ietf.org/doc/html/rfc7692#section-7.1
+func negotiateDeflate(extensions []string) (pd *permessageDeflate) {
+	for _, value := range extensions {
+		for _, offer := range strings.Split(value, ",") {
+			var params = strings.Split(offer, ";")
+			if strings.TrimSpace(params[0]) != ExtensionPermessageDeflate {
+				continue
+			}
+			if deflateParamsAcceptable(params[1:]) {
+				return &permessageDeflate{}
+			}
+		}
+	}
+	return nil
+}
+
+// deflateParamsAcceptable report params can serve by flate.Raw that always use 32K window in both directions.
+func deflateParamsAcceptable(params []string) bool {
+	for _, param := range params {
+		var name, value, _ = strings.Cut(strings.TrimSpace(param), "=")
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.TrimSpace(name) {
+		case "server_no_context_takeover", "client_no_context_takeover":
+		case "client_max_window_bits":
+			// Client just inform it can accept the param, and we never send it back, so client use 15.
+		case "server_max_window_bits":
+			if value != "15" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// compress use the whole message as one DEFLATE stream that ends with a final block, that RFC allow.
+// https://datatracker.ietf.org/doc/html/rfc7692#section-7.2.3.3
+func (pd *permessageDeflate) compress(data []byte) (compressed []byte, err protocol.Error) {
+	var codec protocol.Codec
+	codec, err = flate.Raw.CompressBySlice(data, protocol.CompressOptions{CompressLevel: protocol.CompressLevel_Default})
+	if err != nil {
+		return nil, &ErrCompression
+	}
+	compressed, err = codec.Marshal()
+	if err != nil {
+		return nil, &ErrCompression
+	}
+	compressed = bytes.TrimSuffix(compressed, deflateTail)
+	return
+}
+
+func (pd *permessageDeflate) decompress(data []byte, maxSize int) (decompressed []byte, err protocol.Error) {
+	var source = make([]byte, 0, len(data)+len(deflateTail)+len(deflateFinal))
+	source = append(source, data...)
+	source = append(source, deflateTail...)
+	source = append(source, deflateFinal...)
+
+	var codec protocol.Codec
+	codec, err = flate.Raw.DecompressFromSlice(source)
+	if err != nil {
+		return nil, &ErrCompression
+	}
+	var lw = limitWriter{max: maxSize}
+	var _, goErr = codec.(io.WriterTo).WriteTo(&lw)
+	if goErr == &ErrMessageTooLarge {
+		return nil, &ErrMessageTooLarge
+	}
+	if goErr != nil {
+		return nil, &ErrCompression
+	}
+	return lw.buf.Bytes(), nil
+}
+
+// limitWriter buffer decompressed message and stop decompress as soon as the message exceed the max size.
+type limitWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (lw *limitWriter) Write(p []byte) (n int, goErr error) {
+	if lw.buf.Len()+len(p) > lw.max {
+		return 0, &ErrMessageTooLarge
+	}
+	return lw.buf.Write(p)
+}
@@ -0,0 +1,88 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "WebSocket"
+
+// Declare package errors
+var (
+	ErrHandshake        er.Error
+	ErrHandshakeVersion er.Error
+	ErrHandshakeOrigin  er.Error
+	ErrProtocol         er.Error
+	ErrMessageTooLarge  er.Error
+	ErrInvalidPayload   er.Error
+	ErrCompression      er.Error
+	ErrClosed           er.Error
+)
+
+func init() {
+	ErrHandshake.Init("domain/websocket.protocol; type=error; name=handshake")
+	ErrHandshake.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Handshake",
+		"Opening handshake request or response is not a valid WebSocket handshake",
+		"",
+		"",
+		nil)
+
+	ErrHandshakeVersion.Init("domain/websocket.protocol; type=error; name=handshake-version")
+	ErrHandshakeVersion.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Handshake Version",
+		"Requested WebSocket version is not supported, just version 13 supported",
+		"",
+		"",
+		nil)
+
+	ErrHandshakeOrigin.Init("domain/websocket.protocol; type=error; name=handshake-origin")
+	ErrHandshakeOrigin.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Handshake Origin",
+		"Origin of the opening handshake request is not allowed to open a WebSocket",
+		"",
+		"",
+		nil)
+
+	ErrProtocol.Init("domain/websocket.protocol; type=error; name=protocol")
+	ErrProtocol.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Protocol",
+		"Peer violate WebSocket protocol e.g. send malformed frame or unexpected continuation frame",
+		"",
+		"",
+		nil)
+
+	ErrMessageTooLarge.Init("domain/websocket.protocol; type=error; name=message-too-large")
+	ErrMessageTooLarge.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Message Too Large",
+		"Received message is larger than allowed message size",
+		"",
+		"",
+		nil)
+
+	ErrInvalidPayload.Init("domain/websocket.protocol; type=error; name=invalid-payload")
+	ErrInvalidPayload.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Invalid Payload",
+		"Received text message or close reason is not valid UTF-8 or message payload is not in expected format",
+		"",
+		"",
+		nil)
+
+	ErrCompression.Init("domain/websocket.protocol; type=error; name=compression")
+	ErrCompression.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Compression",
+		"Can't compress or decompress a message by negotiated permessage-deflate extension",
+		"",
+		"",
+		nil)
+
+	ErrClosed.Init("domain/websocket.protocol; type=error; name=closed")
+	ErrClosed.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Closed",
+		"WebSocket closed and can't send or receive any more messages",
+		"",
+		"",
+		nil)
+}
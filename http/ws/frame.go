@@ -0,0 +1,145 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"encoding/binary"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+https://datatracker.ietf.org/doc/html/rfc6455#section-5.2
+
+	 0                   1                   2                   3
+	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+	+-+-+-+-+-------+-+-------------+-------------------------------+
+	|F|R|R|R| opcode|M| Payload len |    Extended payload length    |
+	|I|S|S|S|  (4)  |A|     (7)     |             (16/64)           |
+	|N|V|V|V|       |S|             |   (if payload len==126/127)   |
+	| |1|2|3|       |K|             |                               |
+	+-+-+-+-+-------+-+-------------+ - - - - - - - - - - - - - - - +
+	|     Extended payload length continued, if payload len == 127  |
+	+ - - - - - - - - - - - - - - - +-------------------------------+
+	|                               |Masking-key, if MASK set to 1  |
+	+-------------------------------+-------------------------------+
+	| Masking-key (continued)       |          Payload Data         |
+	+-------------------------------- - - - - - - - - - - - - - - - +
+*/
+
+type frameHeader struct {
+	fin     bool
+	rsv1    bool // permessage-deflate use it to indicate compressed message
+	opcode  opcode
+	masked  bool
+	mask    [4]byte
+	length  uint64
+	dataPos int // payload data position in the frame i.e. header length
+}
+
+// parseFrameHeader parse frame header in given buf. ok is false if buf not has the whole header yet.
+func parseFrameHeader(buf []byte) (fh frameHeader, ok bool, err protocol.Error) {
+	if len(buf) < 2 {
+		return
+	}
+	var b0, b1 = buf[0], buf[1]
+	fh.fin = b0&0x80 != 0
+	fh.rsv1 = b0&0x40 != 0
+	fh.opcode = opcode(b0 & 0x0F)
+	fh.masked = b1&0x80 != 0
+	if b0&0x30 != 0 {
+		// RSV2 and RSV3 not defined by any negotiated extension.
+		err = &ErrProtocol
+		return
+	}
+	switch fh.opcode {
+	case opcodeContinuation, opcodeText, opcodeBinary, opcodeClose, opcodePing, opcodePong:
+	default:
+		err = &ErrProtocol
+		return
+	}
+
+	var pos = 2
+	switch length := b1 & 0x7F; length {
+	case 126:
+		if len(buf) < 4 {
+			return
+		}
+		fh.length = uint64(binary.BigEndian.Uint16(buf[2:]))
+		pos = 4
+	case 127:
+		if len(buf) < 10 {
+			return
+		}
+		fh.length = binary.BigEndian.Uint64(buf[2:])
+		if fh.length>>63 != 0 {
+			err = &ErrProtocol
+			return
+		}
+		pos = 10
+	default:
+		fh.length = uint64(length)
+	}
+
+	if fh.opcode.isControl() && (!fh.fin || fh.length > maxControlPayloadLength) {
+		err = &ErrProtocol
+		return
+	}
+
+	if fh.masked {
+		if len(buf) < pos+4 {
+			return
+		}
+		copy(fh.mask[:], buf[pos:pos+4])
+		pos += 4
+	}
+	fh.dataPos = pos
+	ok = true
+	return
+}
+
+// appendFrame append a frame with given payload. payload masked in the frame if mask is not nil.
+func appendFrame(buf []byte, fin, rsv1 bool, op opcode, payload []byte, mask *[4]byte) []byte {
+	var b0 = byte(op)
+	if fin {
+		b0 |= 0x80
+	}
+	if rsv1 {
+		b0 |= 0x40
+	}
+	var b1 byte
+	if mask != nil {
+		b1 = 0x80
+	}
+
+	var length = len(payload)
+	switch {
+	case length <= 125:
+		buf = append(buf, b0, b1|byte(length))
+	case length <= 0xFFFF:
+		buf = append(buf, b0, b1|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(length))
+	default:
+		buf = append(buf, b0, b1|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(length))
+	}
+
+	if mask == nil {
+		return append(buf, payload...)
+	}
+	buf = append(buf, mask[:]...)
+	var start = len(buf)
+	buf = append(buf, payload...)
+	maskBytes(*mask, 0, buf[start:])
+	return buf
+}
+
+// maskBytes mask or unmask given data in place by the masking key from pos of key and return next pos.
+// https://datatracker.ietf.org/doc/html/rfc6455#section-5.3
+func maskBytes(key [4]byte, pos int, data []byte) int {
+	for i := range data {
+		data[i] ^= key[pos&3]
+		pos++
+	}
+	return pos & 3
+}
@@ -0,0 +1,237 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/http"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// UpgradeOptions indicate how server accept an opening handshake.
+type UpgradeOptions struct {
+	// Subprotocols that server support in order of preference. Empty means no subprotocol.
+	Subprotocols []string
+	// Compression enable permessage-deflate extension if client offer it.
+	Compression bool
+	// CheckOrigin report whether request Origin allow to open WebSocket.
+	// nil means allow requests without Origin header or with Origin that its host is same as request Host.
+	CheckOrigin func(origin, host string) bool
+	// MaxMessageSize is max size of a received message after decompression, 0 means DefaultMaxMessageSize.
+	MaxMessageSize int
+	// MaxFrameSize is max payload size of each sent frame, 0 means DefaultMaxFrameSize.
+	MaxFrameSize int
+}
+
+// DialOptions indicate how client start an opening handshake.
+type DialOptions struct {
+	// Subprotocols that client offer in order of preference.
+	Subprotocols []string
+	// Compression offer permessage-deflate extension to server.
+	Compression bool
+	// MaxMessageSize is max size of a received message after decompression, 0 means DefaultMaxMessageSize.
+	MaxMessageSize int
+	// MaxFrameSize is max payload size of each sent frame, 0 means DefaultMaxFrameSize.
+	MaxFrameSize int
+}
+
+// IsUpgradeRequest report given HTTP/1.1 request ask to upgrade the connection to WebSocket.
+// https://datatracker.ietf.org/doc/html/rfc6455#section-4.2.1
+func IsUpgradeRequest(httpReq *http.Request) bool {
+	return httpReq.Method() == http.MethodGET &&
+		strings.EqualFold(httpReq.H.Get(http.HeaderKeyUpgrade), UpgradeToken) &&
+		headerContainsToken(httpReq.H.Gets(http.HeaderKeyConnection), http.HeaderKeyUpgrade)
+}
+
+// AcceptKey return Sec-WebSocket-Accept value of given Sec-WebSocket-Key.
+func AcceptKey(key string) string {
+	var h = sha1.New()
+	h.Write([]byte(key))
+	h.Write([]byte(GUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Upgrade validate given opening handshake request and send "101 Switching Protocols" on transport.
+// If handshake is not acceptable, httpRes status and header set to describe the reason and error return,
+// so caller must send httpRes as usual HTTP response.
+func Upgrade(transport protocol.Stream, httpReq *http.Request, httpRes *http.Response, options UpgradeOptions) (c *Conn, err protocol.Error) {
+	var key = httpReq.H.Get(HeaderKeySecWebSocketKey)
+	var decodedKey, goErr = base64.StdEncoding.DecodeString(key)
+	if !IsUpgradeRequest(httpReq) || goErr != nil || len(decodedKey) != 16 {
+		httpRes.SetStatus(http.StatusBadRequestCode, http.StatusBadRequestPhrase)
+		err = &ErrHandshake
+		return
+	}
+	if httpReq.H.Get(HeaderKeySecWebSocketVersion) != Version {
+		httpRes.SetStatus(http.StatusUpgradeRequiredCode, http.StatusUpgradeRequiredPhrase)
+		httpRes.H.Set(HeaderKeySecWebSocketVersion, Version)
+		err = &ErrHandshakeVersion
+		return
+	}
+	var checkOrigin = options.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(httpReq.H.Get(http.HeaderKeyOrigin), httpReq.H.Get(http.HeaderKeyHost)) {
+		httpRes.SetStatus(http.StatusForbiddenCode, http.StatusForbiddenPhrase)
+		err = &ErrHandshakeOrigin
+		return
+	}
+
+	c = new(Conn)
+	c.init(transport, false, options.MaxMessageSize, options.MaxFrameSize)
+	c.subprotocol = selectSubprotocol(httpReq.H.Gets(HeaderKeySecWebSocketProtocol), options.Subprotocols)
+	if options.Compression {
+		c.deflate = negotiateDeflate(httpReq.H.Gets(HeaderKeySecWebSocketExtensions))
+	}
+
+	httpRes.SetVersion(httpReq.Version())
+	httpRes.SetStatus(http.StatusSwitchingProtocolsCode, http.StatusSwitchingProtocolsPhrase)
+	httpRes.H.Set(http.HeaderKeyUpgrade, UpgradeToken)
+	httpRes.H.Set(http.HeaderKeyConnection, http.HeaderKeyUpgrade)
+	httpRes.H.Set(HeaderKeySecWebSocketAccept, AcceptKey(key))
+	if c.subprotocol != "" {
+		httpRes.H.Set(HeaderKeySecWebSocketProtocol, c.subprotocol)
+	}
+	if c.deflate != nil {
+		httpRes.H.Set(HeaderKeySecWebSocketExtensions, deflateResponse)
+	}
+	_, err = transport.Encode(httpRes)
+	if err != nil {
+		c = nil
+	}
+	return
+}
+
+// Dial send given HTTP request as opening handshake on transport and wait for the server response.
+// httpReq must have URI path and Host header and any other desire fields e.g. Origin, Cookie, ...
+// https://datatracker.ietf.org/doc/html/rfc6455#section-4.1
+func Dial(transport protocol.Stream, httpReq *http.Request, options DialOptions) (c *Conn, httpRes *http.Response, err protocol.Error) {
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	var key = base64.StdEncoding.EncodeToString(nonce[:])
+
+	httpReq.SetMethod(http.MethodGET)
+	httpReq.SetVersion(http.VersionHTTP11)
+	httpReq.H.Set(http.HeaderKeyUpgrade, UpgradeToken)
+	httpReq.H.Set(http.HeaderKeyConnection, http.HeaderKeyUpgrade)
+	httpReq.H.Set(HeaderKeySecWebSocketKey, key)
+	httpReq.H.Set(HeaderKeySecWebSocketVersion, Version)
+	if len(options.Subprotocols) > 0 {
+		httpReq.H.Set(HeaderKeySecWebSocketProtocol, strings.Join(options.Subprotocols, ", "))
+	}
+	if options.Compression {
+		httpReq.H.Set(HeaderKeySecWebSocketExtensions, deflateOffer)
+	}
+	httpReq.H.SetZeroContentLength()
+	_, err = transport.Encode(httpReq)
+	if err != nil {
+		return
+	}
+
+	// Response may receive in many parts and frames may receive right after it, so read until header end
+	// and keep any remaining data for the connection.
+	var buf = make([]byte, 0, http.MaxHTTPHeaderSize)
+	for !bytes.Contains(buf, headerEnd) {
+		if len(buf) >= http.MaxHTTPHeaderSize {
+			err = &ErrHandshake
+			return
+		}
+		buf, err = transport.MarshalTo(buf)
+		if err != nil {
+			return
+		}
+	}
+	httpRes = new(http.Response)
+	httpRes.Init()
+	var remaining []byte
+	remaining, err = httpRes.UnmarshalFrom(buf)
+	if err != nil {
+		return
+	}
+
+	if httpRes.StatusCode() != http.StatusSwitchingProtocolsCode ||
+		!strings.EqualFold(httpRes.H.Get(http.HeaderKeyUpgrade), UpgradeToken) ||
+		!headerContainsToken(httpRes.H.Gets(http.HeaderKeyConnection), http.HeaderKeyUpgrade) ||
+		httpRes.H.Get(HeaderKeySecWebSocketAccept) != AcceptKey(key) {
+		err = &ErrHandshake
+		return
+	}
+
+	c = new(Conn)
+	c.init(transport, true, options.MaxMessageSize, options.MaxFrameSize)
+	c.readBuf = append(c.readBuf, remaining...)
+	c.subprotocol = httpRes.H.Get(HeaderKeySecWebSocketProtocol)
+	if c.subprotocol != "" && !containsToken(options.Subprotocols, c.subprotocol) {
+		c = nil
+		err = &ErrHandshake
+		return
+	}
+	var extensions = httpRes.H.Gets(HeaderKeySecWebSocketExtensions)
+	if len(extensions) > 0 {
+		if !options.Compression {
+			c = nil
+			err = &ErrHandshake
+			return
+		}
+		c.deflate = negotiateDeflate(extensions)
+		if c.deflate == nil {
+			c = nil
+			err = &ErrHandshake
+			return
+		}
+	}
+	return
+}
+
+var headerEnd = []byte("\r\n\r\n")
+
+// sameOrigin is default UpgradeOptions.CheckOrigin.
+func sameOrigin(origin, host string) bool {
+	if origin == "" {
+		return true
+	}
+	var schemeEnd = strings.Index(origin, "://")
+	if schemeEnd == -1 {
+		return false
+	}
+	return strings.EqualFold(origin[schemeEnd+3:], host)
+}
+
+// selectSubprotocol return first server supported subprotocol that client offer.
+func selectSubprotocol(offers []string, supported []string) string {
+	for _, protocol := range supported {
+		for _, offer := range offers {
+			if containsToken(strings.Split(offer, ","), protocol) {
+				return protocol
+			}
+		}
+	}
+	return ""
+}
+
+// headerContainsToken report any of comma separated header values contain given token case-insensitively.
+func headerContainsToken(values []string, token string) bool {
+	for _, value := range values {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if strings.TrimSpace(t) == token {
+			return true
+		}
+	}
+	return false
+}
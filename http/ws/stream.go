@@ -0,0 +1,172 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+/*
+Stream carry one service call in a pair of binary messages, so sRPC services can serve to browsers over WebSocket.
+
+	request message  = CallID (8 byte little-endian) + ServiceID (8 byte little-endian) + payload
+	response message = CallID (8 byte little-endian) + ErrorID (8 byte little-endian, 0 means no error) + payload
+
+Calls on a connection serve concurrently, so responses can receive in any order and peer match them to requests by CallID.
+*/
+type Stream struct {
+	protocol.Stream // underlying transport stream of the WebSocket connection
+
+	conn *Conn
+	id   uint64 // CallID that peer choose for the call
+
+	service  protocol.Service
+	err      protocol.Error
+	status   protocol.NetworkStatus
+	state    chan protocol.NetworkStatus
+	request  any
+	response any
+
+	req  []byte // request payload
+	res  []byte // response payload
+	sent bool
+}
+
+const (
+	callIDLength     = 8
+	callHeaderLength = callIDLength + 8
+)
+
+// AcceptStream block until next service call received. Returned error is a connection error and
+// no other call can accept after it. If requested service not exist, stream Service() is nil and
+// Error() is the reason, so caller can just Close() it to send the error to the peer.
+func (c *Conn) AcceptStream() (st *Stream, err protocol.Error) {
+	var mt MessageType
+	var data []byte
+	mt, data, err = c.ReadMessage()
+	if err != nil {
+		return
+	}
+	if mt != MessageType_Binary || len(data) < callHeaderLength {
+		err = &ErrInvalidPayload
+		c.Close(CloseCode_UnsupportedData, "")
+		return
+	}
+
+	st = &Stream{
+		Stream: c.transport,
+		conn:   c,
+		id:     binary.LittleEndian.Uint64(data),
+		state:  make(chan protocol.NetworkStatus, 4),
+		req:    data[callHeaderLength:],
+	}
+	st.service, st.err = protocol.App.GetServiceByID(protocol.ID(binary.LittleEndian.Uint64(data[callIDLength:])))
+	if st.err != nil {
+		st.service = nil
+	}
+	st.SetStatus(protocol.NetworkStatus_ReceivedCompletely)
+	return
+}
+
+// Conn return the WebSocket connection that carry the stream.
+func (st *Stream) Conn() *Conn { return st.conn }
+
+//libgo:impl protocol.Stream
+func (st *Stream) Service() protocol.Service       { return st.service }
+func (st *Stream) Error() protocol.Error           { return st.err }
+func (st *Stream) SetService(ser protocol.Service) { st.service = ser }
+func (st *Stream) SetError(err protocol.Error)     { st.err = err }
+
+//libgo:impl protocol.Stream_ID
+func (st *Stream) StreamID() protocol.StreamID { return protocol.StreamID(st.id) }
+func (st *Stream) PeerInitiated() bool         { return !st.conn.isClient }
+func (st *Stream) Bidirectional() bool         { return true }
+
+//libgo:impl protocol.Network_Status
+func (st *Stream) Status() protocol.NetworkStatus {
+	return protocol.NetworkStatus(atomic.LoadUint32((*uint32)(&st.status)))
+}
+func (st *Stream) State() chan protocol.NetworkStatus { return st.state }
+func (st *Stream) SetStatus(ns protocol.NetworkStatus) {
+	atomic.StoreUint32((*uint32)(&st.status), uint32(ns))
+	select {
+	case st.state <- ns:
+	default:
+		// Don't block caller if no one listen to the state channel
+	}
+}
+
+//libgo:impl protocol.StreamLowLevelAPIs
+func (st *Stream) Request() any              { return st.request }
+func (st *Stream) Response() any             { return st.response }
+func (st *Stream) SetRequest(req any)        { st.request = req }
+func (st *Stream) SetResponse(res any)       { st.response = res }
+func (st *Stream) ScheduleProcessingStream() {}
+func (st *Stream) Send(data protocol.Codec) (err protocol.Error) {
+	_, err = st.Encode(data)
+	return
+}
+
+// Close send the response message with any data that write to the stream and the stream error if any exist.
+func (st *Stream) Close() (err protocol.Error) {
+	if st.sent {
+		return
+	}
+	st.sent = true
+
+	var message = make([]byte, callHeaderLength, callHeaderLength+len(st.res))
+	binary.LittleEndian.PutUint64(message, st.id)
+	if st.err != nil {
+		binary.LittleEndian.PutUint64(message[callIDLength:], uint64(st.err.ID()))
+	}
+	message = append(message, st.res...)
+	err = st.conn.WriteMessage(MessageType_Binary, message)
+	if err == nil {
+		st.SetStatus(protocol.NetworkStatus_SentCompletely)
+	}
+	st.SetStatus(protocol.NetworkStatus_Closed)
+	return
+}
+
+//libgo:impl protocol.Codec
+func (st *Stream) MediaType() protocol.MediaType       { return nil }
+func (st *Stream) CompressType() protocol.CompressType { return nil }
+func (st *Stream) Len() (ln int)                       { return len(st.req) }
+func (st *Stream) Decode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return st.Unmarshal(data)
+}
+
+// Encode add given codec data to the response that send on Close().
+func (st *Stream) Encode(source protocol.Codec) (n int, err protocol.Error) {
+	var data []byte
+	data, err = source.Marshal()
+	if err != nil {
+		return
+	}
+	return st.Unmarshal(data)
+}
+func (st *Stream) Marshal() (data []byte, err protocol.Error) { return st.req, nil }
+func (st *Stream) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	return append(data, st.req...), nil
+}
+
+// Unmarshal add given data to the response that send on Close().
+func (st *Stream) Unmarshal(data []byte) (n int, err protocol.Error) {
+	if st.sent {
+		return 0, &ErrClosed
+	}
+	st.res = append(st.res, data...)
+	return len(data), nil
+}
+func (st *Stream) UnmarshalFrom(data []byte) (remaining []byte, err protocol.Error) {
+	_, err = st.Unmarshal(data)
+	return
+}
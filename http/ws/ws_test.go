@@ -0,0 +1,162 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package ws
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// testTransport is a transport stream that read from in and write to out.
+type testTransport struct {
+	protocol.Stream
+	in     []byte
+	out    []byte
+	closed bool
+}
+
+func (tt *testTransport) MarshalTo(data []byte) (added []byte, err protocol.Error) {
+	if len(tt.in) == 0 {
+		return data, &ErrClosed
+	}
+	added = append(data, tt.in...)
+	tt.in = nil
+	return
+}
+func (tt *testTransport) Unmarshal(data []byte) (n int, err protocol.Error) {
+	tt.out = append(tt.out, data...)
+	return len(data), nil
+}
+func (tt *testTransport) Close() (err protocol.Error) {
+	tt.closed = true
+	return
+}
+
+func TestAcceptKey(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc6455#section-1.3
+	var got = AcceptKey("dGhlIHNhbXBsZSBub25jZQ==")
+	if got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("AcceptKey() = %q, want %q", got, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+	}
+}
+
+func testPair(maxFrameSize int, compressed bool) (client, server *Conn, clientTransport, serverTransport *testTransport) {
+	clientTransport = new(testTransport)
+	serverTransport = new(testTransport)
+	client = new(Conn)
+	client.init(clientTransport, true, 0, maxFrameSize)
+	server = new(Conn)
+	server.init(serverTransport, false, 0, maxFrameSize)
+	if compressed {
+		client.deflate = &permessageDeflate{}
+		server.deflate = &permessageDeflate{}
+	}
+	return
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	var messages = [][]byte{
+		[]byte(""),
+		[]byte("Hello"),
+		bytes.Repeat([]byte("0123456789"), 100),
+	}
+	for _, compressed := range []bool{false, true} {
+		var client, server, clientTransport, serverTransport = testPair(64, compressed)
+		for _, message := range messages {
+			var err = client.WriteMessage(MessageType_Text, message)
+			if err != nil {
+				t.Fatalf("Conn.WriteMessage() error = %v", err)
+			}
+		}
+		// Peer ping between data frames must answer by pong.
+		client.Ping([]byte("ping"))
+
+		serverTransport.in = clientTransport.out
+		for _, message := range messages {
+			var mt, data, err = server.ReadMessage()
+			if err != nil {
+				t.Fatalf("Conn.ReadMessage() compressed=%v error = %v", compressed, err)
+			}
+			if mt != MessageType_Text || !bytes.Equal(data, message) {
+				t.Errorf("Conn.ReadMessage() compressed=%v = %q, want %q", compressed, data, message)
+			}
+		}
+
+		var _, _, err = server.ReadMessage()
+		if err == nil {
+			t.Fatalf("Conn.ReadMessage() expected error on transport end")
+		}
+		var fh, ok, _ = parseFrameHeader(serverTransport.out)
+		if !ok || fh.opcode != opcodePong || fh.masked {
+			t.Errorf("server reply frame = %+v, want unmasked pong", fh)
+		}
+	}
+}
+
+func TestCloseHandshake(t *testing.T) {
+	var client, server, clientTransport, serverTransport = testPair(0, false)
+	client.Close(CloseCode_GoingAway, "bye")
+
+	serverTransport.in = clientTransport.out
+	var _, _, err = server.ReadMessage()
+	if err != &ErrClosed {
+		t.Fatalf("Conn.ReadMessage() error = %v, want ErrClosed", err)
+	}
+	if !serverTransport.closed {
+		t.Errorf("server transport not closed after close handshake")
+	}
+
+	clientTransport.in = serverTransport.out
+	_, _, err = client.ReadMessage()
+	if err != &ErrClosed || !clientTransport.closed {
+		t.Errorf("client Conn.ReadMessage() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestUnmaskedClientFrame(t *testing.T) {
+	var _, server, _, serverTransport = testPair(0, false)
+	serverTransport.in = appendFrame(nil, true, false, opcodeBinary, []byte("data"), nil)
+	var _, _, err = server.ReadMessage()
+	if err != &ErrProtocol {
+		t.Errorf("Conn.ReadMessage() error = %v, want ErrProtocol", err)
+	}
+}
+
+func TestStreamResponseCallID(t *testing.T) {
+	var client, server, clientTransport, serverTransport = testPair(0, false)
+	var first = &Stream{conn: server, id: 1, state: make(chan protocol.NetworkStatus, 4)}
+	var second = &Stream{conn: server, id: 2, state: make(chan protocol.NetworkStatus, 4)}
+	first.Unmarshal([]byte("first"))
+	second.SetError(&ErrInvalidPayload)
+
+	// Calls serve concurrently, so second call can response before first one.
+	second.Close()
+	first.Close()
+
+	clientTransport.in = serverTransport.out
+	var tests = []struct {
+		callID  uint64
+		errorID uint64
+		payload string
+	}{
+		{2, uint64(ErrInvalidPayload.ID()), ""},
+		{1, 0, "first"},
+	}
+	for _, tt := range tests {
+		var mt, data, err = client.ReadMessage()
+		if err != nil {
+			t.Fatalf("Conn.ReadMessage() error = %v", err)
+		}
+		if mt != MessageType_Binary || len(data) < callHeaderLength {
+			t.Fatalf("Conn.ReadMessage() = %v %q, want binary call response", mt, data)
+		}
+		var callID = binary.LittleEndian.Uint64(data)
+		var errorID = binary.LittleEndian.Uint64(data[callIDLength:])
+		if callID != tt.callID || errorID != tt.errorID || string(data[callHeaderLength:]) != tt.payload {
+			t.Errorf("call response = %v %v %q, want %v %v %q", callID, errorID, data[callHeaderLength:], tt.callID, tt.errorID, tt.payload)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	cmd "github.com/GeniusesGroup/libgo/command"
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+	"github.com/GeniusesGroup/libgo/timer"
+)
+
+// Command is the "log" command-line command to tail and search the stored logs by its sub commands.
+// e.g. "<app> log tail -n=20 -f", "<app> log search --level=warning,error --from=2h --text=timeout"
+type Command struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	tail   tailCommand
+	search searchCommand
+}
+
+// Init initialize the command to read logs from the given storage e.g. protocol.App logger storage.
+func (c *Command) Init(parent protocol.Command, storage *Storage) {
+	c.MT.Init("domain/geniuses.group; type=command; package=log; name=log")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Log",
+		"Tail and search stored logs of the nodes",
+		"",
+		"",
+		nil)
+
+	c.tail.init(c, storage)
+	c.search.init(c, storage)
+	c.Command.Init(parent, &c.tail, &c.search)
+}
+
+//libgo:impl protocol.Command
+func (c *Command) Name() string               { return "log" }
+func (c *Command) Aliases() []string          { return []string{"logs"} }
+func (c *Command) UsageLine() string          { return "log {tail | search} [flags]" }
+func (c *Command) Runnable() bool             { return true }
+func (c *Command) Request() []protocol.Field  { return nil }
+func (c *Command) Response() []protocol.Field { return nil }
+func (c *Command) ServeCLA(arguments []string) (err protocol.Error) {
+	return cmd.ServeCLA(c, arguments)
+}
+
+// filterFlags are the flags that are same in tail and search commands.
+type filterFlags struct {
+	level  cmd.StringFlag
+	domain cmd.StringFlag
+	text   cmd.StringFlag
+	node   cmd.StringFlag
+}
+
+func (f *filterFlags) init() {
+	f.level.Init("level", "l", "all")
+	f.domain.Init("domain", "d", "")
+	f.text.Init("text", "t", "")
+	f.node.Init("node", "", "")
+}
+
+func (f *filterFlags) fields() []protocol.Field {
+	return []protocol.Field{&f.level, &f.domain, &f.text, &f.node}
+}
+
+func (f *filterFlags) query() (q Query, err protocol.Error) {
+	q.Levels, err = ParseLevels(f.level.Value)
+	if err != nil {
+		return
+	}
+	q.Domain = f.domain.Value
+	q.Text = f.text.Value
+	if f.node.Value != "" {
+		var nodeID, goErr = hex.DecodeString(f.node.Value)
+		if goErr != nil || len(nodeID) != len(q.NodeID) {
+			return q, &cmd.ErrFlagBadValue
+		}
+		copy(q.NodeID[:], nodeID)
+	}
+	return
+}
+
+type tailCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	storage *Storage
+	filterFlags
+	lines  cmd.IntegerFlag
+	follow cmd.BooleanFlag
+}
+
+func (c *tailCommand) init(parent protocol.Command, storage *Storage) {
+	c.MT.Init("domain/geniuses.group; type=command; package=log; name=tail")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Tail",
+		"Print last stored log events of today and follow new ones if requested",
+		"",
+		"",
+		nil)
+	c.Command.Init(parent)
+	c.storage = storage
+	c.filterFlags.init()
+	c.lines.Init("lines", "n", 10)
+	c.follow.Init("follow", "f", false)
+}
+
+//libgo:impl protocol.Command
+func (c *tailCommand) Name() string      { return "tail" }
+func (c *tailCommand) Aliases() []string { return nil }
+func (c *tailCommand) UsageLine() string {
+	return "log tail [-n=lines] [-f] [-l=levels] [-d=domain] [-t=text] [--node=hex-id]"
+}
+func (c *tailCommand) Runnable() bool { return true }
+func (c *tailCommand) Request() []protocol.Field {
+	return append(c.filterFlags.fields(), &c.lines, &c.follow)
+}
+func (c *tailCommand) Response() []protocol.Field { return nil }
+func (c *tailCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	err = cmd.ParseFlags(c.Request(), arguments)
+	if err != nil {
+		return
+	}
+	var q Query
+	q, err = c.filterFlags.query()
+	if err != nil {
+		return
+	}
+	q.Limit = int(c.lines.Value)
+	q.Last = true
+
+	var events []*Event
+	events, err = c.storage.Find(q)
+	if err != nil {
+		return
+	}
+	printEvents(os.Stdout, events)
+
+	// Follow new events by poll the storage. Pending events are included, so no need to wait for flush.
+	q.From = unix.Now()
+	q.Limit = 0
+	for c.follow.Value {
+		if len(events) > 0 {
+			var last = events[len(events)-1].Time()
+			q.From.ChangeTo(unix.SecElapsed(last.SecondElapsed()), last.NanoSecondElapsed()+1)
+		}
+		timer.Sleep(unix.Second)
+		q.To = unix.Now()
+		events, err = c.storage.Find(q)
+		if err != nil {
+			return
+		}
+		printEvents(os.Stdout, events)
+	}
+	return
+}
+
+type searchCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	storage *Storage
+	filterFlags
+	from  cmd.StringFlag
+	to    cmd.StringFlag
+	limit cmd.IntegerFlag
+	last  cmd.BooleanFlag
+}
+
+func (c *searchCommand) init(parent protocol.Command, storage *Storage) {
+	c.MT.Init("domain/geniuses.group; type=command; package=log; name=search")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Search",
+		"Print stored log events that match the given levels, domain, text and time range",
+		"",
+		"Time can be RFC 3339 like 2006-01-02T15:04:05Z, a date like 2006-01-02 or a duration before now like 2h",
+		nil)
+	c.Command.Init(parent)
+	c.storage = storage
+	c.filterFlags.init()
+	c.from.Init("from", "", "")
+	c.to.Init("to", "", "")
+	c.limit.Init("limit", "n", 0)
+	c.last.Init("last", "", false)
+}
+
+//libgo:impl protocol.Command
+func (c *searchCommand) Name() string      { return "search" }
+func (c *searchCommand) Aliases() []string { return []string{"find"} }
+func (c *searchCommand) UsageLine() string {
+	return "log search [-l=levels] [-d=domain] [-t=text] [--from=time] [--to=time] [-n=limit [--last]] [--node=hex-id]"
+}
+func (c *searchCommand) Runnable() bool { return true }
+func (c *searchCommand) Request() []protocol.Field {
+	return append(c.filterFlags.fields(), &c.from, &c.to, &c.limit, &c.last)
+}
+func (c *searchCommand) Response() []protocol.Field { return nil }
+func (c *searchCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	err = cmd.ParseFlags(c.Request(), arguments)
+	if err != nil {
+		return
+	}
+	var q Query
+	q, err = c.filterFlags.query()
+	if err != nil {
+		return
+	}
+	var now = time.Now()
+	q.From, err = ParseTime(c.from.Value, now)
+	if err != nil {
+		return
+	}
+	q.To, err = ParseTime(c.to.Value, now)
+	if err != nil {
+		return
+	}
+	q.Limit = int(c.limit.Value)
+	q.Last = c.last.Value
+
+	var events []*Event
+	events, err = c.storage.Find(q)
+	if err != nil {
+		return
+	}
+	printEvents(os.Stdout, events)
+	return
+}
+
+// ParseTime parse RFC 3339 time, a date as "2006-01-02" in UTC or a duration before now e.g. "2h", "30m".
+// Empty string return zero time.
+func ParseTime(value string, now time.Time) (t unix.Time, err protocol.Error) {
+	if value == "" {
+		return
+	}
+	var goTime time.Time
+	var goErr error
+	if duration, durationErr := time.ParseDuration(value); durationErr == nil {
+		goTime = now.Add(-duration)
+	} else if goTime, goErr = time.Parse(time.RFC3339Nano, value); goErr != nil {
+		goTime, goErr = time.Parse("2006-01-02", value)
+	}
+	if goErr != nil {
+		return t, &ErrBadTimeRange
+	}
+	t.ChangeTo(unix.SecElapsed(goTime.Unix()), int32(goTime.Nanosecond()))
+	return
+}
+
+// FormatEvent write the event in one line as "time level [domain] message" and its stack trace if exist in next lines.
+func FormatEvent(w io.Writer, e protocol.LogEvent) {
+	w.Write(ConsoleFormatter{}.Format(nil, e))
+}
+
+func printEvents(w io.Writer, events []*Event) {
+	for _, e := range events {
+		FormatEvent(w, e)
+	}
+}
@@ -0,0 +1,61 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "Log"
+
+// Declare package errors
+var (
+	ErrStorageFull  er.Error
+	ErrBadRecord    er.Error
+	ErrBadLevel     er.Error
+	ErrBadTimeRange er.Error
+	ErrSinkDial     er.Error
+)
+
+func init() {
+	ErrStorageFull.Init("domain/geniuses.group; type=error; package=log; name=storage_full")
+	ErrStorageFull.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Storage Full",
+		"Log event dropped due to the node daily log size cap reached or pending events can't save to the storage",
+		"",
+		"Increase Storage.MaxDaySize or check the records storage availability",
+		nil)
+
+	ErrBadRecord.Init("domain/geniuses.group; type=error; package=log; name=bad_record")
+	ErrBadRecord.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Record",
+		"Stored log record is corrupted and can't decode to log events",
+		"",
+		"",
+		nil)
+
+	ErrBadLevel.Init("domain/geniuses.group; type=error; package=log; name=bad_level")
+	ErrBadLevel.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Level",
+		"Given log level is not a known level name or number",
+		"Use level names like information, warning, error or a number as levels bitmask",
+		"",
+		nil)
+
+	ErrBadTimeRange.Init("domain/geniuses.group; type=error; package=log; name=bad_time_range")
+	ErrBadTimeRange.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Time Range",
+		"Given time is not valid or the start of the time range is after its end",
+		"Use RFC 3339 time like 2006-01-02T15:04:05Z, a date like 2006-01-02 or a duration from now like 2h",
+		"",
+		nil)
+
+	ErrSinkDial.Init("domain/geniuses.group; type=error; package=log; name=sink_dial")
+	ErrSinkDial.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Sink Dial",
+		"Can't connect to the syslog or journald socket to write log events",
+		"",
+		"Check the socket address and the log daemon is running",
+		nil)
+}
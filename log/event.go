@@ -7,7 +7,6 @@ import (
 
 	"github.com/GeniusesGroup/libgo/event"
 	"github.com/GeniusesGroup/libgo/protocol"
-	// "github.com/GeniusesGroup/libgo/syllab"
 	"github.com/GeniusesGroup/libgo/time/unix"
 )
 
@@ -41,14 +40,35 @@ func (e *Event) Init(level protocol.LogType, domain, message string, stack bool)
 	-- protocol.Syllab interface --
 */
 func (e *Event) CheckSyllab(payload []byte) (err protocol.Error) {
+	var eventStackLen = e.Event.LenOfSyllabStack()
 	if len(payload) < int(e.LenOfSyllabStack()) {
-		// err = &syllab.ErrShortArrayDecode
+		return &event.ErrBadSyllab
 	}
+	err = e.Event.CheckSyllab(payload)
+	if err != nil {
+		return
+	}
+	err = event.CheckSyllabHeap(payload, eventStackLen)
+	if err != nil {
+		return
+	}
+	err = event.CheckSyllabHeap(payload, eventStackLen+8)
 	return
 }
 func (e *Event) FromSyllab(payload []byte, stackIndex uint32) {
+	e.Event.FromSyllab(payload, stackIndex)
+	stackIndex += e.Event.LenOfSyllabStack()
+	e.message = string(event.GetSyllabBytes(payload, stackIndex))
+	var stack = event.GetSyllabBytes(payload, stackIndex+8)
+	if len(stack) > 0 {
+		e.stack = append([]byte(nil), stack...)
+	}
 }
 func (e *Event) ToSyllab(payload []byte, stackIndex, heapIndex uint32) (freeHeapIndex uint32) {
+	freeHeapIndex = e.Event.ToSyllab(payload, stackIndex, heapIndex)
+	stackIndex += e.Event.LenOfSyllabStack()
+	freeHeapIndex = event.SetSyllabString(payload, e.message, stackIndex, freeHeapIndex)
+	freeHeapIndex = event.SetSyllabString(payload, string(e.stack), stackIndex+8, freeHeapIndex)
 	return
 }
 func (e *Event) LenAsSyllab() uint64      { return uint64(e.LenOfSyllabStack() + e.LenOfSyllabHeap()) }
@@ -56,3 +76,17 @@ func (e *Event) LenOfSyllabStack() uint32 { return 16 + e.Event.LenOfSyllabStack
 func (e *Event) LenOfSyllabHeap() (ln uint32) {
 	return uint32(len(e.stack)+len(e.message)) + e.Event.LenOfSyllabHeap()
 }
+
+// FromLogEvent copy given log event data to the Event.
+// It is useful to store events created by other implementations of protocol.LogEvent.
+func (e *Event) FromLogEvent(logEvent protocol.LogEvent) {
+	e.message = logEvent.Message()
+	e.stack = logEvent.Stack()
+	var nodeID = logEvent.NodeID()
+	var time unix.Time
+	var eventTime = logEvent.Time()
+	if eventTime != nil {
+		time.ChangeTo(unix.SecElapsed(eventTime.SecondElapsed()), eventTime.NanoSecondElapsed())
+	}
+	e.Event.Init(logEvent.Level(), logEvent.Domain(), nodeID, time)
+}
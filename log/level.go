@@ -0,0 +1,75 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// LogEvent_All is the bitmask of all log levels.
+const LogEvent_All = protocol.LogEvent_Information | protocol.LogEvent_Notice | protocol.LogEvent_Debug |
+	protocol.LogEvent_DeepDebug | protocol.LogEvent_Warning | protocol.LogEvent_Error | protocol.LogEvent_Alert |
+	protocol.LogEvent_Panic | protocol.LogEvent_Critical | protocol.LogEvent_Emergency | protocol.LogEvent_Fatal |
+	protocol.LogEvent_Security | protocol.LogEvent_Confidential
+
+var levelNames = [...]string{
+	"information",
+	"notice",
+	"debug",
+	"deep-debug",
+	"warning",
+	"error",
+	"alert",
+	"panic",
+	"critical",
+	"emergency",
+	"fatal",
+	"security",
+	"confidential",
+}
+
+// LevelString return the name of the given log level e.g. "warning".
+// Level must be just one level not a bitmask of some levels.
+func LevelString(level protocol.LogType) string {
+	for i, name := range levelNames {
+		if level == 1<<i {
+			return name
+		}
+	}
+	return "unknown(" + strconv.FormatUint(uint64(level), 10) + ")"
+}
+
+// ParseLevels parse comma separated level names e.g. "warning,error" or a number as bitmask of levels.
+// "all" means all levels.
+func ParseLevels(levels string) (mask protocol.LogType, err protocol.Error) {
+	if num, goErr := strconv.ParseUint(levels, 10, 64); goErr == nil {
+		mask = protocol.LogType(num)
+		if mask&^LogEvent_All != 0 {
+			err = &ErrBadLevel
+		}
+		return
+	}
+
+	for _, level := range strings.Split(levels, ",") {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if level == "all" {
+			mask |= LogEvent_All
+			continue
+		}
+		var found bool
+		for i, name := range levelNames {
+			if level == name {
+				mask |= 1 << i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, &ErrBadLevel
+		}
+	}
+	return
+}
@@ -9,10 +9,18 @@ import (
 	"github.com/GeniusesGroup/libgo/protocol"
 )
 
+// Logger dispatch log events to its listeners and save enabled levels to its storage if any.
+// To write events to the console, JSON lines, syslog or journald, add a Sink as an event listener.
 type Logger struct {
 	event.EventTarget
+
+	storage *Storage
 }
 
+// SetStorage set the storage that enabled log events save in it. nil storage means logs don't save.
+func (l *Logger) SetStorage(storage *Storage) { l.storage = storage }
+func (l *Logger) Storage() *Storage           { return l.storage }
+
 // PanicHandler recover from panics if exist to prevent app stop.
 // Call it by defer in any goroutine due to >> https://github.com/golang/go/issues/20161
 func (l *Logger) PanicHandler() {
@@ -52,7 +60,9 @@ func (l *Logger) saveEvent(event protocol.LogEvent) (err protocol.Error) {
 
 	// TODO::: First save locally as cache to reduce network trip for non important data??
 
-	// TODO::: Each day, Save logs to storage(mediatype.LOG.ID()) with NodeID as object ID
-	// protocol.App.Objects().
+	// Each day, logs save to storage(mediatype.LOG.ID()) with NodeID and the day as record ID.
+	if l.storage != nil {
+		err = l.storage.Save(event)
+	}
 	return
 }
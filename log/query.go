@@ -0,0 +1,141 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+// Query indicate which stored log events must find. Zero value of each field means no filter by it.
+type Query struct {
+	NodeID [16]byte         // Zero means the storage node.
+	Levels protocol.LogType // Bitmask of desire levels.
+	Domain string           // Exact match of event domain.
+	Text   string           // Case-insensitive match in the event message.
+
+	// Time range of events. Both are inclusive.
+	// Zero From means the start of the To day and zero To means now.
+	From unix.Time
+	To   unix.Time
+
+	// Limit is the max number of events to return.
+	// Events always return in order that saved, but if Last is true, the last events in the range return not the first ones.
+	Limit int
+	Last  bool
+}
+
+// Match report the event pass the query filters except the node and the time range.
+func (q *Query) Match(e protocol.LogEvent) bool {
+	if q.Levels != 0 && q.Levels&e.Level() == 0 {
+		return false
+	}
+	if q.Domain != "" && q.Domain != e.Domain() {
+		return false
+	}
+	if q.Text != "" && !strings.Contains(strings.ToLower(e.Message()), strings.ToLower(q.Text)) {
+		return false
+	}
+	return true
+}
+
+// Find return stored events that match the query. It includes pending events not flushed yet.
+func (s *Storage) Find(q Query) (events []*Event, err protocol.Error) {
+	if q.NodeID == [16]byte{} {
+		q.NodeID = s.nodeID
+	}
+	if q.To == (unix.Time{}) {
+		q.To = unix.Now()
+	}
+	if q.From == (unix.Time{}) {
+		q.From.ChangeTo(unix.SecElapsed(int64(q.To.DayElapsed())*24*60*60), 0)
+	}
+	var from, to = q.From.NanoElapsed(), q.To.NanoElapsed()
+	if from > to {
+		return nil, &ErrBadTimeRange
+	}
+
+	var add = func(e *Event) (next bool) {
+		var t = e.Time()
+		var nano = unix.NanoElapsed(t.SecondElapsed()*1e9 + int64(t.NanoSecondElapsed()))
+		if nano < from || nano > to || !q.Match(e) {
+			return true
+		}
+		events = append(events, e)
+		if q.Limit > 0 {
+			if q.Last && len(events) > q.Limit {
+				events = events[1:]
+			} else if !q.Last && len(events) == q.Limit {
+				return false
+			}
+		}
+		return true
+	}
+
+	var mediaTypeID = mediaTypeID()
+	// Late events of a day save in the next day record, so check the next day record too.
+	for day := q.From.DayElapsed(); day <= q.To.DayElapsed()+1; day++ {
+		var id = RecordID(q.NodeID, day)
+		var versions uint64
+		versions, err = s.records.Count(mediaTypeID, id, 0, protocol.StorageRecord_LastSourceVersion)
+		if err != nil {
+			return
+		}
+		for v := uint64(0); v < versions; v++ {
+			var record []byte
+			record, _, err = s.records.Get(mediaTypeID, id, v)
+			if err != nil {
+				return
+			}
+			var next bool
+			next, err = DecodeRecord(record, add)
+			if err != nil || !next {
+				return
+			}
+		}
+
+		if q.NodeID == s.nodeID {
+			s.mutex.Lock()
+			var pending []byte
+			if s.day == day {
+				pending = s.pending
+			}
+			s.mutex.Unlock()
+			// Pending bytes up to its current len never change, so it is safe to decode them without lock.
+			var next bool
+			next, err = DecodeRecord(pending, add)
+			if err != nil || !next {
+				return
+			}
+		}
+	}
+	return
+}
+
+// DecodeRecord decode events of a log record version one by one and call fn for each of them until fn return false.
+func DecodeRecord(record []byte, fn func(e *Event) (next bool)) (next bool, err protocol.Error) {
+	next = true
+	for len(record) > 0 && next {
+		if len(record) < 4 {
+			return false, &ErrBadRecord
+		}
+		var ln = binary.LittleEndian.Uint32(record)
+		if uint64(len(record)-4) < uint64(ln) {
+			return false, &ErrBadRecord
+		}
+		var payload = record[4 : 4+ln]
+		record = record[4+ln:]
+
+		var e Event
+		err = e.CheckSyllab(payload)
+		if err != nil {
+			return false, err
+		}
+		e.FromSyllab(payload, 0)
+		next = fn(&e)
+	}
+	return
+}
@@ -0,0 +1,91 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// ConsoleFormatter format events for humans in one line as "time level [domain] message"
+// and its stack trace if exist in next lines indented by a tab.
+// Color add ANSI colors to the level name, so use it just when the writer is a terminal.
+type ConsoleFormatter struct {
+	Color bool
+}
+
+//libgo:impl Formatter
+func (f ConsoleFormatter) Format(buf []byte, e protocol.LogEvent) []byte {
+	buf = appendTime(buf, e.Time(), time.RFC3339Nano)
+	buf = append(buf, ' ')
+
+	var level = LevelString(e.Level())
+	if f.Color {
+		buf = append(buf, "\x1b["...)
+		buf = append(buf, levelColor(e.Level())...)
+		buf = append(buf, 'm')
+		buf = append(buf, level...)
+		buf = append(buf, "\x1b[0m"...)
+	} else {
+		buf = append(buf, level...)
+	}
+	for i := len(level); i < 12; i++ {
+		buf = append(buf, ' ')
+	}
+
+	buf = append(buf, " ["...)
+	buf = append(buf, e.Domain()...)
+	buf = append(buf, "] "...)
+	buf = append(buf, e.Message()...)
+	buf = append(buf, '\n')
+
+	var stack = e.Stack()
+	for len(stack) > 0 {
+		var line = stack
+		var i = bytes.IndexByte(stack, '\n')
+		if i >= 0 {
+			line, stack = stack[:i], stack[i+1:]
+		} else {
+			stack = nil
+		}
+		buf = append(buf, '\t')
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// levelColor return ANSI SGR parameters of the level.
+func levelColor(level protocol.LogType) string {
+	switch level {
+	case protocol.LogEvent_Debug, protocol.LogEvent_DeepDebug:
+		return "90" // gray
+	case protocol.LogEvent_Information:
+		return "36" // cyan
+	case protocol.LogEvent_Notice:
+		return "32" // green
+	case protocol.LogEvent_Warning:
+		return "33" // yellow
+	case protocol.LogEvent_Error, protocol.LogEvent_Panic:
+		return "31" // red
+	case protocol.LogEvent_Alert, protocol.LogEvent_Critical, protocol.LogEvent_Emergency, protocol.LogEvent_Fatal:
+		return "1;31" // bold red
+	case protocol.LogEvent_Security:
+		return "35" // magenta
+	default:
+		return "34" // blue
+	}
+}
+
+// appendTime append the event time in UTC by the given layout. nil time append the zero time.
+func appendTime(buf []byte, t protocol.Time, layout string) []byte {
+	var goTime time.Time
+	if t != nil {
+		goTime = time.Unix(t.SecondElapsed(), int64(t.NanoSecondElapsed()))
+	} else {
+		goTime = time.Unix(0, 0)
+	}
+	return goTime.UTC().AppendFormat(buf, layout)
+}
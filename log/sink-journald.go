@@ -0,0 +1,77 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"encoding/hex"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// JournaldSocket is the systemd journal native protocol socket path.
+const JournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldFormatter format events as systemd journal native protocol datagrams.
+// https://systemd.io/JOURNAL_NATIVE_PROTOCOL/
+// Beside the MESSAGE, PRIORITY and SYSLOG_IDENTIFIER, it adds LOG_LEVEL, LOG_DOMAIN, NODE_ID and STACK fields.
+// Events bigger than the socket buffer can't send, so the sink count them as dropped.
+type JournaldFormatter struct {
+	Identifier string // Empty means the executable name.
+}
+
+//libgo:impl Formatter
+func (f JournaldFormatter) Format(buf []byte, e protocol.LogEvent) []byte {
+	var identifier = f.Identifier
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	buf = appendJournaldField(buf, "MESSAGE", e.Message())
+	buf = appendJournaldField(buf, "PRIORITY", strconv.Itoa(int(SyslogSeverity(e.Level()))))
+	buf = appendJournaldField(buf, "SYSLOG_IDENTIFIER", identifier)
+	buf = appendJournaldField(buf, "LOG_LEVEL", LevelString(e.Level()))
+	buf = appendJournaldField(buf, "LOG_DOMAIN", e.Domain())
+	var nodeID = e.NodeID()
+	if nodeID != (protocol.NodeID{}) {
+		buf = appendJournaldField(buf, "NODE_ID", hex.EncodeToString(nodeID[:]))
+	}
+	var stack = e.Stack()
+	if len(stack) > 0 {
+		buf = appendJournaldField(buf, "STACK", string(stack))
+	}
+	return buf
+}
+
+// appendJournaldField append the field as "KEY=value\n" or in binary form if the value has new line,
+// as "KEY\n" + value length as 64bit little-endian + value + "\n".
+func appendJournaldField(buf []byte, key, value string) []byte {
+	buf = append(buf, key...)
+	if strings.IndexByte(value, '\n') < 0 {
+		buf = append(buf, '=')
+		buf = append(buf, value...)
+		buf = append(buf, '\n')
+		return buf
+	}
+	buf = append(buf, '\n')
+	var ln = len(buf)
+	buf = append(buf, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(buf[ln:], uint64(len(value)))
+	buf = append(buf, value...)
+	buf = append(buf, '\n')
+	return buf
+}
+
+// DialJournald connect to the local systemd journal socket.
+func DialJournald() (conn io.WriteCloser, err protocol.Error) {
+	var c, goErr = net.Dial("unixgram", JournaldSocket)
+	if goErr != nil {
+		return nil, &ErrSinkDial
+	}
+	return c, nil
+}
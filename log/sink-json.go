@@ -0,0 +1,82 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"encoding/hex"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// JSONFormatter format each event as one JSON object in a line (JSON Lines) e.g.
+// {"time":"2006-01-02T15:04:05.999999999Z","level":"error","domain":"HTTP","node":"hex-id","message":"...","stack":"..."}
+// node and stack fields omit if they are empty.
+type JSONFormatter struct{}
+
+//libgo:impl Formatter
+func (f JSONFormatter) Format(buf []byte, e protocol.LogEvent) []byte {
+	buf = append(buf, `{"time":"`...)
+	buf = appendTime(buf, e.Time(), time.RFC3339Nano)
+	buf = append(buf, `","level":`...)
+	buf = appendJSONString(buf, LevelString(e.Level()))
+	buf = append(buf, `,"domain":`...)
+	buf = appendJSONString(buf, e.Domain())
+	var nodeID = e.NodeID()
+	if nodeID != (protocol.NodeID{}) {
+		buf = append(buf, `,"node":"`...)
+		buf = append(buf, hex.EncodeToString(nodeID[:])...)
+		buf = append(buf, '"')
+	}
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, e.Message())
+	var stack = e.Stack()
+	if len(stack) > 0 {
+		buf = append(buf, `,"stack":`...)
+		buf = appendJSONString(buf, string(stack))
+	}
+	buf = append(buf, "}\n"...)
+	return buf
+}
+
+// appendJSONString append s as a JSON string. Invalid UTF-8 bytes replace by U+FFFD.
+func appendJSONString(buf []byte, s string) []byte {
+	const hexDigits = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		var b = s[i]
+		if b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\':
+				buf = append(buf, '\\', b)
+			case b == '\n':
+				buf = append(buf, '\\', 'n')
+			case b == '\r':
+				buf = append(buf, '\\', 'r')
+			case b == '\t':
+				buf = append(buf, '\\', 't')
+			case b < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			default:
+				buf = append(buf, b)
+			}
+			i++
+			continue
+		}
+		var r, size = utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, `\ufffd`...)
+		} else if r == '\u2028' || r == '\u2029' {
+			// Valid in JSON but not in JavaScript strings.
+			buf = append(buf, `\u`...)
+			buf = strconv.AppendInt(buf, int64(r), 16)
+		} else {
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	buf = append(buf, '"')
+	return buf
+}
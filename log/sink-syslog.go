@@ -0,0 +1,144 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// Syslog facilities that usually use by applications. https://www.rfc-editor.org/rfc/rfc5424#section-6.2.1
+const (
+	SyslogFacility_User   uint8 = 1
+	SyslogFacility_Daemon uint8 = 3
+	SyslogFacility_Local0 uint8 = 16
+)
+
+// SyslogFormatter format events as RFC 5424 syslog messages without any transport framing.
+// Empty Hostname and AppName write as nil value "-". Use Init() to fill them from the OS.
+type SyslogFormatter struct {
+	Facility uint8
+	Hostname string
+	AppName  string
+	procID   string
+}
+
+// Init fill the formatter by the OS hostname, executable name and the process ID.
+func (f *SyslogFormatter) Init(facility uint8) {
+	f.Facility = facility
+	f.Hostname, _ = os.Hostname()
+	f.AppName = filepath.Base(os.Args[0])
+	f.procID = strconv.Itoa(os.Getpid())
+}
+
+//libgo:impl Formatter
+func (f *SyslogFormatter) Format(buf []byte, e protocol.LogEvent) []byte {
+	// HEADER = PRI VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID
+	buf = append(buf, '<')
+	buf = strconv.AppendUint(buf, uint64(f.Facility)*8+uint64(SyslogSeverity(e.Level())), 10)
+	buf = append(buf, ">1 "...)
+	buf = appendTime(buf, e.Time(), "2006-01-02T15:04:05.000000Z07:00")
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, f.Hostname, 255)
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, f.AppName, 48)
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, f.procID, 128)
+	buf = append(buf, ' ')
+	buf = appendSyslogName(buf, e.Domain(), 32)
+	// No STRUCTURED-DATA
+	buf = append(buf, " - "...)
+	buf = append(buf, e.Message()...)
+	var stack = e.Stack()
+	if len(stack) > 0 {
+		buf = append(buf, '\n')
+		buf = append(buf, stack...)
+	}
+	return buf
+}
+
+// SyslogSeverity return the RFC 5424 severity of the given level.
+func SyslogSeverity(level protocol.LogType) uint8 {
+	switch level {
+	case protocol.LogEvent_Emergency, protocol.LogEvent_Fatal:
+		return 0
+	case protocol.LogEvent_Alert, protocol.LogEvent_Security:
+		return 1
+	case protocol.LogEvent_Critical, protocol.LogEvent_Panic:
+		return 2
+	case protocol.LogEvent_Error:
+		return 3
+	case protocol.LogEvent_Warning:
+		return 4
+	case protocol.LogEvent_Notice:
+		return 5
+	case protocol.LogEvent_Information, protocol.LogEvent_Confidential:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// appendSyslogName append the header field that just can be printable US-ASCII up to maxLen or "-" if it is empty.
+func appendSyslogName(buf []byte, name string, maxLen int) []byte {
+	if name == "" {
+		return append(buf, '-')
+	}
+	if len(name) > maxLen {
+		name = name[:maxLen]
+	}
+	for i := 0; i < len(name); i++ {
+		var b = name[i]
+		if b < 33 || b > 126 {
+			b = '_'
+		}
+		buf = append(buf, b)
+	}
+	return buf
+}
+
+// DialSyslog connect to the syslog daemon. Empty network means the local daemon unix datagram socket
+// in its usual paths. Stream networks like "tcp" and "unix" frame messages by octet counting as RFC 6587.
+func DialSyslog(network, address string) (conn io.WriteCloser, err protocol.Error) {
+	if network == "" {
+		for _, path := range [...]string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+			var c, goErr = net.Dial("unixgram", path)
+			if goErr == nil {
+				return c, nil
+			}
+		}
+		return nil, &ErrSinkDial
+	}
+
+	var c, goErr = net.Dial(network, address)
+	if goErr != nil {
+		return nil, &ErrSinkDial
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return &octetCountingConn{Conn: c}, nil
+	}
+	return c, nil
+}
+
+// octetCountingConn prefix each written message by its length as RFC 6587 section 3.4.1.
+type octetCountingConn struct {
+	net.Conn
+	buf []byte
+}
+
+func (c *octetCountingConn) Write(message []byte) (n int, err error) {
+	c.buf = strconv.AppendInt(c.buf[:0], int64(len(message)), 10)
+	c.buf = append(c.buf, ' ')
+	c.buf = append(c.buf, message...)
+	_, err = c.Conn.Write(c.buf)
+	if err == nil {
+		n = len(message)
+	}
+	return
+}
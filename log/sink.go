@@ -0,0 +1,126 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// DefaultSinkBufferSize is the number of events that a sink buffer when its writer is slower than the logger.
+const DefaultSinkBufferSize = 1024
+
+// Formatter encode a log event in a sink format e.g. console text, JSON line, syslog or journald message.
+type Formatter interface {
+	// Format append the encoded event to the buf and return it.
+	Format(buf []byte, event protocol.LogEvent) []byte
+}
+
+// Sink write log events to a writer in its own goroutine, so it respect non-blocking contract of protocol.EventListener.
+// Events buffer in a channel and drop when the buffer is full. Each event write by one Write() call,
+// so datagram writers like syslog and journald sockets receive each event in one message.
+// Register it by logger.AddEventListener(protocol.EventMainType_Log, protocol.EventSubType_Unset, &sink, options).
+type Sink struct {
+	// Levels is the bitmask of levels that the sink write. Zero means protocol.LogMode.
+	// Levels that CheckLevelEnabled() reject never write even if set here.
+	Levels protocol.LogType
+
+	writer    io.Writer
+	formatter Formatter
+	events    chan protocol.LogEvent
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	// closeMutex guard closed, so no event add to the buffer after Close() start to drain it.
+	closeMutex sync.RWMutex
+	closed     bool
+	dropped    uint64 // atomic access
+}
+
+// Init initialize the sink and start its writer goroutine. Zero bufferSize means DefaultSinkBufferSize.
+// Sink don't close the writer, so caller must close it if needed after Close() the sink.
+func (s *Sink) Init(writer io.Writer, formatter Formatter, bufferSize int) {
+	if s.Levels == 0 {
+		s.Levels = protocol.LogMode
+	}
+	if bufferSize == 0 {
+		bufferSize = DefaultSinkBufferSize
+	}
+	s.writer = writer
+	s.formatter = formatter
+	s.events = make(chan protocol.LogEvent, bufferSize)
+	s.quit = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.write()
+}
+
+// Close stop the sink after write buffered events. Events dispatch after close drop and count as dropped.
+func (s *Sink) Close() {
+	s.closeOnce.Do(func() {
+		s.closeMutex.Lock()
+		s.closed = true
+		s.closeMutex.Unlock()
+		close(s.quit)
+		<-s.done
+	})
+}
+
+// Dropped return number of events that dropped due to full buffer, failed writes or dispatch after close.
+func (s *Sink) Dropped() uint64 { return atomic.LoadUint64(&s.dropped) }
+
+// Enabled report the sink write events of the given level.
+func (s *Sink) Enabled(level protocol.LogType) bool {
+	return s.Levels&level != 0 && CheckLevelEnabled(level)
+}
+
+//libgo:impl protocol.EventListener
+func (s *Sink) EventHandler(event protocol.Event) {
+	var logEvent, ok = event.(protocol.LogEvent)
+	if !ok || !s.Enabled(logEvent.Level()) {
+		return
+	}
+	s.closeMutex.RLock()
+	if s.closed {
+		s.closeMutex.RUnlock()
+		atomic.AddUint64(&s.dropped, 1)
+		return
+	}
+	select {
+	case s.events <- logEvent:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	s.closeMutex.RUnlock()
+}
+
+func (s *Sink) write() {
+	defer close(s.done)
+	var buf []byte
+	for {
+		select {
+		case e := <-s.events:
+			buf = s.writeEvent(buf, e)
+		case <-s.quit:
+			for {
+				select {
+				case e := <-s.events:
+					buf = s.writeEvent(buf, e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Sink) writeEvent(buf []byte, e protocol.LogEvent) []byte {
+	buf = s.formatter.Format(buf[:0], e)
+	var _, err = s.writer.Write(buf)
+	if err != nil {
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return buf
+}
@@ -0,0 +1,168 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+func testSinkEvent(level protocol.LogType, message, stack string, nodeID protocol.NodeID) *Event {
+	var e = NewEvent(level, "HTTP", message)
+	var t unix.Time
+	t.ChangeTo(0, 5000)
+	e.SetTime(t)
+	e.SetNodeID(nodeID)
+	if stack != "" {
+		e.stack = []byte(stack)
+	}
+	return e
+}
+
+func TestFormatters(t *testing.T) {
+	var nodeID = protocol.NodeID{0xab, 0xcd}
+	var binaryStack = func(key, value string) string {
+		var ln [8]byte
+		binary.LittleEndian.PutUint64(ln[:], uint64(len(value)))
+		return key + "\n" + string(ln[:]) + value + "\n"
+	}
+	var tests = []struct {
+		name      string
+		formatter Formatter
+		event     *Event
+		want      string
+	}{
+		{"console", ConsoleFormatter{}, testSinkEvent(protocol.LogEvent_Error, "a b", "", protocol.NodeID{}),
+			"1970-01-01T00:00:00.000005Z error        [HTTP] a b\n"},
+		{"console stack", ConsoleFormatter{}, testSinkEvent(protocol.LogEvent_Warning, "a", "s1\ns2", protocol.NodeID{}),
+			"1970-01-01T00:00:00.000005Z warning      [HTTP] a\n\ts1\n\ts2\n"},
+		{"console color", ConsoleFormatter{Color: true}, testSinkEvent(protocol.LogEvent_Error, "a", "", protocol.NodeID{}),
+			"1970-01-01T00:00:00.000005Z \x1b[31merror\x1b[0m        [HTTP] a\n"},
+		{"json", JSONFormatter{}, testSinkEvent(protocol.LogEvent_Error, "a", "", protocol.NodeID{}),
+			`{"time":"1970-01-01T00:00:00.000005Z","level":"error","domain":"HTTP","message":"a"}` + "\n"},
+		{"json escape", JSONFormatter{}, testSinkEvent(protocol.LogEvent_Error, "\"q\"\\\n\t\x01\xff\u2028", "s1\ns2", nodeID),
+			`{"time":"1970-01-01T00:00:00.000005Z","level":"error","domain":"HTTP","node":"abcd0000000000000000000000000000",` +
+				`"message":"\"q\"\\\n\t\u0001\ufffd\u2028","stack":"s1\ns2"}` + "\n"},
+		{"syslog", &SyslogFormatter{Facility: SyslogFacility_User, Hostname: "my host"}, testSinkEvent(protocol.LogEvent_Error, "a", "", protocol.NodeID{}),
+			"<11>1 1970-01-01T00:00:00.000005Z my_host - - HTTP - a"},
+		{"syslog stack", &SyslogFormatter{Facility: SyslogFacility_Local0, Hostname: "h", AppName: "app"}, testSinkEvent(protocol.LogEvent_Fatal, "a", "s1\ns2", protocol.NodeID{}),
+			"<128>1 1970-01-01T00:00:00.000005Z h app - HTTP - a\ns1\ns2"},
+		{"journald", JournaldFormatter{Identifier: "app"}, testSinkEvent(protocol.LogEvent_Warning, "a", "", protocol.NodeID{}),
+			"MESSAGE=a\nPRIORITY=4\nSYSLOG_IDENTIFIER=app\nLOG_LEVEL=warning\nLOG_DOMAIN=HTTP\n"},
+		{"journald multi line", JournaldFormatter{Identifier: "app"}, testSinkEvent(protocol.LogEvent_Error, "a\nb", "s1\ns2", nodeID),
+			binaryStack("MESSAGE", "a\nb") + "PRIORITY=3\nSYSLOG_IDENTIFIER=app\nLOG_LEVEL=error\nLOG_DOMAIN=HTTP\n" +
+				"NODE_ID=abcd0000000000000000000000000000\n" + binaryStack("STACK", "s1\ns2")},
+	}
+	for _, tt := range tests {
+		if got := string(tt.formatter.Format([]byte("old"), tt.event)); got != "old"+tt.want {
+			t.Errorf("%s Format() = %q, want %q", tt.name, got, "old"+tt.want)
+		}
+	}
+}
+
+// testWriter record written messages. If block is not nil, Write signal writing if any one wait on it and wait on block.
+type testWriter struct {
+	mutex    sync.Mutex
+	messages []string
+	err      error
+	writing  chan struct{}
+	block    chan struct{}
+}
+
+func (w *testWriter) Write(p []byte) (n int, err error) {
+	if w.block != nil {
+		select {
+		case w.writing <- struct{}{}:
+		default:
+		}
+		<-w.block
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.messages = append(w.messages, string(p))
+	return len(p), nil
+}
+
+func TestSinkDropped(t *testing.T) {
+	var w = testWriter{
+		writing: make(chan struct{}),
+		block:   make(chan struct{}),
+	}
+	var s Sink
+	s.Init(&w, JSONFormatter{}, 1)
+
+	// First event block the writer goroutine, second one fill the buffer and third one drop.
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "1", "", protocol.NodeID{}))
+	<-w.writing
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "2", "", protocol.NodeID{}))
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "3", "", protocol.NodeID{}))
+	if got := s.Dropped(); got != 1 {
+		t.Errorf("Sink.Dropped() full buffer = %d, want 1", got)
+	}
+
+	// Not enabled levels are not dropped events.
+	s.Levels = protocol.LogEvent_Error
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Warning, "4", "", protocol.NodeID{}))
+	if got := s.Dropped(); got != 1 {
+		t.Errorf("Sink.Dropped() not enabled level = %d, want 1", got)
+	}
+
+	close(w.block)
+	s.Close()
+	if len(w.messages) != 2 {
+		t.Errorf("Sink wrote %d events before close, want 2", len(w.messages))
+	}
+
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "5", "", protocol.NodeID{}))
+	if got := s.Dropped(); got != 2 {
+		t.Errorf("Sink.Dropped() after close = %d, want 2", got)
+	}
+	if len(w.messages) != 2 {
+		t.Errorf("Sink wrote %d events after close, want 2", len(w.messages))
+	}
+}
+
+func TestSinkWriteFailed(t *testing.T) {
+	var w = testWriter{err: errors.New("write failed")}
+	var s Sink
+	s.Init(&w, ConsoleFormatter{}, 0)
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "1", "", protocol.NodeID{}))
+	s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "2", "", protocol.NodeID{}))
+	s.Close()
+	if got := s.Dropped(); got != 2 {
+		t.Errorf("Sink.Dropped() = %d, want 2", got)
+	}
+}
+
+// TestSinkCloseRace check any event that dispatch concurrent with Close is written or counted as dropped.
+func TestSinkCloseRace(t *testing.T) {
+	const senders, events = 4, 200
+	var w testWriter
+	var s Sink
+	s.Init(&w, JSONFormatter{}, 16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < events; j++ {
+				s.EventHandler(testSinkEvent(protocol.LogEvent_Error, "a", "", protocol.NodeID{}))
+			}
+		}()
+	}
+	s.Close()
+	wg.Wait()
+
+	if got := uint64(len(w.messages)) + s.Dropped(); got != senders*events {
+		t.Errorf("Sink written + dropped events = %d, want %d", got, senders*events)
+	}
+}
@@ -0,0 +1,294 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/GeniusesGroup/libgo/binary"
+	"github.com/GeniusesGroup/libgo/mediatypes"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+	"github.com/GeniusesGroup/libgo/timer"
+)
+
+// Storage default values that use when related field not set before Init.
+const (
+	DefaultMaxBatchSize  = 64 * 1024
+	DefaultFlushInterval = 10 * unix.Second
+)
+
+// Storage batch log events of a node and save them to the records storage, one record per node per day.
+// Record ID make by RecordID() and each flush of pending events save as a new version of the day record.
+// In each version, events encoded by Syllab and each one prefixed by its length as 4 byte little-endian.
+// Storage day only advance, so late events of a past day e.g. around midnight save in the current day record.
+type Storage struct {
+	// MaxBatchSize is the size in byte that pending events flush to the storage when reach it.
+	// If flush failed, pending events hold up to 4 times of it and new events dropped after that.
+	MaxBatchSize int
+	// MaxDaySize caps the size in byte of the node logs in each day. Zero means no cap.
+	MaxDaySize uint64
+	// RetentionDays is the number of days that logs keep in the storage. Zero means keep forever.
+	RetentionDays int64
+
+	records  protocol.StorageRecords
+	nodeID   [16]byte
+	nodeHash [8]byte
+	timer    timer.Async
+
+	mutex         sync.Mutex
+	day           unix.DayElapsed
+	daySize       uint64 // saved size of the day record
+	pending       []byte
+	pendingEvents uint64
+	dropped       uint64 // atomic access
+}
+
+// batch is the pending events of a day that swap out of the storage under the lock to write them after unlock,
+// so loggers never block on the storage I/O.
+type batch struct {
+	day    unix.DayElapsed
+	data   []byte
+	events uint64
+}
+
+// Init initialize the storage to save log events of the given node in the given records storage.
+func (s *Storage) Init(records protocol.StorageRecords, nodeID [16]byte) {
+	if s.MaxBatchSize == 0 {
+		s.MaxBatchSize = DefaultMaxBatchSize
+	}
+	s.records = records
+	s.nodeID = nodeID
+	s.nodeHash = nodeHash(nodeID)
+	s.day = -1
+	s.timer.Init(s)
+}
+
+// Start flush pending events in each interval. Zero interval means DefaultFlushInterval.
+func (s *Storage) Start(flushInterval protocol.Duration) (err protocol.Error) {
+	if flushInterval == 0 {
+		flushInterval = DefaultFlushInterval
+	}
+	err = s.timer.Tick(flushInterval, flushInterval)
+	return
+}
+
+// Stop stop the flush timer and flush pending events. Call it on app shutdown, otherwise pending events lost.
+func (s *Storage) Stop() (err protocol.Error) {
+	s.timer.Stop()
+	err = s.Flush()
+	return
+}
+
+func (s *Storage) NodeID() [16]byte { return s.nodeID }
+
+// Dropped return number of events that dropped due to MaxDaySize or storage failures.
+func (s *Storage) Dropped() uint64 { return atomic.LoadUint64(&s.dropped) }
+
+// Save add the event to pending events and flush them to the storage if needed.
+func (s *Storage) Save(logEvent protocol.LogEvent) (err protocol.Error) {
+	var e, ok = logEvent.(*Event)
+	if !ok {
+		e = new(Event)
+		e.FromLogEvent(logEvent)
+	}
+	var eventLen = 4 + int(e.LenAsSyllab())
+	var eventDay = unix.DayElapsed(e.Time().SecondElapsed() / (24 * 60 * 60))
+
+	s.mutex.Lock()
+	var lastDay batch
+	var rotated = eventDay > s.day
+	if rotated {
+		// Pending events belong to the last day, so write them to the last day record.
+		lastDay = s.takeBatch()
+		s.day = eventDay
+		// Stored size of the new day add after read it from the storage.
+		s.daySize = 0
+	}
+
+	if s.MaxDaySize > 0 && s.daySize+uint64(len(s.pending)+eventLen) > s.MaxDaySize ||
+		len(s.pending)+eventLen > 4*s.MaxBatchSize {
+		s.mutex.Unlock()
+		if rotated {
+			s.rotate(lastDay, eventDay)
+		}
+		return s.drop()
+	}
+
+	var ln = len(s.pending)
+	if cap(s.pending)-ln < eventLen {
+		var newPending = make([]byte, ln, ln+eventLen+s.MaxBatchSize)
+		copy(newPending, s.pending)
+		s.pending = newPending
+	}
+	s.pending = s.pending[:ln+eventLen]
+	var payload = s.pending[ln+4:]
+	binary.LittleEndian.PutUint32(s.pending[ln:], uint32(eventLen-4))
+	e.ToSyllab(payload, 0, e.LenOfSyllabStack())
+	s.pendingEvents++
+
+	var full batch
+	if len(s.pending) >= s.MaxBatchSize {
+		full = s.takeBatch()
+	}
+	s.mutex.Unlock()
+
+	if rotated {
+		err = s.rotate(lastDay, eventDay)
+	}
+	if writeErr := s.write(full); writeErr != nil {
+		err = writeErr
+	}
+	return
+}
+
+// Flush save pending events to the storage.
+func (s *Storage) Flush() (err protocol.Error) {
+	s.mutex.Lock()
+	var b = s.takeBatch()
+	s.mutex.Unlock()
+	return s.write(b)
+}
+
+// takeBatch swap out pending events. Caller must hold the mutex.
+func (s *Storage) takeBatch() (b batch) {
+	b = batch{day: s.day, data: s.pending, events: s.pendingEvents}
+	// Storage can hold the given record slice, so don't reuse it.
+	s.pending = nil
+	s.pendingEvents = 0
+	return
+}
+
+// write save the batch to the storage. Caller must not hold the mutex.
+// Events of a failed batch of the current day back to pending events to retry them by next flush.
+func (s *Storage) write(b batch) (err protocol.Error) {
+	if len(b.data) == 0 {
+		return
+	}
+	var options = protocol.StorageRecord_SaveOptions{
+		MaxVersion: protocol.StorageRecord_LastSourceVersion,
+	}
+	err = s.records.Save(mediaTypeID(), RecordID(s.nodeID, b.day), b.data, options)
+
+	s.mutex.Lock()
+	switch {
+	case err == nil:
+		if b.day == s.day {
+			s.daySize += uint64(len(b.data))
+		}
+	case b.day == s.day && len(b.data)+len(s.pending) <= 4*s.MaxBatchSize:
+		s.pending = append(b.data, s.pending...)
+		s.pendingEvents += b.events
+	default:
+		atomic.AddUint64(&s.dropped, b.events)
+	}
+	s.mutex.Unlock()
+	return
+}
+
+// rotate write pending events of the last day and read stored size of the new day.
+// Caller must not hold the mutex.
+func (s *Storage) rotate(lastDay batch, day unix.DayElapsed) (err protocol.Error) {
+	err = s.write(lastDay)
+	if s.RetentionDays > 0 {
+		go s.Clean(day - unix.DayElapsed(s.RetentionDays))
+	}
+
+	var daySize, sizeErr = s.storedSize(day)
+	if sizeErr != nil {
+		return sizeErr
+	}
+	s.mutex.Lock()
+	if s.day == day {
+		s.daySize += daySize
+	}
+	s.mutex.Unlock()
+	return
+}
+
+func (s *Storage) drop() (err protocol.Error) {
+	atomic.AddUint64(&s.dropped, 1)
+	return &ErrStorageFull
+}
+
+// storedSize return size of all versions of the node record in the given day.
+func (s *Storage) storedSize(day unix.DayElapsed) (size uint64, err protocol.Error) {
+	var id = RecordID(s.nodeID, day)
+	var versions uint64
+	versions, err = s.records.Count(mediaTypeID(), id, 0, protocol.StorageRecord_LastSourceVersion)
+	if err != nil {
+		return
+	}
+	for v := uint64(0); v < versions; v++ {
+		var ln int
+		ln, err = s.records.Length(mediaTypeID(), id, v)
+		if err != nil {
+			return
+		}
+		size += uint64(ln)
+	}
+	return
+}
+
+// Clean delete the node records of the days before the given day.
+func (s *Storage) Clean(before unix.DayElapsed) (err protocol.Error) {
+	const limit = 256
+	var mediaTypeID = mediaTypeID()
+	var ids [][16]byte
+	var offset uint64
+	for {
+		ids, err = s.records.ListRecords(mediaTypeID, offset, limit)
+		if err != nil {
+			return
+		}
+		var deleted uint64
+		for _, id := range ids {
+			var day, nodeHash = splitRecordID(id)
+			if nodeHash == s.nodeHash && day < before {
+				err = s.records.Delete(mediaTypeID, id)
+				if err != nil {
+					return
+				}
+				deleted++
+			}
+		}
+		if len(ids) < limit {
+			return
+		}
+		// Deleted records removed from the list, so next records shift back.
+		offset += limit - deleted
+	}
+}
+
+//libgo:impl protocol.TimerListener
+func (s *Storage) TimerHandler() {
+	// Flush can block on the storage, so don't block the timer.
+	go s.Flush()
+}
+
+// RecordID return the record ID of the logs of the given node in the given day.
+// First 8 byte is the day as little-endian and last 8 byte is the first 8 byte of SHA3-256 hash of the node ID
+// to let find records of a node in a day without any index.
+func RecordID(nodeID [16]byte, day unix.DayElapsed) (id [16]byte) {
+	binary.LittleEndian.PutUint64(id[:], uint64(day))
+	var hash = nodeHash(nodeID)
+	copy(id[8:], hash[:])
+	return
+}
+
+func splitRecordID(id [16]byte) (day unix.DayElapsed, hash [8]byte) {
+	day = unix.DayElapsed(binary.LittleEndian.Uint64(id[:]))
+	copy(hash[:], id[8:])
+	return
+}
+
+func nodeHash(nodeID [16]byte) (hash [8]byte) {
+	var sum = sha3.Sum256(nodeID[:])
+	copy(hash[:], sum[:])
+	return
+}
+
+func mediaTypeID() uint64 { return uint64(mediatypes.LOG.ID()) }
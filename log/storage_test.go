@@ -0,0 +1,243 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package log
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/time/unix"
+)
+
+// testRecords is an in memory protocol.StorageRecords that keep versions of each record.
+// If block is not nil, Save wait on it after signal saving, to let tests check callers don't block on it.
+type testRecords struct {
+	protocol.StorageRecords
+
+	mutex   sync.Mutex
+	records map[[16]byte][][]byte
+	saveErr protocol.Error
+	saving  chan struct{}
+	block   chan struct{}
+}
+
+func (r *testRecords) Save(mediaTypeID uint64, id [16]byte, record []byte, options protocol.StorageRecord_SaveOptions) (err protocol.Error) {
+	if r.block != nil {
+		r.saving <- struct{}{}
+		<-r.block
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	if r.records == nil {
+		r.records = make(map[[16]byte][][]byte)
+	}
+	r.records[id] = append(r.records[id], record)
+	return
+}
+func (r *testRecords) Count(mediaTypeID uint64, id [16]byte, offset, limit uint64) (numbers uint64, err protocol.Error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return uint64(len(r.records[id])), nil
+}
+func (r *testRecords) Length(mediaTypeID uint64, id [16]byte, versionOffset uint64) (ln int, err protocol.Error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.records[id][versionOffset]), nil
+}
+func (r *testRecords) Get(mediaTypeID uint64, id [16]byte, versionOffset uint64) (record []byte, numbers uint64, err protocol.Error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.records[id][versionOffset], uint64(len(r.records[id])), nil
+}
+
+// events return number of events in all versions of the record.
+func (r *testRecords) events(t *testing.T, id [16]byte) (n int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, record := range r.records[id] {
+		var _, err = DecodeRecord(record, func(e *Event) bool { n++; return true })
+		if err != nil {
+			t.Fatalf("DecodeRecord() error = %v", err)
+		}
+	}
+	return
+}
+
+var testNodeID = [16]byte{1, 2, 3}
+
+func testTime(day unix.DayElapsed) (t unix.Time) {
+	t.ChangeTo(unix.SecElapsed(int64(day)*24*60*60+60), 0)
+	return
+}
+
+func testEvent(day unix.DayElapsed, message string) *Event {
+	var e = InfoEvent("test", message)
+	e.SetTime(testTime(day))
+	return e
+}
+
+func TestStorageSaveFlush(t *testing.T) {
+	var records testRecords
+	var s = Storage{MaxBatchSize: 1024}
+	s.Init(&records, testNodeID)
+
+	for i := 0; i < 3; i++ {
+		if err := s.Save(testEvent(100, "message")); err != nil {
+			t.Fatalf("Storage.Save() error = %v", err)
+		}
+	}
+	if got := records.events(t, RecordID(testNodeID, 100)); got != 0 {
+		t.Errorf("Storage.Save() stored %d events before flush, want 0", got)
+	}
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Storage.Flush() error = %v", err)
+	}
+	if got := records.events(t, RecordID(testNodeID, 100)); got != 3 {
+		t.Errorf("Storage.Flush() stored %d events, want 3", got)
+	}
+
+	// Reach max batch size flush pending events.
+	for i := 0; i < 100 && records.events(t, RecordID(testNodeID, 100)) == 3; i++ {
+		s.Save(testEvent(100, "message"))
+	}
+	if got := records.events(t, RecordID(testNodeID, 100)); got == 3 {
+		t.Errorf("Storage.Save() don't flush full batch")
+	}
+}
+
+func TestStorageRotate(t *testing.T) {
+	var records testRecords
+	var s = Storage{MaxBatchSize: 1024}
+	s.Init(&records, testNodeID)
+
+	var days = []unix.DayElapsed{100, 101, 100, 101, 100, 102}
+	for _, day := range days {
+		if err := s.Save(testEvent(day, "message")); err != nil {
+			t.Fatalf("Storage.Save() error = %v", err)
+		}
+	}
+	s.Flush()
+
+	// Late events of day 100 save in the day 101 record, so day 101 flush just once on rotate to day 102.
+	var tests = []struct {
+		day      unix.DayElapsed
+		versions int
+		events   int
+	}{
+		{100, 1, 1},
+		{101, 1, 4},
+		{102, 1, 1},
+	}
+	for _, tt := range tests {
+		var id = RecordID(testNodeID, tt.day)
+		var versions, _ = records.Count(0, id, 0, 0)
+		if int(versions) != tt.versions || records.events(t, id) != tt.events {
+			t.Errorf("day %d record versions = %d, events = %d, want %d, %d", tt.day, versions, records.events(t, id), tt.versions, tt.events)
+		}
+	}
+
+	var events, err = s.Find(Query{NodeID: testNodeID, From: testTime(100), To: testTime(100)})
+	if err != nil || len(events) != 3 {
+		t.Errorf("Storage.Find() day 100 events = %d, error = %v, want 3 events include late ones", len(events), err)
+	}
+}
+
+func TestStorageSaveNotBlockOnWrite(t *testing.T) {
+	var records = testRecords{
+		saving: make(chan struct{}),
+		block:  make(chan struct{}),
+	}
+	var s = Storage{MaxBatchSize: 1024}
+	s.Init(&records, testNodeID)
+	s.Save(testEvent(100, "message"))
+
+	var flushed = make(chan protocol.Error)
+	go func() { flushed <- s.Flush() }()
+	<-records.saving
+
+	var saved = make(chan protocol.Error)
+	go func() { saved <- s.Save(testEvent(100, "message")) }()
+	select {
+	case err := <-saved:
+		if err != nil {
+			t.Errorf("Storage.Save() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Storage.Save() blocked on storage write of other flush")
+	}
+
+	close(records.block)
+	if err := <-flushed; err != nil {
+		t.Errorf("Storage.Flush() error = %v", err)
+	}
+	records.block = nil
+	s.Flush()
+	if got := records.events(t, RecordID(testNodeID, 100)); got != 2 {
+		t.Errorf("stored events = %d, want 2", got)
+	}
+}
+
+func TestStorageWriteFailed(t *testing.T) {
+	var saveErr er.Error
+	var records = testRecords{saveErr: &saveErr}
+	var s = Storage{MaxBatchSize: 1024}
+	s.Init(&records, testNodeID)
+
+	s.Save(testEvent(100, "message"))
+	if err := s.Flush(); err != &saveErr {
+		t.Errorf("Storage.Flush() error = %v, want %v", err, &saveErr)
+	}
+	s.Save(testEvent(100, "message"))
+
+	// Failed events of the current day retry by next flush.
+	records.saveErr = nil
+	if err := s.Flush(); err != nil {
+		t.Errorf("Storage.Flush() error = %v", err)
+	}
+	if got := records.events(t, RecordID(testNodeID, 100)); got != 2 {
+		t.Errorf("stored events = %d, want 2", got)
+	}
+
+	// Failed events of a past day can't retry after rotate, so they dropped.
+	records.saveErr = &saveErr
+	s.Save(testEvent(100, "message"))
+	s.Save(testEvent(101, "message"))
+	if got := s.Dropped(); got != 1 {
+		t.Errorf("Storage.Dropped() = %d, want 1", got)
+	}
+}
+
+func TestStorageMaxDaySize(t *testing.T) {
+	var records testRecords
+	var s = Storage{MaxBatchSize: 1024, MaxDaySize: 200}
+	s.Init(&records, testNodeID)
+
+	var saved int
+	var err protocol.Error
+	for err == nil {
+		err = s.Save(testEvent(100, "message"))
+		if err == nil {
+			saved++
+		}
+	}
+	if err != &ErrStorageFull || s.Dropped() != 1 {
+		t.Errorf("Storage.Save() error = %v, Dropped() = %d, want %v, 1", err, s.Dropped(), &ErrStorageFull)
+	}
+	s.Flush()
+	if err = s.Save(testEvent(100, "message")); err != &ErrStorageFull {
+		t.Errorf("Storage.Save() after flush error = %v, want %v", err, &ErrStorageFull)
+	}
+	// Next day has its own cap.
+	if err = s.Save(testEvent(101, "message")); err != nil {
+		t.Errorf("Storage.Save() next day error = %v", err)
+	}
+	if got := records.events(t, RecordID(testNodeID, 100)); got != saved {
+		t.Errorf("stored events = %d, want %d", got, saved)
+	}
+}
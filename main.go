@@ -3,9 +3,11 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/GeniusesGroup/libgo/modules"
+	"github.com/GeniusesGroup/libgo/protocol"
 )
 
 func init() {
@@ -17,6 +19,11 @@ func main() {
 	var args = os.Args[1:]
 	var err = modules.RootCommand.ServeCLA(args)
 	if err != nil {
+		if detail := err.Detail(protocol.AppLanguage); detail != nil {
+			fmt.Fprintln(os.Stderr, detail.Summary()+": "+detail.Overview())
+		} else {
+			fmt.Fprintln(os.Stderr, err.Error())
+		}
 		os.Exit(1)
 	}
 }
@@ -3,38 +3,442 @@
 package minify
 
 import (
-	"../protocol"
+	"bytes"
 
-	tcss "github.com/tdewolff/minify/css"
-	"github.com/tdewolff/parse/buffer"
+	"../protocol"
 )
 
 var CSS css
 
-type css struct {
-	tcss.Minifier
-}
+type css struct{}
 
 // Minify replace file data with minify of them.
 func (css *css) Minify(data protocol.Codec) (err protocol.Error) {
-	var rawData = data.Marshal()
-	var minifiedData []byte
-	minifiedData, err = css.MinifyBytes(rawData)
+	return minify(data, css.MinifyBytes)
+}
+
+// MinifyBytes remove comments and not needed whitespace of given CSS and shorten colors and numbers in declaration values.
+// Comments start with "/*!" are license comments and keep as is.
+func (css *css) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
+	var m = cssMinifier{
+		data: data,
+		out:  make([]byte, 0, len(data)),
+	}
+	err = m.minify()
 	if err != nil {
 		return
 	}
-	err = data.Unmarshal(minifiedData)
+	minifiedData = m.out
 	return
 }
 
-// MinifyBytes replace file data with minify of them.
-func (css *css) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
-	var minifiedWriter = buffer.NewWriter(make([]byte, 0, len(data)))
-	var goErr = css.Minifier.Minify(minifier, minifiedWriter, buffer.NewReader(data), nil)
-	if goErr != nil {
-		// err = 
+type cssMinifier struct {
+	data []byte
+	pos  int
+	out  []byte
+
+	blocks       []bool // true means declaration block, false means rule list block e.g. @media
+	parens       int
+	inValue      bool // in a declaration value i.e. after "property:" until ";" or "}"
+	pendingSpace bool // whitespace or comment read that maybe needed between next and last token
+	separated    bool // last token is a separator e.g. "{" or ",", so no space needed after it
+}
+
+func (m *cssMinifier) minify() (err protocol.Error) {
+	for m.pos < len(m.data) {
+		var c = m.data[m.pos]
+		switch {
+		case isSpace(c):
+			m.pos++
+			m.pendingSpace = !m.separated
+		case c == '/' && m.peek(1) == '*':
+			err = m.comment()
+		case c == '"' || c == '\'':
+			err = m.string()
+		case c == '{':
+			m.openBlock()
+		case c == '}':
+			if len(m.blocks) == 0 {
+				return &ErrUnbalancedBlock
+			}
+			m.blocks = m.blocks[:len(m.blocks)-1]
+			m.inValue = false
+			m.pendingSpace = false
+			if last := len(m.out) - 1; last >= 0 && m.out[last] == ';' {
+				m.out = m.out[:last]
+			}
+			m.writeByte('}')
+			m.separated = true
+		case c == ';':
+			m.inValue = false
+			m.pendingSpace = false
+			// Drop empty statements.
+			if last := len(m.out) - 1; last >= 0 && m.out[last] != ';' && m.out[last] != '{' {
+				m.writeByte(';')
+			} else {
+				m.pos++
+			}
+			m.separated = true
+		case c == ':':
+			if m.inDeclarationBlock() && !m.inValue && m.parens == 0 && m.isDeclaration() {
+				m.inValue = true
+				m.pendingSpace = false
+			}
+			m.writeToken(':')
+			m.separated = true
+		case c == '(':
+			m.parens++
+			m.writeToken('(')
+			m.separated = true
+		case c == ')':
+			if m.parens == 0 {
+				return &ErrUnbalancedBlock
+			}
+			m.parens--
+			m.pendingSpace = false
+			m.writeByte(')')
+		case c == ',':
+			m.pendingSpace = false
+			m.writeByte(',')
+			m.separated = true
+		case c == '!':
+			m.pendingSpace = false
+			m.writeByte('!')
+			m.separated = true
+		case (c == '>' || c == '+' || c == '~') && m.parens == 0 && !m.inValue && !m.isNumberStart():
+			// Selector combinator
+			m.pendingSpace = false
+			m.writeByte(c)
+			m.separated = true
+		case c == '#' && m.inValue:
+			m.hexColor()
+		case m.inValue && m.isNumberStart():
+			m.number()
+		case isCSSIdentStart(c) || c == '\\' || (c == '-' && (isCSSIdentStart(m.peek(1)) || m.peek(1) == '-')):
+			err = m.ident()
+		default:
+			m.writeToken(c)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(m.blocks) != 0 || m.parens != 0 {
+		return &ErrUnbalancedBlock
+	}
+	return
+}
+
+func (m *cssMinifier) peek(n int) byte {
+	if m.pos+n < len(m.data) {
+		return m.data[m.pos+n]
+	}
+	return 0
+}
+
+func (m *cssMinifier) inDeclarationBlock() bool {
+	return len(m.blocks) > 0 && m.blocks[len(m.blocks)-1]
+}
+
+// writeByte write given byte that is the current data byte.
+func (m *cssMinifier) writeByte(c byte) {
+	m.out = append(m.out, c)
+	m.pos++
+	m.separated = false
+}
+
+// writeToken write given byte that is the current data byte with a space before it if needed.
+func (m *cssMinifier) writeToken(c byte) {
+	m.writePendingSpace()
+	m.writeByte(c)
+}
+
+func (m *cssMinifier) writePendingSpace() {
+	if m.pendingSpace && len(m.out) > 0 {
+		m.out = append(m.out, ' ')
+	}
+	m.pendingSpace = false
+	m.separated = false
+}
+
+func (m *cssMinifier) comment() (err protocol.Error) {
+	var end = bytes.Index(m.data[m.pos+2:], []byte("*/"))
+	if end == -1 {
+		return &ErrUnterminatedComment
+	}
+	end += m.pos + 4
+	if m.peek(2) == '!' {
+		m.writePendingSpace()
+		m.out = append(m.out, m.data[m.pos:end]...)
+		m.separated = true
+	} else {
+		// Comment separate tokens like whitespace e.g. "a/**/b" is two idents.
+		m.pendingSpace = !m.separated
+	}
+	m.pos = end
+	return
+}
+
+func (m *cssMinifier) string() (err protocol.Error) {
+	var quote = m.data[m.pos]
+	var i = m.pos + 1
+	for ; i < len(m.data); i++ {
+		switch m.data[i] {
+		case '\\':
+			i++
+		case '\n':
+			return &ErrUnterminatedString
+		case quote:
+			m.writePendingSpace()
+			m.out = append(m.out, m.data[m.pos:i+1]...)
+			m.pos = i + 1
+			return
+		}
+	}
+	return &ErrUnterminatedString
+}
+
+// openBlock write "{" and detect the block content by its prelude.
+func (m *cssMinifier) openBlock() {
+	var preludeStart = bytes.LastIndexAny(m.out, "{};") + 1
+	var prelude = m.out[preludeStart:]
+	var declaration = true
+	if len(prelude) > 0 && prelude[0] == '@' {
+		var name = prelude[1:]
+		for i, c := range name {
+			if !isCSSIdentChar(c) {
+				name = name[:i]
+				break
+			}
+		}
+		switch string(bytes.ToLower(name)) {
+		case "media", "supports", "document", "-moz-document", "layer", "container", "scope", "starting-style":
+			declaration = false
+		default:
+			declaration = !bytes.HasSuffix(bytes.ToLower(name), []byte("keyframes"))
+		}
+	}
+	m.blocks = append(m.blocks, declaration)
+	m.inValue = false
+	m.pendingSpace = false
+	m.writeByte('{')
+	m.separated = true
+}
+
+// isDeclaration report the colon in current position is a declaration colon not a pseudo class colon of a nested rule selector.
+func (m *cssMinifier) isDeclaration() bool {
+	var parens int
+	for i := m.pos + 1; i < len(m.data); i++ {
+		switch m.data[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			var quote = m.data[i]
+			for i++; i < len(m.data) && m.data[i] != quote; i++ {
+				if m.data[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			parens++
+		case ')':
+			parens--
+		case ';', '}':
+			return true
+		case '{':
+			if parens <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (m *cssMinifier) isNumberStart() bool {
+	var c = m.data[m.pos]
+	if c == '+' || c == '-' {
+		c = m.peek(1)
+		if c == '.' {
+			return isDigit(m.peek(2))
+		}
+		return isDigit(c) && m.notAfterIdent()
+	}
+	if c == '.' {
+		return isDigit(m.peek(1)) && m.notAfterIdent()
+	}
+	return isDigit(c) && m.notAfterIdent()
+}
+
+// notAfterIdent report current position is not part of an ident e.g. "h1" or a hex color.
+func (m *cssMinifier) notAfterIdent() bool {
+	return m.pendingSpace || len(m.out) == 0 || !isCSSIdentChar(m.out[len(m.out)-1]) && m.out[len(m.out)-1] != '#'
+}
+
+// number write the number in shortest form e.g. "0.50" as ".5" and "10.0" as "10".
+// Unit of the number write later as an ident.
+func (m *cssMinifier) number() {
+	m.writePendingSpace()
+	var c = m.data[m.pos]
+	if c == '+' || c == '-' {
+		if c == '-' {
+			m.out = append(m.out, '-')
+		}
+		m.pos++
+	}
+	var intStart = m.pos
+	for m.pos < len(m.data) && isDigit(m.data[m.pos]) {
+		m.pos++
+	}
+	var intPart = m.data[intStart:m.pos]
+	var fracPart []byte
+	if m.pos < len(m.data) && m.data[m.pos] == '.' && isDigit(m.peek(1)) {
+		var fracStart = m.pos + 1
+		m.pos++
+		for m.pos < len(m.data) && isDigit(m.data[m.pos]) {
+			m.pos++
+		}
+		fracPart = m.data[fracStart:m.pos]
+	}
+	var exponent []byte
+	if c := m.peek(0); c == 'e' || c == 'E' {
+		var n = 1
+		if m.peek(1) == '+' || m.peek(1) == '-' {
+			n = 2
+		}
+		if isDigit(m.peek(n)) {
+			var expStart = m.pos
+			m.pos += n
+			for m.pos < len(m.data) && isDigit(m.data[m.pos]) {
+				m.pos++
+			}
+			exponent = m.data[expStart:m.pos]
+		}
+	}
+
+	intPart = bytes.TrimLeft(intPart, "0")
+	fracPart = bytes.TrimRight(fracPart, "0")
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		m.out = append(m.out, '0')
+	} else {
+		m.out = append(m.out, intPart...)
+		if len(fracPart) > 0 {
+			m.out = append(m.out, '.')
+			m.out = append(m.out, fracPart...)
+		}
+	}
+	m.out = append(m.out, exponent...)
+}
+
+// hexColor write lower case color in shortest form e.g. "#AABBCC" as "#abc".
+func (m *cssMinifier) hexColor() {
+	m.writePendingSpace()
+	var start = m.pos + 1
+	var end = start
+	for end < len(m.data) && isCSSIdentChar(m.data[end]) {
+		end++
+	}
+	var hex = m.data[start:end]
+	m.pos = end
+	if !isHex(hex) || (len(hex) != 3 && len(hex) != 4 && len(hex) != 6 && len(hex) != 8) {
+		m.out = append(m.out, m.data[start-1:end]...)
+		return
+	}
+
+	m.out = append(m.out, '#')
+	if (len(hex) == 6 || len(hex) == 8) && hasPairDigits(hex) {
+		for i := 0; i < len(hex); i += 2 {
+			m.out = append(m.out, toLower(hex[i]))
+		}
 		return
 	}
-	minifiedData = minifiedWriter.Bytes()
+	for _, c := range hex {
+		m.out = append(m.out, toLower(c))
+	}
+}
+
+// ident write an ident and raw content of its url() function if it is "url".
+func (m *cssMinifier) ident() (err protocol.Error) {
+	m.writePendingSpace()
+	var start = m.pos
+	for m.pos < len(m.data) {
+		var c = m.data[m.pos]
+		if c == '\\' {
+			m.pos += 2
+			if m.pos > len(m.data) {
+				// A trailing backslash is copied as is.
+				m.pos = len(m.data)
+			}
+		} else if isCSSIdentChar(c) {
+			m.pos++
+		} else {
+			break
+		}
+	}
+	var ident = m.data[start:m.pos]
+	m.out = append(m.out, ident...)
+	if len(ident) == 1 && toLower(ident[0]) == 'u' && m.peek(0) == '+' {
+		// unicode-range e.g. "U+0025-00FF" is not a number.
+		m.out = append(m.out, '+')
+		m.pos++
+		for m.pos < len(m.data) && (isHex(m.data[m.pos:m.pos+1]) || m.data[m.pos] == '?' || m.data[m.pos] == '-') {
+			m.out = append(m.out, m.data[m.pos])
+			m.pos++
+		}
+		return
+	}
+	if !bytes.EqualFold(ident, []byte("url")) || m.peek(0) != '(' {
+		return
+	}
+
+	// Unquoted url may have any character that must keep as is e.g. "//".
+	var i = m.pos + 1
+	for i < len(m.data) && isSpace(m.data[i]) {
+		i++
+	}
+	if i < len(m.data) && (m.data[i] == '"' || m.data[i] == '\'') {
+		return
+	}
+	var end = bytes.IndexByte(m.data[i:], ')')
+	if end == -1 {
+		return &ErrUnbalancedBlock
+	}
+	m.out = append(m.out, '(')
+	m.out = append(m.out, bytes.TrimRight(m.data[i:i+end], " \t\n\r\f")...)
+	m.out = append(m.out, ')')
+	m.pos = i + end + 1
 	return
 }
+
+func isCSSIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
+
+func isCSSIdentChar(c byte) bool { return isCSSIdentStart(c) || isDigit(c) || c == '-' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHex(b []byte) bool {
+	for _, c := range b {
+		if !isDigit(c) && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPairDigits report each two following hex digits are same e.g. "aabbcc".
+func hasPairDigits(hex []byte) bool {
+	for i := 0; i < len(hex); i += 2 {
+		if toLower(hex[i]) != toLower(hex[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestCSSMinifyBytes(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+		want string
+		err  protocol.Error
+	}{
+		{"empty", "", "", nil},
+		{"whitespace", "a  {\n\tcolor : red ;\n}\n", "a{color:red}", nil},
+		{"comments", "/* a */ a { /* b */ color: red; }", "a{color:red}", nil},
+		{"license comment", "/*! License */\na{color:red}", "/*! License */a{color:red}", nil},
+		{"comment separate idents", "a/**/b{c:d}", "a b{c:d}", nil},
+		{"descendant selector", "div  p , ul > li + a ~ b{c:d}", "div p,ul>li+a~b{c:d}", nil},
+		{"pseudo class", "a:hover , a::before{c:d}", "a:hover,a::before{c:d}", nil},
+		{"empty statements", "a{;color:red;;}", "a{color:red}", nil},
+		{"hex colors", "a{color:#AABBCC;background:#aabbccdd;border-color:#ABCDEF}", "a{color:#abc;background:#abcd;border-color:#abcdef}", nil},
+		{"hex in selector", "#AABBCC{c:d}", "#AABBCC{c:d}", nil},
+		{"numbers", "a{margin:0.50em 10.0px -0.0px 010px;width:1e3px}", "a{margin:.5em 10px -0px 10px;width:1e3px}", nil},
+		{"number in ident", "h1{margin:1px}", "h1{margin:1px}", nil},
+		{"strings", `a{content:"a  b";font-family:'x  y'}`, `a{content:"a  b";font-family:'x  y'}`, nil},
+		{"string with comment", `a{content:"/* x */"}`, `a{content:"/* x */"}`, nil},
+		{"escaped quote", `a{content:"a\"  b"}`, `a{content:"a\"  b"}`, nil},
+		{"unquoted url", "a{background:url( //x.y/a  b.png )}", "a{background:url(//x.y/a  b.png)}", nil},
+		{"calc", "a{width:calc(100% - 2px)}", "a{width:calc(100% - 2px)}", nil},
+		{"important", "a{color:red !important}", "a{color:red!important}", nil},
+		{"media", "@media screen and (max-width: 600px) { a { color: red } }", "@media screen and (max-width:600px){a{color:red}}", nil},
+		{"keyframes", "@keyframes x { from { top: 0px } to { top: 10px } }", "@keyframes x{from{top:0px}to{top:10px}}", nil},
+		{"nested rule", "a { color: red; &:hover { color: blue } }", "a{color:red;&:hover{color:blue}}", nil},
+		{"unicode range", "@font-face{unicode-range:U+0025-00FF}", "@font-face{unicode-range:U+0025-00FF}", nil},
+		{"unterminated comment", "a{color:red} /* x", "", &ErrUnterminatedComment},
+		{"unterminated string", "a{content:\"x\n}", "", &ErrUnterminatedString},
+		{"unbalanced block", "a{color:red}}", "", &ErrUnbalancedBlock},
+		{"unclosed block", "a{color:red", "", &ErrUnbalancedBlock},
+		{"unbalanced paren", "a{width:calc(1px}", "", &ErrUnbalancedBlock},
+	}
+	for _, tt := range tests {
+		var got, err = CSS.MinifyBytes([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("CSS.MinifyBytes() %s error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("CSS.MinifyBytes() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
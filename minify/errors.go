@@ -0,0 +1,79 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	er "../error"
+	"../protocol"
+)
+
+const domainEnglish = "Minify"
+
+// Errors
+var (
+	ErrNotSupported        er.Error
+	ErrUnterminatedComment er.Error
+	ErrUnterminatedString  er.Error
+	ErrUnterminatedRegExp  er.Error
+	ErrUnbalancedBlock     er.Error
+	ErrMalformedTag        er.Error
+	ErrMalformedJSON       er.Error
+)
+
+func init() {
+	ErrNotSupported.Init("domain/minify.protocol; type=error; name=not-supported")
+	ErrNotSupported.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Not Supported",
+		"No minifier exist for given data media type",
+		"",
+		"",
+		nil)
+
+	ErrUnterminatedComment.Init("domain/minify.protocol; type=error; name=unterminated-comment")
+	ErrUnterminatedComment.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Unterminated Comment",
+		"A comment in given data opened but never closed",
+		"",
+		"",
+		nil)
+
+	ErrUnterminatedString.Init("domain/minify.protocol; type=error; name=unterminated-string")
+	ErrUnterminatedString.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Unterminated String",
+		"A string or template literal in given data opened but never closed",
+		"",
+		"",
+		nil)
+
+	ErrUnterminatedRegExp.Init("domain/minify.protocol; type=error; name=unterminated-regexp")
+	ErrUnterminatedRegExp.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Unterminated Regular Expression",
+		"A regular expression literal in given JavaScript opened but never closed in its line",
+		"",
+		"",
+		nil)
+
+	ErrUnbalancedBlock.Init("domain/minify.protocol; type=error; name=unbalanced-block")
+	ErrUnbalancedBlock.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Unbalanced Block",
+		"Given data has a block or parenthesis that closed without open or opened without close",
+		"",
+		"",
+		nil)
+
+	ErrMalformedTag.Init("domain/minify.protocol; type=error; name=malformed-tag")
+	ErrMalformedTag.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Malformed Tag",
+		"A tag in given HTML opened but never closed by '>'",
+		"",
+		"",
+		nil)
+
+	ErrMalformedJSON.Init("domain/minify.protocol; type=error; name=malformed-json")
+	ErrMalformedJSON.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Malformed JSON",
+		"Given JSON has syntax error",
+		"",
+		"",
+		nil)
+}
@@ -3,47 +3,523 @@
 package minify
 
 import (
-	"regexp"
+	"bytes"
 
 	"../protocol"
-
-	thtml "github.com/tdewolff/minify/html"
-	"github.com/tdewolff/parse/buffer"
 )
 
 var HTML html
 
-var htmlMinifier = regexp.MustCompile(`\r?\n|(<!--.*?-->)|(<!--[\w\W\n\s]+?-->)`)
-
-type html struct {
-	thtml.Minifier
-}
+type html struct{}
 
 // Minify replace file data with minify of them.
 func (html *html) Minify(data protocol.Codec) (err protocol.Error) {
-	var rawData = data.Marshal()
-	var minifiedData []byte
-	minifiedData, err = html.MinifyBytes(rawData)
+	return minify(data, html.MinifyBytes)
+}
+
+// MinifyBytes remove comments, collapse whitespace, omit optional end tags and not needed attribute quotes of given HTML.
+// Content of pre and textarea elements keep as is and content of script and style elements minify by JS, JSON and CSS minifiers.
+// Conditional comments e.g. "<!--[if IE]>" keep as is.
+func (html *html) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
+	var m = htmlMinifier{
+		out:       make([]byte, 0, len(data)),
+		lastBlock: true,
+	}
+	m.tokens, err = htmlTokenize(data)
+	if err != nil {
+		return
+	}
+	err = m.minify()
 	if err != nil {
 		return
 	}
-	err = data.Unmarshal(minifiedData)
+	minifiedData = m.out
 	return
 }
 
-// MinifyBytes replace file data with minify of them.
-func (html *html) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
-	// TODO::: have problem minify HTML >> https://github.com/tdewolff/minify/issues/318
-	if true {
-		minifiedData = htmlMinifier.ReplaceAll(data, []byte{})
+type htmlTokenKind uint8
+
+const (
+	htmlToken_Text     htmlTokenKind = iota
+	htmlToken_RawText                // content of script, style, textarea, ... elements
+	htmlToken_StartTag               //
+	htmlToken_EndTag                 //
+	htmlToken_Comment                //
+	htmlToken_Raw                    // doctype, CDATA, processing instruction write as is
+)
+
+type htmlToken struct {
+	kind        htmlTokenKind
+	name        string // lower case tag name
+	data        []byte // source of the token
+	attributes  []htmlAttribute
+	selfClosing bool
+}
+
+type htmlAttribute struct {
+	name     []byte
+	value    []byte
+	quote    byte // 0 means unquoted
+	hasValue bool
+}
+
+type htmlMinifier struct {
+	tokens []htmlToken
+	out    []byte
+
+	lastBlock bool // last written token is a block level tag or nothing written yet
+	preDepth  int
+}
+
+func (m *htmlMinifier) minify() (err protocol.Error) {
+	for i := range m.tokens {
+		var token = &m.tokens[i]
+		switch token.kind {
+		case htmlToken_Text:
+			m.text(i)
+		case htmlToken_RawText:
+			err = m.rawText(i)
+			if err != nil {
+				return
+			}
+		case htmlToken_StartTag:
+			m.startTag(token)
+		case htmlToken_EndTag:
+			if m.preDepth == 0 && m.isOptionalEndTag(i) {
+				m.lastBlock = true
+				continue
+			}
+			if token.name == "pre" && m.preDepth > 0 {
+				m.preDepth--
+			}
+			m.out = append(m.out, "</"...)
+			m.out = append(m.out, token.data[2:2+len(token.name)]...)
+			m.out = append(m.out, '>')
+			m.lastBlock = m.preDepth == 0 && isHTMLBlock(token.name)
+		case htmlToken_Comment:
+			if isConditionalComment(token.data) {
+				m.out = append(m.out, token.data...)
+			}
+		case htmlToken_Raw:
+			if bytes.EqualFold(bytes.Join(bytes.Fields(token.data), []byte(" ")), []byte("<!doctype html>")) {
+				m.out = append(m.out, "<!doctype html>"...)
+			} else {
+				m.out = append(m.out, token.data...)
+			}
+			m.lastBlock = true
+		}
+	}
+	return
+}
+
+// text write collapsed whitespace of the text token and trim it around block level tags.
+func (m *htmlMinifier) text(i int) {
+	var text = m.tokens[i].data
+	if m.preDepth > 0 {
+		m.out = append(m.out, text...)
+		m.lastBlock = false
+		return
+	}
+
+	var start = len(m.out)
+	var space = m.lastBlock || (len(m.out) > 0 && m.out[len(m.out)-1] == ' ')
+	for _, c := range text {
+		if isSpace(c) {
+			if !space {
+				m.out = append(m.out, ' ')
+				space = true
+			}
+			continue
+		}
+		m.out = append(m.out, c)
+		space = false
+	}
+	if len(m.out) > start && m.out[len(m.out)-1] == ' ' {
+		var next = m.nextSignificant(i)
+		if next == nil || (next.kind == htmlToken_StartTag || next.kind == htmlToken_EndTag) && isHTMLBlock(next.name) {
+			m.out = m.out[:len(m.out)-1]
+		}
+	}
+	if len(m.out) > start {
+		m.lastBlock = false
+	}
+}
+
+// rawText write content of raw text elements e.g. script and style after minify them if possible.
+func (m *htmlMinifier) rawText(i int) (err protocol.Error) {
+	var text = m.tokens[i].data
+	var minifier protocol.Minifier
+	if i > 0 && m.tokens[i-1].kind == htmlToken_StartTag {
+		var start = &m.tokens[i-1]
+		switch start.name {
+		case "style":
+			minifier = &CSS
+		case "script":
+			var scriptType = start.attribute("type")
+			switch string(bytes.ToLower(bytes.TrimSpace(scriptType))) {
+			case "", "module", "text/javascript", "application/javascript", "application/ecmascript", "text/ecmascript":
+				minifier = &JS
+			case "importmap", "speculationrules", "application/json", "application/ld+json":
+				minifier = &JSON
+			}
+		}
+	}
+	if minifier == nil {
+		m.out = append(m.out, text...)
 		return
 	}
-	var minifiedWriter = buffer.NewWriter(make([]byte, 0, len(data)))
-	var goErr = html.Minifier.Minify(minifier, minifiedWriter, buffer.NewReader(data), nil)
-	if goErr != nil {
-		// err =
+	if len(bytes.TrimSpace(text)) == 0 {
 		return
 	}
-	minifiedData = minifiedWriter.Bytes()
+	var minified []byte
+	minified, err = minifier.MinifyBytes(text)
+	if err != nil {
+		return
+	}
+	m.out = append(m.out, minified...)
 	return
 }
+
+func (m *htmlMinifier) startTag(token *htmlToken) {
+	m.out = append(m.out, '<')
+	m.out = append(m.out, token.data[1:1+len(token.name)]...)
+	for j, attr := range token.attributes {
+		m.out = append(m.out, ' ')
+		m.out = append(m.out, attr.name...)
+		if !attr.hasValue {
+			continue
+		}
+		m.out = append(m.out, '=')
+		// Last unquoted value and "/>" of a self closing tag join to each other e.g. <a href=x/>
+		var last = token.selfClosing && j == len(token.attributes)-1
+		if len(attr.value) > 0 && !last && canUnquoteAttribute(attr.value) {
+			m.out = append(m.out, attr.value...)
+			continue
+		}
+		var quote = attr.quote
+		if quote == 0 {
+			quote = '"'
+		}
+		m.out = append(m.out, quote)
+		m.out = append(m.out, attr.value...)
+		m.out = append(m.out, quote)
+	}
+	if token.selfClosing {
+		m.out = append(m.out, '/')
+	}
+	m.out = append(m.out, '>')
+
+	if token.name == "pre" {
+		m.preDepth++
+	}
+	m.lastBlock = m.preDepth == 0 && isHTMLBlock(token.name)
+}
+
+// nextSignificant return next token after i that will be written i.e. skip removed comments.
+func (m *htmlMinifier) nextSignificant(i int) *htmlToken {
+	for i++; i < len(m.tokens); i++ {
+		var token = &m.tokens[i]
+		if token.kind == htmlToken_Comment && !isConditionalComment(token.data) {
+			continue
+		}
+		return token
+	}
+	return nil
+}
+
+// isOptionalEndTag report the end tag in i can omit by the next token.
+// https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+func (m *htmlMinifier) isOptionalEndTag(i int) bool {
+	var name = m.tokens[i].name
+	// Next token except removed comments and inter-element whitespace.
+	var next *htmlToken
+	for i++; i < len(m.tokens); i++ {
+		var token = &m.tokens[i]
+		if token.kind == htmlToken_Comment && !isConditionalComment(token.data) ||
+			token.kind == htmlToken_Text && len(bytes.TrimSpace(token.data)) == 0 {
+			continue
+		}
+		next = token
+		break
+	}
+
+	if next == nil {
+		switch name {
+		case "html", "body", "li", "dd", "p", "option", "optgroup", "tr", "td", "th", "tbody", "tfoot":
+			return true
+		}
+		return false
+	}
+	var isEnd = next.kind == htmlToken_EndTag
+	var nextStart string
+	if next.kind == htmlToken_StartTag {
+		nextStart = next.name
+	}
+	switch name {
+	case "li":
+		return isEnd || nextStart == "li"
+	case "dt":
+		return nextStart == "dt" || nextStart == "dd"
+	case "dd":
+		return isEnd || nextStart == "dt" || nextStart == "dd"
+	case "p":
+		return isHTMLParagraphCloser(nextStart)
+	case "option":
+		return isEnd || nextStart == "option" || nextStart == "optgroup"
+	case "optgroup":
+		return isEnd || nextStart == "optgroup"
+	case "tr":
+		return isEnd || nextStart == "tr"
+	case "td", "th":
+		return isEnd || nextStart == "td" || nextStart == "th"
+	case "thead":
+		return nextStart == "tbody" || nextStart == "tfoot"
+	case "tbody":
+		return isEnd || nextStart == "tbody" || nextStart == "tfoot"
+	case "tfoot":
+		return isEnd
+	case "head":
+		return nextStart == "body"
+	case "body":
+		return isEnd && next.name == "html"
+	}
+	return false
+}
+
+func (t *htmlToken) attribute(name string) []byte {
+	for _, attr := range t.attributes {
+		if bytes.EqualFold(attr.name, []byte(name)) {
+			return attr.value
+		}
+	}
+	return nil
+}
+
+// htmlTokenize split given HTML to its tokens.
+func htmlTokenize(data []byte) (tokens []htmlToken, err protocol.Error) {
+	var pos, textStart int
+	// appendText append text to the last text token if exist to let whitespace collapse across them.
+	var appendText = func(end int) {
+		if last := len(tokens) - 1; last >= 0 && tokens[last].kind == htmlToken_Text && textStart+len(tokens[last].data) == pos {
+			tokens[last].data = data[textStart:end]
+		} else {
+			textStart = pos
+			tokens = append(tokens, htmlToken{kind: htmlToken_Text, data: data[pos:end]})
+		}
+	}
+	for pos < len(data) {
+		if data[pos] != '<' || pos+1 == len(data) {
+			var end = bytes.IndexByte(data[pos+1:], '<')
+			if end == -1 {
+				end = len(data)
+			} else {
+				end += pos + 1
+			}
+			appendText(end)
+			pos = end
+			continue
+		}
+
+		var c = data[pos+1]
+		switch {
+		case bytes.HasPrefix(data[pos:], []byte("<!--")):
+			var end = bytes.Index(data[pos+4:], []byte("-->"))
+			if end == -1 {
+				return nil, &ErrUnterminatedComment
+			}
+			end += pos + 7
+			tokens = append(tokens, htmlToken{kind: htmlToken_Comment, data: data[pos:end]})
+			pos = end
+		case bytes.HasPrefix(data[pos:], []byte("<![CDATA[")):
+			var end = bytes.Index(data[pos:], []byte("]]>"))
+			if end == -1 {
+				return nil, &ErrMalformedTag
+			}
+			end += pos + 3
+			tokens = append(tokens, htmlToken{kind: htmlToken_Raw, data: data[pos:end]})
+			pos = end
+		case c == '!' || c == '?':
+			var end = bytes.IndexByte(data[pos:], '>')
+			if end == -1 {
+				return nil, &ErrMalformedTag
+			}
+			end += pos + 1
+			tokens = append(tokens, htmlToken{kind: htmlToken_Raw, data: data[pos:end]})
+			pos = end
+		case c == '/' && pos+2 < len(data) && isHTMLNameStart(data[pos+2]):
+			var token = htmlToken{kind: htmlToken_EndTag}
+			var nameEnd = htmlNameEnd(data, pos+2)
+			token.name = htmlLowerName(data[pos+2 : nameEnd])
+			var end = bytes.IndexByte(data[nameEnd:], '>')
+			if end == -1 {
+				return nil, &ErrMalformedTag
+			}
+			end += nameEnd + 1
+			token.data = data[pos:end]
+			tokens = append(tokens, token)
+			pos = end
+		case isHTMLNameStart(c):
+			var token htmlToken
+			token, pos, err = htmlStartTag(data, pos)
+			if err != nil {
+				return
+			}
+			tokens = append(tokens, token)
+			if isHTMLRawTextElement(token.name) && !token.selfClosing {
+				var end = htmlRawTextEnd(data, pos, token.name)
+				tokens = append(tokens, htmlToken{kind: htmlToken_RawText, data: data[pos:end]})
+				pos = end
+			}
+		default:
+			// "<" that is not start of any tag is a text.
+			appendText(pos + 1)
+			pos++
+		}
+	}
+	return
+}
+
+func htmlStartTag(data []byte, start int) (token htmlToken, pos int, err protocol.Error) {
+	token.kind = htmlToken_StartTag
+	var nameEnd = htmlNameEnd(data, start+1)
+	token.name = htmlLowerName(data[start+1 : nameEnd])
+	pos = nameEnd
+	for {
+		for pos < len(data) && (isSpace(data[pos]) || data[pos] == '/' && (pos+1 == len(data) || data[pos+1] != '>')) {
+			pos++
+		}
+		if pos >= len(data) {
+			return token, pos, &ErrMalformedTag
+		}
+		if data[pos] == '>' {
+			pos++
+			break
+		}
+		if data[pos] == '/' {
+			// "/>"
+			token.selfClosing = true
+			pos += 2
+			break
+		}
+
+		var attr htmlAttribute
+		var nameStart = pos
+		for pos < len(data) && !isSpace(data[pos]) && data[pos] != '=' && data[pos] != '>' && data[pos] != '/' {
+			pos++
+		}
+		attr.name = data[nameStart:pos]
+		var valueStart = pos
+		for valueStart < len(data) && isSpace(data[valueStart]) {
+			valueStart++
+		}
+		if valueStart < len(data) && data[valueStart] == '=' {
+			attr.hasValue = true
+			pos = valueStart + 1
+			for pos < len(data) && isSpace(data[pos]) {
+				pos++
+			}
+			if pos < len(data) && (data[pos] == '"' || data[pos] == '\'') {
+				attr.quote = data[pos]
+				var end = bytes.IndexByte(data[pos+1:], attr.quote)
+				if end == -1 {
+					return token, pos, &ErrMalformedTag
+				}
+				attr.value = data[pos+1 : pos+1+end]
+				pos += end + 2
+			} else {
+				var valueStart = pos
+				for pos < len(data) && !isSpace(data[pos]) && data[pos] != '>' {
+					pos++
+				}
+				attr.value = data[valueStart:pos]
+			}
+		}
+		token.attributes = append(token.attributes, attr)
+	}
+	token.data = data[start:pos]
+	return
+}
+
+// htmlRawTextEnd return start of the end tag of given raw text element or end of data if not exist.
+func htmlRawTextEnd(data []byte, pos int, name string) int {
+	for {
+		var end = bytes.Index(data[pos:], []byte("</"))
+		if end == -1 {
+			return len(data)
+		}
+		end += pos
+		var nameEnd = end + 2 + len(name)
+		if nameEnd <= len(data) && bytes.EqualFold(data[end+2:nameEnd], []byte(name)) &&
+			(nameEnd == len(data) || isSpace(data[nameEnd]) || data[nameEnd] == '>' || data[nameEnd] == '/') {
+			return end
+		}
+		pos = end + 2
+	}
+}
+
+func htmlNameEnd(data []byte, pos int) int {
+	for pos < len(data) && !isSpace(data[pos]) && data[pos] != '>' && data[pos] != '/' {
+		pos++
+	}
+	return pos
+}
+
+// htmlLowerName return ASCII lower case of given tag name. Tag names are ASCII case-insensitive.
+func htmlLowerName(name []byte) string {
+	var lower = make([]byte, len(name))
+	for i, c := range name {
+		lower[i] = toLower(c)
+	}
+	return string(lower)
+}
+
+func isHTMLNameStart(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isHTMLRawTextElement(name string) bool {
+	switch name {
+	case "script", "style", "textarea", "title", "xmp", "iframe", "noembed", "noframes":
+		return true
+	}
+	return false
+}
+
+// canUnquoteAttribute report given attribute value can write without quotes.
+// https://html.spec.whatwg.org/multipage/syntax.html#unquoted
+func canUnquoteAttribute(value []byte) bool {
+	for _, c := range value {
+		switch c {
+		case ' ', '\t', '\n', '\r', '\f', '"', '\'', '=', '<', '>', '`':
+			return false
+		}
+	}
+	return true
+}
+
+func isConditionalComment(comment []byte) bool {
+	return bytes.HasPrefix(comment, []byte("<!--[if")) || bytes.HasPrefix(comment, []byte("<!--<![endif]"))
+}
+
+// isHTMLBlock report whitespace around the element is not rendered e.g. div, p, ...
+// Unknown and custom elements treat as inline elements to not change rendered whitespace.
+func isHTMLBlock(name string) bool {
+	switch name {
+	case "html", "head", "body", "title", "meta", "link", "base",
+		"address", "article", "aside", "blockquote", "caption", "col", "colgroup", "dd", "details", "dialog", "div", "dl", "dt",
+		"fieldset", "figcaption", "figure", "footer", "form", "frame", "frameset", "h1", "h2", "h3", "h4", "h5", "h6",
+		"header", "hgroup", "hr", "legend", "li", "main", "menu", "nav", "noframes", "ol", "optgroup", "option", "p", "pre",
+		"section", "summary", "table", "tbody", "td", "tfoot", "th", "thead", "tr", "ul":
+		return true
+	}
+	return false
+}
+
+// isHTMLParagraphCloser report start tag of given element close an open p element.
+func isHTMLParagraphCloser(name string) bool {
+	switch name {
+	case "address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset", "figcaption", "figure",
+		"footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav", "ol", "p",
+		"pre", "section", "table", "ul":
+		return true
+	}
+	return false
+}
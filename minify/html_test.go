@@ -0,0 +1,54 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestHTMLMinifyBytes(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+		want string
+		err  protocol.Error
+	}{
+		{"empty", "", "", nil},
+		{"doctype", "<!DOCTYPE  html>\n<html>", "<!doctype html><html>", nil},
+		{"collapse whitespace", "<div>\n  a   b\n</div>", "<div>a b</div>", nil},
+		{"inline whitespace", "<p>a <b>b</b> c</p>", "<p>a <b>b</b> c", nil},
+		{"comments", "<div><!-- x -->a</div>", "<div>a</div>", nil},
+		{"conditional comment", "<!--[if IE]><p>x</p><![endif]-->", "<!--[if IE]><p>x</p><![endif]-->", nil},
+		{"unquote attributes", `<a href="/x" class="a b" id='y' title="">x</a>`, `<a href=/x class="a b" id=y title="">x</a>`, nil},
+		{"boolean attribute", `<input disabled type="checkbox">`, `<input disabled type=checkbox>`, nil},
+		{"self closing last attribute", `<img src="a.png"/>`, `<img src="a.png"/>`, nil},
+		{"optional end tags", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>", "<ul><li>a<li>b</ul>", nil},
+		{"paragraph end tag", "<p>a</p><div>b</div>", "<p>a<div>b</div>", nil},
+		{"paragraph before inline", "<p>a</p><span>b</span>", "<p>a</p><span>b</span>", nil},
+		{"pre", "<pre>\n  a   b\n</pre>", "<pre>\n  a   b\n</pre>", nil},
+		{"textarea", "<textarea>  a   b  </textarea>", "<textarea>  a   b  </textarea>", nil},
+		{"script", "<script>\n  var a = 1 ; // x\n</script>", "<script>var a=1;</script>", nil},
+		{"script end tag in string", `<script>var a = "<\/script>" ;</script>`, `<script>var a="<\/script>";</script>`, nil},
+		{"script end tag case", "<script>var a = 1 ;</SCRIPT >", "<script>var a=1;</SCRIPT>", nil},
+		{"script regex", "<script>var a = /<b>/g ;</script>", "<script>var a=/<b>/g;</script>", nil},
+		{"script json", `<script type="application/ld+json">{ "a" : 1 }</script>`, `<script type=application/ld+json>{"a":1}</script>`, nil},
+		{"script unknown type", `<script type="text/template"> <b>  x </b> </script>`, `<script type=text/template> <b>  x </b> </script>`, nil},
+		{"style", "<style>\n a { color : red ; }\n</style>", "<style>a{color:red}</style>", nil},
+		{"less than in text", "<p>a < b</p>", "<p>a < b", nil},
+		{"script error", "<script>var a = 'x\n';</script>", "", &ErrUnterminatedString},
+		{"unterminated comment", "<div><!-- x", "", &ErrUnterminatedComment},
+		{"malformed tag", `<a href="x>`, "", &ErrMalformedTag},
+	}
+	for _, tt := range tests {
+		var got, err = HTML.MinifyBytes([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("HTML.MinifyBytes() %s error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("HTML.MinifyBytes() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
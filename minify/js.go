@@ -0,0 +1,425 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"bytes"
+
+	"../protocol"
+)
+
+var JS js
+
+type js struct{}
+
+// Minify replace file data with minify of them.
+func (js *js) Minify(data protocol.Codec) (err protocol.Error) {
+	return minify(data, js.MinifyBytes)
+}
+
+// MinifyBytes remove comments and not needed whitespace between tokens of given JavaScript.
+// Line terminators keep where removing them can change automatic semicolon insertion (ASI) result.
+// Comments start with "/*!" are license comments and keep as is.
+func (js *js) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
+	var m = jsMinifier{
+		data: data,
+		out:  make([]byte, 0, len(data)),
+	}
+	err = m.minify()
+	if err != nil {
+		return
+	}
+	minifiedData = m.out
+	return
+}
+
+type jsTokenKind uint8
+
+const (
+	jsToken_None       jsTokenKind = iota
+	jsToken_Ident                  // identifiers and keywords
+	jsToken_Number                 //
+	jsToken_Literal                // strings, templates and regular expressions
+	jsToken_Punctuator             //
+)
+
+type jsMinifier struct {
+	data []byte
+	pos  int
+	out  []byte
+
+	last     []byte // last written token
+	lastKind jsTokenKind
+
+	braces         []bool // true means the brace is start of a template literal substitution i.e. "${"
+	pendingSpace   bool
+	pendingNewline bool
+}
+
+func (m *jsMinifier) minify() (err protocol.Error) {
+	if bytes.HasPrefix(m.data, []byte("#!")) {
+		// Hashbang must be first line of the script.
+		var end = bytes.IndexByte(m.data, '\n')
+		if end == -1 {
+			end = len(m.data)
+		} else {
+			// Keep the line break, the script starts on a new line with no previous token.
+			end++
+		}
+		m.out = append(m.out, m.data[:end]...)
+		m.pos = end
+	}
+
+	for m.pos < len(m.data) {
+		var c = m.data[m.pos]
+		if size, newline := jsSpace(m.data[m.pos:]); size > 0 {
+			m.pos += size
+			m.pendingSpace = true
+			m.pendingNewline = m.pendingNewline || newline
+			continue
+		}
+
+		switch {
+		case c == '/' && m.peek(1) == '/':
+			var end = bytes.IndexAny(m.data[m.pos:], "\n\r")
+			if end == -1 {
+				end = len(m.data) - m.pos
+			}
+			m.pos += end
+			m.pendingSpace = true
+		case c == '/' && m.peek(1) == '*':
+			err = m.comment()
+		case c == '"' || c == '\'':
+			err = m.string()
+		case c == '`':
+			err = m.template()
+		case c == '}' && len(m.braces) > 0 && m.braces[len(m.braces)-1]:
+			m.braces = m.braces[:len(m.braces)-1]
+			err = m.template()
+		case isJSIdentStart(c):
+			m.ident()
+		case isDigit(c) || (c == '.' && isDigit(m.peek(1))):
+			m.number()
+		case c == '/' && m.regexAllowed():
+			err = m.regex()
+		default:
+			err = m.punctuator()
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(m.braces) != 0 {
+		return &ErrUnbalancedBlock
+	}
+	return
+}
+
+func (m *jsMinifier) peek(n int) byte {
+	if m.pos+n < len(m.data) {
+		return m.data[m.pos+n]
+	}
+	return 0
+}
+
+// write write the token with needed separator from the last token.
+func (m *jsMinifier) write(kind jsTokenKind, token []byte) {
+	if len(m.out) > 0 {
+		if m.pendingNewline && !m.canJoinLines(kind, token) {
+			m.out = append(m.out, '\n')
+		} else if m.pendingSpace && len(m.last) > 0 && m.needSpace(kind, token) {
+			m.out = append(m.out, ' ')
+		}
+	}
+	m.pendingSpace = false
+	m.pendingNewline = false
+	m.out = append(m.out, token...)
+	m.last = token
+	m.lastKind = kind
+}
+
+// needSpace report given token can't be next to last token without a space e.g. "a b", "a + +b", "a / /re/".
+func (m *jsMinifier) needSpace(kind jsTokenKind, token []byte) bool {
+	var last = m.last[len(m.last)-1]
+	var first = token[0]
+	switch {
+	case isJSIdentChar(last) && isJSIdentChar(first):
+		return true
+	case m.lastKind == jsToken_Number && (isJSIdentChar(first) || first == '.'):
+		return true
+	case (last == '+' || last == '-') && first == last:
+		return true
+	case last == '/' && (first == '/' || first == '*'):
+		return true
+	case m.lastKind == jsToken_Literal && last == '/' && isJSIdentChar(first):
+		// Regular expression without flags e.g. "/re/ in x"
+		return true
+	case last == '<' && first == '!', bytes.HasSuffix(m.last, []byte("--")) && first == '>':
+		// Don't make HTML-like comments "<!--" and "-->"
+		return true
+	}
+	return false
+}
+
+// canJoinLines report removing the line terminator between the last and given token don't change the code.
+// ASI just insert a semicolon when next token can't continue the statement, so lines can join when
+// the last token need more token e.g. "=" or the next token can't start a statement e.g. ".".
+func (m *jsMinifier) canJoinLines(kind jsTokenKind, token []byte) bool {
+	if m.lastKind == jsToken_Punctuator {
+		switch string(m.last) {
+		case ")", "]", "}", "++", "--":
+		default:
+			return true
+		}
+	}
+	if kind == jsToken_Punctuator {
+		switch string(token) {
+		case ".", "?.", ",", ";", ":", "?", ")", "]", "}", "=>",
+			"=", "==", "===", "!=", "!==", "<", ">", "<=", ">=", "&&", "||", "??", "&", "|", "^", "*", "%", "**",
+			"<<", ">>", ">>>", "+=", "-=", "*=", "%=", "**=", "<<=", ">>=", ">>>=", "&=", "|=", "^=", "&&=", "||=", "??=":
+			return true
+		}
+	}
+	return false
+}
+
+// regexAllowed report a "/" in current position start a regular expression not a division.
+func (m *jsMinifier) regexAllowed() bool {
+	switch m.lastKind {
+	case jsToken_None:
+		return true
+	case jsToken_Ident:
+		switch string(m.last) {
+		case "return", "typeof", "instanceof", "in", "of", "new", "delete", "void", "throw", "case", "do", "else", "yield", "await":
+			return true
+		}
+		return false
+	case jsToken_Punctuator:
+		switch string(m.last) {
+		case ")", "]", "++", "--":
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func (m *jsMinifier) comment() (err protocol.Error) {
+	var end = bytes.Index(m.data[m.pos+2:], []byte("*/"))
+	if end == -1 {
+		return &ErrUnterminatedComment
+	}
+	end += m.pos + 4
+	var comment = m.data[m.pos:end]
+	if comment[2] == '!' {
+		if m.pendingNewline && len(m.out) > 0 {
+			m.out = append(m.out, '\n')
+		}
+		m.out = append(m.out, comment...)
+	}
+	m.pendingSpace = true
+	if bytes.ContainsAny(comment, "\n\r\u2028\u2029") {
+		// Multi line comment act as a line terminator in ASI.
+		m.pendingNewline = true
+	}
+	m.pos = end
+	return
+}
+
+func (m *jsMinifier) string() (err protocol.Error) {
+	var quote = m.data[m.pos]
+	for i := m.pos + 1; i < len(m.data); i++ {
+		switch m.data[i] {
+		case '\\':
+			i++
+			if i+1 < len(m.data) && m.data[i] == '\r' && m.data[i+1] == '\n' {
+				// Line continuation by CRLF
+				i++
+			}
+		case '\n', '\r':
+			return &ErrUnterminatedString
+		case quote:
+			m.write(jsToken_Literal, m.data[m.pos:i+1])
+			m.pos = i + 1
+			return
+		}
+	}
+	return &ErrUnterminatedString
+}
+
+// template write a template literal from its start "`" or from end of a substitution "}"
+// until its end "`" or start of next substitution "${".
+func (m *jsMinifier) template() (err protocol.Error) {
+	for i := m.pos + 1; i < len(m.data); i++ {
+		switch m.data[i] {
+		case '\\':
+			i++
+		case '`':
+			m.write(jsToken_Literal, m.data[m.pos:i+1])
+			m.pos = i + 1
+			return
+		case '$':
+			if i+1 < len(m.data) && m.data[i+1] == '{' {
+				m.braces = append(m.braces, true)
+				m.write(jsToken_Punctuator, m.data[m.pos:i+2])
+				// Substitution start an expression like "(".
+				m.last = m.last[len(m.last)-1:]
+				m.pos = i + 2
+				return
+			}
+		}
+	}
+	return &ErrUnterminatedString
+}
+
+func (m *jsMinifier) regex() (err protocol.Error) {
+	var inClass bool
+	for i := m.pos + 1; i < len(m.data); i++ {
+		switch m.data[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n', '\r':
+			return &ErrUnterminatedRegExp
+		case '/':
+			if inClass {
+				continue
+			}
+			i++
+			for i < len(m.data) && isJSIdentChar(m.data[i]) {
+				i++
+			}
+			m.write(jsToken_Literal, m.data[m.pos:i])
+			m.pos = i
+			return
+		}
+	}
+	return &ErrUnterminatedRegExp
+}
+
+func (m *jsMinifier) ident() {
+	var i = m.pos + 1 // first character checked by isJSIdentStart() e.g. "#" of private names
+	if m.data[m.pos] == '\\' {
+		i++
+	}
+	for i < len(m.data) {
+		if m.data[i] == '\\' {
+			// Unicode escape sequence e.g. "a"
+			i += 2
+		} else if isJSIdentChar(m.data[i]) {
+			if size, _ := jsSpace(m.data[i:]); size > 0 {
+				break
+			}
+			i++
+		} else {
+			break
+		}
+	}
+	if i > len(m.data) {
+		i = len(m.data)
+	}
+	m.write(jsToken_Ident, m.data[m.pos:i])
+	m.pos = i
+}
+
+func (m *jsMinifier) number() {
+	var i = m.pos
+	if m.data[i] == '0' && i+1 < len(m.data) && bytes.IndexByte([]byte("xXoObB"), m.data[i+1]) != -1 {
+		i += 2
+		for i < len(m.data) && (isJSIdentChar(m.data[i])) {
+			i++
+		}
+	} else {
+		var digits = func() {
+			for i < len(m.data) && (isDigit(m.data[i]) || m.data[i] == '_') {
+				i++
+			}
+		}
+		digits()
+		if i < len(m.data) && m.data[i] == '.' {
+			i++
+			digits()
+		}
+		if i < len(m.data) && (m.data[i] == 'e' || m.data[i] == 'E') {
+			var j = i + 1
+			if j < len(m.data) && (m.data[j] == '+' || m.data[j] == '-') {
+				j++
+			}
+			if j < len(m.data) && isDigit(m.data[j]) {
+				i = j
+				digits()
+			}
+		}
+		if i < len(m.data) && m.data[i] == 'n' {
+			// BigInt
+			i++
+		}
+	}
+	m.write(jsToken_Number, m.data[m.pos:i])
+	m.pos = i
+}
+
+var jsPunctuators = [...]string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+func (m *jsMinifier) punctuator() (err protocol.Error) {
+	var rest = m.data[m.pos:]
+	var size = 1
+	for _, p := range jsPunctuators {
+		if bytes.HasPrefix(rest, []byte(p)) {
+			size = len(p)
+			break
+		}
+	}
+	if size == 2 && rest[0] == '?' && rest[1] == '.' && len(rest) > 2 && isDigit(rest[2]) {
+		// Conditional operator followed by a number e.g. "a?.5:1"
+		size = 1
+	}
+
+	switch rest[0] {
+	case '{':
+		m.braces = append(m.braces, false)
+	case '}':
+		if len(m.braces) == 0 {
+			return &ErrUnbalancedBlock
+		}
+		m.braces = m.braces[:len(m.braces)-1]
+	}
+	m.write(jsToken_Punctuator, rest[:size])
+	m.pos += size
+	return
+}
+
+// jsSpace return size of whitespace or line terminator in start of given data.
+func jsSpace(data []byte) (size int, newline bool) {
+	switch data[0] {
+	case ' ', '\t', '\v', '\f':
+		return 1, false
+	case '\n', '\r':
+		return 1, true
+	case 0xC2: // NO-BREAK SPACE
+		if len(data) > 1 && data[1] == 0xA0 {
+			return 2, false
+		}
+	case 0xE2: // LINE SEPARATOR, PARAGRAPH SEPARATOR
+		if len(data) > 2 && data[1] == 0x80 && (data[2] == 0xA8 || data[2] == 0xA9) {
+			return 3, true
+		}
+	case 0xEF: // BYTE ORDER MARK
+		if len(data) > 2 && data[1] == 0xBB && data[2] == 0xBF {
+			return 3, false
+		}
+	}
+	return 0, false
+}
+
+func isJSIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || c == '\\' || c == '#' || c >= 0x80
+}
+
+func isJSIdentChar(c byte) bool { return isJSIdentStart(c) && c != '#' || isDigit(c) }
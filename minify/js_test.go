@@ -0,0 +1,65 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestJSMinifyBytes(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+		want string
+		err  protocol.Error
+	}{
+		{"empty", "", "", nil},
+		{"whitespace", "var  a = 1 ;\n\nvar b = a + 2 ;", "var a=1;var b=a+2;", nil},
+		{"line comment", "var a = 1; // one\nvar b;", "var a=1;var b;", nil},
+		{"block comment", "var a /* x */ = 1;", "var a=1;", nil},
+		{"license comment", "/*! License */\nvar a;", "/*! License */\nvar a;", nil},
+		{"comment separate idents", "return/**/a", "return a", nil},
+		{"multi line comment is line terminator", "a/*\n*/b", "a\nb", nil},
+		{"strings", `var a = "x  // y", b = 'z /* w */';`, `var a="x  // y",b='z /* w */';`, nil},
+		{"escaped quote", `var a = "x\"  y";`, `var a="x\"  y";`, nil},
+		{"script end tag in string", `var a = "<\/script>";`, `var a="<\/script>";`, nil},
+		{"template", "var a = `x  ${ b + 1 }  y`;", "var a=`x  ${b+1}  y`;", nil},
+		{"nested template", "`a${ `b${ c }` }`", "`a${`b${c}`}`", nil},
+		{"template with brace", "`a${ {x: 1}.x }b`", "`a${{x:1}.x}b`", nil},
+		{"regex", "var a = /[/]  \\/ x/g ;", "var a=/[/]  \\/ x/g;", nil},
+		{"regex after return", "return /a b/.test(x)", "return/a b/.test(x)", nil},
+		{"regex after paren", "if (x) /a b/.test(y)", "if(x)/a b/.test(y)", nil},
+		{"division", "var a = b / c / d;", "var a=b/c/d;", nil},
+		{"division after paren", "(a) / 2 / (b)", "(a)/2/(b)", nil},
+		{"regex in keyword", "x = /a/ in y", "x=/a/ in y", nil},
+		{"plus plus", "a + +b; a - -b; a++ + b", "a+ +b;a- -b;a++ +b", nil},
+		{"number member", "1 .toString(); 1.5 .toFixed()", "1 .toString();1.5 .toFixed()", nil},
+		{"ASI return", "return\na", "return\na", nil},
+		{"ASI increment", "a\n++b", "a\n++b", nil},
+		{"ASI join operator", "var a = b\n+ c", "var a=b\n+c", nil},
+		{"ASI join after operator", "var a = b +\nc", "var a=b+c", nil},
+		{"ASI join dot", "a\n.b()", "a.b()", nil},
+		{"ASI after brace", "if (a) {\n}\nb()", "if(a){}\nb()", nil},
+		{"html like comment", "a < !b; a-- > b", "a< !b;a-- >b", nil},
+		{"hashbang", "#!/usr/bin/env node\nvar a = 1;", "#!/usr/bin/env node\nvar a=1;", nil},
+		{"optional chaining", "a ?. b; c ? .5 : 1", "a?.b;c?.5:1", nil},
+		{"unterminated comment", "var a; /* x", "", &ErrUnterminatedComment},
+		{"unterminated string", "var a = 'x\n';", "", &ErrUnterminatedString},
+		{"unterminated template", "var a = `x", "", &ErrUnterminatedString},
+		{"unterminated regex", "var a = /x\n/;", "", &ErrUnterminatedRegExp},
+		{"unbalanced block", "}", "", &ErrUnbalancedBlock},
+		{"unclosed block", "{", "", &ErrUnbalancedBlock},
+	}
+	for _, tt := range tests {
+		var got, err = JS.MinifyBytes([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("JS.MinifyBytes() %s error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("JS.MinifyBytes() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"bytes"
+	ejson "encoding/json"
+
+	"../protocol"
+)
+
+var JSON json
+
+type json struct{}
+
+// Minify replace file data with minify of them.
+func (json *json) Minify(data protocol.Codec) (err protocol.Error) {
+	return minify(data, json.MinifyBytes)
+}
+
+// MinifyBytes remove insignificant whitespace of given JSON and check its syntax.
+func (json *json) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	var goErr = ejson.Compact(&buf, data)
+	if goErr != nil {
+		return nil, &ErrMalformedJSON
+	}
+	minifiedData = buf.Bytes()
+	return
+}
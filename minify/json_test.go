@@ -0,0 +1,35 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestJSONMinifyBytes(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+		want string
+		err  protocol.Error
+	}{
+		{"object", "{\n  \"a\" : 1,\n  \"b\" : [ true , null ]\n}", `{"a":1,"b":[true,null]}`, nil},
+		{"string whitespace", `{ "a b" : " c  d " }`, `{"a b":" c  d "}`, nil},
+		{"escaped quote", `[ "a\"  b" ]`, `["a\"  b"]`, nil},
+		{"comment not allowed", `{"a": 1 /* x */}`, "", &ErrMalformedJSON},
+		{"trailing comma", `[1, 2,]`, "", &ErrMalformedJSON},
+		{"unterminated string", `["a]`, "", &ErrMalformedJSON},
+	}
+	for _, tt := range tests {
+		var got, err = JSON.MinifyBytes([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("JSON.MinifyBytes() %s error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("JSON.MinifyBytes() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,36 +3,62 @@
 package minify
 
 import (
-	"regexp"
+	"strings"
 
 	"../protocol"
-
-	"github.com/tdewolff/minify"
-	tcss "github.com/tdewolff/minify/css"
-	thtml "github.com/tdewolff/minify/html"
-	"github.com/tdewolff/minify/js"
-	"github.com/tdewolff/minify/json"
-	"github.com/tdewolff/minify/svg"
-	"github.com/tdewolff/minify/xml"
 )
 
-var minifier = minify.New()
+// Minify replace data with minify of them by its media type.
+func Minify(data protocol.Codec) (err protocol.Error) {
+	var mediaType = data.MediaType()
+	if mediaType == nil {
+		return &ErrNotSupported
+	}
+	var minifier = ByMediaType(mediaType.MediaType())
+	if minifier == nil {
+		return &ErrNotSupported
+	}
+	return minifier.Minify(data)
+}
 
-func init() {
-	minifier.AddFunc("text/css", tcss.Minify)
-	minifier.AddFunc("text/html", thtml.Minify)
-	minifier.AddFunc("image/svg+xml", svg.Minify)
-	minifier.AddFuncRegexp(regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$"), js.Minify)
-	minifier.AddFuncRegexp(regexp.MustCompile("[/+]json$"), json.Minify)
-	minifier.AddFuncRegexp(regexp.MustCompile("[/+]xml$"), xml.Minify)
+// ByMediaType return the minifier of given media type or nil if not exist.
+func ByMediaType(mediaType string) protocol.Minifier {
+	mediaType = strings.ToLower(mediaType)
+	if semiColon := strings.IndexByte(mediaType, ';'); semiColon != -1 {
+		mediaType = strings.TrimSpace(mediaType[:semiColon])
+	}
+	switch mediaType {
+	case "text/css":
+		return &CSS
+	case "text/html":
+		return &HTML
+	case "text/javascript", "application/javascript", "application/x-javascript", "application/ecmascript", "text/ecmascript":
+		return &JS
+	}
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return &JSON
+	}
+	// e.g. "image/svg+xml", "application/atom+xml"
+	if mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml") {
+		return &XML
+	}
+	return nil
 }
 
-// Minify replace file data with minify of them.
-func Minify(data protocol.Codec) (err protocol.Error) {
-	var minifiedData, goErr = minifier.Bytes(data.MediaType().MediaType(), data.Marshal())
-	if goErr != nil {
+// minify replace codec data with minify of them by given minify function.
+func minify(data protocol.Codec, minifyBytes func([]byte) ([]byte, protocol.Error)) (err protocol.Error) {
+	var rawData []byte
+	rawData, err = data.Marshal()
+	if err != nil {
 		return
 	}
-	err = data.Unmarshal(minifiedData)
+	var minifiedData []byte
+	minifiedData, err = minifyBytes(rawData)
+	if err != nil {
+		return
+	}
+	_, err = data.Unmarshal(minifiedData)
 	return
 }
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
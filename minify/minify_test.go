@@ -0,0 +1,34 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestByMediaType(t *testing.T) {
+	var tests = []struct {
+		mediaType string
+		want      protocol.Minifier
+	}{
+		{"text/css", &CSS},
+		{"text/html; charset=utf-8", &HTML},
+		{"Text/JavaScript", &JS},
+		{"application/javascript", &JS},
+		{"application/json", &JSON},
+		{"application/problem+json", &JSON},
+		{"image/svg+xml", &XML},
+		{"application/xml", &XML},
+		{"text/xml", &XML},
+		{"application/atom+xml", &XML},
+		{"image/png", nil},
+		{"text/plain", nil},
+	}
+	for _, tt := range tests {
+		if got := ByMediaType(tt.mediaType); got != tt.want {
+			t.Errorf("ByMediaType(%q) = %T, want %T", tt.mediaType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"bytes"
+
+	"../protocol"
+)
+
+var XML xml
+
+type xml struct{}
+
+// Minify replace file data with minify of them.
+func (xml *xml) Minify(data protocol.Codec) (err protocol.Error) {
+	return minify(data, xml.MinifyBytes)
+}
+
+// MinifyBytes remove comments and whitespace only texts, collapse whitespace of other texts and attributes separators
+// and self close empty elements of given XML e.g. SVG.
+// Content of elements with xml:space="preserve" and CDATA sections keep as is.
+func (xml *xml) MinifyBytes(data []byte) (minifiedData []byte, err protocol.Error) {
+	var m = xmlMinifier{
+		data: data,
+		out:  make([]byte, 0, len(data)),
+	}
+	err = m.minify()
+	if err != nil {
+		return
+	}
+	minifiedData = m.out
+	return
+}
+
+type xmlMinifier struct {
+	data []byte
+	pos  int
+	out  []byte
+
+	elements []xmlElement // open elements
+}
+
+type xmlElement struct {
+	name     []byte
+	preserve bool // xml:space="preserve" set on the element or its ancestors
+	// startEnd is the out length after the element start tag, so the element is empty if nothing wrote after it.
+	startEnd int
+}
+
+func (m *xmlMinifier) minify() (err protocol.Error) {
+	for m.pos < len(m.data) {
+		if m.data[m.pos] != '<' {
+			m.text()
+			continue
+		}
+		switch {
+		case bytes.HasPrefix(m.data[m.pos:], []byte("<!--")):
+			var end = bytes.Index(m.data[m.pos+4:], []byte("-->"))
+			if end == -1 {
+				return &ErrUnterminatedComment
+			}
+			m.pos += end + 7
+		case bytes.HasPrefix(m.data[m.pos:], []byte("<![CDATA[")):
+			err = m.raw("]]>")
+		case bytes.HasPrefix(m.data[m.pos:], []byte("<?")):
+			err = m.raw("?>")
+		case bytes.HasPrefix(m.data[m.pos:], []byte("<!")):
+			err = m.doctype()
+		case bytes.HasPrefix(m.data[m.pos:], []byte("</")):
+			err = m.endTag()
+		default:
+			err = m.startTag()
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(m.elements) != 0 {
+		return &ErrUnbalancedBlock
+	}
+	return
+}
+
+func (m *xmlMinifier) preserve() bool {
+	return len(m.elements) > 0 && m.elements[len(m.elements)-1].preserve
+}
+
+// text write the text until next tag with collapsed whitespace. Whitespace only texts between tags are not written.
+func (m *xmlMinifier) text() {
+	var end = bytes.IndexByte(m.data[m.pos:], '<')
+	if end == -1 {
+		end = len(m.data)
+	} else {
+		end += m.pos
+	}
+	var text = m.data[m.pos:end]
+	m.pos = end
+	if m.preserve() {
+		m.out = append(m.out, text...)
+		return
+	}
+	if len(bytes.TrimSpace(text)) == 0 {
+		return
+	}
+	var space bool
+	for _, c := range text {
+		if isSpace(c) {
+			if !space {
+				m.out = append(m.out, ' ')
+				space = true
+			}
+			continue
+		}
+		m.out = append(m.out, c)
+		space = false
+	}
+}
+
+// raw write data until given end mark as is e.g. CDATA sections and processing instructions.
+func (m *xmlMinifier) raw(endMark string) (err protocol.Error) {
+	var end = bytes.Index(m.data[m.pos:], []byte(endMark))
+	if end == -1 {
+		return &ErrMalformedTag
+	}
+	end += m.pos + len(endMark)
+	m.out = append(m.out, m.data[m.pos:end]...)
+	m.pos = end
+	return
+}
+
+// doctype write the document type declaration as is. It can has an internal subset in brackets that has ">" in it.
+func (m *xmlMinifier) doctype() (err protocol.Error) {
+	var brackets int
+	var quote byte
+	for end := m.pos + 2; end < len(m.data); end++ {
+		var c = m.data[end]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			brackets++
+		case c == ']':
+			brackets--
+		case c == '>' && brackets == 0:
+			m.out = append(m.out, m.data[m.pos:end+1]...)
+			m.pos = end + 1
+			return
+		}
+	}
+	return &ErrMalformedTag
+}
+
+func (m *xmlMinifier) startTag() (err protocol.Error) {
+	var nameEnd = xmlNameEnd(m.data, m.pos+1)
+	if nameEnd == m.pos+1 {
+		return &ErrMalformedTag
+	}
+	var element = xmlElement{
+		name:     m.data[m.pos+1 : nameEnd],
+		preserve: m.preserve(),
+	}
+	m.out = append(m.out, m.data[m.pos:nameEnd]...)
+	m.pos = nameEnd
+	for {
+		m.skipSpaces()
+		if m.pos >= len(m.data) {
+			return &ErrMalformedTag
+		}
+		switch {
+		case m.data[m.pos] == '>':
+			m.pos++
+			m.out = append(m.out, '>')
+			element.startEnd = len(m.out)
+			m.elements = append(m.elements, element)
+			return
+		case bytes.HasPrefix(m.data[m.pos:], []byte("/>")):
+			m.pos += 2
+			m.out = append(m.out, "/>"...)
+			return
+		}
+
+		var attrNameEnd = xmlNameEnd(m.data, m.pos)
+		if attrNameEnd == m.pos {
+			return &ErrMalformedTag
+		}
+		var attrName = m.data[m.pos:attrNameEnd]
+		m.pos = attrNameEnd
+		m.skipSpaces()
+		if m.pos >= len(m.data) || m.data[m.pos] != '=' {
+			return &ErrMalformedTag
+		}
+		m.pos++
+		m.skipSpaces()
+		if m.pos >= len(m.data) || m.data[m.pos] != '"' && m.data[m.pos] != '\'' {
+			return &ErrMalformedTag
+		}
+		var quote = m.data[m.pos]
+		var valueEnd = bytes.IndexByte(m.data[m.pos+1:], quote)
+		if valueEnd == -1 {
+			return &ErrMalformedTag
+		}
+		valueEnd += m.pos + 1
+		var value = m.data[m.pos+1 : valueEnd]
+		m.pos = valueEnd + 1
+
+		if string(attrName) == "xml:space" {
+			element.preserve = string(value) == "preserve"
+		}
+		m.out = append(m.out, ' ')
+		m.out = append(m.out, attrName...)
+		m.out = append(m.out, '=', quote)
+		m.out = append(m.out, value...)
+		m.out = append(m.out, quote)
+	}
+}
+
+// endTag write the end tag or self close its element start tag if the element is empty.
+func (m *xmlMinifier) endTag() (err protocol.Error) {
+	var nameEnd = xmlNameEnd(m.data, m.pos+2)
+	var name = m.data[m.pos+2 : nameEnd]
+	m.pos = nameEnd
+	m.skipSpaces()
+	if m.pos >= len(m.data) || m.data[m.pos] != '>' {
+		return &ErrMalformedTag
+	}
+	m.pos++
+
+	var last = len(m.elements) - 1
+	if last < 0 || !bytes.Equal(m.elements[last].name, name) {
+		return &ErrUnbalancedBlock
+	}
+	var element = m.elements[last]
+	m.elements = m.elements[:last]
+	if element.startEnd == len(m.out) {
+		m.out = append(m.out[:len(m.out)-1], "/>"...)
+		return
+	}
+	m.out = append(m.out, "</"...)
+	m.out = append(m.out, name...)
+	m.out = append(m.out, '>')
+	return
+}
+
+func (m *xmlMinifier) skipSpaces() {
+	for m.pos < len(m.data) && isSpace(m.data[m.pos]) {
+		m.pos++
+	}
+}
+
+func xmlNameEnd(data []byte, pos int) int {
+	for pos < len(data) && !isSpace(data[pos]) && data[pos] != '>' && data[pos] != '/' && data[pos] != '=' {
+		pos++
+	}
+	return pos
+}
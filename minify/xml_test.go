@@ -0,0 +1,46 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package minify
+
+import (
+	"testing"
+
+	"../protocol"
+)
+
+func TestXMLMinifyBytes(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+		want string
+		err  protocol.Error
+	}{
+		{"empty", "", "", nil},
+		{"declaration", "<?xml version=\"1.0\"?>\n<a/>\n", `<?xml version="1.0"?><a/>`, nil},
+		{"doctype", "<!DOCTYPE svg [ <!ENTITY x \"a>b\"> ]>\n<svg/>", "<!DOCTYPE svg [ <!ENTITY x \"a>b\"> ]><svg/>", nil},
+		{"whitespace only text", "<svg>\n  <g>\n    <path d=\"M0 0\"/>\n  </g>\n</svg>", `<svg><g><path d="M0 0"/></g></svg>`, nil},
+		{"collapse text", "<text>\n  a   b\n</text>", "<text> a b </text>", nil},
+		{"comments", "<svg><!-- x --><g/></svg>", "<svg><g/></svg>", nil},
+		{"attributes", "<svg  xmlns = \"http://www.w3.org/2000/svg\"\n  viewBox='0 0  10 10' ></svg>", `<svg xmlns="http://www.w3.org/2000/svg" viewBox='0 0  10 10'/>`, nil},
+		{"self close empty element", "<a><b></b><c> </c></a>", "<a><b/><c/></a>", nil},
+		{"preserve space", "<a xml:space=\"preserve\"> x  <b>  y </b> </a>", "<a xml:space=\"preserve\"> x  <b>  y </b> </a>", nil},
+		{"preserve space end", "<a><b xml:space=\"preserve\"> x </b>  <c> y  z </c></a>", "<a><b xml:space=\"preserve\"> x </b><c> y z </c></a>", nil},
+		{"cdata", "<style><![CDATA[ a  >  b ]]></style>", "<style><![CDATA[ a  >  b ]]></style>", nil},
+		{"entities", "<a>x &amp;  y</a>", "<a>x &amp; y</a>", nil},
+		{"unterminated comment", "<a><!-- x", "", &ErrUnterminatedComment},
+		{"unquoted attribute", "<a b=c/>", "", &ErrMalformedTag},
+		{"unterminated tag", "<a b=\"c\"", "", &ErrMalformedTag},
+		{"mismatched end tag", "<a></b>", "", &ErrUnbalancedBlock},
+		{"unclosed element", "<a><b/>", "", &ErrUnbalancedBlock},
+	}
+	for _, tt := range tests {
+		var got, err = XML.MinifyBytes([]byte(tt.data))
+		if err != tt.err {
+			t.Errorf("XML.MinifyBytes() %s error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && string(got) != tt.want {
+			t.Errorf("XML.MinifyBytes() %s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	cmd "github.com/GeniusesGroup/libgo/command"
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// appCommand is the "app" command that manage an application by its sub commands.
+type appCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	initApp appInitCommand
+}
+
+func (c *appCommand) init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=app")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"App",
+		"Manage an application that use libgo",
+		"",
+		"",
+		nil)
+
+	c.initApp.init(c)
+	c.Command.Init(parent, &c.initApp)
+}
+
+//libgo:impl protocol.Command
+func (c *appCommand) Name() string               { return "app" }
+func (c *appCommand) Aliases() []string          { return []string{"application"} }
+func (c *appCommand) UsageLine() string          { return "app {init} [flags]" }
+func (c *appCommand) Runnable() bool             { return true }
+func (c *appCommand) Request() []protocol.Field  { return nil }
+func (c *appCommand) Response() []protocol.Field { return nil }
+func (c *appCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	return cmd.ServeCLA(c, arguments)
+}
+
+// appInitCommand is the "app init" command that scaffold a new application in a directory.
+// e.g. "libgo app init -d=example.com"
+type appInitCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	domain cmd.StringFlag
+	module cmd.StringFlag
+	dir    cmd.StringFlag
+}
+
+func (c *appInitCommand) init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=app-init")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Init",
+		"Make main.go that wire protocol.App, manifest.go with the domain and its app ID, services.go and go.mod if not exist",
+		"",
+		"Module path is the domain if not given. Exist files never overwrite",
+		nil)
+	c.Command.Init(parent)
+	c.domain.Init("domain", "d", "")
+	c.module.Init("module", "m", "")
+	c.dir.Init("dir", "", ".")
+}
+
+//libgo:impl protocol.Command
+func (c *appInitCommand) Name() string      { return "init" }
+func (c *appInitCommand) Aliases() []string { return []string{"initialize", "new"} }
+func (c *appInitCommand) UsageLine() string {
+	return "app init -d=domain [-m=module-path] [--dir=directory]"
+}
+func (c *appInitCommand) Runnable() bool { return true }
+func (c *appInitCommand) Request() []protocol.Field {
+	return []protocol.Field{&c.domain, &c.module, &c.dir}
+}
+func (c *appInitCommand) Response() []protocol.Field { return nil }
+func (c *appInitCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	err = cmd.ParseFlags(c.Request(), arguments)
+	if err != nil {
+		return
+	}
+	var domain = strings.ToLower(c.domain.Value)
+	if !ValidDomain(domain) {
+		return &ErrBadDomain
+	}
+	var module = c.module.Value
+	if module == "" {
+		module = domain
+	}
+
+	var appID = AppID(domain)
+	var appIDBytes = make([]string, len(appID))
+	for i, b := range appID {
+		appIDBytes[i] = fmt.Sprintf("0x%02x", b)
+	}
+	var data = struct {
+		Domain string
+		Module string
+		AppID  string
+	}{domain, module, strings.Join(appIDBytes, ", ")}
+
+	var files = []file{
+		{"main.go", executeTemplate(appMainFile, data)},
+		{"manifest.go", executeTemplate(appManifestFile, data)},
+		{"services.go", executeTemplate(appServicesFile, data)},
+	}
+	if modulePath(c.dir.Value) == "" {
+		files = append(files, file{"go.mod", executeTemplate(appGoModFile, data)})
+	}
+	err = writeFiles(c.dir.Value, files)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		fmt.Fprintln(os.Stdout, "created", f.path)
+	}
+	fmt.Fprintln(os.Stdout, "Add libgo to the module dependencies by 'go get github.com/GeniusesGroup/libgo' and add domain modules by 'libgo domain new -n=name'")
+	return
+}
+
+var appMainFile = template.Must(template.New("appMainFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package main
+
+import (
+	"github.com/GeniusesGroup/libgo/achaemenid"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func init() {
+	protocol.App = &achaemenid.App
+}
+
+func main() {
+	achaemenid.App.Init()
+	registerServices()
+	// Start block until the app shutdown.
+	achaemenid.App.Start()
+}
+`))
+
+var appManifestFile = template.Must(template.New("appManifestFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package main
+
+// Domain is the internet domain of the application. All domain modules media types belong to it.
+const Domain = "{{.Domain}}"
+
+// AppID is the SHA-512/256 hash of the Domain that act as the application ID.
+var AppID = [32]byte{ {{- .AppID -}} }
+`))
+
+var appServicesFile = template.Must(template.New("appServicesFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package main
+
+// registerServices register services of the domain modules in the application.
+// Add RegisterServices() of each new domain module here e.g. product.RegisterServices()
+func registerServices() {
+}
+`))
+
+var appGoModFile = template.Must(template.New("appGoModFile").Parse(`module {{.Module}}
+
+go 1.19
+`))
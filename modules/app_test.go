@@ -0,0 +1,128 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func TestAppInitCommand(t *testing.T) {
+	var tests = []struct {
+		name       string
+		arguments  []string
+		goMod      string
+		wantModule string
+		err        protocol.Error
+	}{
+		{"domain", []string{"-d=Example.com"}, "", "example.com", nil},
+		{"module", []string{"-d=example.com", "-m=github.com/example/app"}, "", "github.com/example/app", nil},
+		{"exist module", []string{"-d=example.com"}, "module example.org/app\n", "example.org/app", nil},
+		{"no domain", nil, "", "", &ErrBadDomain},
+		{"bad domain", []string{"-d=example"}, "", "", &ErrBadDomain},
+	}
+	for _, tt := range tests {
+		var dir = t.TempDir()
+		if tt.goMod != "" {
+			writeTestFile(t, filepath.Join(dir, "go.mod"), tt.goMod)
+		}
+		var c appInitCommand
+		c.init(nil)
+		var err = c.ServeCLA(append(tt.arguments, "--dir="+dir))
+		if err != tt.err {
+			t.Errorf("%s: app init ServeCLA() error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if got := modulePath(dir); got != tt.wantModule {
+			t.Errorf("%s: app init module = %q, want %q", tt.name, got, tt.wantModule)
+		}
+		if got := appDomain(dir); got != "example.com" {
+			t.Errorf("%s: app init manifest Domain = %q, want %q", tt.name, got, "example.com")
+		}
+		var appID = AppID("example.com")
+		var wantAppID = fmt.Sprintf("var AppID = [32]byte{0x%02x, 0x%02x", appID[0], appID[1])
+		if manifest := readTestFile(t, filepath.Join(dir, "manifest.go")); !strings.Contains(manifest, wantAppID) {
+			t.Errorf("%s: app init manifest = %s, want AppID %s", tt.name, manifest, wantAppID)
+		}
+		for _, name := range []string{"main.go", "services.go"} {
+			if data := readTestFile(t, filepath.Join(dir, name)); !strings.HasPrefix(data, "/* For license") || !strings.Contains(data, "package main") {
+				t.Errorf("%s: app init %s = %s", tt.name, name, data)
+			}
+		}
+
+		// Second run must not overwrite the app files.
+		err = c.ServeCLA(append(tt.arguments, "--dir="+dir))
+		if err != &ErrFileExist {
+			t.Errorf("%s: app init again ServeCLA() error = %v, want %v", tt.name, err, &ErrFileExist)
+		}
+	}
+}
+
+func TestDomainNewCommand(t *testing.T) {
+	var tests = []struct {
+		name       string
+		arguments  []string
+		manifest   bool
+		wantDomain string
+		err        protocol.Error
+	}{
+		{"manifest domain", []string{"-n=product_price"}, true, "example.com", nil},
+		{"given domain", []string{"-n=product_price", "-d=Example.org"}, true, "example.org", nil},
+		{"no manifest", []string{"-n=product_price"}, false, "", &ErrBadDomain},
+		{"no name", nil, true, "", &ErrBadName},
+		{"bad name", []string{"-n=Product"}, true, "", &ErrBadName},
+		{"keyword name", []string{"-n=type"}, true, "", &ErrBadName},
+	}
+	for _, tt := range tests {
+		var dir = t.TempDir()
+		if tt.manifest {
+			var app appInitCommand
+			app.init(nil)
+			if err := app.ServeCLA([]string{"-d=example.com", "--dir=" + dir}); err != nil {
+				t.Fatalf("app init ServeCLA() error = %v", err)
+			}
+		}
+		var c domainNewCommand
+		c.init(nil)
+		var err = c.ServeCLA(append(tt.arguments, "--dir="+dir))
+		if err != tt.err {
+			t.Errorf("%s: domain new ServeCLA() error = %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		var errorsFile = readTestFile(t, filepath.Join(dir, "product_price", "errors.go"))
+		var wantMediaType = "domain/" + tt.wantDomain + "; type=error; package=product_price; name=not_found"
+		if !strings.Contains(errorsFile, "package product_price") || !strings.Contains(errorsFile, wantMediaType) {
+			t.Errorf("%s: domain new errors.go = %s, want media type %q", tt.name, errorsFile, wantMediaType)
+		}
+		var localeEng = readTestFile(t, filepath.Join(dir, "product_price", "locale.eng.go"))
+		if !strings.HasPrefix(localeEng, "//go:build lang_eng\n") || !strings.Contains(localeEng, `"Product Price"`) {
+			t.Errorf("%s: domain new locale.eng.go = %s", tt.name, localeEng)
+		}
+		var localePer = readTestFile(t, filepath.Join(dir, "product_price", "locale.per.go"))
+		if !strings.HasPrefix(localePer, "//go:build lang_per\n") {
+			t.Errorf("%s: domain new locale.per.go = %s", tt.name, localePer)
+		}
+		for _, name := range []string{"service-init.go", "service-get.go"} {
+			if data := readTestFile(t, filepath.Join(dir, "product_price", name)); !strings.Contains(data, "package product_price") {
+				t.Errorf("%s: domain new %s = %s", tt.name, name, data)
+			}
+		}
+
+		err = c.ServeCLA(append(tt.arguments, "--dir="+dir))
+		if err != &ErrFileExist {
+			t.Errorf("%s: domain new again ServeCLA() error = %v, want %v", tt.name, err, &ErrFileExist)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	cmd "github.com/GeniusesGroup/libgo/command"
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// buildCommand is the "build" command that run the code generators and then build the app with the language build tags.
+// e.g. "libgo build -l=eng,per -o=app"
+type buildCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	languages  cmd.StringFlag
+	tags       cmd.StringFlag
+	output     cmd.StringFlag
+	generate   cmd.BooleanFlag
+	generators cmd.StringFlag
+	dir        cmd.StringFlag
+}
+
+func (c *buildCommand) init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=build")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Build",
+		"Run the Syllab and index hash generators on the app packages and then build the app with lang_* build tags of the given languages",
+		"",
+		"Generators named in the comma separated --generators complete their methods in the app Go files in place. Use --generate=false to skip them",
+		nil)
+	c.Command.Init(parent)
+	c.languages.Init("lang", "l", "eng")
+	c.tags.Init("tags", "", "")
+	c.output.Init("output", "o", "")
+	c.generate.Init("generate", "g", true)
+	c.generators.Init("generators", "", DefaultGenerators)
+	c.dir.Init("dir", "", ".")
+}
+
+//libgo:impl protocol.Command
+func (c *buildCommand) Name() string      { return "build" }
+func (c *buildCommand) Aliases() []string { return nil }
+func (c *buildCommand) UsageLine() string {
+	return "build [-l=languages] [--tags=tags] [-o=output] [-g=false] [--generators=syllab,index-hash] [--dir=app-directory]"
+}
+func (c *buildCommand) Runnable() bool { return true }
+func (c *buildCommand) Request() []protocol.Field {
+	return []protocol.Field{&c.languages, &c.tags, &c.output, &c.generate, &c.generators, &c.dir}
+}
+func (c *buildCommand) Response() []protocol.Field { return nil }
+func (c *buildCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	err = cmd.ParseFlags(c.Request(), arguments)
+	if err != nil {
+		return
+	}
+	var tags []string
+	tags, err = BuildTags(c.languages.Value, c.tags.Value)
+	if err != nil {
+		return
+	}
+	var tagsArg = "-tags=" + strings.Join(tags, ",")
+
+	if c.generate.Value {
+		var generators []Generator
+		generators, err = selectGenerators(c.generators.Value)
+		if err != nil {
+			return
+		}
+		err = generate(c.dir.Value, generators)
+		if err != nil {
+			return
+		}
+	}
+
+	var buildArgs = []string{"build", tagsArg}
+	if c.output.Value != "" {
+		buildArgs = append(buildArgs, "-o="+c.output.Value)
+	}
+	buildArgs = append(buildArgs, ".")
+	var goErr = runGo(c.dir.Value, buildArgs...)
+	if goErr != nil {
+		return &ErrBuild
+	}
+	return
+}
+
+// BuildTags return the build tags of the comma separated languages e.g. "eng,per" >> lang_eng, lang_per
+// and other comma separated tags.
+func BuildTags(languages, otherTags string) (tags []string, err protocol.Error) {
+	for _, lang := range strings.Split(languages, ",") {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" {
+			continue
+		}
+		if len(lang) != 3 || strings.Trim(lang, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return nil, &ErrBadLanguage
+		}
+		tags = append(tags, "lang_"+lang)
+	}
+	if len(tags) == 0 {
+		return nil, &ErrBadLanguage
+	}
+	for _, tag := range strings.Split(otherTags, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
+
+// runGo run the go tool in the directory and pass its output to the process stdout and stderr.
+func runGo(dir string, arguments ...string) error {
+	var goCmd = exec.Command("go", arguments...)
+	goCmd.Dir = dir
+	goCmd.Stdout = os.Stdout
+	goCmd.Stderr = os.Stderr
+	return goCmd.Run()
+}
@@ -0,0 +1,58 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func TestBuildTags(t *testing.T) {
+	var tests = []struct {
+		languages string
+		otherTags string
+		want      []string
+		err       protocol.Error
+	}{
+		{"eng", "", []string{"lang_eng"}, nil},
+		{"eng,per", "", []string{"lang_eng", "lang_per"}, nil},
+		{" ENG , per ,", "", []string{"lang_eng", "lang_per"}, nil},
+		{"eng", "dev, debug", []string{"lang_eng", "dev", "debug"}, nil},
+		{"", "", nil, &ErrBadLanguage},
+		{",", "dev", nil, &ErrBadLanguage},
+		{"en", "", nil, &ErrBadLanguage},
+		{"english", "", nil, &ErrBadLanguage},
+		{"en1", "", nil, &ErrBadLanguage},
+	}
+	for _, tt := range tests {
+		var got, err = BuildTags(tt.languages, tt.otherTags)
+		if err != tt.err {
+			t.Errorf("BuildTags(%q, %q) error = %v, want %v", tt.languages, tt.otherTags, err, tt.err)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("BuildTags(%q, %q) = %v, want %v", tt.languages, tt.otherTags, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCommandBadArguments(t *testing.T) {
+	var tests = []struct {
+		name      string
+		arguments []string
+		err       protocol.Error
+	}{
+		{"bad language", []string{"-l=english"}, &ErrBadLanguage},
+		{"unknown generator", []string{"--generators=syllab,json"}, &ErrBadGenerator},
+	}
+	for _, tt := range tests {
+		var c buildCommand
+		c.init(nil)
+		var arguments = append(tt.arguments, "--dir="+t.TempDir())
+		if err := c.ServeCLA(arguments); err != tt.err {
+			t.Errorf("%s: build ServeCLA() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	cmd "github.com/GeniusesGroup/libgo/command"
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// domainCommand is the "domain" command that manage domain modules of an application by its sub commands.
+type domainCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	newDomain domainNewCommand
+}
+
+func (c *domainCommand) init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=domain")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Domain",
+		"Manage domain modules of an application",
+		"",
+		"",
+		nil)
+
+	c.newDomain.init(c)
+	c.Command.Init(parent, &c.newDomain)
+}
+
+//libgo:impl protocol.Command
+func (c *domainCommand) Name() string               { return "domain" }
+func (c *domainCommand) Aliases() []string          { return []string{"module"} }
+func (c *domainCommand) UsageLine() string          { return "domain {new} [flags]" }
+func (c *domainCommand) Runnable() bool             { return true }
+func (c *domainCommand) Request() []protocol.Field  { return nil }
+func (c *domainCommand) Response() []protocol.Field { return nil }
+func (c *domainCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	return cmd.ServeCLA(c, arguments)
+}
+
+// domainNewCommand is the "domain new" command that generate a domain module package with a sample service,
+// its errors and locale files. e.g. "libgo domain new -n=product"
+type domainNewCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	name   cmd.StringFlag
+	domain cmd.StringFlag
+	dir    cmd.StringFlag
+}
+
+func (c *domainNewCommand) init(parent protocol.Command) {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=domain-new")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"New",
+		"Generate a domain module package with a sample service, errors and english and persian locale files",
+		"",
+		"Domain is the Domain constant in manifest.go of the app directory if not given",
+		nil)
+	c.Command.Init(parent)
+	c.name.Init("name", "n", "")
+	c.domain.Init("domain", "d", "")
+	c.dir.Init("dir", "", ".")
+}
+
+//libgo:impl protocol.Command
+func (c *domainNewCommand) Name() string      { return "new" }
+func (c *domainNewCommand) Aliases() []string { return []string{"add", "create"} }
+func (c *domainNewCommand) UsageLine() string {
+	return "domain new -n=name [-d=domain] [--dir=app-directory]"
+}
+func (c *domainNewCommand) Runnable() bool { return true }
+func (c *domainNewCommand) Request() []protocol.Field {
+	return []protocol.Field{&c.name, &c.domain, &c.dir}
+}
+func (c *domainNewCommand) Response() []protocol.Field { return nil }
+func (c *domainNewCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	err = cmd.ParseFlags(c.Request(), arguments)
+	if err != nil {
+		return
+	}
+	var name = c.name.Value
+	if !ValidName(name) {
+		return &ErrBadName
+	}
+	var domain = strings.ToLower(c.domain.Value)
+	if domain == "" {
+		domain = appDomain(c.dir.Value)
+	}
+	if !ValidDomain(domain) {
+		return &ErrBadDomain
+	}
+
+	var data = struct {
+		Domain string
+		Name   string
+		Title  string
+	}{domain, name, titleName(name)}
+
+	var files = []file{
+		{filepath.Join(name, "errors.go"), executeTemplate(domainErrorsFile, data)},
+		{filepath.Join(name, "locale.eng.go"), executeTemplate(domainLocaleEngFile, data)},
+		{filepath.Join(name, "locale.per.go"), executeTemplate(domainLocalePerFile, data)},
+		{filepath.Join(name, "service-init.go"), executeTemplate(domainServiceInitFile, data)},
+		{filepath.Join(name, "service-get.go"), executeTemplate(domainServiceGetFile, data)},
+	}
+	err = writeFiles(c.dir.Value, files)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		fmt.Fprintln(os.Stdout, "created", f.path)
+	}
+
+	var importPath = name
+	if module := modulePath(c.dir.Value); module != "" {
+		importPath = path.Join(module, name)
+	}
+	fmt.Fprintf(os.Stdout, "Import %q and call %s.RegisterServices() in registerServices() of services.go\n", importPath, name)
+	return
+}
+
+var domainErrorsFile = template.Must(template.New("domainErrorsFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package {{.Name}}
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+)
+
+// Declare package errors
+var (
+	ErrNotFound er.Error
+)
+
+func init() {
+	ErrNotFound.Init("domain/{{.Domain}}; type=error; package={{.Name}}; name=not_found")
+}
+`))
+
+var domainLocaleEngFile = template.Must(template.New("domainLocaleEngFile").Parse(`//go:build lang_eng
+
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package {{.Name}}
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "{{.Title}}"
+
+func init() {
+	ErrNotFound.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Not Found",
+		"Requested {{.Title}} not found",
+		"",
+		"",
+		nil)
+
+	GetService.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Get",
+		"Get a {{.Title}} by its ID",
+		"",
+		"",
+		nil)
+}
+`))
+
+var domainLocalePerFile = template.Must(template.New("domainLocalePerFile").Parse(`//go:build lang_per
+
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package {{.Name}}
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// TODO::: translate the domain name
+const domainPersian = "{{.Title}}"
+
+func init() {
+	ErrNotFound.SetDetail(protocol.LanguagePersian, domainPersian,
+		"یافت نشد",
+		"موردی با مشخصات درخواست شده یافت نشد",
+		"",
+		"",
+		nil)
+
+	GetService.SetDetail(protocol.LanguagePersian, domainPersian,
+		"دریافت",
+		"دریافت یک مورد با شناسه آن",
+		"",
+		"",
+		nil)
+}
+`))
+
+var domainServiceInitFile = template.Must(template.New("domainServiceInitFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package {{.Name}}
+
+import (
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// RegisterServices register the domain services in the application.
+func RegisterServices() {
+	protocol.App.RegisterService(&GetService)
+}
+`))
+
+var domainServiceGetFile = template.Must(template.New("domainServiceGetFile").Parse(`/* For license and copyright information please see the LEGAL file in the code repository */
+
+package {{.Name}}
+
+import (
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/service"
+)
+
+// GetService get a {{.Title}} by its ID. Implement ServeSRPC and ServeHTTP handlers to serve it.
+var GetService = getService{}
+
+func init() {
+	GetService.MT.Init("domain/{{.Domain}}; type=service; package={{.Name}}; name=get")
+}
+
+type getService struct {
+	service.Service
+	detail.DetailsContainer
+	mediatype.MT
+}
+
+//libgo:impl protocol.Service
+func (ser *getService) URI() string                 { return "/{{.Name}}/get" }
+func (ser *getService) CRUDType() protocol.CRUD     { return protocol.CRUDRead }
+func (ser *getService) UserType() protocol.UserType { return protocol.UserType_All }
+`))
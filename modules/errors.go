@@ -0,0 +1,88 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	er "github.com/GeniusesGroup/libgo/error"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+const domainEnglish = "Modules"
+
+// Declare package errors
+var (
+	ErrBadDomain    er.Error
+	ErrBadName      er.Error
+	ErrBadLanguage  er.Error
+	ErrFileExist    er.Error
+	ErrWriteFile    er.Error
+	ErrBadGenerator er.Error
+	ErrGenerate     er.Error
+	ErrBuild        er.Error
+)
+
+func init() {
+	ErrBadDomain.Init("domain/geniuses.group; type=error; package=modules; name=bad_domain")
+	ErrBadDomain.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Domain",
+		"Given internet domain is empty or not a valid domain name",
+		"Give the domain of the application like -d=example.com or run the command in an app directory",
+		"",
+		nil)
+
+	ErrBadName.Init("domain/geniuses.group; type=error; package=modules; name=bad_name")
+	ErrBadName.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Name",
+		"Given name is empty or not a valid Go package name",
+		"Use lower case english letters, digits and underscore that start with a letter like -n=product",
+		"",
+		nil)
+
+	ErrBadLanguage.Init("domain/geniuses.group; type=error; package=modules; name=bad_language")
+	ErrBadLanguage.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Language",
+		"Given language is not a three letter language code that use in lang_* build tags",
+		"Use languages like -l=eng,per",
+		"",
+		nil)
+
+	ErrFileExist.Init("domain/geniuses.group; type=error; package=modules; name=file_exist")
+	ErrFileExist.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"File Exist",
+		"A file that must generate already exist, so nothing generated to prevent overwrite your codes",
+		"Remove or rename the exist file and run the command again",
+		"",
+		nil)
+
+	ErrWriteFile.Init("domain/geniuses.group; type=error; package=modules; name=write_file")
+	ErrWriteFile.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Write File",
+		"Generated file can't write to the file system",
+		"Check the directory exist and you have write permission on it",
+		"",
+		nil)
+
+	ErrBadGenerator.Init("domain/geniuses.group; type=error; package=modules; name=bad_generator")
+	ErrBadGenerator.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Bad Generator",
+		"Given generator name is not one of the known code generators",
+		"Use known generators like --generators=syllab,index-hash",
+		"",
+		nil)
+
+	ErrGenerate.Init("domain/geniuses.group; type=error; package=modules; name=generate")
+	ErrGenerate.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Generate Failed",
+		"Code generators failed before build the application",
+		"Check the output of the generators to fix the issues",
+		"",
+		nil)
+
+	ErrBuild.Init("domain/geniuses.group; type=error; package=modules; name=build")
+	ErrBuild.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Build Failed",
+		"Go build of the application failed",
+		"Check the output of the go build command to fix the issues",
+		"",
+		nil)
+}
@@ -0,0 +1,147 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// file is a generated file by its path relative to the directory that generate in it.
+type file struct {
+	path string
+	data []byte
+}
+
+func executeTemplate(t *template.Template, data any) []byte {
+	var buf bytes.Buffer
+	// Templates are static and data fields are valid by the commands, so execute never fail.
+	t.Execute(&buf, data)
+	return buf.Bytes()
+}
+
+// writeFiles write all files in the directory, or nothing if any of them exist to prevent overwrite developer codes.
+func writeFiles(dir string, files []file) (err protocol.Error) {
+	for _, f := range files {
+		var _, goErr = os.Stat(filepath.Join(dir, f.path))
+		if goErr == nil {
+			return &ErrFileExist
+		}
+	}
+	for _, f := range files {
+		var path = filepath.Join(dir, f.path)
+		var goErr = os.MkdirAll(filepath.Dir(path), 0755)
+		if goErr == nil {
+			goErr = os.WriteFile(path, f.data, 0644)
+		}
+		if goErr != nil {
+			return &ErrWriteFile
+		}
+	}
+	return
+}
+
+// AppID return the application ID as SHA-512/256 hash of its internet domain.
+func AppID(domain string) [32]byte { return sha512.Sum512_256([]byte(domain)) }
+
+// ValidDomain report the domain is a valid lower case internet domain name with at least two labels e.g. "example.com"
+// https://datatracker.ietf.org/doc/html/rfc1035#section-2.3.1
+func ValidDomain(domain string) bool {
+	if len(domain) > 253 {
+		return false
+	}
+	var labels = strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			var c = label[i]
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ValidName report the name is a valid lower case Go package name that is not a Go keyword e.g. "product_price"
+func ValidName(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' || token.IsKeyword(name) {
+		return false
+	}
+	for i := 1; i < len(name); i++ {
+		var c = name[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// titleName return the name as words to use in locale texts e.g. "product_price" >> "Product Price"
+func titleName(name string) string {
+	var words = strings.Split(name, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// appDomain return the Domain constant of the app in manifest.go of the directory that generated by "libgo app init".
+func appDomain(dir string) (domain string) {
+	var fileSet = token.NewFileSet()
+	var manifest, goErr = parser.ParseFile(fileSet, filepath.Join(dir, "manifest.go"), nil, 0)
+	if goErr != nil {
+		return
+	}
+	for _, decl := range manifest.Decls {
+		var genDecl, ok = decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			var valueSpec = spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				if name.Name != "Domain" || i >= len(valueSpec.Values) {
+					continue
+				}
+				if lit, ok := valueSpec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					domain, _ = strconv.Unquote(lit.Value)
+					return
+				}
+			}
+		}
+	}
+	return
+}
+
+// modulePath return the module path in go.mod of the directory or empty string if it isn't a Go module root.
+func modulePath(dir string) string {
+	var goMod, goErr = os.ReadFile(filepath.Join(dir, "go.mod"))
+	if goErr != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(line[len("module "):]), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,166 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidDomain(t *testing.T) {
+	var tests = []struct {
+		domain string
+		want   bool
+	}{
+		{"example.com", true},
+		{"sub.example.com", true},
+		{"my-app2.example.com", true},
+		{"", false},
+		{"example", false},
+		{"Example.com", false},
+		{"example..com", false},
+		{".example.com", false},
+		{"-app.example.com", false},
+		{"app-.example.com", false},
+		{"my_app.example.com", false},
+		{strings.Repeat("a", 64) + ".com", false},
+		{strings.Repeat("a.", 127) + "com", false},
+	}
+	for _, tt := range tests {
+		if got := ValidDomain(tt.domain); got != tt.want {
+			t.Errorf("ValidDomain(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	var tests = []struct {
+		name string
+		want bool
+	}{
+		{"product", true},
+		{"product_price", true},
+		{"v2", true},
+		{"", false},
+		{"Product", false},
+		{"2product", false},
+		{"_product", false},
+		{"product-price", false},
+		{"type", false},
+		{"func", false},
+	}
+	for _, tt := range tests {
+		if got := ValidName(tt.name); got != tt.want {
+			t.Errorf("ValidName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTitleName(t *testing.T) {
+	var tests = []struct {
+		name string
+		want string
+	}{
+		{"product", "Product"},
+		{"product_price", "Product Price"},
+		{"product__price", "Product  Price"},
+	}
+	for _, tt := range tests {
+		if got := titleName(tt.name); got != tt.want {
+			t.Errorf("titleName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAppDomain(t *testing.T) {
+	var tests = []struct {
+		name     string
+		manifest string
+		want     string
+	}{
+		{"const", "package main\n\nconst Domain = \"example.com\"\n", "example.com"},
+		{"const group", "package main\n\nconst (\n\tName, Domain = \"app\", \"example.com\"\n)\n", "example.com"},
+		{"var", "package main\n\nvar Domain = \"example.com\"\n", ""},
+		{"not string", "package main\n\nconst Domain = 1\n", ""},
+		{"bad file", "package", ""},
+		{"no file", "", ""},
+	}
+	for _, tt := range tests {
+		var dir = t.TempDir()
+		if tt.manifest != "" {
+			writeTestFile(t, filepath.Join(dir, "manifest.go"), tt.manifest)
+		}
+		if got := appDomain(dir); got != tt.want {
+			t.Errorf("%s: appDomain() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	var tests = []struct {
+		name  string
+		goMod string
+		want  string
+	}{
+		{"module", "module example.com/app\n\ngo 1.19\n", "example.com/app"},
+		{"quoted module", "// app module\nmodule \"example.com/app\"\n", "example.com/app"},
+		{"no module", "go 1.19\n", ""},
+		{"no file", "", ""},
+	}
+	for _, tt := range tests {
+		var dir = t.TempDir()
+		if tt.goMod != "" {
+			writeTestFile(t, filepath.Join(dir, "go.mod"), tt.goMod)
+		}
+		if got := modulePath(dir); got != tt.want {
+			t.Errorf("%s: modulePath() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	var dir = t.TempDir()
+	var files = []file{{"a.go", []byte("a")}, {filepath.Join("b", "b.go"), []byte("b")}}
+	if err := writeFiles(dir, files); err != nil {
+		t.Fatalf("writeFiles() error = %v", err)
+	}
+	for _, f := range files {
+		if got := readTestFile(t, filepath.Join(dir, f.path)); got != string(f.data) {
+			t.Errorf("writeFiles() %s = %q, want %q", f.path, got, f.data)
+		}
+	}
+
+	// No file must write if any of them exist.
+	var again = []file{{"c.go", []byte("c")}, {"a.go", []byte("new a")}}
+	if err := writeFiles(dir, again); err != &ErrFileExist {
+		t.Errorf("writeFiles() exist error = %v, want %v", err, &ErrFileExist)
+	}
+	if _, goErr := os.Stat(filepath.Join(dir, "c.go")); goErr == nil {
+		t.Errorf("writeFiles() wrote c.go while a.go exist")
+	}
+	if got := readTestFile(t, filepath.Join(dir, "a.go")); got != "a" {
+		t.Errorf("writeFiles() overwrite a.go = %q", got)
+	}
+}
+
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	var goErr = os.MkdirAll(filepath.Dir(path), 0755)
+	if goErr == nil {
+		goErr = os.WriteFile(path, []byte(data), 0644)
+	}
+	if goErr != nil {
+		t.Fatal(goErr)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	var data, goErr = os.ReadFile(path)
+	if goErr != nil {
+		t.Fatal(goErr)
+	}
+	return string(data)
+}
@@ -0,0 +1,128 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GeniusesGroup/libgo/assets"
+	"github.com/GeniusesGroup/libgo/pehrest"
+	"github.com/GeniusesGroup/libgo/protocol"
+	"github.com/GeniusesGroup/libgo/syllab"
+)
+
+// DefaultGenerators is the comma separated names of the generators that "build" run before go build.
+const DefaultGenerators = "syllab,index-hash"
+
+// Generator complete the generated methods of a Go file in place.
+// It run just on the files that have any of its markers, so files without its methods template never change.
+type Generator struct {
+	Name     string
+	Markers  [][]byte
+	Complete func(file *assets.File) error
+}
+
+// Generators are the code generators that "build" can run by their names.
+var Generators = []Generator{
+	{
+		Name:    "syllab",
+		Markers: [][]byte{[]byte(") syllabEncoder("), []byte(") SyllabEncoder("), []byte(") syllabDecoder("), []byte(") SyllabDecoder(")},
+		Complete: func(file *assets.File) error {
+			return syllab.CompleteMethods(file, &syllab.GenerationOptions{})
+		},
+	},
+	{
+		Name:     "index-hash",
+		Markers:  [][]byte{[]byte(`index-hash:"`)},
+		Complete: pehrest.CompleteIndexHashMethods,
+	},
+}
+
+// needed report the file data has any of the generator markers.
+func (g *Generator) needed(data []byte) bool {
+	for _, marker := range g.Markers {
+		if bytes.Contains(data, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectGenerators return the generators of the comma separated names.
+func selectGenerators(names string) (selected []Generator, err protocol.Error) {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var found bool
+		for _, g := range Generators {
+			if g.Name == name {
+				selected = append(selected, g)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, &ErrBadGenerator
+		}
+	}
+	return
+}
+
+// generate run the generators on the Go files of the directory and its sub directories and write changed files.
+// Test files, vendor and testdata directories and directories that go tool ignore ("." and "_" prefixed) skip.
+func generate(dir string, generators []Generator) (err protocol.Error) {
+	var goErr = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		var name = entry.Name()
+		if entry.IsDir() {
+			if path != dir && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		return generateFile(path, generators)
+	})
+	if goErr != nil {
+		fmt.Fprintln(os.Stderr, goErr)
+		return &ErrGenerate
+	}
+	return
+}
+
+func generateFile(path string, generators []Generator) (goErr error) {
+	var data []byte
+	data, goErr = os.ReadFile(path)
+	if goErr != nil {
+		return
+	}
+	var file = assets.File{Data: data}
+	for _, g := range generators {
+		if !g.needed(file.Data) {
+			continue
+		}
+		goErr = g.Complete(&file)
+		if goErr != nil {
+			return fmt.Errorf("%s: %s generator: %w", path, g.Name, goErr)
+		}
+	}
+	if file.State != assets.StateChanged {
+		return
+	}
+	goErr = os.WriteFile(path, file.Data, 0644)
+	if goErr == nil {
+		fmt.Fprintln(os.Stdout, "generated", path)
+	}
+	return
+}
@@ -0,0 +1,132 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GeniusesGroup/libgo/assets"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+func TestSelectGenerators(t *testing.T) {
+	var tests = []struct {
+		names string
+		want  []string
+		err   protocol.Error
+	}{
+		{DefaultGenerators, []string{"syllab", "index-hash"}, nil},
+		{"index-hash", []string{"index-hash"}, nil},
+		{" syllab , ", []string{"syllab"}, nil},
+		{"", nil, nil},
+		{"json", nil, &ErrBadGenerator},
+		{"syllab,json", nil, &ErrBadGenerator},
+	}
+	for _, tt := range tests {
+		var got, err = selectGenerators(tt.names)
+		if err != tt.err {
+			t.Errorf("selectGenerators(%q) error = %v, want %v", tt.names, err, tt.err)
+			continue
+		}
+		var gotNames []string
+		for _, g := range got {
+			gotNames = append(gotNames, g.Name)
+		}
+		if strings.Join(gotNames, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("selectGenerators(%q) = %v, want %v", tt.names, gotNames, tt.want)
+		}
+	}
+}
+
+// testGenerator append a generated comment to files that have the marker.
+var testGenerator = Generator{
+	Name:    "test",
+	Markers: [][]byte{[]byte("//test:marker")},
+	Complete: func(file *assets.File) error {
+		file.Data = append(file.Data, "// generated\n"...)
+		file.State = assets.StateChanged
+		return nil
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	const marked = "package app\n\n//test:marker\n"
+	const generated = marked + "// generated\n"
+	const unmarked = "package app\n"
+
+	var dir = t.TempDir()
+	var files = []struct {
+		path string
+		data string
+		want string
+	}{
+		{"marked.go", marked, generated},
+		{"unmarked.go", unmarked, unmarked},
+		{filepath.Join("domain", "marked.go"), marked, generated},
+		{"marked_test.go", marked, marked},
+		{"marked.txt", marked, marked},
+		{filepath.Join("vendor", "marked.go"), marked, marked},
+		{filepath.Join("testdata", "marked.go"), marked, marked},
+		{filepath.Join(".git", "marked.go"), marked, marked},
+		{filepath.Join("_old", "marked.go"), marked, marked},
+	}
+	for _, f := range files {
+		writeTestFile(t, filepath.Join(dir, f.path), f.data)
+	}
+
+	if err := generate(dir, []Generator{testGenerator}); err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for _, f := range files {
+		if got := readTestFile(t, filepath.Join(dir, f.path)); got != f.want {
+			t.Errorf("generate() %s = %q, want %q", f.path, got, f.want)
+		}
+	}
+}
+
+func TestGenerateFailed(t *testing.T) {
+	var dir = t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "marked.go"), "package app\n\n//test:marker\n")
+
+	var failed = testGenerator
+	failed.Complete = func(file *assets.File) error {
+		file.Data = nil
+		file.State = assets.StateChanged
+		return errors.New("bad method")
+	}
+	if err := generate(dir, []Generator{failed}); err != &ErrGenerate {
+		t.Errorf("generate() error = %v, want %v", err, &ErrGenerate)
+	}
+	// Failed generator must not write the file.
+	if got := readTestFile(t, filepath.Join(dir, "marked.go")); got != "package app\n\n//test:marker\n" {
+		t.Errorf("generate() wrote failed file = %q", got)
+	}
+
+	if err := generate(filepath.Join(dir, "not-exist"), []Generator{testGenerator}); err != &ErrGenerate {
+		t.Errorf("generate() not exist directory error = %v, want %v", err, &ErrGenerate)
+	}
+}
+
+func TestGeneratorNeeded(t *testing.T) {
+	var tests = []struct {
+		name      string
+		generator string
+		data      string
+		want      bool
+	}{
+		{"syllab encoder", "syllab", "func (r *Req) syllabEncoder(payload []byte) {}", true},
+		{"syllab exported decoder", "syllab", "func (r *Req) SyllabDecoder(payload []byte) (err error) { return }", true},
+		{"syllab call", "syllab", "r.syllabEncoder(payload)", false},
+		{"index hash", "index-hash", "type Req struct {\n\tID [32]byte `index-hash:\"Name\"`\n}", true},
+		{"index hash other tag", "index-hash", "type Req struct {\n\tID [32]byte `json:\"id\"`\n}", false},
+	}
+	for _, tt := range tests {
+		var g, _ = selectGenerators(tt.generator)
+		if got := g[0].needed([]byte(tt.data)); got != tt.want {
+			t.Errorf("%s: Generator.needed() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package modules
+
+import (
+	cmd "github.com/GeniusesGroup/libgo/command"
+	"github.com/GeniusesGroup/libgo/detail"
+	"github.com/GeniusesGroup/libgo/mediatype"
+	"github.com/GeniusesGroup/libgo/protocol"
+)
+
+// RootCommand is the "libgo" command-line root command that serve all other commands as its sub commands.
+var RootCommand rootCommand
+
+type rootCommand struct {
+	cmd.Command
+	detail.DetailsContainer
+	mediatype.MT
+
+	app        appCommand
+	domain     domainCommand
+	build      buildCommand
+	completion cmd.CompletionCommand
+}
+
+func (c *rootCommand) Init() {
+	c.MT.Init("domain/geniuses.group; type=command; package=modules; name=libgo")
+	c.DetailsContainer.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"libgo",
+		"Generate and build applications that use libgo protocols and packages",
+		"",
+		"",
+		nil)
+
+	c.app.init(c)
+	c.domain.init(c)
+	c.build.init(c)
+	c.completion.Init(c)
+	c.Command.Init(nil, &c.app, &c.domain, &c.build, &c.completion)
+}
+
+//libgo:impl protocol.Command
+func (c *rootCommand) Name() string               { return "libgo" }
+func (c *rootCommand) Aliases() []string          { return nil }
+func (c *rootCommand) UsageLine() string          { return "libgo <command> [arguments]" }
+func (c *rootCommand) Runnable() bool             { return true }
+func (c *rootCommand) Request() []protocol.Field  { return nil }
+func (c *rootCommand) Response() []protocol.Field { return nil }
+func (c *rootCommand) ServeCLA(arguments []string) (err protocol.Error) {
+	return cmd.ServeCLA(c, arguments)
+}
@@ -34,7 +34,7 @@ func (ser *deleteService) DoSRPC(req DeleteRequest) (err protocol.Error) {
 		return delete(req)
 	}
 
-	_, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	_, err = srpc.Do(node.Conn(), ser, &req)
 	return
 }
 
@@ -33,7 +33,7 @@ func (ser *getMetadataService) DoSRPC(req GetMetadataRequest) (res protocol.Obje
 	}
 
 	var srpcRes srpc.Response
-	srpcRes, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	srpcRes, err = srpc.Do(node.Conn(), ser, &req)
 	if err != nil {
 		return
 	}
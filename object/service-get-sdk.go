@@ -33,7 +33,7 @@ func (ser *getService) DoSRPC(req GetRequest) (res protocol.Object, err protocol
 	}
 
 	var srpcRes srpc.Response
-	srpcRes, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	srpcRes, err = srpc.Do(node.Conn(), ser, &req)
 	if err != nil {
 		return
 	}
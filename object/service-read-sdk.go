@@ -34,7 +34,7 @@ func (ser *readService) DoSRPC(req ReadRequest) (res readResponse, err protocol.
 	}
 
 	var srpcRes srpc.Response
-	srpcRes, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	srpcRes, err = srpc.Do(node.Conn(), ser, &req)
 	if err != nil {
 		return
 	}
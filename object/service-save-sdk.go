@@ -52,7 +52,7 @@ func (ser *saveService) DoSRPC(data protocol.Codec) (metadata protocol.ObjectMet
 		return
 	}
 
-	_, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	_, err = srpc.Do(node.Conn(), ser, &req)
 	return
 }
 
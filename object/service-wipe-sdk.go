@@ -34,7 +34,7 @@ func (ser *wipeService) DoSRPC(req WipeRequest) (err protocol.Error) {
 		return wipe(req)
 	}
 
-	_, err = srpc.HandleOutcomeRequest(node.Conn(), ser, &req)
+	_, err = srpc.Do(node.Conn(), ser, &req)
 	return
 }
 
@@ -33,7 +33,7 @@ func (ser *writeService) DoSRPC(req *WriteReq) (err protocol.Error) {
 		return write(req)
 	}
 
-	_, err = srpc.HandleOutcomeRequest(node.Conn(), ser, req)
+	_, err = srpc.Do(node.Conn(), ser, req)
 	return
 }
 
@@ -17,6 +17,11 @@ var (
 	ErrRecordNotExist         er.Error
 	ErrRecordManipulated      er.Error
 	ErrIndexValueAlreadyExist er.Error
+
+	ErrIndexHashFieldNotExist      er.Error
+	ErrIndexHashValueType          er.Error
+	ErrIndexHashKeyType            er.Error
+	ErrIndexHashGeneratedCorrupted er.Error
 )
 
 func init() {
@@ -54,4 +59,32 @@ func init() {
 		"",
 		"",
 		nil)
+
+	ErrIndexHashFieldNotExist.Init("urn:giti:index.protocol:error:index-hash-field-not-exist")
+	ErrIndexHashFieldNotExist.SetDetail(protocol.LanguageEnglish, domainEnglish, "Index Hash Field Not Exist",
+		"Field name in index-hash tag not exist in the struct type",
+		"",
+		"",
+		nil)
+
+	ErrIndexHashValueType.Init("urn:giti:index.protocol:error:index-hash-value-type")
+	ErrIndexHashValueType.SetDetail(protocol.LanguageEnglish, domainEnglish, "Index Hash Value Type",
+		"Value field of index-hash tag must be a byte array up to 32 byte length e.g. [32]byte or [16]byte",
+		"",
+		"",
+		nil)
+
+	ErrIndexHashKeyType.Init("urn:giti:index.protocol:error:index-hash-key-type")
+	ErrIndexHashKeyType.SetDetail(protocol.LanguageEnglish, domainEnglish, "Index Hash Key Type",
+		"Indexed field type must be a byte array, string, bool or fixed size integer or a custom type of them in the same file",
+		"",
+		"",
+		nil)
+
+	ErrIndexHashGeneratedCorrupted.Init("urn:giti:index.protocol:error:index-hash-generated-corrupted")
+	ErrIndexHashGeneratedCorrupted.SetDetail(protocol.LanguageEnglish, domainEnglish, "Index Hash Generated Corrupted",
+		"Start comment of index hash generated methods exist in the file but end comment not exist",
+		"",
+		"",
+		nil)
 }
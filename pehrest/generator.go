@@ -0,0 +1,394 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package pehrest
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"../assets"
+	"../log"
+	"../protocol"
+)
+
+/*
+CompleteIndexHashMethods read `index-hash` tag of struct fields and generate needed methods to maintain hash indexes.
+Tag value is the field name that store in the index as value e.g. RecordID, and the tagged field is the index key.
+Other fields can add to the key by "+" e.g. `index-hash:"ID+GroupID"` index by AllowUserID+GroupID.
+For below struct:
+
+type Product struct {
+	ID          [32]byte
+	AllowUserID [32]byte `index-hash:"ID"`
+}
+
+It generate below methods:
+
+func (p *Product) IndexHashSave() (err protocol.Error)               // call after save new record
+func (p *Product) IndexHashUpdate(old *Product) (err protocol.Error) // call after update exiting record
+func (p *Product) IndexHashDelete() (err protocol.Error)             // call after delete the record
+func (p *Product) FindByAllowUserID(offset, limit uint64) (IDs [][32]byte, err protocol.Error)
+func (p *Product) IndexAllowUserID() (err protocol.Error)
+func (p *Product) UnIndexAllowUserID() (err protocol.Error)
+func (p *Product) hashAllowUserIDForID() (hash [32]byte)
+
+Generated file need "golang.org/x/crypto/sha3", "../ganjine", "../pehrest", "../protocol" and "../syllab" imports.
+*/
+
+const (
+	generatedIndexHashStart = "/* -- Index Hash Generated Methods: Don't edit below codes manually -- */\n"
+	generatedIndexHashEnd   = "/* -- Index Hash Generated Methods: End -- */\n"
+)
+
+// CompleteIndexHashMethods use to update given go file and complete index hash methods of any struct type in it!
+// It will overwrite exiting generated methods! If you need it clone it before pass it here!
+func CompleteIndexHashMethods(file *assets.File) (err error) {
+	var fileSet *token.FileSet = token.NewFileSet()
+	var fileParsed *ast.File
+	fileParsed, err = parser.ParseFile(fileSet, "", file.Data, parser.ParseComments)
+	if err != nil {
+		return
+	}
+
+	var im = indexHashMaker{
+		Types: map[string]*ast.TypeSpec{},
+	}
+	for _, decl := range fileParsed.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, gDecl := range d.Specs {
+				switch gd := gDecl.(type) {
+				case *ast.TypeSpec:
+					im.Types[gd.Name.Name] = gd
+				}
+			}
+		}
+	}
+
+	for _, decl := range fileParsed.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, gDecl := range d.Specs {
+				switch gd := gDecl.(type) {
+				case *ast.TypeSpec:
+					err = im.make(gd)
+					if err != nil {
+						return
+					}
+				}
+			}
+		}
+	}
+
+	if im.Generated.Len() == 0 {
+		return
+	}
+
+	// Generated methods are partial source, so format them alone and don't touch other codes of the file.
+	var generated []byte
+	generated, err = format.Source(im.Generated.Bytes())
+	if err != nil {
+		return
+	}
+
+	// Replace exiting generated methods or add them to end of the file
+	var replace = assets.ReplaceReq{
+		Data:  "\n" + generatedIndexHashStart + string(generated) + generatedIndexHashEnd,
+		Start: len(file.Data),
+		End:   len(file.Data),
+	}
+	var start = bytes.Index(file.Data, []byte(generatedIndexHashStart))
+	if start > -1 {
+		var end = bytes.Index(file.Data[start:], []byte(generatedIndexHashEnd))
+		if end == -1 {
+			return &ErrIndexHashGeneratedCorrupted
+		}
+		replace.Start = start
+		replace.End = start + end + len(generatedIndexHashEnd)
+		// Replace the new line before start comment too, to not duplicate it.
+		if start > 0 && file.Data[start-1] == '\n' {
+			replace.Start--
+		}
+	}
+
+	file.Replace([]assets.ReplaceReq{replace})
+	file.State = assets.StateChanged
+	return
+}
+
+type indexHashMaker struct {
+	Types     map[string]*ast.TypeSpec // All types
+	RN        string                   // Receiver Name
+	RTN       string                   // Receiver Type Name
+	Generated bytes.Buffer             // Generated Data
+
+	save   bytes.Buffer
+	update bytes.Buffer
+	delete bytes.Buffer
+}
+
+type indexHashField struct {
+	KeyFields []*ast.Field // first one is the tagged field
+	KeyNames  []string
+	Value     string // Field name that store as index value
+	ValueCopy bool   // true if value field is byte array smaller than 32 byte
+}
+
+func (im *indexHashMaker) make(typ *ast.TypeSpec) (err error) {
+	var structType, ok = typ.Type.(*ast.StructType)
+	if !ok {
+		return
+	}
+
+	var indexes []indexHashField
+	for _, structField := range structType.Fields.List {
+		if structField.Tag == nil || len(structField.Names) == 0 {
+			continue
+		}
+		var structFieldTag = reflect.StructTag(structField.Tag.Value[1 : len(structField.Tag.Value)-1])
+		var tag = structFieldTag.Get("index-hash")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		var tagParts = strings.Split(tag, "+")
+		var ihf = indexHashField{
+			Value:     tagParts[0],
+			KeyFields: []*ast.Field{structField},
+			KeyNames:  []string{structField.Names[0].Name},
+		}
+		for _, keyName := range tagParts[1:] {
+			var keyField = findStructField(structType, keyName)
+			if keyField == nil {
+				protocol.App.Log(log.WarnEvent(domainEnglish, "Index hash key field "+keyName+" not exist in "+typ.Name.Name))
+				return &ErrIndexHashFieldNotExist
+			}
+			ihf.KeyFields = append(ihf.KeyFields, keyField)
+			ihf.KeyNames = append(ihf.KeyNames, keyName)
+		}
+
+		// Value field may not exist in the struct e.g. promoted from embedded types that we can't access here, so assume it is [32]byte
+		var valueField = findStructField(structType, ihf.Value)
+		if valueField != nil {
+			var byteArrayLen, isByteArray = byteArrayLen(valueField.Type)
+			if !isByteArray || byteArrayLen > 32 {
+				protocol.App.Log(log.WarnEvent(domainEnglish, "Index hash value field "+ihf.Value+" in "+typ.Name.Name+" must be byte array up to 32 byte"))
+				return &ErrIndexHashValueType
+			}
+			ihf.ValueCopy = byteArrayLen != 32
+		}
+
+		indexes = append(indexes, ihf)
+	}
+
+	if len(indexes) == 0 {
+		return
+	}
+
+	im.RTN = typ.Name.Name
+	im.RN = strings.ToLower(im.RTN[:1])
+	im.save.Reset()
+	im.update.Reset()
+	im.delete.Reset()
+
+	for i := range indexes {
+		err = im.makeIndex(&indexes[i])
+		if err != nil {
+			return
+		}
+	}
+
+	im.Generated.WriteString("\n// IndexHashSave add " + im.RTN + " to its hash indexes. Call it after save new record.\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") IndexHashSave() (err protocol.Error) {\n")
+	im.Generated.Write(im.save.Bytes())
+	im.Generated.WriteString("	return\n}\n")
+
+	im.Generated.WriteString("\n// IndexHashUpdate update hash indexes of " + im.RTN + " if any indexed field changed. Call it after update exiting record.\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") IndexHashUpdate(old *" + im.RTN + ") (err protocol.Error) {\n")
+	im.Generated.Write(im.update.Bytes())
+	im.Generated.WriteString("	return\n}\n")
+
+	im.Generated.WriteString("\n// IndexHashDelete delete " + im.RTN + " from its hash indexes. Call it after delete the record.\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") IndexHashDelete() (err protocol.Error) {\n")
+	im.Generated.Write(im.delete.Bytes())
+	im.Generated.WriteString("	return\n}\n")
+	return
+}
+
+func (im *indexHashMaker) makeIndex(ihf *indexHashField) (err error) {
+	var keyName = strings.Join(ihf.KeyNames, "")
+	var hashMethod = "hash" + keyName + "For" + ihf.Value
+	var frn = im.RN + "."
+
+	var indexValue string
+	if ihf.ValueCopy {
+		indexValue = "	var indexValue [32]byte\n	copy(indexValue[:], " + frn + ihf.Value + "[:])\n"
+	} else {
+		indexValue = "	var indexValue = " + frn + ihf.Value + "\n"
+	}
+
+	// Find method
+	im.Generated.WriteString("\n// FindBy" + keyName + " find " + ihf.Value + "s by given " + strings.Join(ihf.KeyNames, "+") + "\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") FindBy" + keyName + "(offset, limit uint64) (" + ihf.Value + "s [][32]byte, err protocol.Error) {\n")
+	im.Generated.WriteString("	var indexReq = &pehrest.HashGetValuesReq{\n")
+	im.Generated.WriteString("		IndexKey: " + frn + hashMethod + "(),\n")
+	im.Generated.WriteString("		Offset:   offset,\n")
+	im.Generated.WriteString("		Limit:    limit,\n")
+	im.Generated.WriteString("	}\n")
+	im.Generated.WriteString("	var indexRes *pehrest.HashGetValuesRes\n")
+	im.Generated.WriteString("	indexRes, err = pehrest.HashGetValues(indexReq)\n")
+	im.Generated.WriteString("	if err != nil {\n		return\n	}\n")
+	im.Generated.WriteString("	" + ihf.Value + "s = indexRes.IndexValues\n")
+	im.Generated.WriteString("	return\n}\n")
+
+	// Index method
+	im.Generated.WriteString("\n// Index" + keyName + " save " + ihf.Value + " chain for " + strings.Join(ihf.KeyNames, "+") + "\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") Index" + keyName + "() (err protocol.Error) {\n")
+	im.Generated.WriteString(indexValue)
+	im.Generated.WriteString("	var indexRequest = pehrest.HashSetValueReq{\n")
+	im.Generated.WriteString("		Type:       ganjine.RequestTypeBroadcast,\n")
+	im.Generated.WriteString("		IndexKey:   " + frn + hashMethod + "(),\n")
+	im.Generated.WriteString("		IndexValue: indexValue,\n")
+	im.Generated.WriteString("	}\n")
+	im.Generated.WriteString("	err = pehrest.HashSetValue(&indexRequest)\n")
+	im.Generated.WriteString("	return\n}\n")
+
+	// UnIndex method
+	im.Generated.WriteString("\n// UnIndex" + keyName + " delete " + ihf.Value + " from " + strings.Join(ihf.KeyNames, "+") + " chain\n")
+	im.Generated.WriteString("func (" + im.RN + " *" + im.RTN + ") UnIndex" + keyName + "() (err protocol.Error) {\n")
+	im.Generated.WriteString(indexValue)
+	im.Generated.WriteString("	var indexRequest = pehrest.HashDeleteValueReq{\n")
+	im.Generated.WriteString("		Type:       ganjine.RequestTypeBroadcast,\n")
+	im.Generated.WriteString("		IndexKey:   " + frn + hashMethod + "(),\n")
+	im.Generated.WriteString("		IndexValue: indexValue,\n")
+	im.Generated.WriteString("	}\n")
+	im.Generated.WriteString("	err = pehrest.HashDeleteValue(&indexRequest)\n")
+	im.Generated.WriteString("	return\n}\n")
+
+	// Hash method
+	var bufLen, encoder bytes.Buffer
+	bufLen.WriteString("len(field)")
+	encoder.WriteString("	copy(buf, field)\n	var i = len(field)\n")
+	for j, keyField := range ihf.KeyFields {
+		err = im.makeKeyEncoder(frn+ihf.KeyNames[j], keyField.Type, map[string]bool{}, &bufLen, &encoder)
+		if err != nil {
+			return
+		}
+	}
+	im.Generated.WriteString("\nfunc (" + im.RN + " *" + im.RTN + ") " + hashMethod + "() (hash [32]byte) {\n")
+	im.Generated.WriteString("	const field = \"" + im.RTN + "." + strings.Join(ihf.KeyNames, "+") + "\"\n")
+	im.Generated.WriteString("	var buf = make([]byte, " + bufLen.String() + ")\n")
+	im.Generated.Write(encoder.Bytes())
+	im.Generated.WriteString("	return sha3.Sum256(buf)\n}\n")
+
+	// Aggregate methods
+	im.save.WriteString("	err = " + frn + "Index" + keyName + "()\n	if err != nil {\n		return\n	}\n")
+	im.delete.WriteString("	err = " + frn + "UnIndex" + keyName + "()\n	if err != nil {\n		return\n	}\n")
+
+	var changed = make([]string, len(ihf.KeyNames))
+	for j, name := range ihf.KeyNames {
+		changed[j] = frn + name + " != old." + name
+	}
+	im.update.WriteString("	if " + strings.Join(changed, " || ") + " {\n")
+	im.update.WriteString("		err = old.UnIndex" + keyName + "()\n		if err != nil {\n			return\n		}\n")
+	im.update.WriteString("		err = " + frn + "Index" + keyName + "()\n		if err != nil {\n			return\n		}\n")
+	im.update.WriteString("	}\n")
+	return
+}
+
+// makeKeyEncoder write needed code to encode given field to the index key buffer.
+// Just comparable types support due to IndexHashUpdate must compare old and new value.
+// visited hold the custom types that resolved for the field, so types that refer to each other can't loop forever.
+func (im *indexHashMaker) makeKeyEncoder(fieldName string, fieldType ast.Expr, visited map[string]bool, bufLen, encoder *bytes.Buffer) (err error) {
+	if ln, isByteArray := byteArrayLen(fieldType); isByteArray {
+		bufLen.WriteString(" + " + strconv.FormatUint(ln, 10))
+		encoder.WriteString("	i += copy(buf[i:], " + fieldName + "[:])\n")
+		return
+	}
+
+	var ident, ok = fieldType.(*ast.Ident)
+	if !ok {
+		protocol.App.Log(log.WarnEvent(domainEnglish, "Index hash key field "+fieldName+" in "+im.RTN+" has not supported type"))
+		return &ErrIndexHashKeyType
+	}
+
+	switch ident.Name {
+	case "string":
+		bufLen.WriteString(" + len(" + fieldName + ")")
+		encoder.WriteString("	i += copy(buf[i:], " + fieldName + ")\n")
+	case "bool":
+		bufLen.WriteString(" + 1")
+		encoder.WriteString("	syllab.SetBool(buf, uint32(i), " + fieldName + ")\n	i++\n")
+	case "byte", "uint8":
+		bufLen.WriteString(" + 1")
+		encoder.WriteString("	syllab.SetUInt8(buf, uint32(i), uint8(" + fieldName + "))\n	i++\n")
+	case "int8":
+		bufLen.WriteString(" + 1")
+		encoder.WriteString("	syllab.SetInt8(buf, uint32(i), int8(" + fieldName + "))\n	i++\n")
+	case "uint16":
+		bufLen.WriteString(" + 2")
+		encoder.WriteString("	syllab.SetUInt16(buf, uint32(i), uint16(" + fieldName + "))\n	i += 2\n")
+	case "int16":
+		bufLen.WriteString(" + 2")
+		encoder.WriteString("	syllab.SetInt16(buf, uint32(i), int16(" + fieldName + "))\n	i += 2\n")
+	case "uint32":
+		bufLen.WriteString(" + 4")
+		encoder.WriteString("	syllab.SetUInt32(buf, uint32(i), uint32(" + fieldName + "))\n	i += 4\n")
+	case "int32":
+		bufLen.WriteString(" + 4")
+		encoder.WriteString("	syllab.SetInt32(buf, uint32(i), int32(" + fieldName + "))\n	i += 4\n")
+	case "uint64":
+		bufLen.WriteString(" + 8")
+		encoder.WriteString("	syllab.SetUInt64(buf, uint32(i), uint64(" + fieldName + "))\n	i += 8\n")
+	case "int64":
+		bufLen.WriteString(" + 8")
+		encoder.WriteString("	syllab.SetInt64(buf, uint32(i), int64(" + fieldName + "))\n	i += 8\n")
+	default:
+		// Custom type in the same file e.g. type UserType uint8
+		var typ, found = im.Types[ident.Name]
+		if !found || visited[ident.Name] {
+			protocol.App.Log(log.WarnEvent(domainEnglish, "Index hash key field "+fieldName+" in "+im.RTN+" has not supported type"))
+			return &ErrIndexHashKeyType
+		}
+		visited[ident.Name] = true
+		return im.makeKeyEncoder(fieldName, typ.Type, visited, bufLen, encoder)
+	}
+	return
+}
+
+func findStructField(structType *ast.StructType, name string) (field *ast.Field) {
+	for _, structField := range structType.Fields.List {
+		for _, fieldName := range structField.Names {
+			if fieldName.Name == name {
+				return structField
+			}
+		}
+	}
+	return
+}
+
+// byteArrayLen return length of given type if it is a byte array e.g. [32]byte
+func byteArrayLen(fieldType ast.Expr) (ln uint64, isByteArray bool) {
+	var arrayType, ok = fieldType.(*ast.ArrayType)
+	if !ok || arrayType.Len == nil {
+		return
+	}
+	var elt, isIdent = arrayType.Elt.(*ast.Ident)
+	if !isIdent || (elt.Name != "byte" && elt.Name != "uint8") {
+		return
+	}
+	var lit, isLit = arrayType.Len.(*ast.BasicLit)
+	if !isLit {
+		return
+	}
+	var err error
+	ln, err = strconv.ParseUint(lit.Value, 10, 64)
+	isByteArray = err == nil
+	return
+}
@@ -0,0 +1,112 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package pehrest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"../assets"
+	"../protocol"
+)
+
+type testApplication struct {
+	protocol.Application
+}
+
+func (app testApplication) Log(event protocol.LogEvent) protocol.Error { return nil }
+
+var updateGolden = flag.Bool("update", false, "update golden files of generator tests")
+
+func TestCompleteIndexHashMethods(t *testing.T) {
+	var src, err = os.ReadFile("testdata/product.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file = assets.File{Data: src}
+	err = CompleteIndexHashMethods(&file)
+	if err != nil {
+		t.Fatalf("CompleteIndexHashMethods() error = %v", err)
+	}
+	if file.State != assets.StateChanged {
+		t.Errorf("CompleteIndexHashMethods() state = %v, want %v", file.State, assets.StateChanged)
+	}
+
+	const goldenPath = "testdata/product.golden"
+	if *updateGolden {
+		err = os.WriteFile(goldenPath, file.Data, 0666)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var golden []byte
+	golden, err = os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(file.Data, golden) {
+		t.Errorf("CompleteIndexHashMethods() output not match %v, run test with -update flag if it is intended", goldenPath)
+	}
+
+	// Run again on generated file must replace exiting generated methods and not duplicate them.
+	err = CompleteIndexHashMethods(&file)
+	if err != nil {
+		t.Fatalf("CompleteIndexHashMethods() on generated file error = %v", err)
+	}
+	if !bytes.Equal(file.Data, golden) {
+		t.Errorf("CompleteIndexHashMethods() on generated file not idempotent")
+	}
+}
+
+func TestCompleteIndexHashMethodsOffset(t *testing.T) {
+	var src, err = os.ReadFile("testdata/product.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty generated block at first of the file, without any new line before it.
+	var file = assets.File{Data: append([]byte(generatedIndexHashStart+generatedIndexHashEnd), src...)}
+	err = CompleteIndexHashMethods(&file)
+	if err != nil {
+		t.Fatalf("CompleteIndexHashMethods() with generated block at offset 0 error = %v", err)
+	}
+	if !bytes.HasPrefix(file.Data, []byte("\n"+generatedIndexHashStart)) {
+		t.Errorf("CompleteIndexHashMethods() with generated block at offset 0 corrupt the file start")
+	}
+	if !bytes.HasSuffix(file.Data, src) {
+		t.Errorf("CompleteIndexHashMethods() with generated block at offset 0 corrupt the file end")
+	}
+	if bytes.Count(file.Data, []byte(generatedIndexHashStart)) != 1 {
+		t.Errorf("CompleteIndexHashMethods() with generated block at offset 0 duplicate generated methods")
+	}
+
+	// Corrupted generated block without end comment.
+	file = assets.File{Data: append(append([]byte{}, src...), generatedIndexHashStart...)}
+	err = CompleteIndexHashMethods(&file)
+	if err != &ErrIndexHashGeneratedCorrupted {
+		t.Errorf("CompleteIndexHashMethods() with corrupted generated block error = %v, want %v", err, &ErrIndexHashGeneratedCorrupted)
+	}
+}
+
+func TestCompleteIndexHashMethodsTypeCycle(t *testing.T) {
+	// Invalid recursive types must not resolve forever.
+	const src = `package product
+
+type A B
+type B A
+
+type Product struct {
+	ID  [32]byte
+	Key A ` + "`index-hash:\"ID\"`" + `
+}
+`
+	protocol.App = testApplication{}
+	var file = assets.File{Data: []byte(src)}
+	var err = CompleteIndexHashMethods(&file)
+	if err != &ErrIndexHashKeyType {
+		t.Errorf("CompleteIndexHashMethods() with recursive key type error = %v, want %v", err, &ErrIndexHashKeyType)
+	}
+}
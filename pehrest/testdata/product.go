@@ -0,0 +1,12 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package testdata
+
+type Product struct {
+	ID          [32]byte
+	AllowUserID [32]byte `index-hash:"ID"`
+	GroupID     [16]byte `index-hash:"ID+Status"`
+	Status      ProductStatus
+}
+
+type ProductStatus uint8
@@ -7,6 +7,20 @@ type Cipher interface {
 	CipherSuite() CipherSuite
 	PublicKey() Codec // DER, PEM, ...
 	// SymmetricKey() []byte // length depend on CipherSuite Can't store it due to security impact
+
+	// Sign make a detached signature of the given data e.g. to sign all data of a stream.
+	Sign(data []byte) (signature []byte)
+	// Verify check given detached signature belong to given data.
+	Verify(data, signature []byte) (err Error)
+	// Rekey derive a new cipher from current session secret with given suite, without any new handshake.
+	// It let long-lived connections rotate keys without reconnecting.
+	Rekey(suite CipherSuite) (cipher Cipher, err Error)
+}
+
+// CipherSuites is the interface that must implement by any object that store cipher suites.
+type CipherSuites interface {
+	RegisterCipherSuite(cs CipherSuite)
+	GetCipherSuiteByID(id uint64) CipherSuite
 }
 
 // A BlockCipher represents an implementation of block cipher
@@ -19,13 +19,19 @@ type ConnectionMetrics interface {
 	NotRequestedPacketsReceived() uint64 // Counts not requested packets received for firewalling server from some attack types
 	SucceedStreamCount() uint64          // Count successful request
 	FailedStreamCount() uint64           // Count failed services call e.g. data validation failed, ...
+	MissedPongs() uint32                 // Count sequential pings that peer not response to them
+	SmoothedRTT() Duration               // Smoothed round-trip time that measure by ping frames
+	RTTVariation() Duration              // Round-trip time variation that measure by ping frames
 
 	StreamSucceed()
 	StreamFailed()
 	PacketReceived(packetLength uint64)
+	PacketReceiveFailed() // malformed or not decryptable packet received, usually protocol violation by peer
 	DuplicatePacketReceived(packetLength uint64)
 	PacketSent(packetLength uint64)
 	PacketResend(packetLength uint64)
+	PingSent(id int64)
+	PongReceived(id int64) (rtt Duration)
 
 	// Rate() uint // Byte/Second
 }
@@ -0,0 +1,13 @@
+/* For license and copyright information please see the LEGAL file in the code repository */
+
+package protocol
+
+// ConnectionQuotas enforce the connection user type quotas e.g. daily service call or concurrent streams.
+// Each check method count the usage and return error if the quota exceeded, so caller must drop the request.
+type ConnectionQuotas interface {
+	StreamOpened() (err Error) // Call on each new stream open
+	StreamClosed()             // Call on each stream close to release concurrent streams quota
+	ServiceCalled() (err Error)
+	PacketSending(packetLength uint64) (err Error)
+	PacketReceiving(packetLength uint64) (err Error)
+}
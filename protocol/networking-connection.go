@@ -16,6 +16,9 @@ type Connection interface {
 	UserID() UserID
 	DelegateUserID() UserID // Persons can delegate to things(as a user type)
 
+	/* Security data */
+	Cipher() Cipher // Selected cipher algorithms https://en.wikipedia.org/wiki/Cipher_suite
+
 	Close() (err Error)  // Just once
 	Revoke() (err Error) // Just once
 	
@@ -24,6 +27,7 @@ type Connection interface {
 	ConnectionLowLevelAPIs
 	Streams
 	ConnectionMetrics
+	ConnectionQuotas
 }
 
 // ConnectionLevelAPIs is low level APIs, don't use them in the services layer, if you don't know how it can be effect the application.
@@ -38,4 +42,8 @@ type ConnectionLowLevelAPIs interface {
 	// Due to speed matters in link layer, and it is very rare situation, it is better to ignore suddenly port unavailability.
 	// After return, caller can't reuse payload array anymore.
 	Send(packet []byte) (err Error) // to send data use Send() that exist in stream of each connection
+
+	// SetCipher change the connection cipher e.g. by change cipher spec frame.
+	// Any packet after call it must encrypt||decrypt by new cipher.
+	SetCipher(cipher Cipher)
 }
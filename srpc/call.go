@@ -0,0 +1,88 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"sync"
+
+	"../protocol"
+	"../timer"
+)
+
+// Call represents an active sRPC call that pipelined on a Client connection.
+type Call struct {
+	client   *Client
+	stream   protocol.Stream
+	deadline timer.Sync // not initialized if call has no deadline
+	slot     bool       // call hold a client slot
+
+	cancelOnce  sync.Once
+	canceled    chan struct{}
+	releaseOnce sync.Once
+}
+
+func (call *Call) Stream() protocol.Stream { return call.stream }
+
+// Wait block caller until get response, deadline reached, call canceled or error occur.
+func (call *Call) Wait() (res Response, err protocol.Error) {
+	defer call.release()
+
+	var state = call.stream.State()
+	// Signal() return nil channel if deadline not initialized, so it never selected.
+	var deadline = call.deadline.Signal()
+	for {
+		select {
+		case status := <-state:
+			switch status {
+			case protocol.NetworkStatus_Ready, protocol.NetworkStatus_ReceivedCompletely:
+				err = call.stream.Error()
+				if err != nil {
+					return
+				}
+				var payload []byte
+				payload, err = call.stream.Marshal()
+				res = Response(payload)
+				return
+			case protocol.NetworkStatus_Timeout:
+				err = &ErrCallDeadlineExceeded
+				return
+			case protocol.NetworkStatus_Closed, protocol.NetworkStatus_BrokenPacket:
+				err = call.stream.Error()
+				if err == nil {
+					err = &ErrCallCanceled
+				}
+				return
+			}
+		case <-deadline:
+			call.Cancel()
+			err = &ErrCallDeadlineExceeded
+			return
+		case <-call.canceled:
+			err = &ErrCallCanceled
+			return
+		}
+	}
+}
+
+// Cancel cancel the call by send close stream frame to the peer and release the call resources.
+// It is safe to call it many times or concurrently with Wait().
+func (call *Call) Cancel() {
+	call.cancelOnce.Do(func() {
+		close(call.canceled)
+		sendCloseStream(call.client.conn, uint32(call.stream.StreamID()))
+		call.stream.Close()
+		call.release()
+	})
+}
+
+// release stop the deadline timer and free the client slot. It is safe to call it many times.
+func (call *Call) release() {
+	call.releaseOnce.Do(func() {
+		if call.deadline.Signal() != nil {
+			call.deadline.Stop()
+		}
+		if call.slot {
+			call.client.releaseSlot()
+		}
+	})
+}
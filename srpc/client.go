@@ -0,0 +1,163 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"../protocol"
+	"../time/earth"
+	"../time/monotonic"
+	"../timer"
+)
+
+const (
+	// DefaultCallTimeout use by Do() when caller not give any deadline.
+	DefaultCallTimeout = 60 * earth.Second
+)
+
+// DefaultRetryPolicy use by Do() to retry idempotent services.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     100 * earth.Millisecond,
+}
+
+// Do call the service on the connection and block caller until get response or error.
+// It is a typed helper for generated *-sdk.go files that don't need to hold a Client.
+func Do(conn protocol.Connection, service protocol.Service, req protocol.Codec) (res Response, err protocol.Error) {
+	var client Client
+	client.Init(conn, 0, DefaultRetryPolicy)
+	res, err = client.Call(service, req, DefaultCallTimeout)
+	return
+}
+
+// Response is the sRPC response payload that received on a stream.
+// Generated SDKs decode it by syllab helpers e.g. syllab.GetByteArray(res.Payload(), 0)
+type Response []byte
+
+func (res Response) Payload() []byte { return res }
+
+// RetryPolicy indicate how a Client retry failed calls. Only idempotent services and temporary errors will retry.
+type RetryPolicy struct {
+	MaxAttempts uint8             // 0 or 1 means no retry
+	Backoff     protocol.Duration // wait before second attempt and double for each next attempt
+}
+
+// Client is a sRPC client bound to a connection that pipeline many concurrent calls over the connection streams.
+// Client is safe to use concurrently after Init.
+type Client struct {
+	conn  protocol.Connection
+	retry RetryPolicy
+	// slots limit number of concurrent calls. nil means no limit and connection streams limit is the only limit.
+	slots chan struct{}
+}
+
+// Init initialize the client. maxConcurrentCalls 0 means no limit.
+func (c *Client) Init(conn protocol.Connection, maxConcurrentCalls int, retry RetryPolicy) {
+	c.conn = conn
+	c.retry = retry
+	if maxConcurrentCalls > 0 {
+		c.slots = make(chan struct{}, maxConcurrentCalls)
+	}
+}
+
+func (c *Client) Connection() protocol.Connection { return c.conn }
+
+// Call send the request to the service and block caller until get response, deadline reached or error occur.
+// timeout 0 means no deadline. Idempotent services will retry by the client RetryPolicy on temporary errors,
+// but all attempts share the timeout, so no attempt will send after the deadline.
+func (c *Client) Call(service protocol.Service, req protocol.Codec, timeout protocol.Duration) (res Response, err protocol.Error) {
+	var deadline = monotonic.Now()
+	deadline.Add(timeout)
+	var backoff = c.retry.Backoff
+	var attempt uint8 = 1
+	for {
+		var call *Call
+		call, err = c.Go(service, req, timeout)
+		if err == nil {
+			res, err = call.Wait()
+		}
+		if err == nil || !c.canRetry(service, err, attempt) {
+			return
+		}
+
+		if backoff > 0 {
+			<-timer.After(backoff)
+			backoff *= 2
+		}
+		if timeout > 0 {
+			timeout = deadline.UntilNow()
+			if timeout <= 0 {
+				return nil, &ErrCallDeadlineExceeded
+			}
+		}
+		attempt++
+	}
+}
+
+// Go send the request to the service without wait for the response, so caller can pipeline many calls
+// on the connection and then wait for each of them by call.Wait() or cancel them by call.Cancel().
+// timeout 0 means no deadline. The deadline include the wait for a free slot of concurrent calls.
+func (c *Client) Go(service protocol.Service, req protocol.Codec, timeout protocol.Duration) (call *Call, err protocol.Error) {
+	call = &Call{
+		client:   c,
+		canceled: make(chan struct{}),
+	}
+	if timeout > 0 {
+		call.deadline.Init()
+		err = call.deadline.Start(timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.takeSlot(call.deadline.Signal())
+	if err != nil {
+		call.release()
+		return nil, err
+	}
+	call.slot = c.slots != nil
+
+	call.stream, err = c.conn.OutcomeStream(service)
+	if err != nil {
+		call.release()
+		return nil, err
+	}
+
+	err = call.stream.Send(req)
+	if err != nil {
+		call.release()
+		return nil, err
+	}
+	return
+}
+
+func (c *Client) canRetry(service protocol.Service, err protocol.Error, attempt uint8) bool {
+	if attempt >= c.retry.MaxAttempts || !err.Temporary() {
+		return false
+	}
+	return isIdempotent(service)
+}
+
+// takeSlot wait for a free slot of concurrent calls until the deadline signal. nil deadline wait without limit.
+func (c *Client) takeSlot(deadline <-chan struct{}) (err protocol.Error) {
+	if c.slots == nil {
+		return
+	}
+	select {
+	case c.slots <- struct{}{}:
+	case <-deadline:
+		err = &ErrCallDeadlineExceeded
+	}
+	return
+}
+
+func (c *Client) releaseSlot() {
+	if c.slots != nil {
+		<-c.slots
+	}
+}
+
+// isIdempotent report whether calling the service many times has the same effect as calling it once.
+func isIdempotent(service protocol.Service) bool {
+	var crud = service.CRUDType()
+	return crud != protocol.CRUDNone && crud&^protocol.CRUDRead == 0
+}
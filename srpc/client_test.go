@@ -0,0 +1,153 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"testing"
+
+	er "../error"
+	"../protocol"
+	"../time/earth"
+)
+
+var errTestTemporary er.Error
+
+func init() {
+	errTestTemporary.Init("domain/srpc.protocol; type=error; name=test-temporary")
+	errTestTemporary.SetTemporary()
+}
+
+type clientTestService struct {
+	protocol.Service
+	crud protocol.CRUD
+}
+
+func (s clientTestService) CRUDType() protocol.CRUD { return s.crud }
+
+// clientTestStream response to each request by given status and error.
+type clientTestStream struct {
+	protocol.Stream
+	state chan protocol.NetworkStatus
+	err   protocol.Error
+}
+
+func (s *clientTestStream) StreamID() protocol.StreamID                   { return 0 }
+func (s *clientTestStream) State() chan protocol.NetworkStatus            { return s.state }
+func (s *clientTestStream) Error() protocol.Error                         { return s.err }
+func (s *clientTestStream) Marshal() (data []byte, err protocol.Error)    { return }
+func (s *clientTestStream) Close() (err protocol.Error)                   { return }
+func (s *clientTestStream) Send(data protocol.Codec) (err protocol.Error) { return }
+
+// clientTestConnection count the outcome streams, so each call attempt.
+type clientTestConnection struct {
+	testConnection
+	respondStatus protocol.NetworkStatus
+	respondErr    protocol.Error
+	attempts      int
+}
+
+func (conn *clientTestConnection) OutcomeStream(service protocol.Service) (stream protocol.Stream, err protocol.Error) {
+	conn.attempts++
+	var s = clientTestStream{
+		state: make(chan protocol.NetworkStatus, 1),
+		err:   conn.respondErr,
+	}
+	s.state <- conn.respondStatus
+	return &s, nil
+}
+
+type clientTest struct {
+	name     string
+	crud     protocol.CRUD
+	status   protocol.NetworkStatus
+	err      protocol.Error
+	wantErr  protocol.Error
+	attempts int
+}
+
+var clientTests = []clientTest{
+	{
+		name:     "succeed",
+		crud:     protocol.CRUDRead,
+		status:   protocol.NetworkStatus_ReceivedCompletely,
+		attempts: 1,
+	}, {
+		name:     "deadline-exceeded",
+		crud:     protocol.CRUDRead,
+		status:   protocol.NetworkStatus_Timeout,
+		wantErr:  &ErrCallDeadlineExceeded,
+		attempts: 1,
+	}, {
+		name:     "canceled",
+		crud:     protocol.CRUDRead,
+		status:   protocol.NetworkStatus_Closed,
+		wantErr:  &ErrCallCanceled,
+		attempts: 1,
+	}, {
+		name:     "temporary-idempotent",
+		crud:     protocol.CRUDRead,
+		status:   protocol.NetworkStatus_ReceivedCompletely,
+		err:      &errTestTemporary,
+		wantErr:  &errTestTemporary,
+		attempts: 3,
+	}, {
+		name:     "temporary-not-idempotent",
+		crud:     protocol.CRUDCreate,
+		status:   protocol.NetworkStatus_ReceivedCompletely,
+		err:      &errTestTemporary,
+		wantErr:  &errTestTemporary,
+		attempts: 1,
+	},
+}
+
+func TestClientCall(t *testing.T) {
+	for _, tt := range clientTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conn = clientTestConnection{
+				testConnection: *newTestConnection(),
+				respondStatus:  tt.status,
+				respondErr:     tt.err,
+			}
+			var client Client
+			client.Init(&conn, 0, RetryPolicy{MaxAttempts: 3})
+
+			var _, err = client.Call(clientTestService{crud: tt.crud}, nil, 0)
+			if err != tt.wantErr {
+				t.Errorf("Client.Call() error = %v, want %v", err, tt.wantErr)
+			}
+			if conn.attempts != tt.attempts {
+				t.Errorf("Client.Call() attempts = %v, want %v", conn.attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestClientSlots(t *testing.T) {
+	var conn = clientTestConnection{
+		testConnection: *newTestConnection(),
+		respondStatus:  protocol.NetworkStatus_ReceivedCompletely,
+	}
+	var client Client
+	client.Init(&conn, 1, RetryPolicy{})
+	var service = clientTestService{crud: protocol.CRUDRead}
+
+	var call, err = client.Go(service, nil, 0)
+	if err != nil {
+		t.Fatalf("Client.Go() error = %v, want nil", err)
+	}
+
+	_, err = client.Go(service, nil, 10*earth.Millisecond)
+	if err != &ErrCallDeadlineExceeded {
+		t.Errorf("Client.Go() on full slots error = %v, want %v", err, &ErrCallDeadlineExceeded)
+	}
+
+	call.Cancel()
+	call, err = client.Go(service, nil, 10*earth.Millisecond)
+	if err != nil {
+		t.Fatalf("Client.Go() after Call.Cancel() error = %v, want nil", err)
+	}
+	call.Cancel()
+	if len(client.slots) != 0 {
+		t.Errorf("Client slots = %v, want 0", len(client.slots))
+	}
+}
@@ -0,0 +1,91 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	er "../error"
+	"../protocol"
+)
+
+const domainEnglish = "sRPC"
+const domainPersian = "sRPC"
+
+// Declare package errors
+var (
+	ErrStreamSignature         er.Error
+	ErrCipherSuiteNotSupported er.Error
+	ErrCipherSuiteInsecure     er.Error
+	ErrCallDeadlineExceeded    er.Error
+	ErrCallCanceled            er.Error
+	ErrFrameTooShort           er.Error
+	ErrFrameLength             er.Error
+	ErrFrameUnknownType        er.Error
+)
+
+func init() {
+	ErrStreamSignature.Init("domain/srpc.protocol; type=error; name=stream-signature")
+	ErrStreamSignature.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Stream Signature",
+		"Received signature of the stream is not valid for the stream data",
+		"",
+		"",
+		nil)
+
+	ErrCipherSuiteNotSupported.Init("domain/srpc.protocol; type=error; name=cipher-suite-not-supported")
+	ErrCipherSuiteNotSupported.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Cipher Suite Not Supported",
+		"Requested cipher suite in change cipher spec frame is not supported",
+		"",
+		"",
+		nil)
+
+	ErrCipherSuiteInsecure.Init("domain/srpc.protocol; type=error; name=cipher-suite-insecure")
+	ErrCipherSuiteInsecure.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Cipher Suite Insecure",
+		"Requested cipher suite in change cipher spec frame has known security issues and can't be use",
+		"",
+		"",
+		nil)
+
+	// Deadline and cancellation errors are not temporary, so client never retry a call that its caller gave up on it
+	// and peer may already run it.
+	ErrCallDeadlineExceeded.Init("domain/srpc.protocol; type=error; name=call-deadline-exceeded")
+	ErrCallDeadlineExceeded.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Call Deadline Exceeded",
+		"Peer not response to the service call before its deadline",
+		"",
+		"",
+		nil)
+
+	ErrCallCanceled.Init("domain/srpc.protocol; type=error; name=call-canceled")
+	ErrCallCanceled.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Call Canceled",
+		"Service call canceled by the caller or peer before get any response",
+		"",
+		"",
+		nil)
+
+	ErrFrameTooShort.Init("domain/srpc.protocol; type=error; name=frame-too-short")
+	ErrFrameTooShort.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Frame Too Short",
+		"Received frame is shorter than its fixed fields length",
+		"",
+		"",
+		nil)
+
+	ErrFrameLength.Init("domain/srpc.protocol; type=error; name=frame-length")
+	ErrFrameLength.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Frame Length",
+		"Length field of received frame is smaller than its fixed fields or larger than remaining packet",
+		"",
+		"",
+		nil)
+
+	ErrFrameUnknownType.Init("domain/srpc.protocol; type=error; name=frame-unknown-type")
+	ErrFrameUnknownType.SetDetail(protocol.LanguageEnglish, domainEnglish,
+		"Frame Unknown Type",
+		"Received frame type is not known and it is not an extension frame that can be skipped",
+		"",
+		"",
+		nil)
+}
@@ -25,6 +25,12 @@ func (f serviceFrame) Time() int64        { return syllab.GetInt64(f, 18) }
 func (f serviceFrame) Payload() []byte    { return f[26:f.Length()] }
 func (f serviceFrame) NextFrame() []byte  { return f[f.Length():] }
 
+const serviceFrameFixedLength = 2 + 8 + 8 + 8 // Length + ServiceID + CompressID + Time
+
+func (f serviceFrame) CheckFrame() (err protocol.Error) {
+	return checkVariableLengthFrame(f, serviceFrameFixedLength)
+}
+
 // callService use to call a service without need to open any stream.
 // It can also use when service request data is smaller than network MTU.
 // Or use for time sensitive data like audio and video that streams shape in app layer
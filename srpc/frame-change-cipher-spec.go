@@ -2,9 +2,97 @@
 
 package srpc
 
-// ChangeCipherSpec use to change cipher use in encryption||decryption proccess by connection!
-// func ChangeCipherSpec() {}
+import (
+	"../crypto"
+	"../protocol"
+	"../syllab"
+)
 
-// type changeCipherSpecReq struct {
-// 	cipherSuite uint16 // Ciphersuite negotiated for the session
-// }
+/*
+type changeCipherSpecFrame struct {
+	CipherSuiteID uint64 // Ciphersuite negotiated for the session
+	Acknowledge   bool   // Peer changed its cipher to the cipher suite
+}
+*/
+type changeCipherSpecFrame []byte
+
+func (f changeCipherSpecFrame) CipherSuiteID() uint64 { return syllab.GetUInt64(f, 0) }
+func (f changeCipherSpecFrame) Acknowledge() bool     { return syllab.GetBool(f, 8) }
+func (f changeCipherSpecFrame) NextFrame() []byte     { return f[changeCipherSpecFrameFixedLength:] }
+func (f changeCipherSpecFrame) CheckFrame() (err protocol.Error) {
+	return checkFixedLengthFrame(f, changeCipherSpecFrameFixedLength)
+}
+
+const (
+	changeCipherSpecFrameFixedLength = 8 + 1                                // CipherSuiteID + Acknowledge
+	changeCipherSpecFrameLength      = 1 + changeCipherSpecFrameFixedLength // Type + CipherSuiteID + Acknowledge
+)
+
+// ChangeCipherSpec use to change cipher use in encryption||decryption process by connection in-band,
+// so long-lived connections can rotate keys without reconnecting.
+// The frame itself encrypt by the old cipher and any packet after it encrypt by the new cipher.
+// Peer install the new cipher and then acknowledge it by a frame that encrypt by the new cipher.
+func ChangeCipherSpec(conn protocol.Connection, suite protocol.CipherSuite) (err protocol.Error) {
+	if suite.Insecure() {
+		return &ErrCipherSuiteInsecure
+	}
+
+	var newCipher protocol.Cipher
+	newCipher, err = conn.Cipher().Rekey(suite)
+	if err != nil {
+		return
+	}
+
+	err = sendChangeCipherSpec(conn, suite.ID(), false)
+	if err != nil {
+		return
+	}
+	conn.SetCipher(newCipher)
+	return
+}
+
+// changeCipherSpec rekey the connection by the cipher suite that peer request and acknowledge it,
+// or check the peer acknowledge of our request.
+// Remaining frames in the same packet had been decrypted by old cipher, so new cipher just use for next packets.
+func changeCipherSpec(conn protocol.Connection, frame changeCipherSpecFrame) (err protocol.Error) {
+	var suiteID = frame.CipherSuiteID()
+	if frame.Acknowledge() {
+		// We changed our cipher when sent the request, so just check peer changed to the same suite.
+		if conn.Cipher().CipherSuite().ID() != suiteID {
+			err = &ErrCipherSuiteNotSupported
+		}
+		return
+	}
+
+	var suite = crypto.Suites.GetCipherSuiteByID(suiteID)
+	if suite == nil {
+		return &ErrCipherSuiteNotSupported
+	}
+	if suite.Insecure() {
+		return &ErrCipherSuiteInsecure
+	}
+
+	var newCipher protocol.Cipher
+	newCipher, err = conn.Cipher().Rekey(suite)
+	if err != nil {
+		return
+	}
+	// Install new cipher before acknowledge, so the acknowledge and any packet after it encrypt by the new cipher.
+	conn.SetCipher(newCipher)
+	err = sendChangeCipherSpec(conn, suiteID, true)
+	return
+}
+
+func sendChangeCipherSpec(conn protocol.Connection, suiteID uint64, acknowledge bool) (err protocol.Error) {
+	var packet, payload []byte
+	packet, payload, err = conn.NewPacket(changeCipherSpecFrameLength)
+	if err != nil {
+		return
+	}
+	payload[0] = frameTypeChangeCipherSpec
+	syllab.SetUInt64(payload, 1, suiteID)
+	syllab.SetBool(payload, 9, acknowledge)
+
+	err = sendPacket(conn, packet)
+	return
+}
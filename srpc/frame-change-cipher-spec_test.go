@@ -0,0 +1,97 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"testing"
+
+	"../crypto"
+	"../protocol"
+)
+
+type sentPacket struct {
+	packet []byte
+	cipher protocol.Cipher // cipher of the connection when packet sent
+}
+
+// cipherTestConnection record sent packets with the cipher that encrypt them.
+type cipherTestConnection struct {
+	testConnection
+	cipher protocol.Cipher
+	sent   []sentPacket
+}
+
+func (conn *cipherTestConnection) Cipher() protocol.Cipher          { return conn.cipher }
+func (conn *cipherTestConnection) SetCipher(cipher protocol.Cipher) { conn.cipher = cipher }
+func (conn *cipherTestConnection) Send(packet []byte) (err protocol.Error) {
+	conn.sent = append(conn.sent, sentPacket{packet: packet, cipher: conn.cipher})
+	return
+}
+
+func newCipherTestConnection(secret [32]byte) *cipherTestConnection {
+	var session crypto.Session
+	session.Init(&crypto.CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_GCM_SHA256, secret)
+	return &cipherTestConnection{
+		testConnection: *newTestConnection(),
+		cipher:         &session,
+	}
+}
+
+func TestChangeCipherSpec(t *testing.T) {
+	var secret = [32]byte{1, 2, 3}
+	var client = newCipherTestConnection(secret)
+	var server = newCipherTestConnection(secret)
+	var oldClientCipher = client.cipher
+	var suite = &crypto.CipherSuite_GP_ECDHE_ED25519_WITH_AES_256_CCM_SHA256
+
+	var err = ChangeCipherSpec(client, suite)
+	if err != nil {
+		t.Fatalf("ChangeCipherSpec() error = %v", err)
+	}
+	if len(client.sent) != 1 || client.sent[0].cipher != oldClientCipher {
+		t.Fatalf("ChangeCipherSpec() request must send once by the old cipher, sent = %v", len(client.sent))
+	}
+	if client.cipher.CipherSuite() != protocol.CipherSuite(suite) {
+		t.Errorf("ChangeCipherSpec() client suite = %v, want %v", client.cipher.CipherSuite(), suite)
+	}
+
+	err = HandleFrames(server, client.sent[0].packet)
+	if err != nil {
+		t.Fatalf("HandleFrames() request error = %v", err)
+	}
+	if server.cipher.CipherSuite() != protocol.CipherSuite(suite) {
+		t.Errorf("HandleFrames() request server suite = %v, want %v", server.cipher.CipherSuite(), suite)
+	}
+	if len(server.sent) != 1 || server.sent[0].cipher != server.cipher {
+		t.Fatalf("HandleFrames() acknowledge must send once by the new cipher, sent = %v", len(server.sent))
+	}
+
+	err = HandleFrames(client, server.sent[0].packet)
+	if err != nil {
+		t.Fatalf("HandleFrames() acknowledge error = %v", err)
+	}
+	if len(client.sent) != 1 {
+		t.Errorf("HandleFrames() acknowledge must not answer, sent = %v", len(client.sent))
+	}
+
+	var data = []byte("stream data")
+	err = server.cipher.Verify(data, client.cipher.Sign(data))
+	if err != nil {
+		t.Errorf("Peers ciphers not match after rekey, error = %v", err)
+	}
+	if oldClientCipher.Verify(data, client.cipher.Sign(data)) == nil {
+		t.Errorf("Cipher not changed after rekey")
+	}
+}
+
+func TestChangeCipherSpecNotSupported(t *testing.T) {
+	var server = newCipherTestConnection([32]byte{1})
+	var frames = []byte{frameTypeChangeCipherSpec, 1, 2, 3, 4, 5, 6, 7, 8, 0}
+	var err = HandleFrames(server, frames)
+	if err != &ErrCipherSuiteNotSupported {
+		t.Errorf("HandleFrames() error = %v, want %v", err, &ErrCipherSuiteNotSupported)
+	}
+	if len(server.sent) != 0 {
+		t.Errorf("HandleFrames() sent = %v, want 0", len(server.sent))
+	}
+}
@@ -2,40 +2,54 @@
 
 package srpc
 
-// import (
-// 	"../authorization"
-// 	"../protocol"
-// )
-
-// var closeStreamService = service.Service{
-// 	URN:                "urn:giti:achaemenid.protocol:service:close-stream",
-// 	Domain:             DomainName,
-// 	ID:                 6917897595815184909,
-// 	IssueDate:          1595478242,
-// 	ExpiryDate:         0,
-// 	ExpireInFavorOfURN: "",
-// 	ExpireInFavorOfID:  0,
-// 	Status:             protocol.SoftwareStatePreAlpha,
-
-// 	Authorization: authorization.Service{
-// 		CRUD:     authorization.CRUDCreate,
-// 		UserType: authorization.UserTypeAll,
-// 	},
-
-// 	Detail: map[protocol.LanguageID]service.ServiceDetail{
-// 		protocol.LanguageEnglish: {
-// 			Name:        "Close Stream",
-// 			Description: `use by peer to close unwanted active StreamID on other party due to MaxConcurrentStreams restriction.`,
-// 			TAGS:        []string{},
-// 		},
-// 	},
-
-// 	SRPCHandler: CloseStream,
-// }
-
-// // CloseStream use by peer to close unwanted active StreamID on other party due to MaxConcurrentStreams restriction.
-// func CloseStream(st protocol.Stream) {
-// }
-
-// type closeStreamReq struct {
-// }
+import (
+	"../protocol"
+	"../syllab"
+)
+
+/*
+type closeStreamFrame struct {
+	StreamID [4]byte // uint32
+}
+*/
+type closeStreamFrame []byte
+
+func (f closeStreamFrame) StreamID() uint32  { return syllab.GetUInt32(f, 0) }
+func (f closeStreamFrame) NextFrame() []byte { return f[closeStreamFrameFixedLength:] }
+func (f closeStreamFrame) CheckFrame() (err protocol.Error) {
+	return checkFixedLengthFrame(f, closeStreamFrameFixedLength)
+}
+
+const (
+	closeStreamFrameFixedLength = 4                               // StreamID
+	closeStreamFrameLength      = 1 + closeStreamFrameFixedLength // Type + StreamID
+)
+
+// sendCloseStream notify the peer to close the stream e.g. to cancel an outcome request
+// or close unwanted active stream on other party due to MaxConcurrentStreams restriction.
+func sendCloseStream(conn protocol.Connection, streamID uint32) (err protocol.Error) {
+	var packet, payload []byte
+	packet, payload, err = conn.NewPacket(closeStreamFrameLength)
+	if err != nil {
+		return
+	}
+
+	payload[0] = frameTypeCloseStream
+	syllab.SetUInt32(payload, 1, streamID)
+	err = sendPacket(conn, packet)
+	return
+}
+
+// closeStream use by peer to close unwanted active stream e.g. peer cancel its request.
+func closeStream(conn protocol.Connection, frame closeStreamFrame) (err protocol.Error) {
+	var stream protocol.Stream
+	stream, err = conn.Stream(uint64(frame.StreamID()))
+	if err != nil {
+		// Stream closed before or never opened, so nothing to do.
+		err = nil
+		return
+	}
+	stream.SetStatus(protocol.NetworkStatus_Closed)
+	err = stream.Close()
+	return
+}
@@ -23,6 +23,12 @@ func (f dataFrame) Offset() uint32    { return syllab.GetUInt32(f, 6) }
 func (f dataFrame) Payload() []byte   { return f[10:f.Length()] }
 func (f dataFrame) NextFrame() []byte { return f[f.Length():] }
 
+const dataFrameFixedLength = 2 + 4 + 4 // Length + StreamID + Offset
+
+func (f dataFrame) CheckFrame() (err protocol.Error) {
+	return checkVariableLengthFrame(f, dataFrameFixedLength)
+}
+
 // appendData add data to the requested offset of the stream
 func appendData(conn protocol.Connection, frame dataFrame) (err protocol.Error) {
 	var streamID uint32 = frame.StreamID()
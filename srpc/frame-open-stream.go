@@ -22,8 +22,15 @@ type openStreamFrame []byte
 func (f openStreamFrame) ProtocolID() uint16                { return syllab.GetUInt16(f, 0) }
 func (f openStreamFrame) SerErrID() uint64                  { return syllab.GetUInt64(f, 2) }
 func (f openStreamFrame) CompressID() uint64                { return syllab.GetUInt64(f, 10) }
-func (f openStreamFrame) Weight() protocol.ConnectionWeight { return protocol.ConnectionWeight(f[10]) }
-func (f openStreamFrame) NextFrame() []byte                 { return f[18:] }
+func (f openStreamFrame) TotalPacket() uint32               { return syllab.GetUInt32(f, 18) }
+func (f openStreamFrame) DataLength() uint64                { return syllab.GetUInt64(f, 22) }
+func (f openStreamFrame) Weight() protocol.ConnectionWeight { return protocol.ConnectionWeight(f[30]) }
+func (f openStreamFrame) NextFrame() []byte                 { return f[openStreamFrameFixedLength:] }
+func (f openStreamFrame) CheckFrame() (err protocol.Error) {
+	return checkFixedLengthFrame(f, openStreamFrameFixedLength)
+}
+
+const openStreamFrameFixedLength = 2 + 8 + 8 + 4 + 8 + 1 // ProtocolID + SerErrID + CompressID + TotalPacket + DataLength + Weight
 
 // setStreamSettings set stream settings like time sensitive use in VoIP, IPTV, ...
 func openStream(conn protocol.Connection, frame openStreamFrame) (err protocol.Error) {
@@ -2,7 +2,10 @@
 
 package srpc
 
-import "../syllab"
+import (
+	"../protocol"
+	"../syllab"
+)
 
 /*
 type paddingFrame struct {
@@ -15,3 +18,9 @@ type paddingFrame []byte
 func (f paddingFrame) Length() uint16    { return syllab.GetUInt16(f, 0) }
 func (f paddingFrame) Payload() []byte   { return f[2:f.Length()] }
 func (f paddingFrame) NextFrame() []byte { return f[f.Length():] }
+
+const paddingFrameFixedLength = 2 // Length
+
+func (f paddingFrame) CheckFrame() (err protocol.Error) {
+	return checkVariableLengthFrame(f, paddingFrameFixedLength)
+}
@@ -2,14 +2,80 @@
 
 package srpc
 
-import "../syllab"
+import (
+	"../protocol"
+	"../syllab"
+	"../time/monotonic"
+)
 
 /*
 type pingFrame struct {
-	ID int64 // Usually unix time
+	ID int64 // Usually monotonic nano time of the sender
 }
 */
 type pingFrame []byte
 
 func (f pingFrame) ID() int64         { return syllab.GetInt64(f, 0) }
-func (f pingFrame) NextFrame() []byte { return f[8:] }
+func (f pingFrame) NextFrame() []byte { return f[pingFrameFixedLength:] }
+func (f pingFrame) CheckFrame() (err protocol.Error) {
+	return checkFixedLengthFrame(f, pingFrameFixedLength)
+}
+
+/*
+type pongFrame struct {
+	ID int64 // ID of the ping frame that this pong is response of it
+}
+*/
+type pongFrame []byte
+
+func (f pongFrame) ID() int64         { return syllab.GetInt64(f, 0) }
+func (f pongFrame) NextFrame() []byte { return f[pongFrameFixedLength:] }
+func (f pongFrame) CheckFrame() (err protocol.Error) {
+	return checkFixedLengthFrame(f, pongFrameFixedLength)
+}
+
+const (
+	pingFrameFixedLength = 8                        // ID
+	pongFrameFixedLength = 8                        // ID
+	pingFrameLength      = 1 + pingFrameFixedLength // Type + ID
+	pongFrameLength      = 1 + pongFrameFixedLength // Type + ID
+)
+
+// SendPing send a ping frame to the peer of the connection with monotonic nano time as ID,
+// so RTT can measure when peer response with a pong frame.
+func SendPing(conn protocol.Connection) (err protocol.Error) {
+	var packet, payload []byte
+	packet, payload, err = conn.NewPacket(pingFrameLength)
+	if err != nil {
+		return
+	}
+
+	var id = int64(monotonic.Now())
+	payload[0] = frameTypePing
+	syllab.SetInt64(payload, 1, id)
+
+	// Store ID before send, so a fast pong can't arrive before we wait for it.
+	// A ping that fails to send will count as missed pong on next ping.
+	conn.PingSent(id)
+	err = sendPacket(conn, packet)
+	return
+}
+
+// ping response to the peer ping frame with a pong frame that carry the same ID.
+func ping(conn protocol.Connection, frame pingFrame) (err protocol.Error) {
+	var packet, payload []byte
+	packet, payload, err = conn.NewPacket(pongFrameLength)
+	if err != nil {
+		return
+	}
+
+	payload[0] = frameTypePong
+	syllab.SetInt64(payload, 1, frame.ID())
+	err = sendPacket(conn, packet)
+	return
+}
+
+// pong update connection RTT metrics by the pong frame that peer send as response of our ping.
+func pong(conn protocol.Connection, frame pongFrame) {
+	conn.PongReceived(frame.ID())
+}
@@ -16,9 +16,64 @@ type signatureFrame struct {
 */
 type signatureFrame []byte
 
-func (f signatureFrame) ID() int64         { return syllab.GetInt64(f, 0) }
-func (f signatureFrame) NextFrame() []byte { return f[8:] }
+func (f signatureFrame) Length() uint16    { return syllab.GetUInt16(f, 0) }
+func (f signatureFrame) StreamID() uint32  { return syllab.GetUInt32(f, 2) }
+func (f signatureFrame) Signature() []byte { return f[6:f.Length()] }
+func (f signatureFrame) NextFrame() []byte { return f[f.Length():] }
 
+const signatureFrameFixedLength = 2 + 4 // Length + StreamID
+
+func (f signatureFrame) CheckFrame() (err protocol.Error) {
+	return checkVariableLengthFrame(f, signatureFrameFixedLength)
+}
+
+// SignStream send a detached signature frame that cover all data frames of the stream.
+// It must call after last data frame of the stream sent, so peer can verify the stream when it is complete.
+func SignStream(conn protocol.Connection, streamID uint32, data []byte) (err protocol.Error) {
+	var signature = conn.Cipher().Sign(data)
+	var frameLength = signatureFrameFixedLength + len(signature)
+
+	var packet, payload []byte
+	packet, payload, err = conn.NewPacket(1 + frameLength)
+	if err != nil {
+		return
+	}
+
+	payload[0] = frameTypeSignature
+	syllab.SetUInt16(payload, 1, uint16(frameLength))
+	syllab.SetUInt32(payload, 3, streamID)
+	copy(payload[1+signatureFrameFixedLength:], signature)
+
+	err = sendPacket(conn, packet)
+	return
+}
+
+// registerStreamSignature verify the detached signature of the stream against all data that received on the stream.
+// The stream will close if signature is not valid.
 func registerStreamSignature(conn protocol.Connection, frame signatureFrame) (err protocol.Error) {
+	var streamID uint32 = frame.StreamID()
+	var stream protocol.Stream
+	stream, err = conn.Stream(uint64(streamID))
+	if err != nil {
+		conn.StreamFailed()
+		// Send response or just ignore stream
+		// TODO::: DDOS!!??
+		return
+	}
+
+	var data []byte
+	data, err = stream.Marshal()
+	if err != nil {
+		return
+	}
+
+	err = conn.Cipher().Verify(data, frame.Signature())
+	if err != nil {
+		conn.StreamFailed()
+		stream.SetStatus(protocol.NetworkStatus_BrokenPacket)
+		stream.SetError(&ErrStreamSignature)
+		stream.Close()
+		err = &ErrStreamSignature
+	}
 	return
 }
@@ -2,6 +2,11 @@
 
 package srpc
 
+import (
+	"../protocol"
+	"../syllab"
+)
+
 /*
 type frame struct {
 	Type     byte
@@ -23,4 +28,61 @@ const (
 	frameTypeCloseStream
 	frameTypeData
 	frameTypeSignature
+	frameTypePong
+	frameTypeChangeCipherSpec
+
+	// Frame types equal or greater than frameTypeExtension are extension frames that must start with
+	// 2 byte length field (including the header fields) like paddingFrame, so any peer that don't know them can skip them.
+	// Any unknown frame type less than frameTypeExtension is a protocol violation.
+	frameTypeExtension byte = 128
 )
+
+/*
+type extensionFrame struct {
+	Length  [2]byte // including the header fields
+	Payload []byte
+}
+*/
+type extensionFrame []byte
+
+func (f extensionFrame) Length() uint16    { return syllab.GetUInt16(f, 0) }
+func (f extensionFrame) Payload() []byte   { return f[2:f.Length()] }
+func (f extensionFrame) NextFrame() []byte { return f[f.Length():] }
+
+const extensionFrameFixedLength = 2 // Length
+
+func (f extensionFrame) CheckFrame() (err protocol.Error) {
+	return checkVariableLengthFrame(f, extensionFrameFixedLength)
+}
+
+// checkFixedLengthFrame check given frame has enough length for the frame type that has fixed length.
+func checkFixedLengthFrame(f []byte, fixedLength int) (err protocol.Error) {
+	if len(f) < fixedLength {
+		err = &ErrFrameTooShort
+	}
+	return
+}
+
+// checkVariableLengthFrame check given frame that start with 2 byte length field (including the header fields)
+// has a length field that cover its fixed fields and is not longer than remaining frames.
+func checkVariableLengthFrame(f []byte, fixedLength int) (err protocol.Error) {
+	if len(f) < fixedLength {
+		return &ErrFrameTooShort
+	}
+	var frameLength = int(syllab.GetUInt16(f, 0))
+	if frameLength < fixedLength || frameLength > len(f) {
+		return &ErrFrameLength
+	}
+	return
+}
+
+// sendPacket send the packet to the peer if the connection daily send quota let it.
+// All frames must send by it, so no packet can bypass the quota.
+func sendPacket(conn protocol.Connection, packet []byte) (err protocol.Error) {
+	err = conn.PacketSending(uint64(len(packet)))
+	if err != nil {
+		return
+	}
+	err = conn.Send(packet)
+	return
+}
@@ -6,20 +6,56 @@ import (
 	"../protocol"
 )
 
+// HandleFrames handle all frames in the given decrypted packet payload.
+// Any malformed frame or unknown frame type that can't skip is a protocol violation that
+// count as failed received packet and close the connection.
+// Packets over the connection daily receive quota drop without handle any of their frames.
 func HandleFrames(conn protocol.Connection, frames []byte) (err protocol.Error) {
+	err = conn.PacketReceiving(uint64(len(frames)))
+	if err != nil {
+		return
+	}
+
 	for len(frames) > 0 {
 		var frame = frame(frames)
-		switch frame.Type() {
+		switch frameType := frame.Type(); frameType {
 		case frameTypePadding:
 			var paddingFrame = paddingFrame(frame.Payload())
+			err = paddingFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
 			// Nothing to do, just ignore padding data
 			frames = paddingFrame.NextFrame()
 		case frameTypePing:
 			var pingFrame = pingFrame(frame.Payload())
-			// TODO:::
+			err = pingFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
+			err = ping(conn, pingFrame)
+			if err != nil {
+				return
+			}
 			frames = pingFrame.NextFrame()
+		case frameTypePong:
+			var pongFrame = pongFrame(frame.Payload())
+			err = pongFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
+			pong(conn, pongFrame)
+			frames = pongFrame.NextFrame()
 		case frameTypeCallService:
 			var serviceFrame = serviceFrame(frame.Payload())
+			err = serviceFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
 			err = callService(conn, serviceFrame)
 			if err != nil {
 				return
@@ -27,13 +63,35 @@ func HandleFrames(conn protocol.Connection, frames []byte) (err protocol.Error)
 			frames = serviceFrame.NextFrame()
 		case frameTypeOpenStream:
 			var openStreamFrame = openStreamFrame(frame.Payload())
+			err = openStreamFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
 			err = openStream(conn, openStreamFrame)
 			if err != nil {
 				return
 			}
 			frames = openStreamFrame.NextFrame()
+		case frameTypeCloseStream:
+			var closeStreamFrame = closeStreamFrame(frame.Payload())
+			err = closeStreamFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
+			err = closeStream(conn, closeStreamFrame)
+			if err != nil {
+				return
+			}
+			frames = closeStreamFrame.NextFrame()
 		case frameTypeData:
 			var dataFrame = dataFrame(frame.Payload())
+			err = dataFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
 			err = appendData(conn, dataFrame)
 			if err != nil {
 				return
@@ -41,14 +99,52 @@ func HandleFrames(conn protocol.Connection, frames []byte) (err protocol.Error)
 			frames = dataFrame.NextFrame()
 		case frameTypeSignature:
 			var signatureFrame = signatureFrame(frame.Payload())
+			err = signatureFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
 			err = registerStreamSignature(conn, signatureFrame)
 			if err != nil {
 				return
 			}
 			frames = signatureFrame.NextFrame()
+		case frameTypeChangeCipherSpec:
+			var changeCipherSpecFrame = changeCipherSpecFrame(frame.Payload())
+			err = changeCipherSpecFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
+			err = changeCipherSpec(conn, changeCipherSpecFrame)
+			if err != nil {
+				return
+			}
+			frames = changeCipherSpecFrame.NextFrame()
 		default:
-			// TODO:::
+			if frameType < frameTypeExtension {
+				err = &ErrFrameUnknownType
+				protocolViolation(conn)
+				return
+			}
+			var extensionFrame = extensionFrame(frame.Payload())
+			err = extensionFrame.CheckFrame()
+			if err != nil {
+				protocolViolation(conn)
+				return
+			}
+			// Skip extension frames that not supported yet.
+			frames = extensionFrame.NextFrame()
 		}
 	}
 	return
 }
+
+// protocolViolation count the packet as failed received packet and close the connection.
+// Peer that send malformed frames is buggy or an attacker, so don't waste any more resources on it.
+func protocolViolation(conn protocol.Connection) {
+	conn.PacketReceiveFailed()
+	conn.SetStatus(protocol.NetworkStatus_BrokenPacket)
+	// Close can be a blocking operation, so call it in new goroutine.
+	go conn.Close()
+}
@@ -0,0 +1,150 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"testing"
+
+	"../connection"
+	"../protocol"
+)
+
+// testConnection implements the protocol.Connection methods that HandleFrames use.
+// Any other method call will panic due to nil embedded interface.
+type testConnection struct {
+	protocol.Connection
+	limiter       connection.Limiter
+	failedPackets uint64
+	sentPackets   uint64
+	status        protocol.NetworkStatus
+	closed        chan struct{}
+}
+
+func newTestConnection() *testConnection { return &testConnection{closed: make(chan struct{}, 1)} }
+
+func (conn *testConnection) Stream(id uint64) (stream protocol.Stream, err protocol.Error) {
+	return nil, &ErrFrameUnknownType
+}
+func (conn *testConnection) NewPacket(payloadLen int) (packet []byte, payload []byte, err protocol.Error) {
+	packet = make([]byte, payloadLen)
+	return packet, packet, nil
+}
+func (conn *testConnection) Send(packet []byte) (err protocol.Error) { conn.sentPackets++; return }
+func (conn *testConnection) Status() protocol.NetworkStatus          { return conn.status }
+func (conn *testConnection) SetStatus(ns protocol.NetworkStatus)     { conn.status = ns }
+func (conn *testConnection) StreamFailed()                           {}
+func (conn *testConnection) PacketSending(packetLength uint64) (err protocol.Error) {
+	return conn.limiter.PacketSending(packetLength)
+}
+func (conn *testConnection) PacketReceiving(packetLength uint64) (err protocol.Error) {
+	return conn.limiter.PacketReceiving(packetLength)
+}
+func (conn *testConnection) PacketReceiveFailed() { conn.failedPackets++ }
+func (conn *testConnection) PongReceived(id int64) (rtt protocol.Duration) {
+	return
+}
+func (conn *testConnection) Close() (err protocol.Error) {
+	conn.closed <- struct{}{}
+	return
+}
+
+type testApplication struct {
+	protocol.Application
+}
+
+func (app testApplication) GetNetworkApplicationHandler(protocolID protocol.NetworkApplication_ProtocolID) protocol.NetworkApplication_Handler {
+	return nil
+}
+
+type framesTest struct {
+	name      string
+	frames    []byte
+	err       protocol.Error
+	violation bool
+}
+
+var framesTests = []framesTest{
+	{
+		name:   "padding",
+		frames: []byte{frameTypePadding, 4, 0, 0, 0},
+	}, {
+		name:   "ping&pong",
+		frames: []byte{frameTypePing, 1, 2, 3, 4, 5, 6, 7, 8, frameTypePong, 1, 2, 3, 4, 5, 6, 7, 8},
+	}, {
+		name:      "short-ping",
+		frames:    []byte{frameTypePing, 1, 2, 3},
+		err:       &ErrFrameTooShort,
+		violation: true,
+	}, {
+		name:      "padding-length-overflow",
+		frames:    []byte{frameTypePadding, 255, 0, 0},
+		err:       &ErrFrameLength,
+		violation: true,
+	}, {
+		name:      "padding-zero-length",
+		frames:    []byte{frameTypePadding, 0, 0},
+		err:       &ErrFrameLength,
+		violation: true,
+	}, {
+		name:      "unknown-type",
+		frames:    []byte{frameTypeExtension - 1, 2, 0},
+		err:       &ErrFrameUnknownType,
+		violation: true,
+	}, {
+		name:   "skip-extension",
+		frames: []byte{frameTypeExtension, 4, 0, 9, 9, frameTypePadding, 2, 0},
+	},
+}
+
+func TestHandleFrames(t *testing.T) {
+	protocol.App = testApplication{}
+	for _, tt := range framesTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conn = newTestConnection()
+			var err = HandleFrames(conn, tt.frames)
+			if err != tt.err {
+				t.Errorf("HandleFrames() error = %v, want %v", err, tt.err)
+			}
+			if tt.violation && conn.failedPackets != 1 {
+				t.Errorf("HandleFrames() failedPackets = %v, want 1", conn.failedPackets)
+			}
+			if !tt.violation && conn.failedPackets != 0 {
+				t.Errorf("HandleFrames() failedPackets = %v, want 0", conn.failedPackets)
+			}
+		})
+	}
+}
+
+func TestHandleFramesQuota(t *testing.T) {
+	var conn = newTestConnection()
+	conn.limiter.Init(&connection.Quota{MaxPacketsReceiveDaily: 2, MaxPacketsSendDaily: 1})
+	var ping = []byte{frameTypePing, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	if err := HandleFrames(conn, ping); err != nil {
+		t.Errorf("HandleFrames() first packet error = %v", err)
+	}
+	if err := HandleFrames(conn, ping); err != connection.ErrDailySendQuota {
+		t.Errorf("HandleFrames() pong over send quota error = %v, want %v", err, connection.ErrDailySendQuota)
+	}
+	if err := HandleFrames(conn, ping); err != connection.ErrDailyReceiveQuota {
+		t.Errorf("HandleFrames() over receive quota error = %v, want %v", err, connection.ErrDailyReceiveQuota)
+	}
+	if conn.sentPackets != 1 {
+		t.Errorf("HandleFrames() sentPackets = %v, want 1", conn.sentPackets)
+	}
+}
+
+func FuzzHandleFrames(f *testing.F) {
+	protocol.App = testApplication{}
+	for _, tt := range framesTests {
+		f.Add(tt.frames)
+	}
+	f.Fuzz(func(t *testing.T, frames []byte) {
+		var conn = newTestConnection()
+		// Must return and never panic or spin forever on any input.
+		var err = HandleFrames(conn, frames)
+		if err == nil && conn.failedPackets != 0 {
+			t.Errorf("HandleFrames() count failed packet without any error")
+		}
+	})
+}
@@ -3,6 +3,7 @@
 package srpc
 
 import (
+	"../authorization"
 	"../protocol"
 )
 
@@ -18,6 +19,14 @@ func (srpc *SRPCHandler) HandleIncomeRequest(stream protocol.Stream) (err protoc
 		return
 	}
 
+	err = authorization.AuthorizeServiceCall(stream, service)
+	if err != nil {
+		stream.SetError(err)
+		stream.SendResponse()
+		stream.Close()
+		return
+	}
+
 	// call request service
 	err = service.ServeSRPC(stream)
 	if err != nil {
@@ -30,7 +39,7 @@ func (srpc *SRPCHandler) HandleIncomeRequest(stream protocol.Stream) (err protoc
 }
 
 // HandleOutcomeRequest use to handle outcoming sRPC request.
-// It block caller until get response or error.
+// It block caller until get response or error without any deadline or retry, use Client or Do() instead.
 func HandleOutcomeRequest(conn protocol.Connection, service protocol.Service, payload protocol.Codec) (stream protocol.Stream, err protocol.Error) {
 	stream, err = conn.OutcomeStream(service)
 	if err != nil {
@@ -0,0 +1,71 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"../protocol"
+	"../timer"
+)
+
+const (
+	// PingerMaxMissedPongs use if application not set any max missed pongs in Pinger.Init()
+	PingerMaxMissedPongs = 3
+)
+
+// Pinger send ping frames to the peer of a connection in each interval and detect connection liveness.
+// A ping counts as missed only if its pong not received until next tick, so peer has one full interval to response.
+// After maxMissedPongs sequential missed pongs, the connection status change to NetworkStatus_NotResponse
+// and if closeNotResponse is true, the connection will close.
+// It implements connection.Pinger, so app can start it for each registered connection by Connections.SetPinger().
+type Pinger struct {
+	conn             protocol.Connection
+	maxMissedPongs   uint32
+	closeNotResponse bool
+	timer            timer.Async
+}
+
+// Init initialize the pinger. maxMissedPongs 0 means use default PingerMaxMissedPongs.
+// maxMissedPongs, closeNotResponse usually get from achaemenid.NetworkInfo.
+func (p *Pinger) Init(conn protocol.Connection, maxMissedPongs uint8, closeNotResponse bool) {
+	if maxMissedPongs == 0 {
+		maxMissedPongs = PingerMaxMissedPongs
+	}
+	p.conn = conn
+	p.maxMissedPongs = uint32(maxMissedPongs)
+	p.closeNotResponse = closeNotResponse
+	p.timer.Init(p)
+}
+
+// Start begin to ping the peer in each interval that calculate by given idle timeout,
+// so peer will ping (maxMissedPongs+1) times in each idleTimeout.
+func (p *Pinger) Start(idleTimeout protocol.Duration) (err protocol.Error) {
+	var interval = idleTimeout / protocol.Duration(p.maxMissedPongs+1)
+	err = p.timer.Tick(interval, interval)
+	return
+}
+
+// Stop stop pinging the peer. Client must call Stop() when connection closed, otherwise **"leaks"** occur.
+func (p *Pinger) Stop() (alreadyStopped bool) { return p.timer.Stop() }
+
+//libgo:impl protocol.TimerListener
+func (p *Pinger) TimerHandler() {
+	var conn = p.conn
+	switch conn.Status() {
+	case protocol.NetworkStatus_Closing, protocol.NetworkStatus_Closed:
+		p.timer.Stop()
+		return
+	}
+
+	// Send is non-blocking and just queue the packet, so it is safe to call it here.
+	// Sending new ping count the last ping as missed if peer not response to it in this interval.
+	SendPing(conn)
+
+	if conn.MissedPongs() >= p.maxMissedPongs {
+		conn.SetStatus(protocol.NetworkStatus_NotResponse)
+		if p.closeNotResponse {
+			p.timer.Stop()
+			// Close can be a blocking operation, so call it in new goroutine.
+			go conn.Close()
+		}
+	}
+}
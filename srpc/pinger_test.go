@@ -0,0 +1,110 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package srpc
+
+import (
+	"testing"
+	"time"
+
+	"../connection"
+	"../protocol"
+)
+
+// pingerTestConnection is a connection that its peer response to each ping immediately if responsive is true.
+type pingerTestConnection struct {
+	testConnection
+	metric     connection.Metric
+	responsive bool
+}
+
+func (conn *pingerTestConnection) Send(packet []byte) (err protocol.Error) {
+	if conn.responsive && packet[0] == frameTypePing {
+		pong(conn, pongFrame(packet[1:]))
+	}
+	return
+}
+func (conn *pingerTestConnection) PingSent(id int64) { conn.metric.PingSent(id) }
+func (conn *pingerTestConnection) PongReceived(id int64) (rtt protocol.Duration) {
+	return conn.metric.PongReceived(id)
+}
+func (conn *pingerTestConnection) MissedPongs() uint32 { return conn.metric.MissedPongs() }
+
+type pingerTest struct {
+	name             string
+	maxMissedPongs   uint8
+	closeNotResponse bool
+	responsive       bool
+	ticks            int
+	status           protocol.NetworkStatus
+	closed           bool
+}
+
+var pingerTests = []pingerTest{
+	{
+		name:       "responsive",
+		responsive: true,
+		ticks:      100,
+		status:     protocol.NetworkStatus_Open,
+	}, {
+		name:           "responsive-max-one",
+		maxMissedPongs: 1,
+		responsive:     true,
+		ticks:          100,
+		status:         protocol.NetworkStatus_Open,
+	}, {
+		name:           "not-response-before-max",
+		maxMissedPongs: 2,
+		ticks:          2,
+		status:         protocol.NetworkStatus_Open,
+	}, {
+		name:           "not-response",
+		maxMissedPongs: 2,
+		ticks:          3,
+		status:         protocol.NetworkStatus_NotResponse,
+	}, {
+		name:   "not-response-default-max",
+		ticks:  PingerMaxMissedPongs + 1,
+		status: protocol.NetworkStatus_NotResponse,
+	}, {
+		name:             "close-not-response",
+		maxMissedPongs:   1,
+		closeNotResponse: true,
+		ticks:            2,
+		status:           protocol.NetworkStatus_NotResponse,
+		closed:           true,
+	},
+}
+
+func TestPinger(t *testing.T) {
+	for _, tt := range pingerTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conn = pingerTestConnection{
+				testConnection: *newTestConnection(),
+				responsive:     tt.responsive,
+			}
+			conn.status = protocol.NetworkStatus_Open
+
+			var p Pinger
+			p.Init(&conn, tt.maxMissedPongs, tt.closeNotResponse)
+			for i := 0; i < tt.ticks; i++ {
+				p.TimerHandler()
+			}
+
+			if conn.status != tt.status {
+				t.Errorf("Pinger.TimerHandler() status = %v, want %v", conn.status, tt.status)
+			}
+			var closed bool
+			if tt.closed {
+				// Pinger close the connection in new goroutine.
+				select {
+				case <-conn.closed:
+					closed = true
+				case <-time.After(time.Second):
+				}
+			}
+			if closed != tt.closed {
+				t.Errorf("Pinger.TimerHandler() closed = %v, want %v", closed, tt.closed)
+			}
+		})
+	}
+}
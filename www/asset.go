@@ -0,0 +1,149 @@
+/* For license and copyright information please see LEGAL file in repository */
+
+package www
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"../protocol"
+)
+
+// Asset is the metadata of a GUI file that compute at assets build time to serve the file over HTTP.
+type Asset struct {
+	// ETag is strong entity tag by hash of the file data.
+	ETag string
+	// Modified is last modification time of the file. It is zero if file system can't tell it in wall clock.
+	Modified time.Time
+	// Immutable is true if file name has hash of its data e.g. "main-en-2246891245.js",
+	// So data of the path never change and can cache forever.
+	Immutable bool
+	// Precompressed are compressed copies of the file that build next to it e.g. "main.js.br" for "main.js".
+	// They sort by their size, so smaller one can prefer when peer accept some of them with same quality.
+	Precompressed []Precompressed
+}
+
+// Precompressed is a compressed copy of a GUI file that file.Compress build at assets build time.
+type Precompressed struct {
+	CompressType protocol.CompressType
+	File         protocol.File
+}
+
+// PrecompressedContentEncodings return content encodings of the asset precompressed files in order of their preference.
+func (asset *Asset) PrecompressedContentEncodings() (contentEncodings []string) {
+	contentEncodings = make([]string, len(asset.Precompressed))
+	for i := range asset.Precompressed {
+		contentEncodings[i] = asset.Precompressed[i].CompressType.ContentEncoding()
+	}
+	return
+}
+
+// PrecompressedByContentEncoding return the asset precompressed copy of given content encoding or nil if not exist.
+func (asset *Asset) PrecompressedByContentEncoding(contentEncoding string) *Precompressed {
+	for i := range asset.Precompressed {
+		if asset.Precompressed[i].CompressType.ContentEncoding() == contentEncoding {
+			return &asset.Precompressed[i]
+		}
+	}
+	return nil
+}
+
+// newAsset make asset of the file. siblings are other files of the file directory to find its precompressed copies.
+func newAsset(file protocol.File, siblings []protocol.File) (asset Asset) {
+	var data, _ = file.Data().Marshal()
+	var hash = sha256.Sum256(data)
+	asset.ETag = `"` + base64.RawURLEncoding.EncodeToString(hash[:16]) + `"`
+	asset.Modified = wallClockTime(file.Metadata().Modified())
+	asset.Immutable = hasDataHashInName(file.Metadata().URI().NameWithoutExtension(), data)
+	asset.Precompressed = precompressedFiles(file.Metadata().URI().Name(), siblings)
+	return
+}
+
+// precompressedFiles return siblings that their name is given name plus extension of a registered compress type.
+func precompressedFiles(name string, siblings []protocol.File) (precompressed []Precompressed) {
+	var prefix = name + "."
+	for _, sibling := range siblings {
+		var siblingName = sibling.Metadata().URI().Name()
+		if !strings.HasPrefix(siblingName, prefix) {
+			continue
+		}
+		var compressType, _ = protocol.OS.GetCompressTypeByFileExtension(siblingName[len(prefix):])
+		if compressType == nil || compressType.FileExtension() == "" {
+			continue
+		}
+		precompressed = append(precompressed, Precompressed{
+			CompressType: compressType,
+			File:         sibling,
+		})
+	}
+	sort.SliceStable(precompressed, func(i, j int) bool {
+		return precompressed[i].File.Data().Len() < precompressed[j].File.Data().Len()
+	})
+	return
+}
+
+// hasDataHashInName report the name end with the hash that file.AddHashToFileName add to it.
+func hasDataHashInName(nameWithoutExtension string, data []byte) bool {
+	var dashIndex = strings.LastIndexByte(nameWithoutExtension, '-')
+	if dashIndex == -1 {
+		return false
+	}
+	var hashOfFileData = strconv.FormatUint(uint64(crc32.ChecksumIEEE(data)), 10)
+	return nameWithoutExtension[dashIndex+1:] == hashOfFileData
+}
+
+func wallClockTime(t protocol.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	switch t.Epoch() {
+	case protocol.TimeEpoch_Unix, protocol.TimeEpoch_UTC:
+		return time.Unix(t.SecondElapsed(), int64(t.NanoSecondElapsed()))
+	}
+	return time.Time{}
+}
+
+// Asset return build time metadata of given GUI file. It compute and cache them if file added after last update.
+func (a *Assets) Asset(file protocol.File) (asset Asset) {
+	var path = file.Metadata().URI().Path()
+	var ok bool
+	a.mutex.RLock()
+	asset, ok = a.assets[path]
+	a.mutex.RUnlock()
+	if ok {
+		return
+	}
+
+	asset = newAsset(file, file.ParentDirectory().Files(0, 0))
+	a.mutex.Lock()
+	if a.assets == nil {
+		a.assets = make(map[string]Asset)
+	}
+	a.assets[path] = asset
+	a.mutex.Unlock()
+	return
+}
+
+// indexAssets compute metadata of all GUI files. Call it after any change in GUI files.
+func (a *Assets) indexAssets() {
+	var assets = make(map[string]Asset)
+	indexAssetsDirectory(a.GUI, assets)
+	a.mutex.Lock()
+	a.assets = assets
+	a.mutex.Unlock()
+}
+
+func indexAssetsDirectory(dir protocol.FileDirectory, assets map[string]Asset) {
+	var files = dir.Files(0, 0)
+	for _, file := range files {
+		assets[file.Metadata().URI().Path()] = newAsset(file, files)
+	}
+	for _, subDir := range dir.Directories(0, 0) {
+		indexAssetsDirectory(subDir, assets)
+	}
+}
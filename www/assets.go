@@ -4,6 +4,7 @@ package www
 
 import (
 	"fmt"
+	"sync"
 
 	"../protocol"
 )
@@ -17,7 +18,10 @@ type Assets struct {
 	GUI         protocol.FileDirectory
 	MainHTMLDir protocol.FileDirectory // files name is just language in iso format e.g. "en", "fa",
 	// OldBrowsers protocol.FileDirectory // files name is just language in iso format e.g. "en", "fa",
-	ContentEncodings []string
+	ContentEncodings []string // nil means all registered content encodings
+
+	mutex  sync.RWMutex
+	assets map[string]Asset // key is file URI path
 }
 
 func (a *Assets) Init() {
@@ -46,9 +50,15 @@ reload:
 
 // Update use to add needed repo files that get from disk or network to the assets!!
 func (a *Assets) update() {
+	var contentEncodings = a.ContentEncodings
+	if contentEncodings == nil {
+		// Precompress assets in all registered formats e.g. br, zstd, gzip, ...
+		contentEncodings = protocol.OS.ContentEncodings()
+	}
 	var c = combine{
-		contentEncodings: a.ContentEncodings,
+		contentEncodings: contentEncodings,
 	}
 	c.update()
+	a.indexAssets()
 	protocol.App.Log(protocol.LogType_Information, "WWW - GUI assets successfully updated and ready to serve")
 }
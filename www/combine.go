@@ -119,7 +119,7 @@ func (c *combine) readyAppJSFiles() {
 		// Add platfrom errors!
 		localeMainJSFile.Data().Append(sdk)
 		file.AddHashToFileName(localeMainJSFile)
-		file.Compress(localeMainJSFile, c.contentEncodings, protocol.BestCompression)
+		file.Compress(localeMainJSFile, c.contentEncodings, protocol.CompressLevel_BestCompression)
 
 		// make main.html
 		var localeJSFileName = localeMainJSFile.Metadata().URI().Name()
@@ -131,7 +131,7 @@ func (c *combine) readyAppJSFiles() {
 		}
 		localeMainHTMLFile.Data().Replace([]byte("/main.js"), []byte(localeJSFileName), 1)
 		file.AddHashToFileName(localeMainHTMLFile)
-		file.Compress(localeMainHTMLFile, c.contentEncodings, protocol.BestCompression)
+		file.Compress(localeMainHTMLFile, c.contentEncodings, protocol.CompressLevel_BestCompression)
 		c.mainHTMLFiles[lang] = localeMainHTMLFile
 
 		// Add localized main html files without any compression to specific serve directory
@@ -180,7 +180,7 @@ func (c *combine) readyDesignLanguagesFiles() {
 				protocol.App.Log(protocol.LogType_Warning, "Minify -", cssName, "occur this error:", err)
 			}
 			file.AddHashToFileName(cssFile)
-			file.Compress(cssFile, c.contentEncodings, protocol.BestCompression)
+			file.Compress(cssFile, c.contentEncodings, protocol.CompressLevel_BestCompression)
 
 			for _, mainFile := range c.mainJSFiles {
 				var fileNameWithoutHashAdded = convert.UnsafeStringToByteSlice(cssName)
@@ -204,7 +204,7 @@ func (c *combine) readyDesignLanguagesFiles() {
 			protocol.App.Log(protocol.LogType_Warning, "Minify -", combinedFileName, "occur this error:", err)
 		}
 		file.AddHashToFileName(combinedFile)
-		file.Compress(combinedFile, c.contentEncodings, protocol.BestCompression)
+		file.Compress(combinedFile, c.contentEncodings, protocol.CompressLevel_BestCompression)
 
 		for _, mainFile := range c.mainJSFiles {
 			var fileNameWithoutHashAdded = convert.UnsafeStringToByteSlice(combinedFileName)
@@ -250,7 +250,7 @@ func (c *combine) readyLandingsFiles() {
 				if err != nil && protocol.AppDebugMode {
 					protocol.App.Log(protocol.LogType_Warning, "WWW Minify -", localizedFileName, "occur this error:", err)
 				}
-				file.Compress(localizedLanding, c.contentEncodings, protocol.BestCompression)
+				file.Compress(localizedLanding, c.contentEncodings, protocol.CompressLevel_BestCompression)
 			}
 		}
 	}
@@ -275,6 +275,6 @@ func (c *combine) readySWFile() {
 		if err != nil && protocol.AppDebugMode {
 			protocol.App.Log(protocol.LogType_Warning, "Minify -", localeSWFileName, "occur this error:", err)
 		}
-		file.Compress(localeSWFile, c.contentEncodings, protocol.BestCompression)
+		file.Compress(localeSWFile, c.contentEncodings, protocol.CompressLevel_BestCompression)
 	}
 }